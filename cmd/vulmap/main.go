@@ -25,7 +25,6 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http"
 	"github.com/khulnasoft-lab/vulmap/pkg/templates"
 	"github.com/khulnasoft-lab/vulmap/pkg/templates/extensions"
-	"github.com/khulnasoft-lab/vulmap/pkg/templates/signer"
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types/scanstrategy"
@@ -53,11 +52,35 @@ func main() {
 		return
 	}
 
+	// manage the signer trust store if requested
+	if options.TrustAction != "" {
+		if err := runner.ManageTrustStore(options); err != nil {
+			gologger.Fatal().Msgf("Could not manage trust store: %s\n", err)
+		}
+		return
+	}
+
+	// export or import an offline templates bundle if requested
+	if options.BundleExport != "" || options.BundleImport != "" {
+		if err := runner.ManageTemplatesBundle(options); err != nil {
+			gologger.Fatal().Msgf("Could not manage templates bundle: %s\n", err)
+		}
+		return
+	}
+
+	// output the template dependency graph if requested
+	if options.TemplateGraph != "" || len(options.TemplateDependents) > 0 {
+		if err := runner.PrintTemplateGraph(options); err != nil {
+			gologger.Fatal().Msgf("Could not print template graph: %s\n", err)
+		}
+		return
+	}
+
 	// sign the templates if requested - only glob syntax is supported
 	if options.SignTemplates {
 		// use parsed options when initializing signer instead of default options
 		templates.UseOptionsForSigner(options)
-		tsigner, err := signer.NewTemplateSigner(nil, nil) // will read from env , config or generate new keys
+		tsigner, err := runner.NewTemplateSigner(options)
 		if err != nil {
 			gologger.Fatal().Msgf("couldn't initialize signer crypto engine: %s\n", err)
 		}
@@ -147,6 +170,8 @@ func main() {
 	if err := vulmapRunner.RunEnumeration(); err != nil {
 		if options.Validate {
 			gologger.Fatal().Msgf("Could not validate templates: %s\n", err)
+		} else if options.TestTemplates {
+			gologger.Fatal().Msgf("Could not test templates: %s\n", err)
 		} else {
 			gologger.Fatal().Msgf("Could not run vulmap: %s\n", err)
 		}
@@ -189,11 +214,18 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringSliceVarP(&options.Workflows, "workflows", "w", nil, "list of workflow or workflow directory to run (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.StringSliceVarP(&options.WorkflowURLs, "workflow-url", "wurl", nil, "workflow url or list containing workflow urls to run (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVar(&options.Validate, "validate", false, "validate the passed templates to vulmap"),
+		flagSet.BoolVarP(&options.TestTemplates, "test-templates", "tt", false, "run the embedded test cases of the passed templates against their fixtures"),
 		flagSet.BoolVarP(&options.NoStrictSyntax, "no-strict-syntax", "nss", false, "disable strict syntax check on templates"),
 		flagSet.BoolVarP(&options.TemplateDisplay, "template-display", "td", false, "displays the templates content"),
 		flagSet.BoolVar(&options.TemplateList, "tl", false, "list all available templates"),
 		flagSet.StringSliceVarConfigOnly(&options.RemoteTemplateDomainList, "remote-template-domain", []string{"templates.vulmap.sh"}, "allowed domain list to load remote templates from"),
 		flagSet.BoolVar(&options.SignTemplates, "sign", false, "signs the templates with the private key defined in VULMAP_SIGNATURE_PRIVATE_KEY env variable"),
+		flagSet.StringVar(&options.TrustAction, "trust", "", "manage the template signer trust store (list, add, revoke)"),
+		flagSet.StringVar(&options.TrustCert, "trust-cert", "", "certificate of the signer to add to or revoke from the trust store"),
+		flagSet.StringVar(&options.TrustName, "trust-name", "", "name or fingerprint of the signer to add to or revoke from the trust store"),
+		flagSet.StringSliceVar(&options.TrustScopes, "trust-scope", nil, "scopes of the signer to add to the trust store (protocol type or path:<dir>)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&options.TemplateGraph, "template-graph", "tgraph", "", "output the dependency graph of the templates (dot, json)"),
+		flagSet.StringSliceVarP(&options.TemplateDependents, "template-dependents", "tdep", nil, "list templates depending on the templates, helper files or js modules (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 	)
 
 	flagSet.CreateGroup("filters", "Filtering",
@@ -211,6 +243,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.VarP(&options.Protocols, "type", "pt", fmt.Sprintf("templates to run based on protocol type. Possible values: %s", templateTypes.GetSupportedProtocolTypes())),
 		flagSet.VarP(&options.ExcludeProtocols, "exclude-type", "ept", fmt.Sprintf("templates to exclude based on protocol type. Possible values: %s", templateTypes.GetSupportedProtocolTypes())),
 		flagSet.StringSliceVarP(&options.IncludeConditions, "template-condition", "tc", nil, "templates to run based on expression condition", goflags.StringSliceOptions),
+		flagSet.StringSliceVarP(&options.TemplateQuery, "template-query", "tq", nil, "templates to run based on query (e.g. 'cve-year >= 2023 AND epss-score > 0.1 AND NOT intrusive')", goflags.StringSliceOptions),
+		flagSet.BoolVarP(&options.TemplateQueryExplain, "template-query-explain", "tq-explain", false, "explain why templates were or weren't selected by the template query"),
 	)
 
 	flagSet.CreateGroup("output", "Output",
@@ -245,6 +279,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.SystemResolvers, "system-resolvers", "sr", false, "use system DNS resolving as error fallback"),
 		flagSet.BoolVarP(&options.DisableClustering, "disable-clustering", "dc", false, "disable clustering of requests"),
 		flagSet.BoolVar(&options.OfflineHTTP, "passive", false, "enable passive HTTP response processing mode"),
+		flagSet.StringVar(&options.PassiveProxy, "passive-proxy", "", "run an intercepting proxy on the address matching observed responses with passive templates (e.g. :8080)"),
+		flagSet.BoolVar(&options.PassiveProxyFuzz, "passive-proxy-fuzz", false, "execute fuzzing templates on the requests observed by the passive proxy"),
 		flagSet.BoolVarP(&options.ForceAttemptHTTP2, "force-http2", "fh2", false, "force http2 connection on requests"),
 		flagSet.BoolVarP(&options.EnvironmentVariables, "env-vars", "ev", false, "enable environment variables to be used in template"),
 		flagSet.StringVarP(&options.ClientCertFile, "client-cert", "cc", "", "client certificate file (PEM-encoded) used for authenticating against scanned hosts"),
@@ -305,6 +341,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.NoHostErrors, "no-mhe", "nmhe", false, "disable skipping host from scan based on errors"),
 		flagSet.BoolVar(&options.Project, "project", false, "use a project folder to avoid sending same request multiple times"),
 		flagSet.StringVar(&options.ProjectPath, "project-path", os.TempDir(), "set a specific project path"),
+		flagSet.StringVar(&options.RecordPath, "record", "", "record all protocol exchanges of the scan to the given directory"),
+		flagSet.StringVar(&options.ReplayPath, "replay", "", "replay recorded protocol exchanges from the given directory without network access"),
 		flagSet.BoolVarP(&options.StopAtFirstMatch, "stop-at-first-match", "spm", false, "stop processing HTTP requests after the first match (may break template/workflow logic)"),
 		flagSet.BoolVar(&options.Stream, "stream", false, "stream mode - start elaborating without sorting the input"),
 		flagSet.EnumVarP(&options.ScanStrategy, "scan-strategy", "ss", goflags.EnumVariable(0), "strategy to use while scanning(auto/host-spray/template-spray)", goflags.AllowdTypes{
@@ -324,6 +362,12 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringSliceVarP(&options.HeadlessOptionalArguments, "headless-options", "ho", nil, "start headless chrome with additional options", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.UseInstalledChrome, "system-chrome", "sc", false, "use local installed Chrome browser instead of vulmap installed"),
 		flagSet.BoolVarP(&options.ShowActions, "list-headless-action", "lha", false, "list available headless actions"),
+		flagSet.BoolVar(&options.Crawl, "crawl", false, "crawl the inputs with the headless browser to discover requests for fuzzing templates"),
+		flagSet.IntVarP(&options.CrawlDepth, "crawl-depth", "cd", 3, "maximum depth of the links followed by the crawler"),
+		flagSet.StringSliceVarP(&options.CrawlScope, "crawl-scope", "cs", nil, "regex of the in scope urls of the crawler (default: hostname of the input)", goflags.FileStringSliceOptions),
+		flagSet.StringSliceVarP(&options.CrawlOutOfScope, "crawl-out-scope", "cos", nil, "regex of the out of scope urls of the crawler", goflags.FileStringSliceOptions),
+		flagSet.StringVar(&options.SessionExport, "session-export", "", "export the session state (cookies, storage, auth headers) of the headless pages to the given file"),
+		flagSet.StringVar(&options.SessionImport, "session-import", "", "import the session state used by the http requests and headless pages from the given file"),
 	)
 
 	flagSet.CreateGroup("debug", "Debug",
@@ -350,7 +394,11 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&updateVulmapBinary, "update", "up", false, "update vulmap engine to the latest released version"),
 		flagSet.BoolVarP(&options.UpdateTemplates, "update-templates", "ut", false, "update vulmap-templates to latest released version"),
 		flagSet.StringVarP(&options.NewTemplatesDirectory, "update-template-dir", "ud", "", "custom directory to install / update vulmap-templates"),
+		flagSet.StringVarP(&options.BundleExport, "bundle-export", "be", "", "export installed templates to a signed offline bundle file (.tar.zst, .tar.gz)"),
+		flagSet.StringVarP(&options.BundleImport, "bundle-import", "bi", "", "verify and install templates from a signed offline bundle file"),
 		flagSet.CallbackVarP(disableUpdatesCallback, "disable-update-check", "duc", "disable automatic vulmap/templates update check"),
+		flagSet.StringVarP(&options.Lockfile, "lockfile", "lock", "", "refuse to run if installed templates don't match the lockfile (written if missing or with -ut)"),
+		flagSet.BoolVarP(&options.LockfileSync, "lockfile-sync", "lock-sync", false, "install the templates of the lockfile if installed templates don't match"),
 	)
 
 	flagSet.CreateGroup("stats", "Statistics",
@@ -518,6 +566,12 @@ func printTemplateVersion() {
 	if fileutil.FolderExists(cfg.CustomAzureTemplatesDirectory) {
 		gologger.Info().Msgf("Custom Azure templates location: %s ", cfg.CustomAzureTemplatesDirectory)
 	}
+	if fileutil.FolderExists(cfg.CustomGitTemplatesDirectory) {
+		gologger.Info().Msgf("Custom git templates location: %s ", cfg.CustomGitTemplatesDirectory)
+	}
+	if fileutil.FolderExists(cfg.CustomHTTPTemplatesDirectory) {
+		gologger.Info().Msgf("Custom HTTP templates location: %s ", cfg.CustomHTTPTemplatesDirectory)
+	}
 	os.Exit(0)
 }
 
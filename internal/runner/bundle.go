@@ -0,0 +1,31 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/installer"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// ManageTemplatesBundle exports or imports the offline templates bundle of the options
+func ManageTemplatesBundle(options *types.Options) error {
+	if options.BundleExport != "" && options.BundleImport != "" {
+		return fmt.Errorf("-bundle-export and -bundle-import can't be used together")
+	}
+	if options.BundleImport != "" {
+		tm := &installer.TemplateManager{}
+		return tm.ImportBundle(options.BundleImport)
+	}
+
+	tsigner, err := NewTemplateSigner(options)
+	if err != nil {
+		return fmt.Errorf("couldn't initialize signer crypto engine: %w", err)
+	}
+	manifest, err := installer.ExportBundle(options.BundleExport, tsigner)
+	if err != nil {
+		return err
+	}
+	gologger.Info().Msgf("Exported %d files of vulmap-templates %s to %s signed by %s", len(manifest.Files), manifest.TemplatesVersion, options.BundleExport, tsigner.Identifier())
+	return nil
+}
@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/remeh/sizedwaitgroup"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/core"
+	"github.com/khulnasoft-lab/vulmap/pkg/core/inputs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless/engine"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates"
+)
+
+// executeCrawlInput crawls the inputs with the headless browser and executes the
+// fuzzing templates on the discovered requests and the other templates on the inputs.
+func (r *Runner) executeCrawlInput(templatesList []*templates.Template, executorEngine *core.Engine) *atomic.Bool {
+	var fuzzingTemplates, otherTemplates []*templates.Template
+	for _, template := range templatesList {
+		if template.HasFuzzingRules() {
+			fuzzingTemplates = append(fuzzingTemplates, template)
+		} else {
+			otherTemplates = append(otherTemplates, template)
+		}
+	}
+
+	results := &atomic.Bool{}
+	if len(otherTemplates) > 0 {
+		results.Store(executorEngine.ExecuteScanWithOpts(otherTemplates, r.hmapInputProvider, r.options.DisableClustering).Load())
+	}
+	if len(fuzzingTemplates) > 0 {
+		crawled := r.crawlInputs()
+		gologger.Info().Msgf("Crawler discovered %d requests for %d fuzzing templates", crawled.Count(), len(fuzzingTemplates))
+		if executorEngine.ExecuteScanWithOpts(fuzzingTemplates, crawled, true).Load() {
+			results.Store(true)
+		}
+	}
+	return results
+}
+
+// crawlInputs crawls the URL inputs and returns the discovered requests
+func (r *Runner) crawlInputs() *inputs.SimpleInputProvider {
+	options := &engine.CrawlOptions{
+		Timeout:  time.Duration(r.options.PageTimeout) * time.Second,
+		MaxDepth: r.options.CrawlDepth,
+	}
+	// the regexes were validated with the options
+	for _, scope := range r.options.CrawlScope {
+		options.Scope = append(options.Scope, regexp.MustCompile(scope))
+	}
+	for _, scope := range r.options.CrawlOutOfScope {
+		options.OutOfScope = append(options.OutOfScope, regexp.MustCompile(scope))
+	}
+
+	crawled := &inputs.SimpleInputProvider{}
+	var mu sync.Mutex
+	swg := sizedwaitgroup.New(r.options.HeadlessBulkSize)
+	r.hmapInputProvider.Scan(func(value *contextargs.MetaInput) bool {
+		if !strings.Contains(value.Input, "://") {
+			gologger.Verbose().Msgf("Could not crawl %s: input is not a url\n", value.Input)
+			return true
+		}
+		swg.Add()
+		go func(input string) {
+			defer swg.Done()
+
+			instance, err := r.browser.NewInstance()
+			if err != nil {
+				gologger.Warning().Msgf("Could not create browser instance to crawl %s: %s\n", input, err)
+				return
+			}
+			defer instance.Close()
+
+			err = instance.Crawl(input, options, func(discovered *contextargs.MetaInput) {
+				gologger.Verbose().Msgf("Crawler discovered %s %s\n", discovered.Request.Method, discovered.Input)
+				mu.Lock()
+				crawled.Inputs = append(crawled.Inputs, discovered)
+				mu.Unlock()
+			})
+			if err != nil {
+				gologger.Warning().Msgf("Could not crawl %s: %s\n", input, err)
+			}
+		}(value.Input)
+		return true
+	})
+	swg.Wait()
+	return crawled
+}
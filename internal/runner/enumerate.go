@@ -31,6 +31,9 @@ func (r *Runner) runStandardEnumeration(executerOpts protocols.ExecutorOptions,
 	if r.options.AutomaticScan {
 		return r.executeSmartWorkflowInput(executerOpts, store, engine)
 	}
+	if r.options.PassiveProxy != "" {
+		return r.executePassiveProxy(executerOpts, store, engine)
+	}
 	return r.executeTemplatesInput(store, engine)
 }
 
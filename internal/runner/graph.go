@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/disk"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates/graph"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// PrintTemplateGraph writes the dependency graph of the templates or the
+// templates depending on the nodes queried with -template-dependents
+func PrintTemplateGraph(options *types.Options) error {
+	format := strings.ToLower(options.TemplateGraph)
+	switch format {
+	case "":
+		format = "dot"
+	case "dot", "json":
+	default:
+		return fmt.Errorf("invalid template graph format %s (dot, json)", options.TemplateGraph)
+	}
+
+	catalog := disk.NewCatalog(config.DefaultConfig.TemplatesDirectory)
+	definitions := append([]string{config.DefaultConfig.TemplatesDirectory}, options.Templates...)
+	definitions = append(definitions, options.Workflows...)
+	templatePaths, errs := catalog.GetTemplatesPath(definitions)
+	for template, err := range errs {
+		gologger.Warning().Msgf("Could not find template %s: %s", template, err)
+	}
+	g, err := graph.Build(catalog, templatePaths)
+	if err != nil {
+		return fmt.Errorf("could not build template graph: %w", err)
+	}
+
+	var output io.Writer = os.Stdout
+	if options.Output != "" {
+		file, err := os.Create(options.Output)
+		if err != nil {
+			return fmt.Errorf("could not create output file %s: %w", options.Output, err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	if len(options.TemplateDependents) == 0 {
+		if format == "json" {
+			return g.WriteJSON(output)
+		}
+		return g.WriteDOT(output)
+	}
+	var ids []string
+	for _, query := range options.TemplateDependents {
+		resolved := g.Resolve(query)
+		if len(resolved) == 0 {
+			gologger.Warning().Msgf("Could not find %s in template graph", query)
+		}
+		ids = append(ids, resolved...)
+	}
+	dependents := g.Dependents(ids...)
+	gologger.Info().Msgf("Found %d templates depending on %s", len(dependents), strings.Join(options.TemplateDependents, ","))
+	if format == "json" {
+		if dependents == nil {
+			dependents = []string{}
+		}
+		return json.NewEncoder(output).Encode(dependents)
+	}
+	for _, dependent := range dependents {
+		if _, err := fmt.Fprintln(output, dependent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
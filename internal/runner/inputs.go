@@ -50,7 +50,7 @@ func (r *Runner) initializeTemplatesHTTPInput() (*hybrid.HybridMap, error) {
 		go func(input *contextargs.MetaInput) {
 			defer swg.Done()
 
-			if result := utils.ProbeURL(input.Input, httpxClient); result != "" {
+			if result := r.probeURL(input.Input, httpxClient); result != "" {
 				atomic.AddInt32(&count, 1)
 				_ = hm.Set(input.Input, []byte(result))
 			}
@@ -62,3 +62,21 @@ func (r *Runner) initializeTemplatesHTTPInput() (*hybrid.HybridMap, error) {
 	gologger.Info().Msgf("Found %d URL from httpx", atomic.LoadInt32(&count))
 	return hm, nil
 }
+
+// probeURL probes the input for a http url. The result is recorded when
+// recording a scan and served from the recording when replaying one.
+func (r *Runner) probeURL(input string, httpxClient *httpx.HTTPX) string {
+	if r.replay.Replaying() {
+		if entry, err := r.replay.Replay("probe", input); err == nil && len(entry.Data) > 0 {
+			return string(entry.Data[0])
+		}
+		return ""
+	}
+	result := utils.ProbeURL(input, httpxClient)
+	if result != "" {
+		if err := r.replay.Record("probe", input, []byte(result)); err != nil {
+			gologger.Warning().Msgf("Could not record probe for %s: %s", input, err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/khulnasoft-lab/gologger"
+	fileutil "github.com/khulnasoft-lab/utils/file"
+	"github.com/khulnasoft-lab/vulmap/pkg/external/customtemplates"
+	"github.com/khulnasoft-lab/vulmap/pkg/installer"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// maxLockfileDiffs is the maximum number of differences to the lockfile reported
+const maxLockfileDiffs = 10
+
+// verifyLockfile verifies the installed templates against the lockfile of the
+// options. The lockfile is written if it does not exist or templates were
+// updated, and the templates of the lockfile are installed with -lockfile-sync.
+func verifyLockfile(options *types.Options) error {
+	tm := &installer.TemplateManager{DisablePublicTemplates: options.PublicTemplateDisableDownload}
+	installed, err := tm.GenerateLockfile()
+	if err != nil {
+		return err
+	}
+	if !fileutil.FileExists(options.Lockfile) || options.UpdateTemplates {
+		if err := installed.Write(options.Lockfile); err != nil {
+			return fmt.Errorf("could not write lockfile %s: %w", options.Lockfile, err)
+		}
+		gologger.Info().Msgf("Pinned vulmap-templates %s and %d custom templates sources in lockfile %s", installed.TemplatesVersion, len(installed.CustomTemplates), options.Lockfile)
+		return nil
+	}
+
+	lock, err := installer.ReadLockfile(options.Lockfile)
+	if err != nil {
+		return err
+	}
+	diffs := lock.Diff(installed)
+	if len(diffs) > 0 && options.LockfileSync {
+		gologger.Info().Msgf("Installed templates don't match lockfile %s, syncing", options.Lockfile)
+		if tm.CustomTemplates, err = customtemplates.NewCustomTemplatesManager(options); err != nil {
+			gologger.Error().Label("custom-templates").Msgf("Failed to create custom templates manager: %s\n", err)
+		}
+		if err := tm.SyncLockfile(lock); err != nil {
+			return fmt.Errorf("could not sync templates with lockfile %s: %w", options.Lockfile, err)
+		}
+		if installed, err = tm.GenerateLockfile(); err != nil {
+			return err
+		}
+		diffs = lock.Diff(installed)
+	}
+	if len(diffs) == 0 {
+		gologger.Verbose().Msgf("Installed templates match lockfile %s", options.Lockfile)
+		return nil
+	}
+	for _, diff := range diffs[:min(len(diffs), maxLockfileDiffs)] {
+		gologger.Error().Msgf("Lockfile mismatch: %s", diff)
+	}
+	if len(diffs) > maxLockfileDiffs {
+		gologger.Error().Msgf("Lockfile mismatch: %d more differences", len(diffs)-maxLockfileDiffs)
+	}
+	hint := "use -lockfile-sync to install the templates of the lockfile or -ut to update it"
+	if options.LockfileSync {
+		hint = "the templates of the lockfile could not be installed"
+	}
+	return fmt.Errorf("installed templates don't match lockfile %s (%d differences): %s", options.Lockfile, len(diffs), hint)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolinit"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless/engine"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates/signer"
 	protocoltypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	fileutil "github.com/khulnasoft-lab/utils/file"
@@ -94,7 +96,7 @@ func ParseOptions(options *types.Options) {
 		}
 	}
 
-	if options.OfflineHTTP {
+	if options.OfflineHTTP || options.PassiveProxy != "" {
 		options.DisableHTTPProbe = true
 	}
 }
@@ -120,6 +122,20 @@ func ValidateOptions(options *types.Options) error {
 		return errors.New("headless mode (-headless) is required if -ho, -sb, -sc or -lha are set")
 	}
 
+	if options.Crawl && !options.Headless {
+		return errors.New("headless mode (-headless) is required for -crawl")
+	}
+	for _, scopes := range []goflags.StringSlice{options.CrawlScope, options.CrawlOutOfScope} {
+		for _, scope := range scopes {
+			if _, err := regexp.Compile(scope); err != nil {
+				return errors.Wrapf(err, "invalid crawl scope regex %s", scope)
+			}
+		}
+	}
+
+	if options.RecordPath != "" && options.ReplayPath != "" {
+		return errors.New("both record and replay specified")
+	}
 	if options.FollowHostRedirects && options.FollowRedirects {
 		return errors.New("both follow host redirects and follow redirects specified")
 	}
@@ -157,6 +173,22 @@ func ValidateOptions(options *types.Options) error {
 		}
 	}
 
+	if options.LockfileSync && options.Lockfile == "" {
+		return errors.New("-lockfile-sync requires -lockfile")
+	}
+	if options.LockfileSync && options.UpdateTemplates {
+		return errors.New("-lockfile-sync and -update-templates can't be used together")
+	}
+	if options.TemplateQueryExplain && len(options.TemplateQuery) == 0 {
+		return errors.New("-template-query-explain requires -template-query")
+	}
+	if options.PassiveProxyFuzz && options.PassiveProxy == "" {
+		return errors.New("-passive-proxy-fuzz requires -passive-proxy")
+	}
+	if options.PassiveProxy != "" && options.OfflineHTTP {
+		return errors.New("-passive-proxy and -passive can't be used together")
+	}
+
 	// Verify that all GitLab options are provided if the GitLab server or token is provided
 	if len(options.GitLabTemplateRepositoryIDs) != 0 && options.UpdateTemplates && !options.GitLabTemplateDisableDownload {
 		missing := validateMissingGitLabOptions(options)
@@ -352,6 +384,17 @@ func validateCertificatePaths(certificatePaths ...string) {
 	}
 }
 
+// readSignatureEnvVars reads the template signature algorithm and
+// custom public key from env variables
+func readSignatureEnvVars(options *types.Options) {
+	if options.CodeTemplateSignaturePublicKey == "" {
+		options.CodeTemplateSignaturePublicKey = os.Getenv(signer.PublicKeyEnvName)
+	}
+	if options.CodeTemplateSignatureAlgorithm == "" {
+		options.CodeTemplateSignatureAlgorithm = os.Getenv(signer.AlgorithmEnvName)
+	}
+}
+
 // Read the input from env and set options
 func readEnvInputVars(options *types.Options) {
 	if strings.EqualFold(os.Getenv("VULMAP_CLOUD"), "true") {
@@ -406,9 +449,22 @@ func readEnvInputVars(options *types.Options) {
 	options.AzureClientSecret = os.Getenv("AZURE_CLIENT_SECRET")
 	options.AzureServiceURL = os.Getenv("AZURE_SERVICE_URL")
 
+	// Generic git options for downloading templates from any git server
+	if repolist = os.Getenv("GIT_TEMPLATE_REPO"); repolist != "" {
+		options.GitTemplateRepo = append(options.GitTemplateRepo, stringsutil.SplitAny(repolist, ",")...)
+	}
+	options.GitTemplateUsername = os.Getenv("GIT_TEMPLATE_USERNAME")
+	options.GitTemplateToken = os.Getenv("GIT_TEMPLATE_TOKEN")
+	options.GitTemplateSSHKey = os.Getenv("GIT_TEMPLATE_SSH_KEY")
+
+	// HTTP(S) options for downloading templates from tarballs
+	if urllist := os.Getenv("HTTP_TEMPLATE_URL"); urllist != "" {
+		options.HTTPTemplateURL = append(options.HTTPTemplateURL, stringsutil.SplitAny(urllist, ",")...)
+	}
+	options.HTTPTemplateToken = os.Getenv("HTTP_TEMPLATE_TOKEN")
+
 	// Custom public keys for template verification
-	options.CodeTemplateSignaturePublicKey = os.Getenv("VULMAP_SIGNATURE_PUBLIC_KEY")
-	options.CodeTemplateSignatureAlgorithm = os.Getenv("VULMAP_SIGNATURE_ALGORITHM")
+	readSignatureEnvVars(options)
 
 	// General options to disable the template download locations from being used.
 	// This will override the default behavior of downloading templates from the default locations as well as the
@@ -420,6 +476,8 @@ func readEnvInputVars(options *types.Options) {
 	options.GitLabTemplateDisableDownload = getBoolEnvValue("DISABLE_VULMAP_TEMPLATES_GITLAB_DOWNLOAD")
 	options.AwsTemplateDisableDownload = getBoolEnvValue("DISABLE_VULMAP_TEMPLATES_AWS_DOWNLOAD")
 	options.AzureTemplateDisableDownload = getBoolEnvValue("DISABLE_VULMAP_TEMPLATES_AZURE_DOWNLOAD")
+	options.GitTemplateDisableDownload = getBoolEnvValue("DISABLE_VULMAP_TEMPLATES_GIT_DOWNLOAD")
+	options.HTTPTemplateDisableDownload = getBoolEnvValue("DISABLE_VULMAP_TEMPLATES_HTTP_DOWNLOAD")
 
 	// Options to modify the behavior of exporters
 	options.MarkdownExportSortMode = strings.ToLower(os.Getenv("MARKDOWN_EXPORT_SORT_MODE"))
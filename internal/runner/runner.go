@@ -38,19 +38,23 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/projectfile"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/automaticscan"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/passive"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/hosterrorscache"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolinit"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/uncover"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/excludematchers"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless/engine"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
 	"github.com/khulnasoft-lab/vulmap/pkg/reporting"
 	"github.com/khulnasoft-lab/vulmap/pkg/reporting/exporters/jsonexporter"
 	"github.com/khulnasoft-lab/vulmap/pkg/reporting/exporters/jsonl"
 	"github.com/khulnasoft-lab/vulmap/pkg/reporting/exporters/markdown"
 	"github.com/khulnasoft-lab/vulmap/pkg/reporting/exporters/sarif"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
 	"github.com/khulnasoft-lab/vulmap/pkg/templates"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/utils"
@@ -66,6 +70,8 @@ type Runner struct {
 	interactsh        *interactsh.Client
 	options           *types.Options
 	projectFile       *projectfile.ProjectFile
+	replay            *replay.Store
+	session           *session.Store
 	catalog           catalog.Catalog
 	progress          progress.Progress
 	colorizer         aurora.Aurora
@@ -78,6 +84,7 @@ type Runner struct {
 	pprofServer       *http.Server
 	cloudClient       *vulmapcloud.Client
 	cloudTargets      []string
+	passiveProxy      *passive.Service
 }
 
 const pprofServerAddress = "127.0.0.1:8086"
@@ -120,8 +127,11 @@ func New(options *types.Options) (*Runner, error) {
 		if err := tm.FreshInstallIfNotExists(); err != nil {
 			gologger.Warning().Msgf("failed to install vulmap templates: %s\n", err)
 		}
-		if err := tm.UpdateIfOutdated(); err != nil {
-			gologger.Warning().Msgf("failed to update vulmap templates: %s\n", err)
+		// templates pinned by a lockfile are only updated explicitly
+		if options.Lockfile == "" || options.UpdateTemplates {
+			if err := tm.UpdateIfOutdated(); err != nil {
+				gologger.Warning().Msgf("failed to update vulmap templates: %s\n", err)
+			}
 		}
 
 		if config.DefaultConfig.NeedsIgnoreFileUpdate() {
@@ -143,6 +153,12 @@ func New(options *types.Options) (*Runner, error) {
 		}
 	}
 
+	if options.Lockfile != "" {
+		if err := verifyLockfile(options); err != nil {
+			return nil, err
+		}
+	}
+
 	if options.Validate {
 		parsers.ShouldValidate = true
 	}
@@ -275,6 +291,29 @@ func New(options *types.Options) (*Runner, error) {
 		}
 	}
 
+	// create the record/replay store if requested
+	if options.RecordPath != "" || options.ReplayPath != "" {
+		replayOptions := &replay.Options{Path: options.RecordPath}
+		if options.ReplayPath != "" {
+			replayOptions = &replay.Options{Path: options.ReplayPath, Replay: true}
+		}
+		var replayErr error
+		runner.replay, replayErr = replay.New(replayOptions)
+		if replayErr != nil {
+			return nil, replayErr
+		}
+	}
+
+	// create the session store if requested
+	if options.SessionExport != "" || options.SessionImport != "" {
+		var sessionErr error
+		runner.session, sessionErr = session.New(&session.Options{ImportPath: options.SessionImport, ExportPath: options.SessionExport})
+		if sessionErr != nil {
+			return nil, sessionErr
+		}
+		protocolstate.Session = runner.session
+	}
+
 	// create the resume configuration structure
 	resumeCfg := types.NewResumeCfg()
 	if runner.options.ShouldLoadResume() {
@@ -307,6 +346,7 @@ func New(options *types.Options) (*Runner, error) {
 	opts.Debug = runner.options.Debug
 	opts.DebugRequest = runner.options.DebugRequests
 	opts.DebugResponse = runner.options.DebugResponse
+	opts.Replay = runner.replay
 	if httpclient != nil {
 		opts.HTTPClient = httpclient
 	}
@@ -333,6 +373,7 @@ func New(options *types.Options) (*Runner, error) {
 	} else {
 		runner.rateLimiter = ratelimit.NewUnlimited(context.Background())
 	}
+	protocolstate.RateLimiter = runner.rateLimiter
 	return runner, nil
 }
 
@@ -378,12 +419,19 @@ func createReportingOptions(options *types.Options) (*reporting.Options, error)
 
 // Close releases all the resources and cleans up
 func (r *Runner) Close() {
+	// wait for the templates matching the observed traffic before closing the output
+	if r.passiveProxy != nil {
+		r.passiveProxy.Close()
+	}
 	if r.output != nil {
 		r.output.Close()
 	}
 	if r.projectFile != nil {
 		r.projectFile.Close()
 	}
+	if err := r.session.Close(); err != nil {
+		gologger.Error().Msgf("Could not export session state: %s", err)
+	}
 	r.hmapInputProvider.Close()
 	protocolinit.Close()
 	if r.pprofServer != nil {
@@ -426,6 +474,8 @@ func (r *Runner) RunEnumeration() error {
 		RateLimiter:     r.rateLimiter,
 		Interactsh:      r.interactsh,
 		ProjectFile:     r.projectFile,
+		Replay:          r.replay,
+		Session:         r.session,
 		Browser:         r.browser,
 		Colorizer:       r.colorizer,
 		ResumeCfg:       r.resumeCfg,
@@ -484,6 +534,14 @@ func (r *Runner) RunEnumeration() error {
 		}
 		return nil // exit
 	}
+	if r.options.TestTemplates {
+		store.Load()
+		return r.runTemplateTests(store)
+	}
+	if r.options.TemplateQueryExplain {
+		r.explainTemplateQueries(store)
+		return nil
+	}
 	store.Load()
 	// TODO: remove below functions after v3 or update warning messages
 	disk.PrintDeprecatedPathsMsgIfApplicable(r.options.Silent)
@@ -640,6 +698,27 @@ func (r *Runner) executeSmartWorkflowInput(executorOpts protocols.ExecutorOption
 	return result, nil
 }
 
+func (r *Runner) executePassiveProxy(executorOpts protocols.ExecutorOptions, store *loader.Store, engine *core.Engine) (*atomic.Bool, error) {
+	service, err := passive.New(passive.Options{
+		ExecuterOpts: executorOpts,
+		Templates:    store.Templates(),
+		Engine:       engine,
+		Address:      r.options.PassiveProxy,
+		Directory:    config.DefaultConfig.GetConfigDir(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create passive proxy service")
+	}
+	r.passiveProxy = service
+	if err := service.ListenAndServe(); err != nil {
+		return nil, errors.Wrap(err, "could not run passive proxy")
+	}
+	// the proxy runs until it is closed by the runner
+	result := &atomic.Bool{}
+	result.Store(service.Wait())
+	return result, nil
+}
+
 func (r *Runner) executeTemplatesInput(store *loader.Store, engine *core.Engine) (*atomic.Bool, error) {
 	if r.options.VerboseVerbose {
 		for _, template := range store.Templates() {
@@ -658,6 +737,9 @@ func (r *Runner) executeTemplatesInput(store *loader.Store, engine *core.Engine)
 		return nil, errors.New("no templates provided for scan")
 	}
 
+	if r.options.Crawl {
+		return r.executeCrawlInput(finalTemplates, engine), nil
+	}
 	results := engine.ExecuteScanWithOpts(finalTemplates, r.hmapInputProvider, r.options.DisableClustering)
 	return results, nil
 }
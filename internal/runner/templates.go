@@ -2,6 +2,7 @@ package runner
 
 import (
 	"bytes"
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -68,6 +69,23 @@ func (r *Runner) listAvailableStoreTemplates(store *loader.Store) {
 	}
 }
 
+// explainTemplateQueries prints why the templates of the store were or weren't
+// selected by the template queries
+func (r *Runner) explainTemplateQueries(store *loader.Store) {
+	var selected int
+	explanations := store.ExplainTemplates()
+	for _, explanation := range explanations {
+		status := r.colorizer.Red("excluded").String()
+		if explanation.Selected {
+			status = r.colorizer.Green("selected").String()
+			selected++
+		}
+		path := strings.TrimPrefix(explanation.Path, config.DefaultConfig.TemplatesDirectory+string(filepath.Separator))
+		gologger.Silent().Msgf("[%s] [%s] %s\n%s\n", status, explanation.ID, path, explanation.Explanation)
+	}
+	gologger.Info().Msgf("Template queries selected %d of %d templates", selected, len(explanations))
+}
+
 func (r *Runner) highlightTemplate(body *[]byte) ([]byte, error) {
 	var buf bytes.Buffer
 	// YAML lexer, true color terminal formatter and monokai style
@@ -87,5 +105,32 @@ func hasExtraFlags(options *types.Options) bool {
 		options.ExcludedTemplates != nil || options.ExcludeMatchers != nil ||
 		options.Severities != nil || options.ExcludeSeverities != nil ||
 		options.Protocols != nil || options.ExcludeProtocols != nil ||
-		options.IncludeConditions != nil || options.TemplateList
+		options.IncludeConditions != nil || options.TemplateQuery != nil ||
+		options.TemplateList
+}
+
+// runTemplateTests runs the embedded test cases of the loaded templates
+// against their fixtures and returns an error if any of them failed.
+func (r *Runner) runTemplateTests(store *loader.Store) error {
+	var total, failed int
+	for _, tpl := range store.Templates() {
+		for _, result := range tpl.RunTests() {
+			total++
+			if result.Passed() {
+				gologger.Info().Msgf("[%s] Test case %s passed\n", tpl.ID, result.Name)
+				continue
+			}
+			failed++
+			gologger.Error().Msgf("[%s] Test case %s failed:\n\t%s\n", tpl.ID, result.Name, strings.Join(result.Failures, "\n\t"))
+		}
+	}
+	if total == 0 {
+		gologger.Warning().Msgf("No template test cases found\n")
+		return nil
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d/%d template test cases failed", failed, total)
+	}
+	gologger.Info().Msgf("All %d template test cases passed\n", total)
+	return nil
 }
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/khulnasoft-lab/gologger"
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates/signer"
+	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// ManageTrustStore performs the trust store action of the options
+func ManageTrustStore(options *types.Options) error {
+	readSignatureEnvVars(options)
+	trustStore, err := signer.LoadTrustStore(signer.DefaultTrustStoreDir())
+	if err != nil {
+		return err
+	}
+
+	switch options.TrustAction {
+	case "list":
+		listTrustStore(trustStore)
+		return nil
+	case "add":
+		if options.TrustCert == "" {
+			return fmt.Errorf("no certificate specified to add, use -trust-cert")
+		}
+		if err := validateTrustScopes(options.TrustScopes); err != nil {
+			return err
+		}
+		cert, err := os.ReadFile(options.TrustCert)
+		if err != nil {
+			return err
+		}
+		added, err := trustStore.Add(options.TrustName, options.CodeTemplateSignatureAlgorithm, cert, options.TrustScopes)
+		if err != nil {
+			return err
+		}
+		if err := trustStore.Save(); err != nil {
+			return err
+		}
+		gologger.Info().Msgf("Added signer %s (%s) to trust store %s", added.Name, added.Fingerprint, trustStore.Dir())
+		return nil
+	case "revoke":
+		nameOrFingerprint := options.TrustName
+		if options.TrustCert != "" {
+			cert, err := os.ReadFile(options.TrustCert)
+			if err != nil {
+				return err
+			}
+			if nameOrFingerprint, err = signer.CertificateFingerprint(options.CodeTemplateSignatureAlgorithm, cert); err != nil {
+				return err
+			}
+		}
+		if nameOrFingerprint == "" {
+			return fmt.Errorf("no signer specified to revoke, use -trust-name or -trust-cert")
+		}
+		revoked, err := trustStore.Revoke(nameOrFingerprint)
+		if err != nil {
+			return err
+		}
+		if err := trustStore.Save(); err != nil {
+			return err
+		}
+		gologger.Info().Msgf("Revoked signer %s in trust store %s", revoked.Fingerprint, trustStore.Dir())
+		return nil
+	default:
+		return fmt.Errorf("unknown trust action %q (list, add, revoke)", options.TrustAction)
+	}
+}
+
+// NewTemplateSigner creates the template signer of the signature algorithm
+// of the options
+func NewTemplateSigner(options *types.Options) (*signer.TemplateSigner, error) {
+	readSignatureEnvVars(options)
+	if options.CodeTemplateSignatureAlgorithm != "" && !sliceutil.Contains(signer.SupportedAlgorithms, options.CodeTemplateSignatureAlgorithm) {
+		return nil, fmt.Errorf("unknown signature algorithm %q (%s)", options.CodeTemplateSignatureAlgorithm, strings.Join(signer.SupportedAlgorithms, ","))
+	}
+	// ecdsa keys are read from env or config or generated, the private keys of the
+	// other algorithms are only read from env
+	return signer.NewTemplateSignerWithAlgorithm(options.CodeTemplateSignatureAlgorithm, nil, nil)
+}
+
+// listTrustStore lists the signers trusted for verifying templates
+// and the revoked signers
+func listTrustStore(trustStore *signer.TrustStore) {
+	gologger.Print().Msgf("\nListing trusted template signers of %s\n", trustStore.Dir())
+	for _, verifier := range signer.DefaultTemplateVerifiers {
+		scopes := "all templates"
+		if len(verifier.Scopes()) > 0 {
+			scopes = strings.Join(verifier.Scopes(), ",")
+		}
+		gologger.Silent().Msgf("%s\t%s\t%s\t%s\n", verifier.Identifier(), verifier.Algorithm(), verifier.Fingerprint(), scopes)
+	}
+	for _, revoked := range trustStore.Revoked {
+		gologger.Silent().Msgf("%s\t%s\trevoked at %s\n", revoked.Name, revoked.Fingerprint, revoked.RevokedAt.Format("2006-01-02 15:04:05"))
+	}
+}
+
+// validateTrustScopes validates the scopes of a signer
+func validateTrustScopes(scopes []string) error {
+	var supported []string
+	for _, protocolType := range templateTypes.GetSupportedProtocolTypes() {
+		if name := protocolType.String(); name != "" {
+			supported = append(supported, name)
+		}
+	}
+	for _, scope := range scopes {
+		if strings.HasPrefix(scope, signer.PathScopePrefix) {
+			continue
+		}
+		if !sliceutil.Contains(supported, scope) {
+			return fmt.Errorf("invalid scope %q, expected a protocol type (%s) or %s<dir>", scope, strings.Join(supported, ","), signer.PathScopePrefix)
+		}
+	}
+	return nil
+}
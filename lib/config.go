@@ -52,6 +52,7 @@ type TemplateFilters struct {
 	IDs                  []string // filter by template IDs
 	ExcludeIDs           []string // filter by excluding template IDs
 	TemplateCondition    []string // DSL condition/ expression
+	TemplateQuery        []string // template query (e.g. cve-year >= 2023 AND NOT intrusive)
 }
 
 // WithTemplateFilters sets template filters and only templates matching the filters will be
@@ -85,6 +86,7 @@ func WithTemplateFilters(filters TemplateFilters) VulmapSDKOptions {
 		e.opts.Protocols = pt
 		e.opts.ExcludeProtocols = ept
 		e.opts.IncludeConditions = filters.TemplateCondition
+		e.opts.TemplateQuery = filters.TemplateQuery
 		return nil
 	}
 }
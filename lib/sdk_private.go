@@ -159,6 +159,7 @@ func (e *VulmapEngine) init() error {
 	} else {
 		e.executerOpts.RateLimiter = ratelimit.NewUnlimited(context.Background())
 	}
+	protocolstate.RateLimiter = e.executerOpts.RateLimiter
 
 	e.engine = core.New(e.opts)
 	e.engine.SetExecuterOptions(e.executerOpts)
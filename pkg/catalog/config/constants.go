@@ -23,6 +23,8 @@ const (
 	CustomGitHubTemplatesDirName = "github"
 	CustomAzureTemplatesDirName  = "azure"
 	CustomGitLabTemplatesDirName = "gitlab"
+	CustomGitTemplatesDirName    = "git"
+	CustomHTTPTemplatesDirName   = "https"
 	BinaryName                   = "vulmap"
 	FallbackConfigFolderName     = ".vulmap-config"
 	VulmapConfigDirEnv           = "VULMAP_CONFIG_DIR"
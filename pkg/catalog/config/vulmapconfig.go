@@ -31,6 +31,8 @@ type Config struct {
 	CustomGitHubTemplatesDirectory string `json:"custom-github-templates-directory"`
 	CustomGitLabTemplatesDirectory string `json:"custom-gitlab-templates-directory"`
 	CustomAzureTemplatesDirectory  string `json:"custom-azure-templates-directory"`
+	CustomGitTemplatesDirectory    string `json:"custom-git-templates-directory"`
+	CustomHTTPTemplatesDirectory   string `json:"custom-http-templates-directory"`
 
 	TemplateVersion        string `json:"vulmap-templates-version,omitempty"`
 	VulmapIgnoreHash       string `json:"vulmap-ignore-hash,omitempty"`
@@ -127,7 +129,7 @@ func (c *Config) GetKeysDir() string {
 
 // GetAllCustomTemplateDirs returns all custom template directories
 func (c *Config) GetAllCustomTemplateDirs() []string {
-	return []string{c.CustomS3TemplatesDirectory, c.CustomGitHubTemplatesDirectory, c.CustomGitLabTemplatesDirectory, c.CustomAzureTemplatesDirectory}
+	return []string{c.CustomS3TemplatesDirectory, c.CustomGitHubTemplatesDirectory, c.CustomGitLabTemplatesDirectory, c.CustomAzureTemplatesDirectory, c.CustomGitTemplatesDirectory, c.CustomHTTPTemplatesDirectory}
 }
 
 // GetReportingConfigFilePath returns the vulmap reporting config file path
@@ -216,6 +218,8 @@ func (c *Config) SetTemplatesDir(dirPath string) {
 	c.CustomS3TemplatesDirectory = filepath.Join(dirPath, CustomS3TemplatesDirName)
 	c.CustomGitLabTemplatesDirectory = filepath.Join(dirPath, CustomGitLabTemplatesDirName)
 	c.CustomAzureTemplatesDirectory = filepath.Join(dirPath, CustomAzureTemplatesDirName)
+	c.CustomGitTemplatesDirectory = filepath.Join(dirPath, CustomGitTemplatesDirName)
+	c.CustomHTTPTemplatesDirectory = filepath.Join(dirPath, CustomHTTPTemplatesDirName)
 }
 
 // SetTemplatesVersion sets the new vulmap templates version
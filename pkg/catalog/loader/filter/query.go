@@ -0,0 +1,524 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates"
+)
+
+// Query is a parsed template query selecting templates with boolean logic,
+// comparisons and globs over the template info, classification and metadata.
+//
+// Example: cve-year >= 2023 AND epss-score > 0.1 AND NOT intrusive
+type Query struct {
+	raw  string
+	root queryNode
+}
+
+// queryFieldAliases maps the aliases of query fields to their field
+var queryFieldAliases = map[string]string{
+	"tag":         "tags",
+	"author":      "authors",
+	"reference":   "references",
+	"type":        "protocol",
+	"cve":         "cve-id",
+	"cwe":         "cwe-id",
+	"cvss":        "cvss-score",
+	"epss":        "epss-score",
+	"template-id": "id",
+}
+
+// queryFields contains the fields supported by template queries besides metadata.<key>
+var queryFields = map[string]struct{}{
+	"id": {}, "path": {}, "name": {}, "description": {}, "impact": {}, "remediation": {},
+	"authors": {}, "tags": {}, "references": {}, "severity": {}, "protocol": {},
+	"cve-id": {}, "cve-year": {}, "cwe-id": {}, "cvss-metrics": {}, "cvss-score": {},
+	"epss-score": {}, "epss-percentile": {}, "cpe": {},
+}
+
+var reCVEYear = regexp.MustCompile(`(?i)cve-(\d{4})-`)
+
+// ParseQuery parses a template query
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template query %q: %w", query, err)
+	}
+	p := &queryParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid template query %q: %w", query, err)
+	}
+	return &Query{raw: query, root: root}, nil
+}
+
+// String returns the query as given by the user
+func (q *Query) String() string {
+	return q.raw
+}
+
+// Match returns true if the template matches the query
+func (q *Query) Match(template *templates.Template) bool {
+	return q.root.eval(collectQueryFields(template))
+}
+
+// Explain returns whether the template matches the query along with the
+// evaluation of every expression of the query
+func (q *Query) Explain(template *templates.Template) (bool, string) {
+	var builder strings.Builder
+	matched := q.root.explain(collectQueryFields(template), 0, &builder)
+	return matched, strings.TrimSuffix(builder.String(), "\n")
+}
+
+// collectQueryFields returns the values of the query fields of the template
+func collectQueryFields(template *templates.Template) map[string][]string {
+	info := template.Info
+	fields := map[string][]string{
+		"id":          {template.ID},
+		"path":        {template.Path},
+		"name":        {info.Name},
+		"description": {info.Description},
+		"impact":      {info.Impact},
+		"remediation": {info.Remediation},
+		"authors":     info.Authors.ToSlice(),
+		"tags":        info.Tags.ToSlice(),
+		"severity":    {info.SeverityHolder.Severity.String()},
+		"protocol":    {template.Type().String()},
+	}
+	if info.Reference != nil {
+		fields["references"] = info.Reference.ToSlice()
+	}
+	cveIDs := []string{template.ID}
+	if classification := info.Classification; classification != nil {
+		fields["cve-id"] = classification.CVEID.ToSlice()
+		fields["cwe-id"] = classification.CWEID.ToSlice()
+		fields["cvss-metrics"] = []string{classification.CVSSMetrics}
+		fields["cpe"] = []string{classification.CPE}
+		if classification.CVSSScore != 0 {
+			fields["cvss-score"] = []string{strconv.FormatFloat(classification.CVSSScore, 'f', -1, 64)}
+		}
+		if classification.EPSSScore != 0 {
+			fields["epss-score"] = []string{strconv.FormatFloat(classification.EPSSScore, 'f', -1, 64)}
+		}
+		if classification.EPSSPercentile != 0 {
+			fields["epss-percentile"] = []string{strconv.FormatFloat(classification.EPSSPercentile, 'f', -1, 64)}
+		}
+		cveIDs = append(cveIDs, fields["cve-id"]...)
+	}
+	for _, cveID := range cveIDs {
+		if match := reCVEYear.FindStringSubmatch(cveID); match != nil {
+			fields["cve-year"] = sliceutil.Dedupe(append(fields["cve-year"], match[1]))
+		}
+	}
+	for key, value := range info.Metadata {
+		field := "metadata." + normalizeQueryField(key)
+		switch value := value.(type) {
+		case []interface{}:
+			for _, item := range value {
+				fields[field] = append(fields[field], fmt.Sprint(item))
+			}
+		case nil:
+		default:
+			fields[field] = []string{fmt.Sprint(value)}
+		}
+	}
+	for field, values := range fields {
+		values = sliceutil.PruneEmptyStrings(values)
+		if len(values) == 0 {
+			delete(fields, field)
+		} else {
+			fields[field] = values
+		}
+	}
+	return fields
+}
+
+// queryNode is an expression of a template query
+type queryNode interface {
+	eval(fields map[string][]string) bool
+	explain(fields map[string][]string, depth int, builder *strings.Builder) bool
+}
+
+// logicalNode combines expressions with AND or OR
+type logicalNode struct {
+	operator string
+	operands []queryNode
+}
+
+func (n *logicalNode) eval(fields map[string][]string) bool {
+	for _, operand := range n.operands {
+		matched := operand.eval(fields)
+		if n.operator == "OR" && matched {
+			return true
+		}
+		if n.operator == "AND" && !matched {
+			return false
+		}
+	}
+	return n.operator == "AND"
+}
+
+func (n *logicalNode) explain(fields map[string][]string, depth int, builder *strings.Builder) bool {
+	var operands strings.Builder
+	matched := n.operator == "AND"
+	for _, operand := range n.operands {
+		result := operand.explain(fields, depth+1, &operands)
+		if n.operator == "OR" {
+			matched = matched || result
+		} else {
+			matched = matched && result
+		}
+	}
+	fmt.Fprintf(builder, "%s%s => %t\n%s", strings.Repeat("  ", depth), n.operator, matched, operands.String())
+	return matched
+}
+
+// notNode negates an expression
+type notNode struct {
+	operand queryNode
+}
+
+func (n *notNode) eval(fields map[string][]string) bool {
+	return !n.operand.eval(fields)
+}
+
+func (n *notNode) explain(fields map[string][]string, depth int, builder *strings.Builder) bool {
+	var operand strings.Builder
+	matched := !n.operand.explain(fields, depth+1, &operand)
+	fmt.Fprintf(builder, "%sNOT => %t\n%s", strings.Repeat("  ", depth), matched, operand.String())
+	return matched
+}
+
+// comparisonNode compares the values of a field with a value
+type comparisonNode struct {
+	field    string
+	operator string
+	value    string
+	number   float64
+	glob     *regexp.Regexp
+}
+
+func (n *comparisonNode) eval(fields map[string][]string) bool {
+	switch n.operator {
+	case "!=":
+		return !n.any(fields[n.field], "=")
+	case "!~":
+		return !n.any(fields[n.field], "~")
+	}
+	return n.any(fields[n.field], n.operator)
+}
+
+// any returns true if any of the values matches the operator
+func (n *comparisonNode) any(values []string, operator string) bool {
+	for _, value := range values {
+		switch operator {
+		case "=":
+			if strings.EqualFold(value, n.value) {
+				return true
+			}
+		case "~":
+			if n.glob.MatchString(value) {
+				return true
+			}
+		default:
+			if n.compare(value, operator) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compare compares the value with the number or severity of the node
+func (n *comparisonNode) compare(value, operator string) bool {
+	left, right := 0.0, n.number
+	if n.field == "severity" {
+		level, ok := querySeverity(value)
+		if !ok {
+			return false
+		}
+		left = level
+	} else {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		left = number
+	}
+	switch operator {
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	}
+	return false
+}
+
+func (n *comparisonNode) explain(fields map[string][]string, depth int, builder *strings.Builder) bool {
+	matched := n.eval(fields)
+	values := "<missing>"
+	if len(fields[n.field]) > 0 {
+		values = strings.Join(fields[n.field], ", ")
+	}
+	fmt.Fprintf(builder, "%s%s %s %s => %t (%s: %s)\n", strings.Repeat("  ", depth), n.field, n.operator, n.value, matched, n.field, values)
+	return matched
+}
+
+// newComparisonNode returns the comparison of the field with the value
+func newComparisonNode(field, operator, value string) (*comparisonNode, error) {
+	field = normalizeQueryField(field)
+	for _, prefix := range []string{"info.", "classification."} {
+		field = strings.TrimPrefix(field, prefix)
+	}
+	if alias, ok := queryFieldAliases[field]; ok {
+		field = alias
+	}
+	if _, ok := queryFields[field]; !ok && !strings.HasPrefix(field, "metadata.") {
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+	if operator == "==" {
+		operator = "="
+	}
+	node := &comparisonNode{field: field, operator: operator, value: value}
+	switch operator {
+	case "~", "!~":
+		node.glob = regexp.MustCompile("(?is)^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(value)) + "$")
+	case ">", ">=", "<", "<=":
+		if field == "severity" {
+			level, ok := querySeverity(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid severity %q", value)
+			}
+			node.number = level
+			break
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s expects a number instead of %q", field, operator, value)
+		}
+		node.number = number
+	}
+	return node, nil
+}
+
+// queryToken is a token of a template query
+type queryToken struct {
+	kind  queryTokenKind
+	value string
+}
+
+type queryTokenKind int
+
+const (
+	tokenWord queryTokenKind = iota
+	tokenString
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+// queryOperators contains the operators of template queries by decreasing length
+var queryOperators = []string{"==", "!=", ">=", "<=", "!~", "&&", "||", "=", ">", "<", "~", "!"}
+
+// tokenizeQuery splits the template query into tokens
+func tokenizeQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenOpen, value: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenClose, value: ")"})
+			i++
+		case r == '"' || r == '\'':
+			var value strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				value.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated string %s", string(runes[i:]))
+			}
+			tokens = append(tokens, queryToken{kind: tokenString, value: value.String()})
+			i = j + 1
+		case strings.ContainsRune("=!<>~&|", r):
+			operator := ""
+			for _, candidate := range queryOperators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					operator = candidate
+					break
+				}
+			}
+			switch operator {
+			case "":
+				return nil, fmt.Errorf("unexpected %q", string(r))
+			case "&&":
+				tokens = append(tokens, queryToken{kind: tokenAnd, value: operator})
+			case "||":
+				tokens = append(tokens, queryToken{kind: tokenOr, value: operator})
+			case "!":
+				tokens = append(tokens, queryToken{kind: tokenNot, value: operator})
+			default:
+				tokens = append(tokens, queryToken{kind: tokenOperator, value: operator})
+			}
+			i += len([]rune(operator))
+		default:
+			j := i
+			for ; j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("()\"'=!<>~&|", runes[j]); j++ {
+			}
+			word := string(runes[i:j])
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, queryToken{kind: tokenAnd, value: word})
+			case "OR":
+				tokens = append(tokens, queryToken{kind: tokenOr, value: word})
+			case "NOT":
+				tokens = append(tokens, queryToken{kind: tokenNot, value: word})
+			default:
+				tokens = append(tokens, queryToken{kind: tokenWord, value: word})
+			}
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// queryParser is a recursive descent parser of template queries
+//
+//	or         = and { OR and }
+//	and        = not { [AND] not }
+//	not        = NOT not | primary
+//	primary    = "(" or ")" | field operator value | tag
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	return p.parseLogical("OR", tokenOr, p.parseAnd)
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	return p.parseLogical("AND", tokenAnd, p.parseNot)
+}
+
+// parseLogical parses operands separated by the operator. Operands of AND
+// can also be separated by whitespace only.
+func (p *queryParser) parseLogical(operator string, kind queryTokenKind, parseOperand func() (queryNode, error)) (queryNode, error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []queryNode{operand}
+	for {
+		token := p.peek()
+		if token == nil {
+			break
+		}
+		if token.kind == kind {
+			p.pos++
+		} else if kind != tokenAnd || (token.kind != tokenWord && token.kind != tokenString && token.kind != tokenNot && token.kind != tokenOpen) {
+			break
+		}
+		if operand, err = parseOperand(); err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &logicalNode{operator: operator, operands: operands}, nil
+}
+
+func (p *queryParser) parseNot() (queryNode, error) {
+	if token := p.peek(); token != nil && token.kind == tokenNot {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	token := p.peek()
+	if token == nil {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	p.pos++
+	switch token.kind {
+	case tokenOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != tokenClose {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return node, nil
+	case tokenWord, tokenString:
+		operator := p.peek()
+		if token.kind == tokenString || operator == nil || operator.kind != tokenOperator {
+			// a single value matches the tags of templates
+			return newComparisonNode("tags", "=", token.value)
+		}
+		p.pos++
+		value := p.peek()
+		if value == nil || (value.kind != tokenWord && value.kind != tokenString) {
+			return nil, fmt.Errorf("missing value after %s %s", token.value, operator.value)
+		}
+		p.pos++
+		return newComparisonNode(token.value, operator.value, value.value)
+	}
+	return nil, fmt.Errorf("unexpected %q", token.value)
+}
+
+// normalizeQueryField returns the lowercase field with dashes instead of underscores
+func normalizeQueryField(field string) string {
+	return strings.ReplaceAll(strings.ToLower(field), "_", "-")
+}
+
+// querySeverity returns the level of a supported severity
+func querySeverity(value string) (float64, bool) {
+	for _, supported := range severity.GetSupportedSeverities() {
+		if supported != severity.Unknown && strings.EqualFold(supported.String(), strings.TrimSpace(value)) {
+			return float64(supported), true
+		}
+	}
+	return 0, false
+}
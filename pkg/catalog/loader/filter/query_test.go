@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/model"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/stringslice"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateQuery(t *testing.T) {
+	template := &templates.Template{
+		ID:           "CVE-2023-1234",
+		RequestsHTTP: []*http.Request{{}},
+		Info: model.Info{
+			Name:           "Example RCE",
+			Authors:        stringslice.StringSlice{Value: []string{"pdteam"}},
+			Tags:           stringslice.StringSlice{Value: []string{"cve", "rce", "apache"}},
+			SeverityHolder: severity.Holder{Severity: severity.High},
+			Metadata:       map[string]interface{}{"max-request": 2, "vendor": "apache", "product": []interface{}{"httpd", "tomcat"}},
+			Classification: &model.Classification{
+				CVEID:          stringslice.StringSlice{Value: []string{"CVE-2023-1234"}},
+				CWEID:          stringslice.StringSlice{Value: []string{"CWE-78"}},
+				CVSSScore:      9.8,
+				EPSSScore:      0.42,
+				EPSSPercentile: 0.97,
+				CPE:            "cpe:2.3:a:apache:http_server:*:*:*:*:*:*:*:*",
+			},
+		},
+	}
+
+	tests := map[string]bool{
+		"cve-year >= 2023 AND epss-score > 0.1 AND NOT intrusive": true,
+		"cve-year >= 2023 && epss > 0.5":                          false,
+		"cve-year<2023 OR cvss-score>=9":                          true,
+		"rce apache":                                              true,
+		"!rce":                                                    false,
+		"severity >= high AND severity < critical":                true,
+		"severity = critical":                                     false,
+		"cwe-id = cwe-78 AND protocol = http":                     true,
+		`cpe ~ "cpe:2.3:a:apache:*"`:                              true,
+		"id ~ cve-2023-* AND name ~ '*rce'":                       true,
+		"metadata.max-request > 1 AND metadata.vendor = apache":   true,
+		"metadata.product = tomcat":                               true,
+		"metadata.shodan-query ~ *":                               false,
+		"metadata.shodan-query != x":                              true,
+		"(tag = xss OR tags = rce) AND author = pdteam":           true,
+		"NOT (tag = rce OR tag = xss)":                            false,
+		"info.classification.epss-percentile > 0.95":              true,
+	}
+	for raw, expected := range tests {
+		query, err := ParseQuery(raw)
+		require.Nil(t, err, "could not parse query %s", raw)
+		require.Equal(t, expected, query.Match(template), "unexpected match of query %s", raw)
+	}
+
+	for _, raw := range []string{"", "cvss >", "unknown = 1", "cvss > high", "severity > extreme", "(rce", "rce)", `name = "rce`, "rce | xss"} {
+		_, err := ParseQuery(raw)
+		require.NotNil(t, err, "invalid query %q parsed", raw)
+	}
+
+	filter, err := New(&Config{Queries: []string{"cve-year >= 2023 AND NOT intrusive", "epss > 0.5"}})
+	require.Nil(t, err)
+	matched, err := filter.Match(template, nil)
+	require.Nil(t, err)
+	require.False(t, matched)
+
+	matched, explanation := filter.ExplainQueries(template)
+	require.False(t, matched)
+	require.Contains(t, explanation, "cve-year >= 2023 => true (cve-year: 2023)")
+	require.Contains(t, explanation, "tags = intrusive => false (tags: cve, rce, apache)")
+	require.Contains(t, explanation, "epss-score > 0.5 => false (epss-score: 0.42)")
+}
@@ -3,6 +3,7 @@ package filter
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
@@ -32,6 +33,7 @@ type TagFilter struct {
 	allowedIds        map[string]struct{}
 	excludeIds        map[string]struct{}
 	includeConditions map[string]*govaluate.EvaluableExpression
+	queries           []*Query
 }
 
 // ErrExcluded is returned for excluded templates
@@ -81,6 +83,10 @@ func (tagFilter *TagFilter) Match(template *templates.Template, extraTags []stri
 		return false, nil
 	}
 
+	if !isQueryMatch(tagFilter, template) {
+		return false, nil
+	}
+
 	return true, nil
 }
 
@@ -268,6 +274,9 @@ func tryCollectConditionsMatchinfo(template *templates.Template) map[string]inte
 	for _, req := range template.RequestsWebsocket {
 		matcherTypes = append(matcherTypes, collectMatcherTypes(req.Matchers)...)
 	}
+	for _, req := range template.RequestsGRPC {
+		matcherTypes = append(matcherTypes, collectMatcherTypes(req.Matchers)...)
+	}
 	matcherTypes = sliceutil.Dedupe(sliceutil.PruneEmptyStrings(matcherTypes))
 	parameters["matcher_type"] = matcherTypes
 
@@ -297,6 +306,9 @@ func tryCollectConditionsMatchinfo(template *templates.Template) map[string]inte
 	for _, req := range template.RequestsWebsocket {
 		extractorTypes = append(extractorTypes, collectExtractorTypes(req.Extractors)...)
 	}
+	for _, req := range template.RequestsGRPC {
+		extractorTypes = append(extractorTypes, collectExtractorTypes(req.Extractors)...)
+	}
 	extractorTypes = sliceutil.Dedupe(sliceutil.PruneEmptyStrings(extractorTypes))
 	parameters["extractor_type"] = extractorTypes
 
@@ -348,6 +360,28 @@ func isConditionMatch(tagFilter *TagFilter, template *templates.Template) bool {
 	return true
 }
 
+func isQueryMatch(tagFilter *TagFilter, template *templates.Template) bool {
+	for _, query := range tagFilter.queries {
+		if !query.Match(template) {
+			return false
+		}
+	}
+	return true
+}
+
+// ExplainQueries returns whether the template matches the template queries
+// of the filter along with the evaluation of every query expression
+func (tagFilter *TagFilter) ExplainQueries(template *templates.Template) (bool, string) {
+	matched := true
+	var explanations []string
+	for _, query := range tagFilter.queries {
+		queryMatched, explanation := query.Explain(template)
+		matched = matched && queryMatched
+		explanations = append(explanations, fmt.Sprintf("query %q => %t\n%s", query, queryMatched, indent(explanation)))
+	}
+	return matched, strings.Join(explanations, "\n")
+}
+
 type Config struct {
 	Tags              []string
 	ExcludeTags       []string
@@ -360,11 +394,12 @@ type Config struct {
 	Protocols         types.ProtocolTypes
 	ExcludeProtocols  types.ProtocolTypes
 	IncludeConditions []string
+	Queries           []string
 }
 
 // New returns a tag filter for vulmap tag based execution
 //
-// It takes into account Tags, Severities, ExcludeSeverities, Authors, IncludeTags, ExcludeTags, Conditions.
+// It takes into account Tags, Severities, ExcludeSeverities, Authors, IncludeTags, ExcludeTags, Conditions, Queries.
 func New(config *Config) (*TagFilter, error) {
 	filter := &TagFilter{
 		allowedTags:       make(map[string]struct{}),
@@ -451,6 +486,13 @@ func New(config *Config) (*TagFilter, error) {
 		}
 		filter.includeConditions[includeCondition] = compiled
 	}
+	for _, rawQuery := range config.Queries {
+		query, err := ParseQuery(rawQuery)
+		if err != nil {
+			return nil, err
+		}
+		filter.queries = append(filter.queries, query)
+	}
 	return filter, nil
 }
 
@@ -471,6 +513,10 @@ func splitCommaTrim(value string) []string {
 	return final
 }
 
+func indent(value string) string {
+	return "  " + strings.ReplaceAll(value, "\n", "\n  ")
+}
+
 func toMap(slice []string) map[string]struct{} {
 	result := make(map[string]struct{}, len(slice))
 	for _, value := range slice {
@@ -59,6 +59,7 @@ type Config struct {
 	IncludeIds        []string
 	ExcludeIds        []string
 	IncludeConditions []string
+	TemplateQueries   []string
 
 	Catalog         catalog.Catalog
 	ExecutorOptions protocols.ExecutorOptions
@@ -103,6 +104,7 @@ func NewConfig(options *types.Options, catalog catalog.Catalog, executerOpts pro
 		Protocols:                options.Protocols,
 		ExcludeProtocols:         options.ExcludeProtocols,
 		IncludeConditions:        options.IncludeConditions,
+		TemplateQueries:          options.TemplateQuery,
 		Catalog:                  catalog,
 		ExecutorOptions:          executerOpts,
 	}
@@ -124,6 +126,7 @@ func New(config *Config) (*Store, error) {
 		Protocols:         config.Protocols,
 		ExcludeProtocols:  config.ExcludeProtocols,
 		IncludeConditions: config.IncludeConditions,
+		Queries:           config.TemplateQueries,
 	})
 	if err != nil {
 		return nil, err
@@ -251,6 +254,49 @@ func (store *Store) Load() {
 	store.workflows = store.LoadWorkflows(store.finalWorkflows)
 }
 
+// TemplateExplanation explains why a template was or wasn't selected by the filters of the store
+type TemplateExplanation struct {
+	Path     string
+	ID       string
+	Selected bool
+	// Explanation contains the evaluation of the template queries
+	Explanation string
+}
+
+// ExplainTemplates explains the selection of the templates of the store
+// by the template queries and remaining filters sorted by their path
+func (store *Store) ExplainTemplates() []TemplateExplanation {
+	templatePaths, errs := store.config.Catalog.GetTemplatesPath(store.finalTemplates)
+	store.logErroredTemplates(errs)
+	filteredTemplatePaths := store.pathFilter.Match(templatePaths)
+
+	explanations := make([]TemplateExplanation, 0, len(filteredTemplatePaths))
+	for templatePath := range filteredTemplatePaths {
+		template, err := parsers.ParseTemplate(templatePath, store.config.Catalog)
+		if err != nil || len(template.Workflows) > 0 {
+			continue
+		}
+		selected, err := store.tagFilter.Match(template, nil)
+		queryMatched, explanation := store.tagFilter.ExplainQueries(template)
+		switch {
+		case errors.Is(err, filter.ErrExcluded):
+			explanation += "\nexcluded by -exclude-tags"
+		case queryMatched && !selected:
+			explanation += "\nexcluded by other filters"
+		}
+		explanations = append(explanations, TemplateExplanation{
+			Path:        templatePath,
+			ID:          template.ID,
+			Selected:    selected,
+			Explanation: strings.TrimPrefix(explanation, "\n"),
+		})
+	}
+	sort.Slice(explanations, func(i, j int) bool {
+		return explanations[i].Path < explanations[j].Path
+	})
+	return explanations
+}
+
 var templateIDPathMap map[string]string
 
 func init() {
@@ -312,6 +358,7 @@ func areWorkflowOrTemplatesValid(store *Store, filteredTemplatePaths map[string]
 			if !isWorkflow && len(template.Workflows) > 0 {
 				continue
 			}
+			logTemplateSigner(template)
 		}
 		if isWorkflow {
 			if !areWorkflowTemplatesValid(store, template.Workflows) {
@@ -323,11 +370,21 @@ func areWorkflowOrTemplatesValid(store *Store, filteredTemplatePaths map[string]
 	return areTemplatesValid
 }
 
+// logTemplateSigner logs the signer that verified the template signature
+func logTemplateSigner(template *templates.Template) {
+	if template.SignedBy != "" {
+		gologger.Verbose().Msgf("[%s] Template %s is signed by %s\n", template.ID, template.Path, template.SignedBy)
+	}
+}
+
 func areWorkflowTemplatesValid(store *Store, workflows []*workflows.WorkflowTemplate) bool {
 	for _, workflow := range workflows {
 		if !areWorkflowTemplatesValid(store, workflow.Subtemplates) {
 			return false
 		}
+		if !areWorkflowTemplatesValid(store, workflow.OnFailure) {
+			return false
+		}
 		_, err := store.config.Catalog.GetTemplatePath(workflow.Template)
 		if err != nil {
 			if isParsingError("Error occurred loading template %s: %s\n", workflow.Template, err) {
@@ -410,6 +467,7 @@ func (store *Store) LoadTemplatesWithTags(templatesList, tags []string) []*templ
 						gologger.Print().Msgf("[%v] Tampered/Unsigned template at %v.\n", aurora.Yellow("WRN").String(), templatePath)
 					}
 				} else {
+					logTemplateSigner(parsed)
 					loadedTemplates = append(loadedTemplates, parsed)
 				}
 			}
@@ -463,6 +521,9 @@ func workflowContainsProtocol(workflow []*workflows.WorkflowTemplate) bool {
 				return true
 			}
 		}
+		if workflowContainsProtocol(workflow.OnFailure) {
+			return true
+		}
 		for _, executer := range workflow.Executers {
 			if executer.TemplateType == templateTypes.HTTPProtocol || executer.TemplateType == templateTypes.HeadlessProtocol {
 				return true
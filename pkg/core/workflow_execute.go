@@ -8,6 +8,7 @@ import (
 	"github.com/remeh/sizedwaitgroup"
 
 	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
 	"github.com/khulnasoft-lab/vulmap/pkg/output"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/workflows"
@@ -55,6 +56,38 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, input *co
 	var err error
 	var mainErr error
 
+	if matched, err := template.MatchCondition(input.GetAll()); !matched {
+		if err != nil {
+			gologger.Warning().Msgf("[%s] Skipping workflow step, could not evaluate condition: %s\n", template.Template, err)
+		}
+		return nil
+	}
+	if len(template.Import) > 0 {
+		imported, err := template.ImportValues(input.GetAll())
+		if err != nil {
+			return err
+		}
+		// imported values are scoped to the step and its children
+		input = input.Clone()
+		input.Merge(imported)
+	}
+	// exported values are only passed to the children of the step
+	childInput := input
+	if len(template.Export.ToSlice()) > 0 {
+		childInput = input.Clone()
+	}
+	setExtracts := func(result *operators.Result, normalize bool) {
+		if len(template.Export.ToSlice()) > 0 {
+			for k, v := range template.ExportValues(result) {
+				setWorkflowValue(childInput, k, v, true)
+			}
+			return
+		}
+		for k, v := range result.Extracts {
+			setWorkflowValue(input, k, v, normalize)
+		}
+	}
+
 	if len(template.Matchers) == 0 {
 		for _, executer := range template.Executers {
 			executer.Options.Progress.AddToTotal(int64(executer.Executer.Requests()))
@@ -68,25 +101,7 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, input *co
 					if len(result.Results) > 0 {
 						firstMatched = true
 					}
-
-					if result.OperatorsResult != nil && result.OperatorsResult.Extracts != nil {
-						for k, v := range result.OperatorsResult.Extracts {
-							// normalize items:
-							switch len(v) {
-							case 0, 1:
-								// - key:[item] => key: item
-								input.Set(k, v[0])
-							default:
-								// - key:[item_0, ..., item_n] => key0:item_0, keyn:item_n
-								for vIdx, vVal := range v {
-									normalizedKIdx := fmt.Sprintf("%s%d", k, vIdx)
-									input.Set(normalizedKIdx, vVal)
-								}
-								// also add the original name with full slice
-								input.Set(k, v)
-							}
-						}
-					}
+					setExtracts(result.OperatorsResult, true)
 				})
 			} else {
 				var matched bool
@@ -112,6 +127,7 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, input *co
 		results.CompareAndSwap(false, firstMatched)
 	}
 	if len(template.Matchers) > 0 {
+		var matcherMatched atomic.Bool
 		for _, executer := range template.Executers {
 			executer.Options.Progress.AddToTotal(int64(executer.Executer.Requests()))
 
@@ -119,29 +135,14 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, input *co
 				if event.OperatorsResult == nil {
 					return
 				}
-
-				if event.OperatorsResult.Extracts != nil {
-					for k, v := range event.OperatorsResult.Extracts {
-						input.Set(k, v)
-					}
-				}
+				setExtracts(event.OperatorsResult, false)
 
 				for _, matcher := range template.Matchers {
 					if !matcher.Match(event.OperatorsResult) {
 						continue
 					}
-
-					for _, subtemplate := range matcher.Subtemplates {
-						swg.Add()
-
-						go func(subtemplate *workflows.WorkflowTemplate) {
-							defer swg.Done()
-
-							if err := e.runWorkflowStep(subtemplate, input, results, swg, w); err != nil {
-								gologger.Warning().Msgf(workflowStepExecutionError, subtemplate.Template, err)
-							}
-						}(subtemplate)
-					}
+					matcherMatched.Store(true)
+					e.runWorkflowChildren(matcher.Subtemplates, childInput, results, swg, w)
 				}
 			})
 			if err != nil {
@@ -153,19 +154,56 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, input *co
 				continue
 			}
 		}
+		if !matcherMatched.Load() || mainErr != nil {
+			e.runWorkflowChildren(template.OnFailure, childInput, results, swg, w)
+		}
 		return mainErr
 	}
 	if len(template.Subtemplates) > 0 && firstMatched {
-		for _, subtemplate := range template.Subtemplates {
-			swg.Add()
+		e.runWorkflowChildren(template.Subtemplates, childInput, results, swg, w)
+	}
+	if !firstMatched || mainErr != nil {
+		e.runWorkflowChildren(template.OnFailure, childInput, results, swg, w)
+	}
+	return mainErr
+}
 
-			go func(template *workflows.WorkflowTemplate) {
-				if err := e.runWorkflowStep(template, input, results, swg, w); err != nil {
-					gologger.Warning().Msgf(workflowStepExecutionError, template.Template, err)
-				}
-				swg.Done()
-			}(subtemplate)
+// runWorkflowChildren runs the child steps of a workflow step concurrently
+func (e *Engine) runWorkflowChildren(templates []*workflows.WorkflowTemplate, input *contextargs.Context, results *atomic.Bool, swg *sizedwaitgroup.SizedWaitGroup, w *workflows.Workflow) {
+	for _, template := range templates {
+		swg.Add()
+
+		go func(template *workflows.WorkflowTemplate) {
+			defer swg.Done()
+
+			if err := e.runWorkflowStep(template, input, results, swg, w); err != nil {
+				gologger.Warning().Msgf(workflowStepExecutionError, template.Template, err)
+			}
+		}(template)
+	}
+}
+
+// setWorkflowValue sets an extracted value in the workflow context.
+//
+// If normalize is true, single values are set as is and multiple values
+// are additionally set with their index as suffix (key0, key1, ...).
+func setWorkflowValue(input *contextargs.Context, key string, values []string, normalize bool) {
+	if !normalize {
+		input.Set(key, values)
+		return
+	}
+	switch len(values) {
+	case 0:
+	case 1:
+		// - key:[item] => key: item
+		input.Set(key, values[0])
+	default:
+		// - key:[item_0, ..., item_n] => key0:item_0, keyn:item_n
+		for vIdx, vVal := range values {
+			normalizedKIdx := fmt.Sprintf("%s%d", key, vIdx)
+			input.Set(normalizedKIdx, vVal)
 		}
+		// also add the original name with full slice
+		input.Set(key, values)
 	}
-	return mainErr
 }
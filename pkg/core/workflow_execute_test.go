@@ -161,9 +161,75 @@ func TestWorkflowsSubtemplatesWithMatcherNoMatch(t *testing.T) {
 	require.Equal(t, "", secondInput, "could not get correct second input")
 }
 
+func TestWorkflowsExportImportCondition(t *testing.T) {
+	progressBar, _ := progress.NewStatsTicker(0, false, false, false, 0)
+
+	var imported, leaked interface{}
+	var skipped bool
+	vulnerable := &workflows.WorkflowTemplate{Condition: "compare_versions(version, '< 5.8.0')", Import: map[string]string{"api_token": "token"}, Executers: []*workflows.ProtocolExecuterPair{{
+		Executer: &mockExecuter{result: true, contextHook: func(input *contextargs.Context) {
+			imported, _ = input.Get("api_token")
+			leaked, _ = input.Get("internal")
+		}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+	}}
+	patched := &workflows.WorkflowTemplate{Condition: "compare_versions(version, '>= 5.8.0')", Executers: []*workflows.ProtocolExecuterPair{{
+		Executer: &mockExecuter{result: true, contextHook: func(input *contextargs.Context) {
+			skipped = true
+		}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+	}}
+	for _, step := range []*workflows.WorkflowTemplate{vulnerable, patched} {
+		require.Nil(t, step.Compile(), "could not compile workflow step")
+	}
+	workflow := &workflows.Workflow{Options: &protocols.ExecutorOptions{Options: &types.Options{TemplateThreads: 10}}, Workflows: []*workflows.WorkflowTemplate{
+		{Export: stringslice.StringSlice{Value: []string{"version", "token"}}, Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, outputs: []*output.InternalWrappedEvent{
+				{OperatorsResult: &operators.Result{
+					Extracts:      map[string][]string{"version": {"5.7.2"}, "internal": {"value"}},
+					DynamicValues: map[string][]string{"token": {"secret"}},
+				}, Results: []*output.ResultEvent{{}}},
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}, Subtemplates: []*workflows.WorkflowTemplate{vulnerable, patched}},
+	}}
+
+	engine := &Engine{}
+	matched := engine.executeWorkflow(&contextargs.MetaInput{Input: "https://test.com"}, workflow)
+	require.True(t, matched, "could not get correct match value")
+
+	require.Equal(t, "secret", imported, "could not get imported value")
+	require.Nil(t, leaked, "got value not exported by parent step")
+	require.False(t, skipped, "ran step with false condition")
+}
+
+func TestWorkflowsOnFailure(t *testing.T) {
+	progressBar, _ := progress.NewStatsTicker(0, false, false, false, 0)
+
+	var subtemplateRan, failureRan bool
+	workflow := &workflows.Workflow{Options: &protocols.ExecutorOptions{Options: &types.Options{TemplateThreads: 10}}, Workflows: []*workflows.WorkflowTemplate{
+		{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: false}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}, Subtemplates: []*workflows.WorkflowTemplate{{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, executeHook: func(input *contextargs.MetaInput) {
+				subtemplateRan = true
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}}}, OnFailure: []*workflows.WorkflowTemplate{{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, executeHook: func(input *contextargs.MetaInput) {
+				failureRan = true
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}}}},
+	}}
+
+	engine := &Engine{}
+	matched := engine.executeWorkflow(&contextargs.MetaInput{Input: "https://test.com"}, workflow)
+	require.True(t, matched, "could not get correct match value")
+
+	require.False(t, subtemplateRan, "ran subtemplate of failed step")
+	require.True(t, failureRan, "did not run on-failure step")
+}
+
 type mockExecuter struct {
 	result      bool
 	executeHook func(input *contextargs.MetaInput)
+	contextHook func(input *contextargs.Context)
 	outputs     []*output.InternalWrappedEvent
 }
 
@@ -182,6 +248,9 @@ func (m *mockExecuter) Execute(input *contextargs.Context) (bool, error) {
 	if m.executeHook != nil {
 		m.executeHook(input.MetaInput)
 	}
+	if m.contextHook != nil {
+		m.contextHook(input)
+	}
 	return m.result, nil
 }
 
@@ -190,6 +259,9 @@ func (m *mockExecuter) ExecuteWithResults(input *contextargs.Context, callback p
 	if m.executeHook != nil {
 		m.executeHook(input.MetaInput)
 	}
+	if m.contextHook != nil {
+		m.contextHook(input)
+	}
 	for _, output := range m.outputs {
 		callback(output)
 	}
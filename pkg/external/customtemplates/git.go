@@ -0,0 +1,234 @@
+package customtemplates
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/khulnasoft-lab/gologger"
+	fileutil "github.com/khulnasoft-lab/utils/file"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+	"github.com/pkg/errors"
+)
+
+var _ Provider = &customTemplateGitRepo{}
+
+// customTemplateGitRepo is a custom templates repository hosted on any git
+// server (e.g. Gitea, Bitbucket Server) or in a local directory
+type customTemplateGitRepo struct {
+	cloneURL string
+	// ref is the optional branch, tag or commit the repository is pinned to
+	ref string
+	// name is the path of the repository clone below the git templates directory
+	name string
+	auth transport.AuthMethod
+}
+
+// Download clones the custom git template repository
+func (ctr *customTemplateGitRepo) Download(ctx context.Context) {
+	clonePath := ctr.getLocalRepoClonePath(config.DefaultConfig.CustomGitTemplatesDirectory)
+
+	if !fileutil.FolderExists(clonePath) {
+		if err := ctr.cloneRepo(ctx, clonePath); err != nil {
+			gologger.Error().Msgf("%s", err)
+			// remove partial clones so that the next update retries cloning
+			_ = os.RemoveAll(clonePath)
+		} else {
+			gologger.Info().Msgf("Repo %s cloned successfully at %s", ctr.cloneURL, clonePath)
+		}
+	}
+}
+
+// Update pulls the changes of the custom git template repository
+func (ctr *customTemplateGitRepo) Update(ctx context.Context) {
+	clonePath := ctr.getLocalRepoClonePath(config.DefaultConfig.CustomGitTemplatesDirectory)
+
+	// If folder does not exits then clone/download the repo
+	if !fileutil.FolderExists(clonePath) {
+		ctr.Download(ctx)
+		return
+	}
+	if err := ctr.pullChanges(ctx, clonePath); err != nil {
+		gologger.Error().Msgf("%s", err)
+	} else {
+		gologger.Info().Msgf("Repo %s successfully pulled the changes.\n", ctr.cloneURL)
+	}
+}
+
+// NewGitProviders returns new instances of generic git providers for downloading custom templates
+func NewGitProviders(options *types.Options) ([]*customTemplateGitRepo, error) {
+	providers := []*customTemplateGitRepo{}
+	if options.GitTemplateDisableDownload {
+		return providers, nil
+	}
+
+	for _, repo := range options.GitTemplateRepo {
+		customTemplateRepo, err := newCustomTemplateGitRepo(repo, options)
+		if err != nil {
+			gologger.Error().Msgf("%s", err)
+			continue
+		}
+		providers = append(providers, customTemplateRepo)
+	}
+	return providers, nil
+}
+
+// newCustomTemplateGitRepo returns a git provider for the clone url
+// optionally pinned to a branch, tag or commit with a #ref suffix
+// e.g., https://gitea.example.com/org/templates.git#v1.0.0
+func newCustomTemplateGitRepo(repo string, options *types.Options) (*customTemplateGitRepo, error) {
+	cloneURL, ref, _ := strings.Cut(strings.TrimSpace(repo), "#")
+	name, err := getGitRepoLocalName(cloneURL)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := getGitAuth(cloneURL, options)
+	if err != nil {
+		return nil, errors.Errorf("%s: %s", cloneURL, err)
+	}
+	return &customTemplateGitRepo{
+		cloneURL: cloneURL,
+		ref:      ref,
+		name:     name,
+		auth:     auth,
+	}, nil
+}
+
+// getGitRepoLocalName returns the path of the repository clone
+// in the format of 'host/path' for uniqueness, local repositories
+// are cloned in the format of 'local/path'
+// e.g., it takes input git@bitbucket.example.com:org/templates.git and
+// returns bitbucket.example.com/org/templates
+func getGitRepoLocalName(cloneURL string) (string, error) {
+	var host, repoPath string
+	if !strings.Contains(cloneURL, "://") && strings.Contains(cloneURL, ":") {
+		// scp-like syntax of ssh urls i.e. user@host:path
+		userHost, p, _ := strings.Cut(cloneURL, ":")
+		if i := strings.LastIndex(userHost, "@"); i >= 0 {
+			userHost = userHost[i+1:]
+		}
+		host, repoPath = userHost, p
+	} else {
+		u, err := url.Parse(cloneURL)
+		if err != nil {
+			return "", errors.Errorf("wrong git clone url: %s", cloneURL)
+		}
+		host, repoPath = u.Hostname(), u.Path
+	}
+	if host == "" {
+		host = "local"
+	}
+	repoPath = strings.TrimSuffix(strings.Trim(path.Clean("/"+repoPath), "/"), ".git")
+	if repoPath == "" {
+		return "", errors.Errorf("wrong git clone url: %s", cloneURL)
+	}
+	return filepath.Join(host, filepath.FromSlash(repoPath)), nil
+}
+
+// getGitAuth returns the auth method for the clone url. Repositories
+// cloned over http(s) use the token as password and repositories
+// cloned over ssh use the private key or the ssh agent.
+func getGitAuth(cloneURL string, options *types.Options) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(cloneURL)
+	if err != nil {
+		return nil, err
+	}
+	switch endpoint.Protocol {
+	case "http", "https":
+		if options.GitTemplateToken == "" {
+			return nil, nil
+		}
+		username := options.GitTemplateUsername
+		if username == "" {
+			username = "git"
+		}
+		return &githttp.BasicAuth{Username: username, Password: options.GitTemplateToken}, nil
+	case "ssh":
+		if options.GitTemplateSSHKey == "" {
+			// go-git falls back to the ssh agent
+			return nil, nil
+		}
+		user := endpoint.User
+		if user == "" {
+			user = "git"
+		}
+		return gitssh.NewPublicKeysFromFile(user, options.GitTemplateSSHKey, "")
+	}
+	return nil, nil
+}
+
+// download the git repo to a given path
+func (ctr *customTemplateGitRepo) cloneRepo(ctx context.Context, clonePath string) error {
+	r, err := git.PlainCloneContext(ctx, clonePath, false, &git.CloneOptions{
+		URL:  ctr.cloneURL,
+		Auth: ctr.auth,
+		Tags: git.AllTags,
+	})
+	if err != nil {
+		return errors.Errorf("%s: %s", ctr.cloneURL, err.Error())
+	}
+	if ctr.ref != "" {
+		return ctr.checkoutRef(r)
+	}
+	return nil
+}
+
+// performs the git pull on given repo or fetches and checks out the pinned ref
+func (ctr *customTemplateGitRepo) pullChanges(ctx context.Context, repoPath string) error {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return err
+	}
+	if ctr.ref != "" {
+		err = r.FetchContext(ctx, &git.FetchOptions{RemoteName: "origin", Auth: ctr.auth, Tags: git.AllTags, Force: true})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return errors.Errorf("%s: %s", ctr.cloneURL, err.Error())
+		}
+		return ctr.checkoutRef(r)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	err = w.PullContext(ctx, &git.PullOptions{RemoteName: "origin", Auth: ctr.auth})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return errors.Errorf("%s: %s", ctr.cloneURL, err.Error())
+	}
+	return nil
+}
+
+// checkoutRef checks out the branch, tag or commit the repository is pinned to
+func (ctr *customTemplateGitRepo) checkoutRef(r *git.Repository) error {
+	var hash *plumbing.Hash
+	for _, revision := range []string{"refs/remotes/origin/" + ctr.ref, "refs/tags/" + ctr.ref, ctr.ref} {
+		if h, err := r.ResolveRevision(plumbing.Revision(revision)); err == nil {
+			hash = h
+			break
+		}
+	}
+	if hash == nil {
+		return errors.Errorf("%s: ref %s not found", ctr.cloneURL, ctr.ref)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return errors.Errorf("%s: could not checkout %s: %s", ctr.cloneURL, ctr.ref, err.Error())
+	}
+	return nil
+}
+
+// All custom git repos are cloned in the format of 'host/path' for uniqueness
+func (ctr *customTemplateGitRepo) getLocalRepoClonePath(downloadPath string) string {
+	return filepath.Join(downloadPath, ctr.name)
+}
@@ -0,0 +1,88 @@
+package customtemplates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// commitTemplate writes the template to the repository and commits it
+func commitTemplate(t *testing.T, r *git.Repository, dir, name, content string) {
+	require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	w, err := r.Worktree()
+	require.Nil(t, err)
+	_, err = w.Add(name)
+	require.Nil(t, err)
+	_, err = w.Commit("add "+name, &git.CommitOptions{Author: &object.Signature{Name: "vulmap", Email: "vulmap@example.com", When: time.Now()}})
+	require.Nil(t, err)
+}
+
+func TestDownloadCustomTemplatesFromGit(t *testing.T) {
+	gologger.DefaultLogger.SetWriter(&testutils.NoopWriter{})
+
+	repoDir := t.TempDir()
+	r, err := git.PlainInit(repoDir, false)
+	require.Nil(t, err)
+	commitTemplate(t, r, repoDir, "first.yaml", "id: first\n")
+	head, err := r.Head()
+	require.Nil(t, err)
+	_, err = r.CreateTag("v1.0.0", head.Hash(), nil)
+	require.Nil(t, err)
+
+	templatesDirectory := t.TempDir()
+	config.DefaultConfig.SetTemplatesDir(templatesDirectory)
+
+	options := testutils.DefaultOptions
+	options.GitTemplateRepo = []string{"file://" + repoDir, "file://" + repoDir + "#v1.0.0"}
+	defer func() {
+		options.GitTemplateRepo = nil
+	}()
+	providers, err := NewGitProviders(options)
+	require.Nil(t, err, "could not create git providers")
+	require.Len(t, providers, 2)
+	latest, pinned := providers[0], providers[1]
+
+	latest.Download(context.Background())
+	clonePath := latest.getLocalRepoClonePath(config.DefaultConfig.CustomGitTemplatesDirectory)
+	require.Equal(t, filepath.Join(templatesDirectory, "git", "local", filepath.FromSlash(repoDir[1:])), clonePath)
+	require.FileExists(t, filepath.Join(clonePath, "first.yaml"))
+
+	// pinned repositories stay at the ref after upstream changes
+	pinned.name = filepath.Join("local", "pinned")
+	pinned.Download(context.Background())
+	commitTemplate(t, r, repoDir, "second.yaml", "id: second\n")
+
+	latest.Update(context.Background())
+	require.FileExists(t, filepath.Join(clonePath, "second.yaml"))
+
+	pinned.Update(context.Background())
+	pinnedPath := pinned.getLocalRepoClonePath(config.DefaultConfig.CustomGitTemplatesDirectory)
+	require.FileExists(t, filepath.Join(pinnedPath, "first.yaml"))
+	require.NoFileExists(t, filepath.Join(pinnedPath, "second.yaml"))
+}
+
+func TestGetGitRepoLocalName(t *testing.T) {
+	tests := map[string]string{
+		"https://gitea.example.com/org/templates.git":             "gitea.example.com/org/templates",
+		"ssh://git@bitbucket.example.com:7999/proj/templates.git": "bitbucket.example.com/proj/templates",
+		"git@bitbucket.example.com:proj/templates.git":            "bitbucket.example.com/proj/templates",
+		"file:///srv/git/templates":                               "local/srv/git/templates",
+		"https://gitea.example.com/../../etc/templates.git":       "gitea.example.com/etc/templates",
+	}
+	for cloneURL, expected := range tests {
+		name, err := getGitRepoLocalName(cloneURL)
+		require.Nil(t, err, "could not get local name of %s", cloneURL)
+		require.Equal(t, filepath.FromSlash(expected), name)
+	}
+	_, err := getGitRepoLocalName("https://gitea.example.com/")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,221 @@
+package customtemplates
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/retryablehttp-go"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+	"github.com/pkg/errors"
+)
+
+var _ Provider = &customTemplateHTTPTarball{}
+
+// tarballExtensions are removed from the tarball url to get the directory name
+var tarballExtensions = []string{".tar.gz", ".tgz", ".tar"}
+
+// customTemplateHTTPTarball is a custom templates tarball hosted on a http(s) server
+type customTemplateHTTPTarball struct {
+	url string
+	// checksum is the optional sha256 checksum the tarball is pinned to
+	checksum string
+	// name is the path of the extracted tarball below the http templates directory
+	name       string
+	token      string
+	httpClient *retryablehttp.Client
+}
+
+// Download downloads and extracts the custom templates tarball
+func (ct *customTemplateHTTPTarball) Download(ctx context.Context) {
+	downloadPath := filepath.Join(config.DefaultConfig.CustomHTTPTemplatesDirectory, ct.name)
+	if err := ct.downloadTarball(ctx, downloadPath); err != nil {
+		gologger.Error().Msgf("error downloading templates tarball %s: %s", ct.url, err)
+		return
+	}
+	gologger.Info().Msgf("Templates tarball %s was downloaded successfully at %s", ct.url, downloadPath)
+}
+
+// Update downloads the custom templates tarball again
+func (ct *customTemplateHTTPTarball) Update(ctx context.Context) {
+	ct.Download(ctx)
+}
+
+// NewHTTPTarballProviders returns new instances of http providers for downloading custom templates tarballs
+func NewHTTPTarballProviders(options *types.Options) ([]*customTemplateHTTPTarball, error) {
+	providers := []*customTemplateHTTPTarball{}
+	if options.HTTPTemplateDisableDownload {
+		return providers, nil
+	}
+
+	httpClient := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	for _, tarballURL := range options.HTTPTemplateURL {
+		customTemplateTarball, err := newCustomTemplateHTTPTarball(tarballURL, options.HTTPTemplateToken, httpClient)
+		if err != nil {
+			gologger.Error().Msgf("%s", err)
+			continue
+		}
+		providers = append(providers, customTemplateTarball)
+	}
+	return providers, nil
+}
+
+// newCustomTemplateHTTPTarball returns a http provider for the tarball url
+// optionally pinned to a checksum with a #sha256=<checksum> suffix
+// e.g., https://artifacts.example.com/templates.tar.gz#sha256=<checksum>
+func newCustomTemplateHTTPTarball(tarballURL, token string, httpClient *retryablehttp.Client) (*customTemplateHTTPTarball, error) {
+	u, err := url.Parse(strings.TrimSpace(tarballURL))
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf("wrong templates tarball url: %s", tarballURL)
+	}
+	var checksum string
+	if u.Fragment != "" {
+		value, ok := strings.CutPrefix(u.Fragment, "sha256=")
+		if _, err := hex.DecodeString(value); !ok || err != nil || len(value) != sha256.Size*2 {
+			return nil, errors.Errorf("%s: checksum must be in the format #sha256=<checksum>", tarballURL)
+		}
+		checksum = strings.ToLower(value)
+		u.Fragment = ""
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		// tarballs downloaded over plain http can only be trusted with a checksum
+		if checksum == "" {
+			return nil, errors.Errorf("%s: http urls must be pinned to a checksum with #sha256=<checksum>", tarballURL)
+		}
+	default:
+		return nil, errors.Errorf("%s: unsupported scheme %s", tarballURL, u.Scheme)
+	}
+
+	name := strings.Trim(path.Clean("/"+u.Path), "/")
+	for _, ext := range tarballExtensions {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return &customTemplateHTTPTarball{
+		url:        u.String(),
+		checksum:   checksum,
+		name:       filepath.Join(u.Hostname(), filepath.FromSlash(name)),
+		token:      token,
+		httpClient: httpClient,
+	}, nil
+}
+
+// downloadTarball downloads the tarball to a temporary file verifying the
+// checksum and replaces the download path with the extracted tarball
+func (ct *customTemplateHTTPTarball) downloadTarball(ctx context.Context, downloadPath string) error {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, ct.url, nil)
+	if err != nil {
+		return err
+	}
+	if ct.token != "" {
+		req.Header.Set("Authorization", "Bearer "+ct.token)
+	}
+	resp, err := ct.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	tarball, err := os.CreateTemp("", "vulmap-templates-tarball-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tarball.Name())
+	defer tarball.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tarball, hasher), resp.Body); err != nil {
+		return err
+	}
+	if checksum := hex.EncodeToString(hasher.Sum(nil)); ct.checksum != "" && checksum != ct.checksum {
+		return fmt.Errorf("checksum mismatch: expected %s got %s", ct.checksum, checksum)
+	}
+	if _, err := tarball.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// extract next to the download path and swap directories once extracted
+	if err := os.MkdirAll(filepath.Dir(downloadPath), 0755); err != nil {
+		return err
+	}
+	stagingDir, err := os.MkdirTemp(filepath.Dir(downloadPath), "."+filepath.Base(downloadPath)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+	if err := extractTarball(tarball, stagingDir); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(downloadPath); err != nil {
+		return err
+	}
+	return os.Rename(stagingDir, downloadPath)
+}
+
+// extractTarball extracts the regular files and directories of a
+// optionally gzip compressed tarball to the directory
+func extractTarball(reader io.Reader, dir string) error {
+	buffered := bufio.NewReader(reader)
+	if magic, _ := buffered.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	} else {
+		reader = buffered
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("illegal file path in tarball: %s", header.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tr)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
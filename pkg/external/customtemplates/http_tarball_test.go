@@ -0,0 +1,91 @@
+package customtemplates
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTarball returns a gzip compressed tarball of the files
+func newTestTarball(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.Nil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.Nil(t, err)
+	}
+	require.Nil(t, tw.Close())
+	require.Nil(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestDownloadCustomTemplatesFromHTTPTarball(t *testing.T) {
+	gologger.DefaultLogger.SetWriter(&testutils.NoopWriter{})
+
+	tarball := newTestTarball(t, map[string]string{"http/test.yaml": "id: test\n"})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(tarball)
+	}))
+	defer ts.Close()
+
+	templatesDirectory := t.TempDir()
+	config.DefaultConfig.SetTemplatesDir(templatesDirectory)
+	checksum := sha256.Sum256(tarball)
+
+	options := testutils.DefaultOptions
+	options.HTTPTemplateToken = "token"
+	options.HTTPTemplateURL = []string{
+		ts.URL + "/templates.tar.gz",
+		ts.URL + "/release/templates.tgz#sha256=" + hex.EncodeToString(checksum[:]),
+		ts.URL + "/tampered.tar.gz#sha256=" + hex.EncodeToString(make([]byte, sha256.Size)),
+	}
+	defer func() {
+		options.HTTPTemplateToken = ""
+		options.HTTPTemplateURL = nil
+	}()
+	providers, err := NewHTTPTarballProviders(options)
+	require.Nil(t, err, "could not create http providers")
+	// plain http urls without checksum are rejected
+	require.Len(t, providers, 2)
+
+	for _, provider := range providers {
+		provider.Download(context.Background())
+	}
+	downloadDirectory := filepath.Join(templatesDirectory, "https", "127.0.0.1")
+	require.FileExists(t, filepath.Join(downloadDirectory, "release", "templates", "http", "test.yaml"))
+	require.NoDirExists(t, filepath.Join(downloadDirectory, "tampered"), "tarball with wrong checksum extracted")
+
+	// updates replace the previously extracted tarball
+	stale := filepath.Join(downloadDirectory, "release", "templates", "stale.yaml")
+	require.Nil(t, os.WriteFile(stale, []byte("id: stale\n"), 0644))
+	providers[0].Update(context.Background())
+	require.NoFileExists(t, stale)
+	require.FileExists(t, filepath.Join(downloadDirectory, "release", "templates", "http", "test.yaml"))
+}
+
+func TestExtractTarballPathTraversal(t *testing.T) {
+	tarball := newTestTarball(t, map[string]string{"../evil.yaml": "id: evil\n"})
+	dir := t.TempDir()
+	err := extractTarball(bytes.NewReader(tarball), filepath.Join(dir, "templates"))
+	require.NotNil(t, err)
+	require.NoFileExists(t, filepath.Join(dir, "evil.yaml"))
+}
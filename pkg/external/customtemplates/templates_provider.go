@@ -76,5 +76,23 @@ func NewCustomTemplatesManager(options *types.Options) (*CustomTemplatesManager,
 		ctm.providers = append(ctm.providers, v)
 	}
 
+	// Add generic git providers
+	gitProviders, err := NewGitProviders(options)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create git providers for custom templates")
+	}
+	for _, v := range gitProviders {
+		ctm.providers = append(ctm.providers, v)
+	}
+
+	// Add HTTP(S) tarball providers
+	httpProviders, err := NewHTTPTarballProviders(options)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create http providers for custom templates")
+	}
+	for _, v := range httpProviders {
+		ctm.providers = append(ctm.providers, v)
+	}
+
 	return ctm, nil
 }
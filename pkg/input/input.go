@@ -43,7 +43,7 @@ func (h *Helper) Transform(input string, protocol templateTypes.ProtocolType) st
 		return h.convertInputToType(input, typeFilepath, "")
 	case templateTypes.HTTPProtocol, templateTypes.HeadlessProtocol:
 		return h.convertInputToType(input, typeURL, "")
-	case templateTypes.NetworkProtocol:
+	case templateTypes.NetworkProtocol, templateTypes.GRPCProtocol:
 		return h.convertInputToType(input, typeHostWithOptionalPort, "")
 	case templateTypes.WebsocketProtocol:
 		return h.convertInputToType(input, typeWebsocket, "")
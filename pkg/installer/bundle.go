@@ -0,0 +1,413 @@
+package installer
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/khulnasoft-lab/gologger"
+	errorutil "github.com/khulnasoft-lab/utils/errors"
+	fileutil "github.com/khulnasoft-lab/utils/file"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates/signer"
+)
+
+const (
+	bundleManifestFilename  = "manifest.json"
+	bundleSignatureFilename = "manifest.sig"
+	// files of the templates directory are stored below templates/
+	// and files of the config directory below config/
+	bundleTemplatesDir = "templates/"
+	bundleConfigDir    = "config/"
+)
+
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// BundleManifest describes the content of an offline templates bundle
+type BundleManifest struct {
+	VulmapVersion    string    `json:"vulmap-version"`
+	TemplatesVersion string    `json:"templates-version"`
+	IgnoreHash       string    `json:"ignore-hash,omitempty"`
+	CreatedAt        time.Time `json:"created-at"`
+	// Files contains the sha256 checksum of every file in the bundle
+	Files map[string]string `json:"files"`
+}
+
+// ExportBundle packs the templates directory including custom templates,
+// checksums, the wappalyzer mapping and the ignore file into an archive
+// signed by the template signer. The archive is compressed with zstd or
+// gzip based on the file extension (.zst, .gz).
+func ExportBundle(bundlePath string, templateSigner *signer.TemplateSigner) (*BundleManifest, error) {
+	files, err := bundleSourceFiles()
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BundleManifest{
+		VulmapVersion:    config.Version,
+		TemplatesVersion: config.DefaultConfig.TemplateVersion,
+		IgnoreHash:       config.DefaultConfig.VulmapIgnoreHash,
+		CreatedAt:        time.Now().UTC(),
+		Files:            make(map[string]string, len(files)),
+	}
+	for name, source := range files {
+		checksum, err := fileChecksum(source)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files[name] = checksum
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	signature, err := templateSigner.SignData(manifestData)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("failed to sign bundle manifest")
+	}
+
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var compressor io.WriteCloser
+	switch {
+	case strings.HasSuffix(bundlePath, ".zst"):
+		if compressor, err = zstd.NewWriter(file); err != nil {
+			return nil, err
+		}
+	case strings.HasSuffix(bundlePath, ".gz"), strings.HasSuffix(bundlePath, ".tgz"):
+		compressor = gzip.NewWriter(file)
+	default:
+		compressor = nopWriteCloser{file}
+	}
+	tw := tar.NewWriter(compressor)
+
+	// manifest and signature come first so they can be verified before extraction
+	if err := writeTarFile(tw, bundleManifestFilename, manifestData, 0600); err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, bundleSignatureFilename, []byte(signature), 0600); err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(manifest.Files) {
+		bin, err := os.ReadFile(files[name])
+		if err != nil {
+			return nil, err
+		}
+		if checksum := sha256.Sum256(bin); hex.EncodeToString(checksum[:]) != manifest.Files[name] {
+			return nil, fmt.Errorf("file %s changed while exporting bundle", files[name])
+		}
+		info, err := os.Stat(files[name])
+		if err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tw, name, bin, info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := compressor.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, file.Close()
+}
+
+// ImportBundle verifies the signature of an offline templates bundle with
+// the default template verifiers and installs it the same way templates are
+// updated, printing the summary of changes.
+func (t *TemplateManager) ImportBundle(bundlePath string) error {
+	stagingDir, err := os.MkdirTemp("", "vulmap-bundle-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	manifest, signedBy, err := extractBundle(bundlePath, stagingDir)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("failed to verify bundle %s", bundlePath)
+	}
+	gologger.Info().Msgf("Bundle %s (vulmap-templates %s) is signed by %s", bundlePath, manifest.TemplatesVersion, signedBy)
+
+	dir := config.DefaultConfig.TemplatesDirectory
+	if !fileutil.FolderExists(dir) {
+		if err := fileutil.CreateFolder(dir); err != nil {
+			return errorutil.NewWithErr(err).Msgf("failed to create directory at %s", dir)
+		}
+	}
+	// firstly, read checksums from .checksum file these are used to generate stats
+	oldchecksums, err := t.getChecksumFromDir(dir)
+	if err != nil {
+		oldchecksums = make(map[string]string)
+	}
+	localTemplatesIndex, err := config.GetVulmapTemplatesIndex()
+	if err != nil || localTemplatesIndex == nil {
+		localTemplatesIndex = map[string]string{}
+	}
+
+	for _, name := range sortedKeys(manifest.Files) {
+		writePath := bundleInstallPath(dir, name)
+		if writePath == "" {
+			continue
+		}
+		staged := filepath.Join(stagingDir, filepath.FromSlash(name))
+		bin, err := os.ReadFile(staged)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(staged)
+		if err != nil {
+			return err
+		}
+		if err := fileutil.CreateFolder(filepath.Dir(writePath)); err != nil {
+			return errorutil.NewWithErr(err).Msgf("failed to create directory for %s", writePath)
+		}
+		if err := writeTemplateFile(localTemplatesIndex, name, writePath, bin, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if err := updateTemplatesMetadata(dir, manifest.TemplatesVersion); err != nil {
+		return err
+	}
+	// the bundle is the latest version available to air-gapped scanners
+	if err := config.DefaultConfig.WriteVersionCheckData(config.DefaultConfig.VulmapIgnoreHash, "", manifest.TemplatesVersion); err != nil {
+		return err
+	}
+	if err := t.writeChecksumFileInDir(dir); err != nil {
+		return err
+	}
+
+	newchecksums, err := t.getChecksumFromDir(dir)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("failed to get checksums from %s after import", dir)
+	}
+	logUpdateResults(manifest.TemplatesVersion, dir, t.summarizeChanges(oldchecksums, newchecksums))
+	return nil
+}
+
+// extractBundle verifies the bundle manifest signature and extracts the
+// files of the bundle to the staging directory verifying their checksums.
+// It returns the manifest and the identifier of the signer.
+func extractBundle(bundlePath, stagingDir string) (*BundleManifest, string, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	reader, err := decompressBundle(file)
+	if err != nil {
+		return nil, "", err
+	}
+	defer reader.Close()
+	tr := tar.NewReader(reader)
+
+	manifestData, err := readTarFile(tr, bundleManifestFilename)
+	if err != nil {
+		return nil, "", err
+	}
+	signature, err := readTarFile(tr, bundleSignatureFilename)
+	if err != nil {
+		return nil, "", err
+	}
+	signedBy := ""
+	for _, verifier := range signer.DefaultTemplateVerifiers {
+		if verified, _ := verifier.VerifyData(manifestData, string(signature)); verified {
+			signedBy = verifier.Identifier()
+			break
+		}
+	}
+	if signedBy == "" {
+		return nil, "", errorutil.New("bundle is not signed by a trusted signer")
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, "", errorutil.NewWithErr(err).Msgf("invalid bundle manifest")
+	}
+	if _, ok := manifest.Files[bundleConfigDir+config.VulmapIgnoreFileName]; !ok {
+		return nil, "", errorutil.New("bundle does not contain the vulmap ignore file")
+	}
+
+	extracted := make(map[string]struct{}, len(manifest.Files))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		checksum, ok := manifest.Files[name]
+		if !ok || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return nil, "", fmt.Errorf("unexpected file %s in bundle", header.Name)
+		}
+		bin, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, "", err
+		}
+		if actual := sha256.Sum256(bin); hex.EncodeToString(actual[:]) != checksum {
+			return nil, "", fmt.Errorf("checksum mismatch of %s in bundle", name)
+		}
+		staged := filepath.Join(stagingDir, filepath.FromSlash(name))
+		if err := fileutil.CreateFolder(filepath.Dir(staged)); err != nil {
+			return nil, "", err
+		}
+		if err := os.WriteFile(staged, bin, header.FileInfo().Mode().Perm()); err != nil {
+			return nil, "", err
+		}
+		extracted[name] = struct{}{}
+	}
+	for name := range manifest.Files {
+		if _, ok := extracted[name]; !ok {
+			return nil, "", fmt.Errorf("file %s missing in bundle", name)
+		}
+	}
+	return &manifest, signedBy, nil
+}
+
+// bundleSourceFiles returns the bundle names and paths of all files
+// to export in a bundle
+func bundleSourceFiles() (map[string]string, error) {
+	files := map[string]string{}
+	dir := config.DefaultConfig.TemplatesDirectory
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		files[bundleTemplatesDir+filepath.ToSlash(relPath)] = filePath
+		return nil
+	})
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("failed to read templates directory %s", dir)
+	}
+	ignoreFile := config.DefaultConfig.GetIgnoreFilePath()
+	if !fileutil.FileExists(ignoreFile) {
+		return nil, errorutil.NewWithTag("bundle", "vulmap ignore file not found at %s: install vulmap-templates before exporting a bundle", ignoreFile)
+	}
+	files[bundleConfigDir+config.VulmapIgnoreFileName] = ignoreFile
+	return files, nil
+}
+
+// bundleInstallPath returns the path a bundle file is installed at or an
+// empty string if the file is not installed
+func bundleInstallPath(templatesDir, name string) string {
+	switch {
+	case name == bundleConfigDir+config.VulmapIgnoreFileName:
+		return config.DefaultConfig.GetIgnoreFilePath()
+	case strings.HasPrefix(name, bundleTemplatesDir):
+		relPath := strings.TrimPrefix(name, bundleTemplatesDir)
+		// checksums and index contain absolute paths and are regenerated
+		if relPath == config.VulmapTemplatesCheckSumFileName || relPath == config.VulmapTemplatesIndexFileName {
+			return ""
+		}
+		newPath := filepath.Clean(filepath.Join(templatesDir, filepath.FromSlash(relPath)))
+		if !strings.HasPrefix(newPath, filepath.Clean(templatesDir)+string(os.PathSeparator)) {
+			// we don't allow LFI
+			return ""
+		}
+		return newPath
+	default:
+		return ""
+	}
+}
+
+// decompressBundle returns the decompressed reader of a bundle
+// detecting the compression from its magic bytes
+func decompressBundle(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.Equal(magic, zstdMagic):
+		decoder, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	case len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		return gzip.NewReader(br)
+	default:
+		return io.NopCloser(br), nil
+	}
+}
+
+func readTarFile(tr *tar.Reader, name string) ([]byte, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("failed to read %s from bundle", name)
+	}
+	if header.Name != name {
+		return nil, fmt.Errorf("expected %s in bundle, found %s", name, header.Name)
+	}
+	return io.ReadAll(tr)
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, mode fs.FileMode) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    int64(mode),
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func fileChecksum(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
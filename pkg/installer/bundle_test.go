@@ -0,0 +1,92 @@
+package installer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates/signer"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// newTestBundleSigner returns an ssh signer and its verifier
+func newTestBundleSigner(t *testing.T) (*signer.TemplateSigner, *signer.TemplateSigner) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.Nil(t, err)
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	require.Nil(t, err)
+
+	bundleSigner, err := signer.NewTemplateSignerWithAlgorithm(signer.SSH, nil, pem.EncodeToMemory(block))
+	require.Nil(t, err)
+	verifier, err := signer.NewTemplateSigVerifierWithAlgorithm(signer.SSH, ssh.MarshalAuthorizedKey(sshPublicKey))
+	require.Nil(t, err)
+	return bundleSigner, verifier
+}
+
+// setTestTemplatesDirs sets the config and templates directories to temporary directories
+func setTestTemplatesDirs(t *testing.T) string {
+	cfgdir := t.TempDir()
+	config.DefaultConfig.SetConfigDir(cfgdir)
+	templatesDir := filepath.Join(t.TempDir(), "templates")
+	config.DefaultConfig.SetTemplatesDir(templatesDir)
+	return templatesDir
+}
+
+func TestTemplatesBundle(t *testing.T) {
+	HideProgressBar = true
+	defaultVerifiers := signer.DefaultTemplateVerifiers
+	t.Cleanup(func() {
+		signer.DefaultTemplateVerifiers = defaultVerifiers
+	})
+	bundleSigner, verifier := newTestBundleSigner(t)
+
+	// templates of the exporting host
+	sourceDir := setTestTemplatesDirs(t)
+	sourceFiles := map[string]string{
+		"http/cves/test.yaml":                  "id: test\n",
+		"github/org-repo/custom.yaml":          "id: custom\n",
+		config.VulmapTemplatesCheckSumFileName: "",
+	}
+	for name, content := range sourceFiles {
+		path := filepath.Join(sourceDir, filepath.FromSlash(name))
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.Nil(t, os.WriteFile(config.DefaultConfig.GetIgnoreFilePath(), []byte("tags:\n  - fuzz\n"), 0644))
+	require.Nil(t, config.DefaultConfig.SetTemplatesVersion("v9.9.9"))
+
+	bundlePath := filepath.Join(t.TempDir(), "templates.tar.zst")
+	manifest, err := ExportBundle(bundlePath, bundleSigner)
+	require.Nil(t, err, "could not export bundle")
+	require.Equal(t, "v9.9.9", manifest.TemplatesVersion)
+	require.Contains(t, manifest.Files, "templates/http/cves/test.yaml")
+	require.Contains(t, manifest.Files, "config/"+config.VulmapIgnoreFileName)
+
+	// air-gapped host
+	targetDir := setTestTemplatesDirs(t)
+	tm := &TemplateManager{}
+
+	signer.DefaultTemplateVerifiers = nil
+	err = tm.ImportBundle(bundlePath)
+	require.ErrorContains(t, err, "not signed by a trusted signer")
+	require.NoFileExists(t, filepath.Join(targetDir, "http", "cves", "test.yaml"))
+
+	signer.DefaultTemplateVerifiers = []*signer.TemplateSigner{verifier}
+	require.Nil(t, tm.ImportBundle(bundlePath), "could not import bundle")
+	for _, name := range []string{"http/cves/test.yaml", "github/org-repo/custom.yaml"} {
+		bin, err := os.ReadFile(filepath.Join(targetDir, filepath.FromSlash(name)))
+		require.Nil(t, err)
+		require.Equal(t, sourceFiles[name], string(bin))
+	}
+	require.FileExists(t, config.DefaultConfig.GetIgnoreFilePath())
+	require.FileExists(t, config.DefaultConfig.GetChecksumFilePath())
+	require.Equal(t, "v9.9.9", config.DefaultConfig.TemplateVersion)
+	require.Equal(t, "v9.9.9", config.DefaultConfig.LatestVulmapTemplatesVersion)
+}
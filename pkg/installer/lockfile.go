@@ -0,0 +1,328 @@
+package installer
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v30/github"
+	"gopkg.in/yaml.v2"
+
+	"github.com/khulnasoft-lab/gologger"
+	errorutil "github.com/khulnasoft-lab/utils/errors"
+	fileutil "github.com/khulnasoft-lab/utils/file"
+	updateutils "github.com/khulnasoft-lab/utils/update"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+)
+
+// DefaultLockfileName is the default filename of the templates lockfile
+const DefaultLockfileName = "vulmap.lock"
+
+// Lockfile pins the installed templates for reproducible scans
+type Lockfile struct {
+	// TemplatesVersion is the release version of vulmap-templates
+	TemplatesVersion string `yaml:"templates-version"`
+	// Templates contains the md5 checksum of every file of vulmap-templates
+	// by its path relative to the templates directory
+	Templates map[string]string `yaml:"templates"`
+	// CustomTemplates contains the sources of custom templates
+	CustomTemplates []LockedTemplatesSource `yaml:"custom-templates,omitempty"`
+}
+
+// LockedTemplatesSource is a custom templates source pinned by the lockfile.
+// Git repositories are pinned to their commit, other sources to the checksum
+// of their files.
+type LockedTemplatesSource struct {
+	// Path is the path of the source relative to the templates directory
+	Path string `yaml:"path"`
+	// URL is the remote url of git repositories
+	URL string `yaml:"url,omitempty"`
+	// Commit is the checked out commit of git repositories
+	Commit string `yaml:"commit,omitempty"`
+	// Checksum is the sha256 checksum of the files of other sources
+	Checksum string `yaml:"checksum,omitempty"`
+}
+
+// ReadLockfile reads the templates lockfile at given path
+func ReadLockfile(lockfilePath string) (*Lockfile, error) {
+	bin, err := os.ReadFile(lockfilePath)
+	if err != nil {
+		return nil, err
+	}
+	lock := &Lockfile{}
+	if err := yaml.Unmarshal(bin, lock); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse lockfile %s", lockfilePath)
+	}
+	return lock, nil
+}
+
+// Write writes the lockfile to given path
+func (l *Lockfile) Write(lockfilePath string) error {
+	bin, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockfilePath, bin, 0644)
+}
+
+// Diff returns the differences of the installed templates to the lockfile
+func (l *Lockfile) Diff(installed *Lockfile) []string {
+	var diffs []string
+	if l.TemplatesVersion != installed.TemplatesVersion {
+		diffs = append(diffs, fmt.Sprintf("vulmap-templates %s are installed instead of %s", installed.TemplatesVersion, l.TemplatesVersion))
+	}
+	for _, path := range sortedKeys(l.Templates) {
+		checksum, ok := installed.Templates[path]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("missing template %s", path))
+		} else if checksum != l.Templates[path] {
+			diffs = append(diffs, fmt.Sprintf("modified template %s", path))
+		}
+	}
+	for _, path := range sortedKeys(installed.Templates) {
+		if _, ok := l.Templates[path]; !ok {
+			diffs = append(diffs, fmt.Sprintf("unlocked template %s", path))
+		}
+	}
+
+	for _, source := range l.CustomTemplates {
+		current := installed.customTemplatesSource(source.Path)
+		switch {
+		case current == nil:
+			diffs = append(diffs, fmt.Sprintf("missing custom templates %s", source.Path))
+		case current.Commit != source.Commit:
+			diffs = append(diffs, fmt.Sprintf("custom templates %s are at commit %s instead of %s", source.Path, current.Commit, source.Commit))
+		case current.Checksum != source.Checksum:
+			diffs = append(diffs, fmt.Sprintf("modified custom templates %s", source.Path))
+		}
+	}
+	for _, source := range installed.CustomTemplates {
+		if l.customTemplatesSource(source.Path) == nil {
+			diffs = append(diffs, fmt.Sprintf("unlocked custom templates %s", source.Path))
+		}
+	}
+	return diffs
+}
+
+// customTemplatesSource returns the custom templates source at the path
+func (l *Lockfile) customTemplatesSource(path string) *LockedTemplatesSource {
+	for i := range l.CustomTemplates {
+		if l.CustomTemplates[i].Path == path {
+			return &l.CustomTemplates[i]
+		}
+	}
+	return nil
+}
+
+// GenerateLockfile returns the lockfile of the installed templates
+func (t *TemplateManager) GenerateLockfile() (*Lockfile, error) {
+	dir := config.DefaultConfig.TemplatesDirectory
+	checksums, err := t.calculateChecksumMap(dir)
+	if err != nil {
+		return nil, err
+	}
+	lock := &Lockfile{
+		TemplatesVersion: config.DefaultConfig.TemplateVersion,
+		Templates:        make(map[string]string, len(checksums)),
+	}
+	for path, checksum := range checksums {
+		if relPath := lockfileTemplatePath(dir, path); relPath != "" {
+			lock.Templates[relPath] = checksum
+		}
+	}
+	if lock.CustomTemplates, err = customTemplatesSources(dir); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// SyncLockfile installs the vulmap-templates release of the lockfile removing
+// templates not in the lockfile and checks out the locked commits of custom
+// templates. Missing commits are fetched by updating the custom templates.
+func (t *TemplateManager) SyncLockfile(lock *Lockfile) error {
+	dir := config.DefaultConfig.TemplatesDirectory
+	installed, err := t.GenerateLockfile()
+	if err != nil {
+		return err
+	}
+
+	templatesLock := &Lockfile{TemplatesVersion: lock.TemplatesVersion, Templates: lock.Templates}
+	if !t.DisablePublicTemplates && len(templatesLock.Diff(&Lockfile{TemplatesVersion: installed.TemplatesVersion, Templates: installed.Templates})) > 0 {
+		if err := t.installTemplatesVersion(dir, lock.TemplatesVersion); err != nil {
+			return err
+		}
+		checksums, err := t.calculateChecksumMap(dir)
+		if err != nil {
+			return err
+		}
+		for path := range checksums {
+			if relPath := lockfileTemplatePath(dir, path); relPath != "" && lock.Templates[relPath] == "" {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+			}
+		}
+		PurgeEmptyDirectories(dir)
+		if err := t.writeChecksumFileInDir(dir); err != nil {
+			return err
+		}
+	}
+
+	checkout := func() []LockedTemplatesSource {
+		var failed []LockedTemplatesSource
+		for _, source := range lock.CustomTemplates {
+			if current := installed.customTemplatesSource(source.Path); current != nil && *current == source {
+				continue
+			}
+			if source.Commit == "" {
+				failed = append(failed, source)
+				continue
+			}
+			if err := checkoutCommit(filepath.Join(dir, filepath.FromSlash(source.Path)), source.Commit); err != nil {
+				gologger.Verbose().Msgf("could not checkout %s of %s: %s", source.Commit, source.Path, err)
+				failed = append(failed, source)
+			}
+		}
+		return failed
+	}
+	if failed := checkout(); len(failed) > 0 && t.CustomTemplates != nil {
+		t.CustomTemplates.Update(context.TODO())
+		if installed, err = t.GenerateLockfile(); err != nil {
+			return err
+		}
+		checkout()
+	}
+	return nil
+}
+
+// installTemplatesVersion installs the release version of vulmap-templates at given directory
+func (t *TemplateManager) installTemplatesVersion(dir, version string) error {
+	if !fileutil.FolderExists(dir) {
+		if err := fileutil.CreateFolder(dir); err != nil {
+			return errorutil.NewWithErr(err).Msgf("failed to create directory at %s", dir)
+		}
+	}
+	ghrd, err := updateutils.NewghReleaseDownloader(config.OfficialVulmapTemplatesRepoName)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("failed to install templates at %s", dir)
+	}
+	if ghrd.Latest.GetTagName() != version {
+		// source of older releases is downloaded from the zipball of the tag
+		zipballURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/zipball/%s", updateutils.Organization, config.OfficialVulmapTemplatesRepoName, url.PathEscape(version))
+		ghrd.Latest = &github.RepositoryRelease{TagName: github.String(version), ZipballURL: github.String(zipballURL)}
+	}
+	gologger.Info().Msgf("Installing vulmap-templates %s of lockfile", version)
+	return t.writeTemplatesToDisk(ghrd, dir)
+}
+
+// lockfileTemplatePath returns the slash separated path of a template relative
+// to the templates directory, metadata files of the directory are skipped
+func lockfileTemplatePath(dir, path string) string {
+	relPath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return ""
+	}
+	switch relPath {
+	case config.VulmapTemplatesCheckSumFileName, config.VulmapTemplatesIndexFileName:
+		return ""
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// customTemplatesSources returns the sources of custom templates in the
+// templates directory. Every git repository is a source and the remaining
+// files of each custom templates directory (e.g. s3) form another source.
+func customTemplatesSources(dir string) ([]LockedTemplatesSource, error) {
+	var sources []LockedTemplatesSource
+	for _, customDir := range config.DefaultConfig.GetAllCustomTemplateDirs() {
+		if !fileutil.FolderExists(customDir) {
+			continue
+		}
+		checksums := map[string]string{}
+		err := filepath.WalkDir(customDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				bin, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				checksums[filepath.ToSlash(path)] = fmt.Sprintf("%x", md5.Sum(bin))
+				return nil
+			}
+			if !fileutil.FolderExists(filepath.Join(path, git.GitDirName)) {
+				return nil
+			}
+			source, err := gitTemplatesSource(dir, path)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, *source)
+			return filepath.SkipDir
+		})
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("failed to read custom templates at %s", customDir)
+		}
+		if len(checksums) > 0 {
+			relPath, _ := filepath.Rel(dir, customDir)
+			hasher := sha256.New()
+			for _, path := range sortedKeys(checksums) {
+				relFile, _ := filepath.Rel(customDir, filepath.FromSlash(path))
+				fmt.Fprintf(hasher, "%s,%s\n", filepath.ToSlash(relFile), checksums[path])
+			}
+			sources = append(sources, LockedTemplatesSource{Path: filepath.ToSlash(relPath), Checksum: fmt.Sprintf("%x", hasher.Sum(nil))})
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Path < sources[j].Path
+	})
+	return sources, nil
+}
+
+// gitTemplatesSource returns the source of the custom templates git repository
+func gitTemplatesSource(dir, repoPath string) (*LockedTemplatesSource, error) {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	head, err := r.Head()
+	if err != nil {
+		return nil, err
+	}
+	relPath, err := filepath.Rel(dir, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	source := &LockedTemplatesSource{Path: filepath.ToSlash(relPath), Commit: head.Hash().String()}
+	if remote, err := r.Remote(git.DefaultRemoteName); err == nil && len(remote.Config().URLs) > 0 {
+		source.URL = remote.Config().URLs[0]
+		// do not leak credentials of clone urls into the lockfile
+		if u, err := url.Parse(source.URL); err == nil && u.User != nil {
+			u.User = nil
+			source.URL = u.String()
+		}
+	}
+	return source, nil
+}
+
+// checkoutCommit checks out the commit of the git repository
+func checkoutCommit(repoPath, commit string) error {
+	r, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return err
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	return w.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(commit), Force: true})
+}
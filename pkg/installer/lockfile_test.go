@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestFile writes the content to the file creating its directory
+func writeTestFile(t *testing.T, path, content string) {
+	require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.Nil(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLockfile(t *testing.T) {
+	templatesDir := setTestTemplatesDirs(t)
+	writeTestFile(t, filepath.Join(templatesDir, "http", "cves", "test.yaml"), "id: test\n")
+	writeTestFile(t, filepath.Join(templatesDir, config.VulmapTemplatesCheckSumFileName), "")
+	writeTestFile(t, filepath.Join(config.DefaultConfig.CustomS3TemplatesDirectory, "bucket", "s3.yaml"), "id: s3\n")
+	require.Nil(t, config.DefaultConfig.SetTemplatesVersion("v9.9.9"))
+
+	// custom templates repository cloned from github
+	repoDir := filepath.Join(config.DefaultConfig.CustomGitHubTemplatesDirectory, "org", "repo")
+	r, err := git.PlainInit(repoDir, false)
+	require.Nil(t, err)
+	commit := func(name string) {
+		writeTestFile(t, filepath.Join(repoDir, name), "id: "+name+"\n")
+		w, err := r.Worktree()
+		require.Nil(t, err)
+		_, err = w.Add(name)
+		require.Nil(t, err)
+		_, err = w.Commit("add "+name, &git.CommitOptions{Author: &object.Signature{Name: "vulmap", When: time.Now()}})
+		require.Nil(t, err)
+	}
+	commit("first.yaml")
+
+	tm := &TemplateManager{DisablePublicTemplates: true}
+	lock, err := tm.GenerateLockfile()
+	require.Nil(t, err, "could not generate lockfile")
+	require.Equal(t, "v9.9.9", lock.TemplatesVersion)
+	require.Equal(t, map[string]string{"http/cves/test.yaml": fmt.Sprintf("%x", md5.Sum([]byte("id: test\n")))}, lock.Templates)
+	require.Len(t, lock.CustomTemplates, 2)
+	require.Equal(t, "github/org/repo", lock.CustomTemplates[0].Path)
+	require.NotEmpty(t, lock.CustomTemplates[0].Commit)
+	require.Equal(t, "s3", lock.CustomTemplates[1].Path)
+	require.NotEmpty(t, lock.CustomTemplates[1].Checksum)
+
+	lockfilePath := filepath.Join(t.TempDir(), DefaultLockfileName)
+	require.Nil(t, lock.Write(lockfilePath))
+	lock, err = ReadLockfile(lockfilePath)
+	require.Nil(t, err, "could not read lockfile")
+
+	installed, err := tm.GenerateLockfile()
+	require.Nil(t, err)
+	require.Empty(t, lock.Diff(installed))
+
+	writeTestFile(t, filepath.Join(templatesDir, "http", "cves", "test.yaml"), "id: modified\n")
+	writeTestFile(t, filepath.Join(templatesDir, "http", "new.yaml"), "id: new\n")
+	writeTestFile(t, filepath.Join(config.DefaultConfig.CustomS3TemplatesDirectory, "bucket", "s3.yaml"), "id: modified\n")
+	commit("second.yaml")
+	installed, err = tm.GenerateLockfile()
+	require.Nil(t, err)
+	diffs := lock.Diff(installed)
+	require.Len(t, diffs, 4)
+	require.Equal(t, "modified template http/cves/test.yaml", diffs[0])
+	require.Equal(t, "unlocked template http/new.yaml", diffs[1])
+	require.Contains(t, diffs[2], "custom templates github/org/repo are at commit")
+	require.Equal(t, "modified custom templates s3", diffs[3])
+
+	// syncing checks out the locked commit of custom templates
+	require.Nil(t, tm.SyncLockfile(lock), "could not sync lockfile")
+	require.NoFileExists(t, filepath.Join(repoDir, "second.yaml"))
+	installed, err = tm.GenerateLockfile()
+	require.Nil(t, err)
+	require.Len(t, lock.Diff(installed), 3)
+}
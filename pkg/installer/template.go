@@ -150,19 +150,18 @@ func (t *TemplateManager) updateTemplatesAt(dir string) error {
 
 	// summarize all changes
 	results := t.summarizeChanges(oldchecksums, newchecksums)
+	logUpdateResults(ghrd.Latest.GetTagName(), dir, results)
+	return nil
+}
 
-	// print summary
-	if results.totalCount > 0 {
-		gologger.Info().Msgf("Successfully updated vulmap-templates (%v) to %s. GoodLuck!", ghrd.Latest.GetTagName(), dir)
-		if !HideUpdateChangesTable {
-			// print summary table
-			gologger.Print().Msgf("\nVulmap Templates %s Changelog\n", ghrd.Latest.GetTagName())
-			gologger.DefaultLogger.Print().Msg(results.String())
-		}
-	} else {
-		gologger.Info().Msgf("Successfully updated vulmap-templates (%v) to %s. GoodLuck!", ghrd.Latest.GetTagName(), dir)
+// logUpdateResults prints the summary of a templates update
+func logUpdateResults(version, dir string, results *templateUpdateResults) {
+	gologger.Info().Msgf("Successfully updated vulmap-templates (%v) to %s. GoodLuck!", version, dir)
+	if results.totalCount > 0 && !HideUpdateChangesTable {
+		// print summary table
+		gologger.Print().Msgf("\nVulmap Templates %s Changelog\n", version)
+		gologger.DefaultLogger.Print().Msg(results.String())
 	}
-	return nil
 }
 
 // summarizeChanges summarizes changes between old and new checksums
@@ -262,33 +261,65 @@ func (t *TemplateManager) writeTemplatesToDisk(ghrd *updateutils.GHReleaseDownlo
 			// if error occurs, iteration also stops
 			return errorutil.NewWithErr(err).Msgf("failed to read file %s", uri)
 		}
-		// TODO: It might be better to just download index file from vulmap templates repo
-		// instead of creating it from scratch
-		id, _ := config.GetTemplateIDFromReader(bytes.NewReader(bin), uri)
-		if id != "" {
-			// based on template id, check if we are updating a path of official vulmap template
-			if oldPath, ok := localTemplatesIndex[id]; ok {
-				if oldPath != writePath {
-					// write new template at a new path and delete old template
-					if err := os.WriteFile(writePath, bin, f.Mode()); err != nil {
-						return errorutil.NewWithErr(err).Msgf("failed to write file %s", uri)
-					}
-					// after successful write, remove old template
-					if err := os.Remove(oldPath); err != nil {
-						gologger.Warning().Msgf("failed to remove old template %s: %s", oldPath, err)
-					}
-					return nil
-				}
-			}
-		}
-		// no change in template Path of official templates
-		return os.WriteFile(writePath, bin, f.Mode())
+		return writeTemplateFile(localTemplatesIndex, uri, writePath, bin, f.Mode())
 	}
 	err = ghrd.DownloadSourceWithCallback(!HideProgressBar, callbackFunc)
 	if err != nil {
 		return errorutil.NewWithErr(err).Msgf("failed to download templates")
 	}
 
+	if err := updateTemplatesMetadata(dir, ghrd.Latest.GetTagName()); err != nil {
+		return err
+	}
+
+	if !HideReleaseNotes {
+		output := ghrd.Latest.GetBody()
+		// adjust colors for both dark / light terminal themes
+		r, err := glamour.NewTermRenderer(glamour.WithAutoStyle())
+		if err != nil {
+			gologger.Error().Msgf("markdown rendering not supported: %v", err)
+		}
+		if rendered, err := r.Render(output); err == nil {
+			output = rendered
+		} else {
+			gologger.Error().Msg(err.Error())
+		}
+		gologger.Print().Msgf("\n%v\n\n", output)
+	}
+
+	// after installation, create and write checksums to .checksum file
+	return t.writeChecksumFileInDir(dir)
+}
+
+// writeTemplateFile writes a template file at the given path. Official templates
+// moved to a new path are removed from their old path of the local templates index.
+func writeTemplateFile(localTemplatesIndex map[string]string, uri, writePath string, bin []byte, mode fs.FileMode) error {
+	// TODO: It might be better to just download index file from vulmap templates repo
+	// instead of creating it from scratch
+	id, _ := config.GetTemplateIDFromReader(bytes.NewReader(bin), uri)
+	if id != "" {
+		// based on template id, check if we are updating a path of official vulmap template
+		if oldPath, ok := localTemplatesIndex[id]; ok {
+			if oldPath != writePath {
+				// write new template at a new path and delete old template
+				if err := os.WriteFile(writePath, bin, mode); err != nil {
+					return errorutil.NewWithErr(err).Msgf("failed to write file %s", uri)
+				}
+				// after successful write, remove old template
+				if err := os.Remove(oldPath); err != nil {
+					gologger.Warning().Msgf("failed to remove old template %s: %s", oldPath, err)
+				}
+				return nil
+			}
+		}
+	}
+	// no change in template Path of official templates
+	return os.WriteFile(writePath, bin, mode)
+}
+
+// updateTemplatesMetadata updates the templates config, version, ignore hash
+// and index after new templates were written to the directory
+func updateTemplatesMetadata(dir, version string) error {
 	if err := config.DefaultConfig.WriteTemplatesConfig(); err != nil {
 		return errorutil.NewWithErr(err).Msgf("failed to write templates config")
 	}
@@ -298,7 +329,7 @@ func (t *TemplateManager) writeTemplatesToDisk(ghrd *updateutils.GHReleaseDownlo
 	}
 
 	// update templates version in config file
-	if err := config.DefaultConfig.SetTemplatesVersion(ghrd.Latest.GetTagName()); err != nil {
+	if err := config.DefaultConfig.SetTemplatesVersion(version); err != nil {
 		return errorutil.NewWithErr(err).Msgf("failed to update templates version")
 	}
 
@@ -315,24 +346,7 @@ func (t *TemplateManager) writeTemplatesToDisk(ghrd *updateutils.GHReleaseDownlo
 	if err = config.DefaultConfig.WriteTemplatesIndex(index); err != nil {
 		return errorutil.NewWithErr(err).Msgf("failed to write vulmap templates index")
 	}
-
-	if !HideReleaseNotes {
-		output := ghrd.Latest.GetBody()
-		// adjust colors for both dark / light terminal themes
-		r, err := glamour.NewTermRenderer(glamour.WithAutoStyle())
-		if err != nil {
-			gologger.Error().Msgf("markdown rendering not supported: %v", err)
-		}
-		if rendered, err := r.Render(output); err == nil {
-			output = rendered
-		} else {
-			gologger.Error().Msg(err.Error())
-		}
-		gologger.Print().Msgf("\n%v\n\n", output)
-	}
-
-	// after installation, create and write checksums to .checksum file
-	return t.writeChecksumFileInDir(dir)
+	return nil
 }
 
 // getChecksumFromDir returns a map containing checksums (md5 hash) of all yaml files (with .yaml extension)
@@ -366,7 +380,7 @@ func (t *TemplateManager) writeChecksumFileInDir(dir string) error {
 	}
 	var buff bytes.Buffer
 	for k, v := range checksumMap {
-		buff.WriteString(k + "," + v)
+		buff.WriteString(k + "," + v + "\n")
 	}
 	return os.WriteFile(config.DefaultConfig.GetChecksumFilePath(), buff.Bytes(), checkSumFilePerm)
 }
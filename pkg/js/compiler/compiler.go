@@ -12,11 +12,20 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/khulnasoft-lab/gologger"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libamqp"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libbacnet"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libbytes"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libelasticsearch"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libfs"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libhttp"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libikev2"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libkafka"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libkerberos"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libldap"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libmemcached"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libmodbus"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libmongodb"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libmqtt"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libmssql"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libmysql"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libnet"
@@ -26,12 +35,15 @@ import (
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/librdp"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libredis"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/librsync"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libs7comm"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libsmb"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libsmtp"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libsnmp"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libssh"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libstructs"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libtelnet"
 	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libvnc"
+	_ "github.com/khulnasoft-lab/vulmap/pkg/js/generated/go/libzookeeper"
 	"github.com/khulnasoft-lab/vulmap/pkg/js/global"
 	"github.com/khulnasoft-lab/vulmap/pkg/js/libs/goconsole"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
@@ -1,11 +1,25 @@
 package compiler
 
 import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gosnmp/gosnmp"
 	"github.com/khulnasoft-lab/gologger"
 	"github.com/khulnasoft-lab/gologger/levels"
+	"github.com/khulnasoft-lab/ratelimit"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
 )
 
 func TestNewCompilerConsoleDebug(t *testing.T) {
@@ -68,6 +82,501 @@ func TestCompilerCaptureOutput(t *testing.T) {
 	}
 }
 
+func TestCompilerHTTPModule(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			if r.Method == http.MethodPost && r.FormValue("csrf") == "token" && r.Header.Get("X-Custom") == "value" {
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "admin"})
+				return
+			}
+			_, _ = w.Write([]byte(`<input name="csrf" value="token">`))
+		case "/api":
+			if cookie, err := r.Cookie("session"); err == nil && cookie.Value == "admin" {
+				_, _ = w.Write([]byte(`{"role":"admin"}`))
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	if err := protocolstate.Init(types.DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	// the three requests of the script take the tokens of the engine rate limiter
+	protocolstate.RateLimiter = ratelimit.New(context.Background(), 3, time.Hour)
+	defer func() {
+		protocolstate.RateLimiter.Stop()
+		protocolstate.RateLimiter = nil
+	}()
+	compiler := New()
+	args := NewExecuteArgs()
+	args.Args["BaseURL"] = ts.URL
+	result, err := compiler.ExecuteWithOptions(`
+		let http = require('vulmap/http');
+		let c = http.Client();
+		c.SetHeader('X-Custom', 'value');
+		let token = c.Get(BaseURL + '/login').Body.match(/value="([^"]+)"/)[1];
+		let login = c.Post(BaseURL + '/login', 'application/x-www-form-urlencoded', 'csrf=' + token);
+		let resp = c.Get(BaseURL + '/api');
+		let result = {status: resp.StatusCode, role: resp.JSON().role, session: login.Cookies()['session']};
+		result
+	`, args, &ExecuteOptions{CaptureOutput: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["role"] != "admin" || result["session"] != "admin" || result["status"] != float64(200) {
+		t.Fatalf("unexpected result from http module, got=%v", result)
+	}
+	if protocolstate.RateLimiter.CanTake() {
+		t.Fatalf("unexpected tokens left in the engine rate limiter")
+	}
+}
+
+func TestCompilerServiceModules(t *testing.T) {
+	memcachedPort := newFakeServer(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		line, _ := reader.ReadString('\n')
+		switch strings.TrimSpace(line) {
+		case "version":
+			_, _ = conn.Write([]byte("VERSION 1.6.21\r\n"))
+		case "stats":
+			_, _ = conn.Write([]byte("STAT pid 1\r\nSTAT curr_items 1\r\nEND\r\n"))
+		case "get session":
+			_, _ = conn.Write([]byte("VALUE session 0 5\r\nadmin\r\nEND\r\n"))
+		}
+	})
+	mqttPort := newFakeServer(t, func(conn net.Conn) {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		_, _ = io.CopyN(io.Discard, conn, int64(header[1]))
+		_, _ = conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+	})
+	zookeeperPort := newFakeServer(t, func(conn net.Conn) {
+		command := make([]byte, 4)
+		if _, err := io.ReadFull(conn, command); err != nil {
+			return
+		}
+		switch string(command) {
+		case "ruok":
+			_, _ = conn.Write([]byte("imok"))
+		case "srvr":
+			_, _ = conn.Write([]byte("Zookeeper version: 3.8.3-6ad6d364c7c0bcf0de452d54ebefa3058098ab56, built on 2023-10-05 10:34 UTC\nMode: standalone\n"))
+		}
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`{"name":"node-1","cluster_name":"docker-cluster","version":{"number":"8.11.1"},"tagline":"You Know, for Search"}`))
+		case "/_cat/indices":
+			_, _ = w.Write([]byte(`[{"index":"users"}]`))
+		}
+	}))
+	defer ts.Close()
+	elasticsearchHost, elasticsearchPort, _ := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+
+	if err := protocolstate.Init(types.DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	compiler := New()
+	args := NewExecuteArgs()
+	args.Args["Memcached"] = memcachedPort
+	args.Args["MQTT"] = mqttPort
+	args.Args["ZooKeeper"] = zookeeperPort
+	args.Args["ESHost"] = elasticsearchHost
+	args.Args["ESPort"], _ = strconv.Atoi(elasticsearchPort)
+	result, err := compiler.ExecuteWithOptions(`
+		let memcached = require('vulmap/memcached').MemcachedClient();
+		let mqtt = require('vulmap/mqtt').MQTTClient();
+		let zookeeper = require('vulmap/zookeeper').ZooKeeperClient();
+		let elasticsearch = require('vulmap/elasticsearch').ElasticsearchClient();
+		let result = {
+			memcached: memcached.IsMemcached('127.0.0.1', Memcached),
+			items: memcached.Stats('127.0.0.1', Memcached)['curr_items'],
+			session: memcached.Get('127.0.0.1', Memcached, 'session'),
+			mqtt: mqtt.IsUnauthenticated('127.0.0.1', MQTT),
+			zookeeper: zookeeper.IsZooKeeper('127.0.0.1', ZooKeeper),
+			zkversion: zookeeper.GetVersion('127.0.0.1', ZooKeeper),
+			esversion: elasticsearch.IsElasticsearch(ESHost, ESPort, false).Version,
+			indices: elasticsearch.ListIndices(ESHost, ESPort, false, '', '').join(','),
+		};
+		result
+	`, args, &ExecuteOptions{CaptureOutput: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"memcached": "1.6.21",
+		"items":     "1",
+		"session":   "admin",
+		"mqtt":      true,
+		"zookeeper": true,
+		"zkversion": "3.8.3-6ad6d364c7c0bcf0de452d54ebefa3058098ab56, built on 2023-10-05 10:34 UTC",
+		"esversion": "8.11.1",
+		"indices":   "users",
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Fatalf("unexpected %v from service modules, got=%v want=%v", key, result[key], value)
+		}
+	}
+}
+
+func TestCompilerICSModules(t *testing.T) {
+	modbusPort := newFakeServer(t, func(conn net.Conn) {
+		for {
+			header := make([]byte, 7)
+			if _, err := io.ReadFull(conn, header); err != nil {
+				return
+			}
+			pdu := make([]byte, int(header[5])-1)
+			if _, err := io.ReadFull(conn, pdu); err != nil {
+				return
+			}
+			var response []byte
+			switch pdu[0] {
+			case 0x03:
+				response = []byte{0x03, 0x04, 0x00, 0x01, 0x01, 0x00}
+			case 0x06:
+				response = pdu
+			case 0x2b:
+				response = append([]byte{0x2b, 0x0e, 0x02, 0x82, 0x00, 0x00, 0x02, 0x00, 0x04}, "Acme"...)
+				response = append(append(response, 0x01, 0x05), "PLC-1"...)
+			}
+			header[4], header[5] = 0, byte(len(response)+1)
+			_, _ = conn.Write(append(header, response...))
+		}
+	})
+	bacnetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bacnetConn.Close()
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			n, addr, err := bacnetConn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			property := buffer[n-1]
+			response := []byte{0x81, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x30, buffer[8], 0x0c, 0x0c, 0x02, 0x00, 0x00, 0x01, 0x19, property, 0x3e}
+			switch property {
+			case 120:
+				response = append(response, 0x21, 0x07)
+			case 121:
+				response = append(append(response, 0x75, 0x07, 0x00), "Vendor"...)
+			default:
+				response = []byte{0x81, 0x0a, 0x00, 0x00, 0x01, 0x00, 0x50, buffer[8], 0x0c, 0x91, 0x02, 0x91, 0x20}
+			}
+			if response[6] == 0x30 {
+				response = append(response, 0x3f)
+			}
+			response[3] = byte(len(response))
+			_, _ = bacnetConn.WriteTo(response, addr)
+		}
+	}()
+
+	if err := protocolstate.Init(types.DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	compiler := New()
+	args := NewExecuteArgs()
+	args.Args["Modbus"] = modbusPort
+	args.Args["BACnet"] = bacnetConn.LocalAddr().(*net.UDPAddr).Port
+	result, err := compiler.ExecuteWithOptions(`
+		let modbus = require('vulmap/modbus').ModbusClient();
+		let bacnet = require('vulmap/bacnet').BACnetClient();
+		let blocked = false;
+		try {
+			modbus.WriteSingleRegister('127.0.0.1', Modbus, 1, 0, 42);
+		} catch (e) {
+			blocked = true;
+		}
+		modbus.EnableWrites();
+		let info = bacnet.GetDeviceInfo('127.0.0.1', BACnet);
+		let result = {
+			registers: modbus.ReadHoldingRegisters('127.0.0.1', Modbus, 1, 0, 2).join(','),
+			vendor: modbus.ReadDeviceIdentification('127.0.0.1', Modbus, 1)['VendorName'],
+			blocked: blocked,
+			written: modbus.WriteSingleRegister('127.0.0.1', Modbus, 1, 0, 42),
+			vendorid: info.VendorID,
+			vendorname: info.VendorName,
+		};
+		result
+	`, args, &ExecuteOptions{CaptureOutput: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"registers":  "1,256",
+		"vendor":     "Acme",
+		"blocked":    true,
+		"written":    true,
+		"vendorid":   "7",
+		"vendorname": "Vendor",
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Fatalf("unexpected %v from ics modules, got=%v want=%v", key, result[key], value)
+		}
+	}
+}
+
+func TestCompilerICSValidation(t *testing.T) {
+	// snmp agent answering get requests with the system description
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+	var sets atomic.Int32
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			n, addr, err := agent.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			request, err := (&gosnmp.GoSNMP{Version: gosnmp.Version2c}).SnmpDecodePacket(buffer[:n])
+			if err != nil {
+				continue
+			}
+			if request.PDUType == gosnmp.SetRequest {
+				sets.Add(1)
+			}
+			response := &gosnmp.SnmpPacket{
+				Version:   request.Version,
+				Community: request.Community,
+				PDUType:   gosnmp.GetResponse,
+				RequestID: request.RequestID,
+				Variables: []gosnmp.SnmpPDU{{Name: ".1.3.6.1.2.1.1.1.0", Type: gosnmp.OctetString, Value: []byte("Acme Switch")}},
+			}
+			data, err := response.MarshalMsg()
+			if err != nil {
+				continue
+			}
+			_, _ = agent.WriteTo(data, addr)
+		}
+	}()
+	// server confirming the cotp connection without speaking s7comm
+	s7Port := newFakeServer(t, func(conn net.Conn) {
+		header := make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(conn, header); err != nil {
+				return
+			}
+			if _, err := io.CopyN(io.Discard, conn, int64(binary.BigEndian.Uint16(header[2:]))-4); err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte{0x03, 0x00, 0x00, 0x0b, 0x06, 0xd0, 0x00, 0x01, 0x00, 0x00, 0x00})
+		}
+	})
+
+	if err := protocolstate.Init(types.DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	compiler := New()
+	args := NewExecuteArgs()
+	args.Args["SNMP"] = agent.LocalAddr().(*net.UDPAddr).Port
+	args.Args["S7"] = s7Port
+	result, err := compiler.ExecuteWithOptions(`
+		let snmp = require('vulmap/snmp').SNMPClient();
+		let s7comm = require('vulmap/s7comm').S7commClient();
+		let failure = (fn) => {
+			try {
+				fn();
+			} catch (e) {
+				return String(e);
+			}
+			return '';
+		};
+		let result = {
+			sysdescr: snmp.IsSNMP('127.0.0.1', SNMP, 'public'),
+			blocked: failure(() => snmp.Set('127.0.0.1', SNMP, '2c', 'private', '1.3.6.1.2.1.1.5.0', 'pwned')),
+			version: failure(() => snmp.Get('127.0.0.1', SNMP, '3', 'public', ['1.3.6.1.2.1.1.1.0'])),
+			oids: failure(() => snmp.Get('127.0.0.1', SNMP, '2c', 'public', [])),
+			auth: failure(() => snmp.GetV3('127.0.0.1', SNMP, 'admin', 'SHA1', 'password', '', '', ['1.3.6.1.2.1.1.1.0'])),
+			priv: failure(() => snmp.GetV3('127.0.0.1', SNMP, 'admin', 'SHA', 'password', '3DES', 'password', ['1.3.6.1.2.1.1.1.0'])),
+			s7comm: failure(() => s7comm.IsS7comm('127.0.0.1', S7)),
+			s7write: typeof s7comm.WriteArea,
+		};
+		result
+	`, args, &ExecuteOptions{CaptureOutput: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["sysdescr"] != "Acme Switch" {
+		t.Fatalf("unexpected snmp system description, got=%v", result["sysdescr"])
+	}
+	expected := map[string]string{
+		"blocked": "write operations are disabled",
+		"version": "unsupported snmp version",
+		"oids":    "no oids specified",
+		"auth":    "unsupported snmp authentication protocol",
+		"priv":    "unsupported snmp privacy protocol",
+		"s7comm":  "not a s7comm server",
+	}
+	for key, value := range expected {
+		if message, _ := result[key].(string); !strings.Contains(message, value) {
+			t.Fatalf("unexpected %v error from ics modules, got=%q want=%q", key, message, value)
+		}
+	}
+	if result["s7write"] != "undefined" {
+		t.Fatalf("unexpected s7comm write operation, got=%v", result["s7write"])
+	}
+	if sets.Load() != 0 {
+		t.Fatalf("unexpected snmp set requests while writes are disabled, got=%d", sets.Load())
+	}
+}
+
+func TestCompilerDataModules(t *testing.T) {
+	// amqp broker advertising its properties and closing the connection
+	// after the credentials are sent, as brokers refusing them do
+	amqpPort := newFakeServer(t, func(conn net.Conn) {
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		var properties []byte
+		for _, property := range [][2]string{{"product", "RabbitMQ"}, {"version", "3.12.10"}, {"platform", "Erlang/OTP 26.1"}} {
+			properties = append(append(properties, byte(len(property[0]))), property[0]...)
+			properties = binary.BigEndian.AppendUint32(append(properties, 'S'), uint32(len(property[1])))
+			properties = append(properties, property[1]...)
+		}
+		payload := []byte{0x00, 0x0a, 0x00, 0x0a, 0x00, 0x09}
+		payload = append(binary.BigEndian.AppendUint32(payload, uint32(len(properties))), properties...)
+		for _, value := range []string{"PLAIN AMQPLAIN", "en_US"} {
+			payload = append(binary.BigEndian.AppendUint32(payload, uint32(len(value))), value...)
+		}
+		frame := binary.BigEndian.AppendUint32([]byte{0x01, 0x00, 0x00}, uint32(len(payload)))
+		_, _ = conn.Write(append(append(frame, payload...), 0xce))
+		_, _ = conn.Read(make([]byte, 1024))
+	})
+
+	if err := protocolstate.Init(types.DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	compiler := New()
+	args := NewExecuteArgs()
+	args.Args["AMQP"] = amqpPort
+	result, err := compiler.ExecuteWithOptions(`
+		let amqp = require('vulmap/amqp').AMQPClient();
+		let info = amqp.IsAMQP('127.0.0.1', AMQP);
+		let result = {
+			product: info.Product,
+			version: info.Version,
+			platform: info.Platform,
+			mechanisms: info.Mechanisms.join(','),
+			unauthenticated: amqp.IsUnauthenticated('127.0.0.1', AMQP),
+			connected: amqp.Connect('127.0.0.1', AMQP, 'admin', 'admin', ''),
+		};
+		result
+	`, args, &ExecuteOptions{CaptureOutput: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"product":         "RabbitMQ",
+		"version":         "3.12.10",
+		"platform":        "Erlang/OTP 26.1",
+		"mechanisms":      "PLAIN,AMQPLAIN",
+		"unauthenticated": false,
+		"connected":       false,
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Fatalf("unexpected %v from data modules, got=%v want=%v", key, result[key], value)
+		}
+	}
+}
+
+func TestCompilerDataValidation(t *testing.T) {
+	// server replying to any request with a http response
+	httpPort := newFakeServer(t, func(conn net.Conn) {
+		_, _ = conn.Read(make([]byte, 1024))
+		_, _ = conn.Write([]byte("HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\nConnection: close\r\n\r\n"))
+	})
+
+	if err := protocolstate.Init(types.DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	compiler := New()
+	args := NewExecuteArgs()
+	args.Args["HTTP"] = httpPort
+	result, err := compiler.ExecuteWithOptions(`
+		let mongodb = require('vulmap/mongodb').MongoDBClient();
+		let amqp = require('vulmap/amqp').AMQPClient();
+		let kafka = require('vulmap/kafka').KafkaClient();
+		let failure = (fn) => {
+			try {
+				fn();
+			} catch (e) {
+				return String(e);
+			}
+			return '';
+		};
+		let detected = (fn) => {
+			try {
+				return fn();
+			} catch (e) {
+				return false;
+			}
+		};
+		let result = {
+			amqp: failure(() => amqp.IsAMQP('127.0.0.1', HTTP)),
+			amqpauth: failure(() => amqp.IsUnauthenticated('127.0.0.1', HTTP)),
+			mongodb: failure(() => mongodb.IsMongoDB('127.0.0.1', HTTP)),
+			kafka: detected(() => kafka.IsKafka('127.0.0.1', HTTP)),
+		};
+		result
+	`, args, &ExecuteOptions{CaptureOutput: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"amqp":     "not an amqp broker",
+		"amqpauth": "not an amqp broker",
+	}
+	for key, value := range expected {
+		if message, _ := result[key].(string); !strings.Contains(message, value) {
+			t.Fatalf("unexpected %v error from data modules, got=%q want=%q", key, message, value)
+		}
+	}
+	if result["mongodb"] == "" {
+		t.Fatalf("unexpected mongodb server detected on http server")
+	}
+	if result["kafka"] != false {
+		t.Fatalf("unexpected kafka broker detected on http server, got=%v", result["kafka"])
+	}
+}
+
+// newFakeServer starts a tcp server calling handler for each connection
+// and returns its port
+func newFakeServer(t *testing.T, handler func(conn net.Conn)) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handler(conn)
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
 type noopWriter struct {
 	Callback func(data []byte, level levels.Level)
 }
@@ -0,0 +1,36 @@
+package amqp
+
+import (
+	lib_amqp "github.com/khulnasoft-lab/vulmap/pkg/js/libs/amqp"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/amqp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"AMQPClient": func() lib_amqp.AMQPClient { return lib_amqp.AMQPClient{} },
+			"AMQPInfo":   func() lib_amqp.AMQPInfo { return lib_amqp.AMQPInfo{} },
+			"QueueInfo":  func() lib_amqp.QueueInfo { return lib_amqp.QueueInfo{} },
+
+			// Types (pointer type)
+			"NewAMQPClient": func() *lib_amqp.AMQPClient { return &lib_amqp.AMQPClient{} },
+			"NewAMQPInfo":   func() *lib_amqp.AMQPInfo { return &lib_amqp.AMQPInfo{} },
+			"NewQueueInfo":  func() *lib_amqp.QueueInfo { return &lib_amqp.QueueInfo{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,34 @@
+package bacnet
+
+import (
+	lib_bacnet "github.com/khulnasoft-lab/vulmap/pkg/js/libs/bacnet"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/bacnet")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"BACnetClient":     func() lib_bacnet.BACnetClient { return lib_bacnet.BACnetClient{} },
+			"BACnetDeviceInfo": func() lib_bacnet.BACnetDeviceInfo { return lib_bacnet.BACnetDeviceInfo{} },
+
+			// Types (pointer type)
+			"NewBACnetClient":     func() *lib_bacnet.BACnetClient { return &lib_bacnet.BACnetClient{} },
+			"NewBACnetDeviceInfo": func() *lib_bacnet.BACnetDeviceInfo { return &lib_bacnet.BACnetDeviceInfo{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
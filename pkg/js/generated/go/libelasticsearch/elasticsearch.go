@@ -0,0 +1,34 @@
+package elasticsearch
+
+import (
+	lib_elasticsearch "github.com/khulnasoft-lab/vulmap/pkg/js/libs/elasticsearch"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/elasticsearch")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"ElasticsearchClient": func() lib_elasticsearch.ElasticsearchClient { return lib_elasticsearch.ElasticsearchClient{} },
+			"ElasticsearchInfo":   func() lib_elasticsearch.ElasticsearchInfo { return lib_elasticsearch.ElasticsearchInfo{} },
+
+			// Types (pointer type)
+			"NewElasticsearchClient": func() *lib_elasticsearch.ElasticsearchClient { return &lib_elasticsearch.ElasticsearchClient{} },
+			"NewElasticsearchInfo":   func() *lib_elasticsearch.ElasticsearchInfo { return &lib_elasticsearch.ElasticsearchInfo{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	lib_http "github.com/khulnasoft-lab/vulmap/pkg/js/libs/http"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/http")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"Client":   func() lib_http.Client { return lib_http.Client{} },
+			"Request":  func() lib_http.Request { return lib_http.Request{} },
+			"Response": func() lib_http.Response { return lib_http.Response{} },
+
+			// Types (pointer type)
+			"NewClient":   func() *lib_http.Client { return &lib_http.Client{} },
+			"NewRequest":  func() *lib_http.Request { return &lib_http.Request{} },
+			"NewResponse": func() *lib_http.Response { return &lib_http.Response{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,32 @@
+package kafka
+
+import (
+	lib_kafka "github.com/khulnasoft-lab/vulmap/pkg/js/libs/kafka"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/kafka")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"KafkaClient": func() lib_kafka.KafkaClient { return lib_kafka.KafkaClient{} },
+
+			// Types (pointer type)
+			"NewKafkaClient": func() *lib_kafka.KafkaClient { return &lib_kafka.KafkaClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
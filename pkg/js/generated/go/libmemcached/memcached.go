@@ -0,0 +1,32 @@
+package memcached
+
+import (
+	lib_memcached "github.com/khulnasoft-lab/vulmap/pkg/js/libs/memcached"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/memcached")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"MemcachedClient": func() lib_memcached.MemcachedClient { return lib_memcached.MemcachedClient{} },
+
+			// Types (pointer type)
+			"NewMemcachedClient": func() *lib_memcached.MemcachedClient { return &lib_memcached.MemcachedClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
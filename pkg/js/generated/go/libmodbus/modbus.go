@@ -0,0 +1,32 @@
+package modbus
+
+import (
+	lib_modbus "github.com/khulnasoft-lab/vulmap/pkg/js/libs/modbus"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/modbus")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"ModbusClient": func() lib_modbus.ModbusClient { return lib_modbus.ModbusClient{} },
+
+			// Types (pointer type)
+			"NewModbusClient": func() *lib_modbus.ModbusClient { return &lib_modbus.ModbusClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
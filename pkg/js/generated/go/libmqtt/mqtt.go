@@ -0,0 +1,32 @@
+package mqtt
+
+import (
+	lib_mqtt "github.com/khulnasoft-lab/vulmap/pkg/js/libs/mqtt"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/mqtt")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"MQTTClient": func() lib_mqtt.MQTTClient { return lib_mqtt.MQTTClient{} },
+
+			// Types (pointer type)
+			"NewMQTTClient": func() *lib_mqtt.MQTTClient { return &lib_mqtt.MQTTClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
@@ -0,0 +1,34 @@
+package s7comm
+
+import (
+	lib_s7comm "github.com/khulnasoft-lab/vulmap/pkg/js/libs/s7comm"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/s7comm")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"S7Info":       func() lib_s7comm.S7Info { return lib_s7comm.S7Info{} },
+			"S7commClient": func() lib_s7comm.S7commClient { return lib_s7comm.S7commClient{} },
+
+			// Types (pointer type)
+			"NewS7Info":       func() *lib_s7comm.S7Info { return &lib_s7comm.S7Info{} },
+			"NewS7commClient": func() *lib_s7comm.S7commClient { return &lib_s7comm.S7commClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
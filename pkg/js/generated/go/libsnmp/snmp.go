@@ -0,0 +1,32 @@
+package snmp
+
+import (
+	lib_snmp "github.com/khulnasoft-lab/vulmap/pkg/js/libs/snmp"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/snmp")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"SNMPClient": func() lib_snmp.SNMPClient { return lib_snmp.SNMPClient{} },
+
+			// Types (pointer type)
+			"NewSNMPClient": func() *lib_snmp.SNMPClient { return &lib_snmp.SNMPClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
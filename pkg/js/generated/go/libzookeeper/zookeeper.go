@@ -0,0 +1,32 @@
+package zookeeper
+
+import (
+	lib_zookeeper "github.com/khulnasoft-lab/vulmap/pkg/js/libs/zookeeper"
+
+	"github.com/dop251/goja"
+	"github.com/khulnasoft-lab/vulmap/pkg/js/gojs"
+)
+
+var (
+	module = gojs.NewGojaModule("vulmap/zookeeper")
+)
+
+func init() {
+	module.Set(
+		gojs.Objects{
+			// Functions
+
+			// Var and consts
+
+			// Types (value type)
+			"ZooKeeperClient": func() lib_zookeeper.ZooKeeperClient { return lib_zookeeper.ZooKeeperClient{} },
+
+			// Types (pointer type)
+			"NewZooKeeperClient": func() *lib_zookeeper.ZooKeeperClient { return &lib_zookeeper.ZooKeeperClient{} },
+		},
+	).Register()
+}
+
+func Enable(runtime *goja.Runtime) {
+	module.Enable(runtime)
+}
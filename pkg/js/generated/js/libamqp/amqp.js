@@ -0,0 +1,94 @@
+/** @module amqp */
+
+/**
+ * @class
+ * @classdesc AMQPClient is a client for AMQP 0-9-1 brokers (e.g. RabbitMQ).
+ */
+class AMQPClient {
+    /**
+    * @method
+    * @description IsAMQP checks if the given host is running an AMQP 0-9-1 broker.
+    * @param {string} host - The host of the amqp broker.
+    * @param {number} port - The port of the amqp broker.
+    * @returns {AMQPInfo} - The information advertised by the broker.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/amqp');
+    * let c = m.AMQPClient();
+    * let info = c.IsAMQP('localhost', 5672);
+    */
+    IsAMQP(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the broker accepts the default guest credentials or the ANONYMOUS mechanism.
+    * @param {string} host - The host of the amqp broker.
+    * @param {number} port - The port of the amqp broker.
+    * @returns {boolean} - Whether the broker accepts anonymous or default credentials.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/amqp');
+    * let c = m.AMQPClient();
+    * let open = c.IsUnauthenticated('localhost', 5672);
+    */
+    IsUnauthenticated(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect tries to connect to the virtual host of the broker with the given credentials.
+    * @param {string} host - The host of the amqp broker.
+    * @param {number} port - The port of the amqp broker.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} vhost - The virtual host to connect to.
+    * @returns {boolean} - Whether the credentials were accepted.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/amqp');
+    * let c = m.AMQPClient();
+    * let ok = c.Connect('localhost', 5672, 'guest', 'guest', '/');
+    */
+    Connect(host, port, username, password, vhost) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetQueueInfo returns information about an existing queue in the virtual host using given credentials.
+    * @param {string} host - The host of the amqp broker.
+    * @param {number} port - The port of the amqp broker.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} vhost - The virtual host of the queue.
+    * @param {string} queue - The name of the queue.
+    * @returns {QueueInfo} - The information of the queue.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/amqp');
+    * let c = m.AMQPClient();
+    * let queue = c.GetQueueInfo('localhost', 5672, 'guest', 'guest', '/', 'orders');
+    */
+    GetQueueInfo(host, port, username, password, vhost, queue) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} AMQPInfo
+ * @description AMQPInfo contains information about an AMQP broker (Product, Version, Platform, Mechanisms).
+ */
+const AMQPInfo = {};
+
+/**
+ * @typedef {object} QueueInfo
+ * @description QueueInfo contains information about a queue (Name, Messages, Consumers).
+ */
+const QueueInfo = {};
+
+module.exports = {
+    AMQPClient: AMQPClient,
+};
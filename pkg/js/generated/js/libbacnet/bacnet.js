@@ -0,0 +1,49 @@
+/** @module bacnet */
+
+/**
+ * @class
+ * @classdesc BACnetClient is a client for BACnet/IP devices. Only read operations are performed.
+ */
+class BACnetClient {
+    /**
+    * @method
+    * @description IsBACnet checks if the given host is running a BACnet/IP device.
+    * @param {string} host - The host of the bacnet device.
+    * @param {number} port - The port of the bacnet device.
+    * @returns {boolean} - Whether the host is running a BACnet/IP device.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/bacnet');
+    * let c = m.BACnetClient();
+    * let isBACnet = c.IsBACnet('localhost', 47808);
+    */
+    IsBACnet(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetDeviceInfo returns the information of the device object of a BACnet/IP device.
+    * @param {string} host - The host of the bacnet device.
+    * @param {number} port - The port of the bacnet device.
+    * @returns {BACnetDeviceInfo} - The information of the device.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/bacnet');
+    * let c = m.BACnetClient();
+    * let info = c.GetDeviceInfo('localhost', 47808);
+    */
+    GetDeviceInfo(host, port) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} BACnetDeviceInfo
+ * @description BACnetDeviceInfo contains information about a BACnet device (VendorID, VendorName, ObjectName, ModelName, FirmwareRevision, ApplicationSoftware, Description, Location).
+ */
+const BACnetDeviceInfo = {};
+
+module.exports = {
+    BACnetClient: BACnetClient,
+};
@@ -0,0 +1,130 @@
+/** @module elasticsearch */
+
+/**
+ * @class
+ * @classdesc ElasticsearchClient is a client for Elasticsearch and OpenSearch servers.
+ */
+class ElasticsearchClient {
+    /**
+    * @method
+    * @description IsElasticsearch checks if the given host is running Elasticsearch or OpenSearch.
+    * @param {string} host - The host of the elasticsearch server.
+    * @param {number} port - The port of the elasticsearch server.
+    * @param {boolean} useTLS - Whether to use HTTPS.
+    * @returns {ElasticsearchInfo} - The information of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let info = c.IsElasticsearch('localhost', 9200, false);
+    */
+    IsElasticsearch(host, port, useTLS) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetInfo returns the information of the server using given credentials.
+    * @param {string} host - The host of the elasticsearch server.
+    * @param {number} port - The port of the elasticsearch server.
+    * @param {boolean} useTLS - Whether to use HTTPS.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {ElasticsearchInfo} - The information of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let info = c.GetInfo('localhost', 9200, false, 'elastic', 'changeme');
+    */
+    GetInfo(host, port, useTLS, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the cluster can be queried without authentication.
+    * @param {string} host - The host of the elasticsearch server.
+    * @param {number} port - The port of the elasticsearch server.
+    * @param {boolean} useTLS - Whether to use HTTPS.
+    * @returns {boolean} - Whether the cluster can be queried without authentication.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let open = c.IsUnauthenticated('localhost', 9200, false);
+    */
+    IsUnauthenticated(host, port, useTLS) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListIndices returns the names of the indices of the cluster using given credentials.
+    * @param {string} host - The host of the elasticsearch server.
+    * @param {number} port - The port of the elasticsearch server.
+    * @param {boolean} useTLS - Whether to use HTTPS.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {string[]} - The names of the indices.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let indices = c.ListIndices('localhost', 9200, false, '', '');
+    */
+    ListIndices(host, port, useTLS, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Query makes a GET request to a path of the REST API using given credentials and returns the response body.
+    * @param {string} host - The host of the elasticsearch server.
+    * @param {number} port - The port of the elasticsearch server.
+    * @param {boolean} useTLS - Whether to use HTTPS.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} path - The path of the REST API.
+    * @returns {string} - The response body.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let nodes = c.Query('localhost', 9200, false, '', '', '/_nodes');
+    */
+    Query(host, port, useTLS, username, password, path) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Search runs a search query (JSON) on an index using given credentials and returns the response body.
+    * @param {string} host - The host of the elasticsearch server.
+    * @param {number} port - The port of the elasticsearch server.
+    * @param {boolean} useTLS - Whether to use HTTPS.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} index - The index to search.
+    * @param {string} query - The search query (JSON).
+    * @returns {string} - The response body.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/elasticsearch');
+    * let c = m.ElasticsearchClient();
+    * let hits = c.Search('localhost', 9200, false, '', '', 'users', '{"query":{"match_all":{}}}');
+    */
+    Search(host, port, useTLS, username, password, index, query) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} ElasticsearchInfo
+ * @description ElasticsearchInfo contains information about an Elasticsearch or OpenSearch server (Name, ClusterName, Version, Distribution, Tagline).
+ */
+const ElasticsearchInfo = {};
+
+module.exports = {
+    ElasticsearchClient: ElasticsearchClient,
+};
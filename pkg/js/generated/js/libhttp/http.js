@@ -0,0 +1,161 @@
+/** @module http */
+
+/**
+ * @class
+ * @classdesc Client is a HTTP client for making requests to web servers. Internally client uses the shared vulmap http client pool and respects proxy, rate limit, redirect, custom header and network policy options. Requests share the rate limit of the engine. Cookies are shared between all requests made by the same client.
+ */
+class Client {
+    /**
+    * @method
+    * @description Do sends the request and returns the response
+    * @param {Request} req - The request to send.
+    * @returns {Response} - The response received from the server.
+    * @throws {error} - The error encountered while sending the request.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * let req = m.Request();
+    * req.Method = 'PUT';
+    * req.URL = 'http://localhost/api/users/1';
+    * req.Headers = {'Content-Type': 'application/json'};
+    * req.Body = '{"name": "vulmap"}';
+    * let resp = c.Do(req);
+    */
+    Do(req) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Get makes a GET request to the url and returns the response
+    * @param {string} url - The url to request.
+    * @returns {Response} - The response received from the server.
+    * @throws {error} - The error encountered while sending the request.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * let resp = c.Get('http://localhost/login');
+    */
+    Get(url) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Post makes a POST request to the url with the body and content type
+    * @param {string} url - The url to request.
+    * @param {string} contentType - The content type of the body.
+    * @param {string} body - The body of the request.
+    * @returns {Response} - The response received from the server.
+    * @throws {error} - The error encountered while sending the request.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * let resp = c.Post('http://localhost/login', 'application/x-www-form-urlencoded', 'username=admin&password=admin');
+    */
+    Post(url, contentType, body) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Request makes a request with the method, url, headers and body
+    * @param {string} method - The HTTP method of the request.
+    * @param {string} url - The url to request.
+    * @param {Object} headers - The headers of the request.
+    * @param {string} body - The body of the request.
+    * @returns {Response} - The response received from the server.
+    * @throws {error} - The error encountered while sending the request.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * let resp = c.Request('DELETE', 'http://localhost/api/users/1', {'Authorization': 'Bearer token'}, '');
+    */
+    Request(method, url, headers, body) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description SetHeader sets a header sent with every request made by the client
+    * @param {string} key - The name of the header.
+    * @param {string} value - The value of the header.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * c.SetHeader('Authorization', 'Bearer token');
+    */
+    SetHeader(key, value) {
+        // implemented in go
+    };
+};
+
+/**
+ * @class
+ * @classdesc Request is a HTTP request which can be sent using Client.Do
+ * @property {string} Method - The HTTP method of the request (default GET).
+ * @property {string} URL - The URL of the request.
+ * @property {Object} Headers - The headers of the request.
+ * @property {string} Body - The body of the request.
+ */
+class Request {
+};
+
+/**
+ * @class
+ * @classdesc Response is a HTTP response received from a server
+ * @property {number} StatusCode - The status code of the response.
+ * @property {string} Status - The status line of the response.
+ * @property {Object} Headers - The headers of the response.
+ * @property {string} Body - The body of the response.
+ * @property {string} URL - The URL of the response, redirects are only followed with the follow redirects options of vulmap (-fr, -fhr).
+ * @property {number} ContentLength - The length of the response body.
+ */
+class Response {
+    /**
+    * @method
+    * @description Cookies returns the cookies set by the response
+    * @returns {Object} - The cookies set by the response.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * let cookies = c.Get('http://localhost/login').Cookies();
+    */
+    Cookies() {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetHeader returns the value of a response header (case-insensitive)
+    * @param {string} name - The name of the header.
+    * @returns {string} - The value of the header.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * let server = c.Get('http://localhost').GetHeader('server');
+    */
+    GetHeader(name) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description JSON decodes the body of the response as JSON
+    * @returns {Object} - The decoded body.
+    * @throws {error} - The error encountered while decoding the body.
+    * @example
+    * let m = require('vulmap/http');
+    * let c = m.Client();
+    * let data = c.Get('http://localhost/api/users').JSON();
+    */
+    JSON() {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    Client: Client,
+    Request: Request,
+    Response: Response,
+};
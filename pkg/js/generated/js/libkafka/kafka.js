@@ -0,0 +1,97 @@
+/** @module kafka */
+
+/**
+ * @class
+ * @classdesc KafkaClient is a client for Kafka brokers.
+ */
+class KafkaClient {
+    /**
+    * @method
+    * @description IsKafka checks if the given host is running a Kafka broker (>= 0.10.0.0).
+    * @param {string} host - The host of the kafka broker.
+    * @param {number} port - The port of the kafka broker.
+    * @returns {boolean} - Whether the host is running a Kafka broker.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/kafka');
+    * let c = m.KafkaClient();
+    * let isKafka = c.IsKafka('localhost', 9092);
+    */
+    IsKafka(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the topics of the broker can be listed without authentication.
+    * @param {string} host - The host of the kafka broker.
+    * @param {number} port - The port of the kafka broker.
+    * @returns {boolean} - Whether topics can be listed without authentication.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/kafka');
+    * let c = m.KafkaClient();
+    * let open = c.IsUnauthenticated('localhost', 9092);
+    */
+    IsUnauthenticated(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect tries to connect to the broker with the given credentials using the SASL PLAIN mechanism.
+    * @param {string} host - The host of the kafka broker.
+    * @param {number} port - The port of the kafka broker.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {boolean} - Whether the credentials were accepted.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/kafka');
+    * let c = m.KafkaClient();
+    * let ok = c.Connect('localhost', 9092, 'admin', 'admin-secret');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListTopics returns the topics of the cluster. If username is not empty the connection is authenticated using the SASL PLAIN mechanism.
+    * @param {string} host - The host of the kafka broker.
+    * @param {number} port - The port of the kafka broker.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {string[]} - The topics of the cluster.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/kafka');
+    * let c = m.KafkaClient();
+    * let topics = c.ListTopics('localhost', 9092, '', '');
+    */
+    ListTopics(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListBrokers returns the addresses of the brokers of the cluster. If username is not empty the connection is authenticated using the SASL PLAIN mechanism.
+    * @param {string} host - The host of the kafka broker.
+    * @param {number} port - The port of the kafka broker.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {string[]} - The addresses of the brokers.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/kafka');
+    * let c = m.KafkaClient();
+    * let brokers = c.ListBrokers('localhost', 9092, '', '');
+    */
+    ListBrokers(host, port, username, password) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    KafkaClient: KafkaClient,
+};
@@ -0,0 +1,92 @@
+/** @module memcached */
+
+/**
+ * @class
+ * @classdesc MemcachedClient is a client for Memcached servers.
+ */
+class MemcachedClient {
+    /**
+    * @method
+    * @description IsMemcached checks if the given host is running a Memcached server.
+    * @param {string} host - The host of the memcached server.
+    * @param {number} port - The port of the memcached server.
+    * @returns {string} - The version of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/memcached');
+    * let c = m.MemcachedClient();
+    * let version = c.IsMemcached('localhost', 11211);
+    */
+    IsMemcached(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the Memcached server allows reading statistics without authentication.
+    * @param {string} host - The host of the memcached server.
+    * @param {number} port - The port of the memcached server.
+    * @returns {boolean} - Whether statistics can be read without authentication.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/memcached');
+    * let c = m.MemcachedClient();
+    * let open = c.IsUnauthenticated('localhost', 11211);
+    */
+    IsUnauthenticated(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Stats returns the general statistics of the Memcached server.
+    * @param {string} host - The host of the memcached server.
+    * @param {number} port - The port of the memcached server.
+    * @returns {Object} - The statistics of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/memcached');
+    * let c = m.MemcachedClient();
+    * let stats = c.Stats('localhost', 11211);
+    */
+    Stats(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListKeys returns the keys stored in the Memcached server using the lru_crawler metadump command (memcached >= 1.4.31).
+    * @param {string} host - The host of the memcached server.
+    * @param {number} port - The port of the memcached server.
+    * @returns {string[]} - The keys stored in the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/memcached');
+    * let c = m.MemcachedClient();
+    * let keys = c.ListKeys('localhost', 11211);
+    */
+    ListKeys(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Get returns the value of a key stored in the Memcached server.
+    * @param {string} host - The host of the memcached server.
+    * @param {number} port - The port of the memcached server.
+    * @param {string} key - The key to get.
+    * @returns {string} - The value of the key.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/memcached');
+    * let c = m.MemcachedClient();
+    * let value = c.Get('localhost', 11211, 'session');
+    */
+    Get(host, port, key) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    MemcachedClient: MemcachedClient,
+};
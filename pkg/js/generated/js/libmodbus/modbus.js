@@ -0,0 +1,172 @@
+/** @module modbus */
+
+/**
+ * @class
+ * @classdesc ModbusClient is a client for Modbus/TCP devices. Only read operations are allowed by default, write operations must be explicitly enabled using EnableWrites.
+ */
+class ModbusClient {
+    /**
+    * @method
+    * @description EnableWrites allows write operations (e.g. WriteSingleCoil) to be performed by the client.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * c.EnableWrites();
+    */
+    EnableWrites() {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsModbus checks if the given host is running a Modbus/TCP server.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @returns {boolean} - Whether the host is running a Modbus/TCP server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * let isModbus = c.IsModbus('localhost', 502);
+    */
+    IsModbus(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ReadCoils reads quantity coils starting at address from the unit.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @param {number} unitID - The unit identifier of the device.
+    * @param {number} address - The starting address.
+    * @param {number} quantity - The number of items to read.
+    * @returns {boolean[]} - The values of the coils.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * let coils = c.ReadCoils('localhost', 502, 1, 0, 8);
+    */
+    ReadCoils(host, port, unitID, address, quantity) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ReadDiscreteInputs reads quantity discrete inputs starting at address from the unit.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @param {number} unitID - The unit identifier of the device.
+    * @param {number} address - The starting address.
+    * @param {number} quantity - The number of items to read.
+    * @returns {boolean[]} - The values of the discrete inputs.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * let inputs = c.ReadDiscreteInputs('localhost', 502, 1, 0, 8);
+    */
+    ReadDiscreteInputs(host, port, unitID, address, quantity) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ReadHoldingRegisters reads quantity holding registers starting at address from the unit.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @param {number} unitID - The unit identifier of the device.
+    * @param {number} address - The starting address.
+    * @param {number} quantity - The number of items to read.
+    * @returns {number[]} - The values of the registers.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * let registers = c.ReadHoldingRegisters('localhost', 502, 1, 0, 10);
+    */
+    ReadHoldingRegisters(host, port, unitID, address, quantity) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ReadInputRegisters reads quantity input registers starting at address from the unit.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @param {number} unitID - The unit identifier of the device.
+    * @param {number} address - The starting address.
+    * @param {number} quantity - The number of items to read.
+    * @returns {number[]} - The values of the registers.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * let registers = c.ReadInputRegisters('localhost', 502, 1, 0, 10);
+    */
+    ReadInputRegisters(host, port, unitID, address, quantity) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ReadDeviceIdentification reads the basic and regular device identification objects of the unit.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @param {number} unitID - The unit identifier of the device.
+    * @returns {Object} - The device identification objects (e.g. VendorName, ProductCode, MajorMinorRevision).
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * let id = c.ReadDeviceIdentification('localhost', 502, 1);
+    */
+    ReadDeviceIdentification(host, port, unitID) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description WriteSingleCoil writes a single coil at address of the unit. Writes must be enabled using EnableWrites.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @param {number} unitID - The unit identifier of the device.
+    * @param {number} address - The address of the coil.
+    * @param {boolean} value - The value to write.
+    * @returns {boolean} - Whether the write succeeded.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * c.EnableWrites();
+    * let ok = c.WriteSingleCoil('localhost', 502, 1, 0, true);
+    */
+    WriteSingleCoil(host, port, unitID, address, value) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description WriteSingleRegister writes a single holding register at address of the unit. Writes must be enabled using EnableWrites.
+    * @param {string} host - The host of the modbus device.
+    * @param {number} port - The port of the modbus device.
+    * @param {number} unitID - The unit identifier of the device.
+    * @param {number} address - The address of the register.
+    * @param {number} value - The value to write.
+    * @returns {boolean} - Whether the write succeeded.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/modbus');
+    * let c = m.ModbusClient();
+    * c.EnableWrites();
+    * let ok = c.WriteSingleRegister('localhost', 502, 1, 0, 42);
+    */
+    WriteSingleRegister(host, port, unitID, address, value) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    ModbusClient: ModbusClient,
+};
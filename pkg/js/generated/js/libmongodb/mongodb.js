@@ -0,0 +1,114 @@
+/** @module mongodb */
+
+/**
+ * @class
+ * @classdesc MongoDBClient is a client for MongoDB servers.
+ */
+class MongoDBClient {
+    /**
+    * @method
+    * @description IsMongoDB checks if the given host is running a MongoDB server.
+    * @param {string} host - The host of the mongodb server.
+    * @param {number} port - The port of the mongodb server.
+    * @returns {boolean} - Whether the host is running a MongoDB server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mongodb');
+    * let c = m.MongoDBClient();
+    * let isMongo = c.IsMongoDB('localhost', 27017);
+    */
+    IsMongoDB(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetVersion returns the version of the MongoDB server using the buildInfo command.
+    * @param {string} host - The host of the mongodb server.
+    * @param {number} port - The port of the mongodb server.
+    * @returns {string} - The version of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mongodb');
+    * let c = m.MongoDBClient();
+    * let version = c.GetVersion('localhost', 27017);
+    */
+    GetVersion(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the databases can be listed without authentication.
+    * @param {string} host - The host of the mongodb server.
+    * @param {number} port - The port of the mongodb server.
+    * @returns {boolean} - Whether databases can be listed without authentication.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mongodb');
+    * let c = m.MongoDBClient();
+    * let open = c.IsUnauthenticated('localhost', 27017);
+    */
+    IsUnauthenticated(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect tries to connect to the server with the given credentials.
+    * @param {string} host - The host of the mongodb server.
+    * @param {number} port - The port of the mongodb server.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {boolean} - Whether the credentials were accepted.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mongodb');
+    * let c = m.MongoDBClient();
+    * let ok = c.Connect('localhost', 27017, 'admin', 'admin');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListDatabases returns the names of the databases of the server. If username is not empty the connection is authenticated against the admin database.
+    * @param {string} host - The host of the mongodb server.
+    * @param {number} port - The port of the mongodb server.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {string[]} - The names of the databases.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mongodb');
+    * let c = m.MongoDBClient();
+    * let databases = c.ListDatabases('localhost', 27017, '', '');
+    */
+    ListDatabases(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListCollections returns the names of the collections of a database.
+    * @param {string} host - The host of the mongodb server.
+    * @param {number} port - The port of the mongodb server.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} database - The name of the database.
+    * @returns {string[]} - The names of the collections.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mongodb');
+    * let c = m.MongoDBClient();
+    * let collections = c.ListCollections('localhost', 27017, '', '', 'admin');
+    */
+    ListCollections(host, port, username, password, database) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    MongoDBClient: MongoDBClient,
+};
@@ -0,0 +1,81 @@
+/** @module mqtt */
+
+/**
+ * @class
+ * @classdesc MQTTClient is a client for MQTT brokers.
+ */
+class MQTTClient {
+    /**
+    * @method
+    * @description IsMQTT checks if the given host is running a MQTT broker. Brokers refusing unauthenticated connections are also detected.
+    * @param {string} host - The host of the mqtt broker.
+    * @param {number} port - The port of the mqtt broker.
+    * @returns {boolean} - Whether the host is running a MQTT broker.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mqtt');
+    * let c = m.MQTTClient();
+    * let isMQTT = c.IsMQTT('localhost', 1883);
+    */
+    IsMQTT(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the broker accepts connections without credentials.
+    * @param {string} host - The host of the mqtt broker.
+    * @param {number} port - The port of the mqtt broker.
+    * @returns {boolean} - Whether the broker accepts connections without credentials.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mqtt');
+    * let c = m.MQTTClient();
+    * let open = c.IsUnauthenticated('localhost', 1883);
+    */
+    IsUnauthenticated(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Connect tries to connect to the broker with the given credentials.
+    * @param {string} host - The host of the mqtt broker.
+    * @param {number} port - The port of the mqtt broker.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @returns {boolean} - Whether the connection was accepted.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mqtt');
+    * let c = m.MQTTClient();
+    * let ok = c.Connect('localhost', 1883, 'admin', 'admin');
+    */
+    Connect(host, port, username, password) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Subscribe connects to the broker with the given credentials, subscribes to the topic filter and returns messages received until the timeout expires.
+    * @param {string} host - The host of the mqtt broker.
+    * @param {number} port - The port of the mqtt broker.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} topic - The topic filter to subscribe to (e.g. #).
+    * @param {number} timeout - The time to wait for messages in seconds.
+    * @returns {Object} - A map of topic and last received payload.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/mqtt');
+    * let c = m.MQTTClient();
+    * let messages = c.Subscribe('localhost', 1883, '', '', '#', 5);
+    */
+    Subscribe(host, port, username, password, topic, timeout) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    MQTTClient: MQTTClient,
+};
@@ -0,0 +1,49 @@
+/** @module s7comm */
+
+/**
+ * @class
+ * @classdesc S7commClient is a client for Siemens S7 PLCs. Only read operations of the system status list are performed.
+ */
+class S7commClient {
+    /**
+    * @method
+    * @description IsS7comm checks if the given host is running a S7comm server.
+    * @param {string} host - The host of the s7 plc.
+    * @param {number} port - The port of the s7 plc.
+    * @returns {boolean} - Whether the host is running a S7comm server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/s7comm');
+    * let c = m.S7commClient();
+    * let isS7 = c.IsS7comm('localhost', 102);
+    */
+    IsS7comm(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetModuleIdentification returns the module and component identification of the PLC.
+    * @param {string} host - The host of the s7 plc.
+    * @param {number} port - The port of the s7 plc.
+    * @returns {S7Info} - The identification of the PLC.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/s7comm');
+    * let c = m.S7commClient();
+    * let info = c.GetModuleIdentification('localhost', 102);
+    */
+    GetModuleIdentification(host, port) {
+        // implemented in go
+    };
+};
+
+/**
+ * @typedef {object} S7Info
+ * @description S7Info contains identification information about a S7 PLC (Module, BasicHardware, Version, SystemName, ModuleType, PlantIdentification, Copyright, SerialNumber).
+ */
+const S7Info = {};
+
+module.exports = {
+    S7commClient: S7commClient,
+};
@@ -0,0 +1,143 @@
+/** @module snmp */
+
+/**
+ * @class
+ * @classdesc SNMPClient is a client for SNMP agents. Only read operations are allowed by default, write operations must be explicitly enabled using EnableWrites.
+ */
+class SNMPClient {
+    /**
+    * @method
+    * @description EnableWrites allows write operations (e.g. Set) to be performed by the client.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * c.EnableWrites();
+    */
+    EnableWrites() {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsSNMP checks if the given host is running a SNMP agent accepting the community using SNMPv2c.
+    * @param {string} host - The host of the snmp agent.
+    * @param {number} port - The port of the snmp agent.
+    * @param {string} community - The community string.
+    * @returns {string} - The system description of the agent.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * let sysDescr = c.IsSNMP('localhost', 161, 'public');
+    */
+    IsSNMP(host, port, community) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Get returns the values of the oids using SNMP version 1 or 2c and the community.
+    * @param {string} host - The host of the snmp agent.
+    * @param {number} port - The port of the snmp agent.
+    * @param {string} version - The SNMP version (1 or 2c).
+    * @param {string} community - The community string.
+    * @param {string[]} oids - The oids to get.
+    * @returns {Object} - A map of oid and value.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * let values = c.Get('localhost', 161, '2c', 'public', ['1.3.6.1.2.1.1.5.0']);
+    */
+    Get(host, port, version, community, oids) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Walk returns the values of the subtree of the oid using SNMP version 1 or 2c and the community.
+    * @param {string} host - The host of the snmp agent.
+    * @param {number} port - The port of the snmp agent.
+    * @param {string} version - The SNMP version (1 or 2c).
+    * @param {string} community - The community string.
+    * @param {string} oid - The root oid of the subtree.
+    * @returns {Object} - A map of oid and value.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * let system = c.Walk('localhost', 161, '2c', 'public', '1.3.6.1.2.1.1');
+    */
+    Walk(host, port, version, community, oid) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetV3 returns the values of the oids using SNMPv3 with the user credentials.
+    * @param {string} host - The host of the snmp agent.
+    * @param {number} port - The port of the snmp agent.
+    * @param {string} username - The SNMPv3 user name.
+    * @param {string} authProtocol - The authentication protocol (MD5, SHA, SHA224, SHA256, SHA384, SHA512 or empty).
+    * @param {string} authPassword - The authentication password.
+    * @param {string} privProtocol - The privacy protocol (DES, AES, AES192, AES256, AES192C, AES256C or empty).
+    * @param {string} privPassword - The privacy password.
+    * @param {string[]} oids - The oids to get.
+    * @returns {Object} - A map of oid and value.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * let values = c.GetV3('localhost', 161, 'admin', 'SHA', 'authpass', 'AES', 'privpass', ['1.3.6.1.2.1.1.5.0']);
+    */
+    GetV3(host, port, username, authProtocol, authPassword, privProtocol, privPassword, oids) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description WalkV3 returns the values of the subtree of the oid using SNMPv3 with the user credentials.
+    * @param {string} host - The host of the snmp agent.
+    * @param {number} port - The port of the snmp agent.
+    * @param {string} username - The SNMPv3 user name.
+    * @param {string} authProtocol - The authentication protocol (MD5, SHA, SHA224, SHA256, SHA384, SHA512 or empty).
+    * @param {string} authPassword - The authentication password.
+    * @param {string} privProtocol - The privacy protocol (DES, AES, AES192, AES256, AES192C, AES256C or empty).
+    * @param {string} privPassword - The privacy password.
+    * @param {string} oid - The root oid of the subtree.
+    * @returns {Object} - A map of oid and value.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * let system = c.WalkV3('localhost', 161, 'admin', 'SHA', 'authpass', '', '', '1.3.6.1.2.1.1');
+    */
+    WalkV3(host, port, username, authProtocol, authPassword, privProtocol, privPassword, oid) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description Set sets the string value of the oid using SNMP version 1 or 2c and the community. Writes must be enabled using EnableWrites.
+    * @param {string} host - The host of the snmp agent.
+    * @param {number} port - The port of the snmp agent.
+    * @param {string} version - The SNMP version (1 or 2c).
+    * @param {string} community - The community string.
+    * @param {string} oid - The oid to set.
+    * @param {string} value - The string value to set.
+    * @returns {boolean} - Whether the write succeeded.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/snmp');
+    * let c = m.SNMPClient();
+    * c.EnableWrites();
+    * let ok = c.Set('localhost', 161, '2c', 'private', '1.3.6.1.2.1.1.6.0', 'lab');
+    */
+    Set(host, port, version, community, oid, value) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    SNMPClient: SNMPClient,
+};
@@ -0,0 +1,114 @@
+/** @module zookeeper */
+
+/**
+ * @class
+ * @classdesc ZooKeeperClient is a client for ZooKeeper servers.
+ */
+class ZooKeeperClient {
+    /**
+    * @method
+    * @description IsZooKeeper checks if the given host is running a ZooKeeper server.
+    * @param {string} host - The host of the zookeeper server.
+    * @param {number} port - The port of the zookeeper server.
+    * @returns {boolean} - Whether the host is running a ZooKeeper server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/zookeeper');
+    * let c = m.ZooKeeperClient();
+    * let isZK = c.IsZooKeeper('localhost', 2181);
+    */
+    IsZooKeeper(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetVersion returns the version of the ZooKeeper server using the srvr command.
+    * @param {string} host - The host of the zookeeper server.
+    * @param {number} port - The port of the zookeeper server.
+    * @returns {string} - The version of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/zookeeper');
+    * let c = m.ZooKeeperClient();
+    * let version = c.GetVersion('localhost', 2181);
+    */
+    GetVersion(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description FourLetterWord sends a four letter word command to the ZooKeeper server and returns the response.
+    * @param {string} host - The host of the zookeeper server.
+    * @param {number} port - The port of the zookeeper server.
+    * @param {string} command - The four letter word command (e.g. stat, envi, conf).
+    * @returns {string} - The response of the server.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/zookeeper');
+    * let c = m.ZooKeeperClient();
+    * let env = c.FourLetterWord('localhost', 2181, 'envi');
+    */
+    FourLetterWord(host, port, command) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description IsUnauthenticated checks if the root znode can be listed without authentication.
+    * @param {string} host - The host of the zookeeper server.
+    * @param {number} port - The port of the zookeeper server.
+    * @returns {boolean} - Whether the root znode can be listed without authentication.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/zookeeper');
+    * let c = m.ZooKeeperClient();
+    * let open = c.IsUnauthenticated('localhost', 2181);
+    */
+    IsUnauthenticated(host, port) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description ListChildren returns the children of a znode path. If username is not empty the session is authenticated using the digest scheme.
+    * @param {string} host - The host of the zookeeper server.
+    * @param {number} port - The port of the zookeeper server.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} path - The znode path.
+    * @returns {string[]} - The children of the znode.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/zookeeper');
+    * let c = m.ZooKeeperClient();
+    * let children = c.ListChildren('localhost', 2181, '', '', '/');
+    */
+    ListChildren(host, port, username, password, path) {
+        // implemented in go
+    };
+
+    /**
+    * @method
+    * @description GetData returns the data of a znode path. If username is not empty the session is authenticated using the digest scheme.
+    * @param {string} host - The host of the zookeeper server.
+    * @param {number} port - The port of the zookeeper server.
+    * @param {string} username - The username for authentication.
+    * @param {string} password - The password for authentication.
+    * @param {string} path - The znode path.
+    * @returns {string} - The data of the znode.
+    * @throws {error} - The error encountered during the request.
+    * @example
+    * let m = require('vulmap/zookeeper');
+    * let c = m.ZooKeeperClient();
+    * let data = c.GetData('localhost', 2181, '', '', '/zookeeper/config');
+    */
+    GetData(host, port, username, password, path) {
+        // implemented in go
+    };
+};
+
+module.exports = {
+    ZooKeeperClient: ZooKeeperClient,
+};
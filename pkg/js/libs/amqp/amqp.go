@@ -0,0 +1,263 @@
+package amqp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// protocolHeader is the AMQP 0-9-1 protocol header sent by clients
+var protocolHeader = []byte{'A', 'M', 'Q', 'P', 0, 0, 9, 1}
+
+// AMQPClient is a client for AMQP 0-9-1 brokers (e.g. RabbitMQ).
+//
+// Internally client uses github.com/rabbitmq/amqp091-go driver.
+type AMQPClient struct{}
+
+// AMQPInfo contains information about an AMQP broker.
+type AMQPInfo struct {
+	// Product is the product name of the broker (e.g. RabbitMQ)
+	Product string
+	// Version is the version of the broker
+	Version string
+	// Platform is the platform of the broker (e.g. Erlang/OTP 26.1)
+	Platform string
+	// Mechanisms are the SASL mechanisms supported by the broker
+	Mechanisms []string
+}
+
+// QueueInfo contains information about a queue.
+type QueueInfo struct {
+	// Name is the name of the queue
+	Name string
+	// Messages is the number of messages ready in the queue
+	Messages int
+	// Consumers is the number of consumers of the queue
+	Consumers int
+}
+
+// IsAMQP checks if the given host is running an AMQP 0-9-1 broker.
+//
+// Returns the information advertised by the broker in the connection.start method.
+func (c *AMQPClient) IsAMQP(host string, port int) (*AMQPInfo, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+
+	if _, err := conn.Write(protocolHeader); err != nil {
+		return nil, err
+	}
+	// frame: type(1) channel(2) size(4)
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	// brokers not supporting the requested version reply with their protocol header
+	if bytes.HasPrefix(header, []byte("AMQP")) {
+		return &AMQPInfo{}, nil
+	}
+	size := binary.BigEndian.Uint32(header[3:7])
+	if header[0] != 1 || size < 6 || size > 1024*1024 {
+		return nil, fmt.Errorf("not an amqp broker")
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return nil, err
+	}
+	// connection.start: class-id 10, method-id 10
+	if binary.BigEndian.Uint16(payload[0:2]) != 10 || binary.BigEndian.Uint16(payload[2:4]) != 10 {
+		return nil, fmt.Errorf("not an amqp broker")
+	}
+	reader := bytes.NewReader(payload[6:])
+	properties, err := readTable(reader)
+	if err != nil {
+		return nil, err
+	}
+	info := &AMQPInfo{
+		Product:  properties["product"],
+		Version:  properties["version"],
+		Platform: properties["platform"],
+	}
+	if mechanisms, err := readLongString(reader); err == nil {
+		info.Mechanisms = strings.Fields(mechanisms)
+	}
+	return info, nil
+}
+
+// IsUnauthenticated checks if the broker accepts the default guest
+// credentials or the ANONYMOUS mechanism.
+func (c *AMQPClient) IsUnauthenticated(host string, port int) (bool, error) {
+	info, err := c.IsAMQP(host, port)
+	if err != nil {
+		return false, err
+	}
+	for _, mechanism := range info.Mechanisms {
+		if mechanism == "ANONYMOUS" {
+			return true, nil
+		}
+	}
+	return c.Connect(host, port, "guest", "guest", "/")
+}
+
+// Connect tries to connect to the virtual host of the broker with the given credentials.
+//
+// Returns false and no error if the credentials were refused.
+func (c *AMQPClient) Connect(host string, port int, username, password, vhost string) (bool, error) {
+	conn, err := dial(host, port, username, password, vhost)
+	if err != nil {
+		if errors.Is(err, amqp.ErrCredentials) {
+			return false, nil
+		}
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
+
+// GetQueueInfo returns information about an existing queue in the virtual host
+// using given credentials.
+func (c *AMQPClient) GetQueueInfo(host string, port int, username, password, vhost, queue string) (*QueueInfo, error) {
+	conn, err := dial(host, port, username, password, vhost)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	defer channel.Close()
+
+	q, err := channel.QueueDeclarePassive(queue, false, false, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &QueueInfo{Name: q.Name, Messages: q.Messages, Consumers: q.Consumers}, nil
+}
+
+func dial(host string, port int, username, password, vhost string) (*amqp.Connection, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	if vhost == "" {
+		vhost = "/"
+	}
+	uri := amqp.URI{
+		Scheme:   "amqp",
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		Vhost:    vhost,
+	}
+	return amqp.DialConfig(uri.String(), amqp.Config{
+		Vhost: vhost,
+		Dial: func(network, addr string) (net.Conn, error) {
+			conn, err := protocolstate.Dialer.Dial(context.TODO(), network, addr)
+			if err != nil {
+				return nil, err
+			}
+			_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+			return conn, nil
+		},
+	})
+}
+
+// readTable reads an AMQP field table and returns its string fields
+func readTable(reader *bytes.Reader) (map[string]string, error) {
+	var size uint32
+	if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if int(size) > reader.Len() {
+		return nil, fmt.Errorf("invalid amqp table size %d", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+	table := bytes.NewReader(data)
+	fields := make(map[string]string)
+	for table.Len() > 0 {
+		nameLength, err := table.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		name := make([]byte, nameLength)
+		if _, err := io.ReadFull(table, name); err != nil {
+			return nil, err
+		}
+		kind, err := table.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		var skip int64
+		switch kind {
+		case 'S':
+			value, err := readLongString(table)
+			if err != nil {
+				return nil, err
+			}
+			fields[string(name)] = value
+			continue
+		case 'F':
+			if _, err := readTable(table); err != nil {
+				return nil, err
+			}
+			continue
+		case 't', 'b', 'B':
+			skip = 1
+		case 's', 'u':
+			skip = 2
+		case 'I', 'i', 'f':
+			skip = 4
+		case 'l', 'L', 'd', 'T':
+			skip = 8
+		default:
+			// unknown field type, remaining fields cannot be parsed
+			return fields, nil
+		}
+		if _, err := table.Seek(skip, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+func readLongString(reader *bytes.Reader) (string, error) {
+	var length uint32
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	if int(length) > reader.Len() {
+		return "", fmt.Errorf("invalid amqp string length %d", length)
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(reader, value); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
@@ -0,0 +1,292 @@
+package bacnet
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// BACnet device object properties
+const (
+	propertyApplicationSoftwareVersion = 12
+	propertyDescription                = 28
+	propertyFirmwareRevision           = 44
+	propertyLocation                   = 58
+	propertyModelName                  = 70
+	propertyObjectName                 = 77
+	propertyVendorIdentifier           = 120
+	propertyVendorName                 = 121
+)
+
+// BACnet APDU types and application tags
+const (
+	apduComplexAck     = 0x30
+	apduError          = 0x50
+	apduReject         = 0x60
+	apduAbort          = 0x70
+	tagUnsignedInteger = 2
+	tagSignedInteger   = 3
+	tagCharacterString = 7
+	serviceReadProp    = 0x0c
+	invokeID           = 0x01
+)
+
+// BACnetClient is a client for BACnet/IP devices.
+//
+// Internally client uses ReadProperty requests on the
+// device object and only performs read operations.
+type BACnetClient struct{}
+
+// BACnetDeviceInfo contains information about a BACnet device.
+type BACnetDeviceInfo struct {
+	// VendorID is the vendor identifier of the device
+	VendorID string
+	// VendorName is the vendor name of the device
+	VendorName string
+	// ObjectName is the name of the device object
+	ObjectName string
+	// ModelName is the model name of the device
+	ModelName string
+	// FirmwareRevision is the firmware revision of the device
+	FirmwareRevision string
+	// ApplicationSoftware is the application software version of the device
+	ApplicationSoftware string
+	// Description is the description of the device
+	Description string
+	// Location is the location of the device
+	Location string
+}
+
+// IsBACnet checks if the given host is running a BACnet/IP device.
+func (c *BACnetClient) IsBACnet(host string, port int) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// an error response for the property still identifies a BACnet device
+	if _, err := readProperty(conn, propertyVendorIdentifier); err != nil {
+		if _, ok := err.(*propertyError); ok {
+			return true, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetDeviceInfo returns the information of the device object of a BACnet/IP device.
+func (c *BACnetClient) GetDeviceInfo(host string, port int) (*BACnetDeviceInfo, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	info := &BACnetDeviceInfo{}
+	properties := []struct {
+		id    byte
+		value *string
+	}{
+		{propertyVendorIdentifier, &info.VendorID},
+		{propertyVendorName, &info.VendorName},
+		{propertyObjectName, &info.ObjectName},
+		{propertyModelName, &info.ModelName},
+		{propertyFirmwareRevision, &info.FirmwareRevision},
+		{propertyApplicationSoftwareVersion, &info.ApplicationSoftware},
+		{propertyDescription, &info.Description},
+		{propertyLocation, &info.Location},
+	}
+	for i, property := range properties {
+		value, err := readProperty(conn, property.id)
+		if err != nil {
+			// device does not respond to the first request
+			if _, ok := err.(*propertyError); !ok && i == 0 {
+				return nil, err
+			}
+			continue
+		}
+		*property.value = value
+	}
+	return info, nil
+}
+
+// propertyError is returned when the device responds with an error for a property
+type propertyError struct {
+	apduType byte
+}
+
+func (e *propertyError) Error() string {
+	return fmt.Sprintf("bacnet property read failed (apdu type 0x%02x)", e.apduType)
+}
+
+// readProperty reads a property of the wildcard device object instance
+func readProperty(conn net.Conn, property byte) (string, error) {
+	request := []byte{
+		// BVLC: BACnet/IP, original-unicast-NPDU, length
+		0x81, 0x0a, 0x00, 0x11,
+		// NPDU: version, expecting reply
+		0x01, 0x04,
+		// APDU: confirmed request, max segments/apdu, invoke id, read property
+		0x00, 0x05, invokeID, serviceReadProp,
+		// context tag 0: device object, instance 4194303 (wildcard)
+		0x0c, 0x02, 0x3f, 0xff, 0xff,
+		// context tag 1: property identifier
+		0x19, property,
+	}
+	if _, err := conn.Write(request); err != nil {
+		return "", err
+	}
+	buffer := make([]byte, 1500)
+	n, err := conn.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+	response := buffer[:n]
+	if len(response) < 6 || response[0] != 0x81 {
+		return "", fmt.Errorf("not a bacnet device")
+	}
+	offset, err := skipNPDU(response, 4)
+	if err != nil {
+		return "", err
+	}
+	if offset+3 > len(response) {
+		return "", fmt.Errorf("invalid bacnet response")
+	}
+	switch apduType := response[offset] & 0xf0; apduType {
+	case apduComplexAck:
+	case apduError, apduReject, apduAbort:
+		return "", &propertyError{apduType: apduType}
+	default:
+		return "", fmt.Errorf("unexpected bacnet apdu type 0x%02x", apduType)
+	}
+	// complex ack: type, invoke id, service, object id (5), property id (2), opening tag 3
+	offset += 3 + 5 + 2
+	if offset >= len(response) || response[offset] != 0x3e {
+		return "", fmt.Errorf("invalid bacnet read property response")
+	}
+	return decodeValue(response[offset+1:])
+}
+
+// skipNPDU returns the offset of the APDU after the NPDU
+func skipNPDU(data []byte, offset int) (int, error) {
+	if offset+2 > len(data) {
+		return 0, fmt.Errorf("invalid bacnet npdu")
+	}
+	control := data[offset+1]
+	offset += 2
+	// destination specifier: net(2), len(1), address
+	if control&0x20 != 0 {
+		if offset+3 > len(data) {
+			return 0, fmt.Errorf("invalid bacnet npdu")
+		}
+		offset += 3 + int(data[offset+2])
+	}
+	// source specifier: net(2), len(1), address
+	if control&0x08 != 0 {
+		if offset+3 > len(data) {
+			return 0, fmt.Errorf("invalid bacnet npdu")
+		}
+		offset += 3 + int(data[offset+2])
+	}
+	// hop count
+	if control&0x20 != 0 {
+		offset++
+	}
+	// network layer message type
+	if control&0x80 != 0 {
+		return 0, fmt.Errorf("unexpected bacnet network layer message")
+	}
+	return offset, nil
+}
+
+// decodeValue decodes an application tagged value as a string
+func decodeValue(data []byte) (string, error) {
+	if len(data) < 1 {
+		return "", fmt.Errorf("invalid bacnet value")
+	}
+	tag, length, offset := data[0]>>4, int(data[0]&0x07), 1
+	if length == 5 {
+		if len(data) < 2 {
+			return "", fmt.Errorf("invalid bacnet value")
+		}
+		length, offset = int(data[1]), 2
+		switch length {
+		case 254:
+			if len(data) < 4 {
+				return "", fmt.Errorf("invalid bacnet value")
+			}
+			length, offset = int(binary.BigEndian.Uint16(data[2:])), 4
+		case 255:
+			return "", fmt.Errorf("unsupported bacnet value length")
+		}
+	}
+	if offset+length > len(data) {
+		return "", fmt.Errorf("invalid bacnet value")
+	}
+	value := data[offset : offset+length]
+	switch tag {
+	case tagUnsignedInteger:
+		var number uint64
+		for _, b := range value {
+			number = number<<8 | uint64(b)
+		}
+		return strconv.FormatUint(number, 10), nil
+	case tagSignedInteger:
+		var number int64
+		for i, b := range value {
+			if i == 0 && b&0x80 != 0 {
+				number = -1
+			}
+			number = number<<8 | int64(b)
+		}
+		return strconv.FormatInt(number, 10), nil
+	case tagCharacterString:
+		if len(value) < 1 {
+			return "", nil
+		}
+		// character set: 0 = UTF-8, 4 = UCS-2, 5 = ISO 8859-1
+		switch value[0] {
+		case 4:
+			chars := make([]uint16, 0, len(value[1:])/2)
+			for i := 1; i+1 < len(value); i += 2 {
+				chars = append(chars, binary.BigEndian.Uint16(value[i:]))
+			}
+			return string(utf16.Decode(chars)), nil
+		case 5:
+			runes := make([]rune, 0, len(value)-1)
+			for _, b := range value[1:] {
+				runes = append(runes, rune(b))
+			}
+			return string(runes), nil
+		default:
+			return strings.TrimRight(string(value[1:]), "\x00"), nil
+		}
+	default:
+		return fmt.Sprintf("%x", value), nil
+	}
+}
+
+func dial(host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+	return conn, nil
+}
@@ -0,0 +1,187 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/khulnasoft-lab/retryablehttp-go"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
+)
+
+// maxResponseSize is the maximum size of a response body to read
+const maxResponseSize = 10 * 1024 * 1024
+
+// ElasticsearchClient is a client for Elasticsearch and OpenSearch servers.
+//
+// Internally client uses the REST API of the server over HTTP(S).
+type ElasticsearchClient struct{}
+
+// ElasticsearchInfo contains information about an Elasticsearch or OpenSearch server.
+type ElasticsearchInfo struct {
+	// Name is the name of the node
+	Name string
+	// ClusterName is the name of the cluster
+	ClusterName string
+	// Version is the version of the server
+	Version string
+	// Distribution is either elasticsearch or opensearch
+	Distribution string
+	// Tagline is the tagline returned by the server
+	Tagline string
+}
+
+// IsElasticsearch checks if the given host is running Elasticsearch or OpenSearch.
+//
+// Returns the information of the server if it responds to unauthenticated
+// requests, or an empty distribution with no error if it requires authentication.
+func (c *ElasticsearchClient) IsElasticsearch(host string, port int, useTLS bool) (*ElasticsearchInfo, error) {
+	return c.GetInfo(host, port, useTLS, "", "")
+}
+
+// GetInfo returns the information of the server using given credentials.
+func (c *ElasticsearchClient) GetInfo(host string, port int, useTLS bool, username, password string) (*ElasticsearchInfo, error) {
+	status, body, header, err := request(host, port, useTLS, username, password, http.MethodGet, "/", "")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		if strings.Contains(header.Get("WWW-Authenticate"), "elasticsearch") || strings.Contains(body, "security_exception") {
+			return &ElasticsearchInfo{Distribution: "elasticsearch"}, nil
+		}
+		return nil, fmt.Errorf("authentication required (status %d)", status)
+	}
+
+	response := struct {
+		Name        string `json:"name"`
+		ClusterName string `json:"cluster_name"`
+		Tagline     string `json:"tagline"`
+		Version     struct {
+			Number       string `json:"number"`
+			Distribution string `json:"distribution"`
+		} `json:"version"`
+	}{}
+	if err := json.Unmarshal([]byte(body), &response); err != nil || response.Version.Number == "" {
+		return nil, fmt.Errorf("not an elasticsearch server")
+	}
+	info := &ElasticsearchInfo{
+		Name:         response.Name,
+		ClusterName:  response.ClusterName,
+		Version:      response.Version.Number,
+		Distribution: response.Version.Distribution,
+		Tagline:      response.Tagline,
+	}
+	if info.Distribution == "" {
+		info.Distribution = "elasticsearch"
+	}
+	return info, nil
+}
+
+// IsUnauthenticated checks if the cluster can be queried without authentication.
+func (c *ElasticsearchClient) IsUnauthenticated(host string, port int, useTLS bool) (bool, error) {
+	status, _, _, err := request(host, port, useTLS, "", "", http.MethodGet, "/_cluster/health", "")
+	if err != nil {
+		return false, err
+	}
+	return status == http.StatusOK, nil
+}
+
+// ListIndices returns the names of the indices of the cluster using given credentials.
+func (c *ElasticsearchClient) ListIndices(host string, port int, useTLS bool, username, password string) ([]string, error) {
+	body, err := c.Query(host, port, useTLS, username, password, "/_cat/indices?format=json&h=index")
+	if err != nil {
+		return nil, err
+	}
+	var indices []struct {
+		Index string `json:"index"`
+	}
+	if err := json.Unmarshal([]byte(body), &indices); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, index.Index)
+	}
+	return names, nil
+}
+
+// Query makes a GET request to a path of the REST API using given credentials
+// and returns the response body.
+func (c *ElasticsearchClient) Query(host string, port int, useTLS bool, username, password, path string) (string, error) {
+	status, body, _, err := request(host, port, useTLS, username, password, http.MethodGet, path, "")
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return body, fmt.Errorf("unexpected status code %d", status)
+	}
+	return body, nil
+}
+
+// Search runs a search query (JSON) on an index using given credentials
+// and returns the response body.
+func (c *ElasticsearchClient) Search(host string, port int, useTLS bool, username, password, index, query string) (string, error) {
+	status, body, _, err := request(host, port, useTLS, username, password, http.MethodPost, "/"+strings.TrimPrefix(index, "/")+"/_search", query)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return body, fmt.Errorf("unexpected status code %d", status)
+	}
+	return body, nil
+}
+
+func request(host string, port int, useTLS bool, username, password, method, path, body string) (int, string, http.Header, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return 0, "", nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)), path)
+
+	var reqBody interface{}
+	if body != "" {
+		reqBody = body
+	}
+	req, err := retryablehttp.NewRequest(method, url, reqBody)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	options := protocolstate.GetOptions()
+	// no-op if the pool was already initialized by the engine
+	if err := httpclientpool.Init(options); err != nil {
+		return 0, "", nil, err
+	}
+	client, err := httpclientpool.Get(options, &httpclientpool.Configuration{})
+	if err != nil {
+		return 0, "", nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return resp.StatusCode, string(data), resp.Header, nil
+}
@@ -0,0 +1,238 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corpix/uarand"
+
+	"github.com/khulnasoft-lab/ratelimit"
+	"github.com/khulnasoft-lab/retryablehttp-go"
+	urlutil "github.com/khulnasoft-lab/utils/url"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
+)
+
+// defaultMaxResponseSize is the maximum size of response body read if not configured
+const defaultMaxResponseSize = 10 * 1024 * 1024
+
+var (
+	limiter     *ratelimit.Limiter
+	limiterOnce sync.Once
+)
+
+// Client is a HTTP client for making requests to web servers.
+//
+// Internally client uses the shared vulmap http client pool and respects
+// proxy, rate limit, redirect, custom header and network policy options.
+// Requests share the rate limit of the engine. Cookies are shared between
+// all requests made by the same client.
+type Client struct {
+	client  *retryablehttp.Client
+	headers map[string]string
+}
+
+// Request is a HTTP request which can be sent using Client.Do
+type Request struct {
+	// Method is the HTTP method of the request (default GET)
+	Method string
+	// URL is the URL of the request
+	URL string
+	// Headers contains the headers of the request
+	Headers map[string]string
+	// Body is the body of the request
+	Body string
+}
+
+// Response is a HTTP response received from a server
+type Response struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+	// Status is the status line of the response (e.g. 200 OK)
+	Status string
+	// Headers contains the headers of the response (multiple values are joined with comma)
+	Headers map[string]string
+	// Body is the body of the response
+	Body string
+	// URL is the URL of the response, redirects are only followed with the
+	// follow redirects options of vulmap (-fr, -fhr)
+	URL string
+	// ContentLength is the length of the response body
+	ContentLength int
+
+	header http.Header
+}
+
+// SetHeader sets a header sent with every request made by the client
+func (c *Client) SetHeader(key, value string) {
+	if c.headers == nil {
+		c.headers = make(map[string]string)
+	}
+	c.headers[key] = value
+}
+
+// Get makes a GET request to the url and returns the response
+func (c *Client) Get(url string) (*Response, error) {
+	return c.Do(&Request{Method: http.MethodGet, URL: url})
+}
+
+// Post makes a POST request to the url with the body and content type
+func (c *Client) Post(url, contentType, body string) (*Response, error) {
+	return c.Do(&Request{Method: http.MethodPost, URL: url, Body: body, Headers: map[string]string{"Content-Type": contentType}})
+}
+
+// Request makes a request with the method, url, headers and body
+func (c *Client) Request(method, url string, headers map[string]string, body string) (*Response, error) {
+	return c.Do(&Request{Method: method, URL: url, Headers: headers, Body: body})
+}
+
+// Do sends the request and returns the response
+func (c *Client) Do(req *Request) (*Response, error) {
+	if req == nil || req.URL == "" {
+		return nil, fmt.Errorf("request url cannot be empty")
+	}
+	parsed, err := urlutil.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !protocolstate.IsHostAllowed(parsed.Hostname()) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(parsed.Hostname())
+	}
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	options := protocolstate.GetOptions()
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body interface{}
+	if req.Body != "" {
+		body = req.Body
+	}
+	httpReq, err := retryablehttp.NewRequestFromURL(method, parsed, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("User-Agent", uarand.GetRandom())
+	for _, header := range options.CustomHeaders {
+		if key, value, ok := strings.Cut(header, ":"); ok {
+			setHeader(httpReq, key, value)
+		}
+	}
+	for key, value := range c.headers {
+		setHeader(httpReq, key, value)
+	}
+	for key, value := range req.Headers {
+		setHeader(httpReq, key, value)
+	}
+	// the imported session is sent with the requests, its cookies are seeded in the jar of the client
+	protocolstate.Session.SetHeaders(httpReq.Header, parsed.URL)
+	protocolstate.Session.SetCookies(c.client.HTTPClient.Jar, parsed.URL)
+
+	getLimiter().Take()
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	maxSize := int64(options.ResponseReadSize)
+	if maxSize <= 0 {
+		maxSize = defaultMaxResponseSize
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return nil, err
+	}
+	response := &Response{
+		StatusCode:    resp.StatusCode,
+		Status:        resp.Status,
+		Headers:       make(map[string]string, len(resp.Header)),
+		Body:          string(data),
+		URL:           resp.Request.URL.String(),
+		ContentLength: len(data),
+		header:        resp.Header,
+	}
+	for key, values := range resp.Header {
+		response.Headers[key] = strings.Join(values, ", ")
+	}
+	return response, nil
+}
+
+// init creates the underlying http client with a dedicated cookie jar
+func (c *Client) init() error {
+	if c.client != nil {
+		return nil
+	}
+	options := protocolstate.GetOptions()
+	// no-op if the pool was already initialized by the engine
+	if err := httpclientpool.Init(options); err != nil {
+		return err
+	}
+	client, err := httpclientpool.Get(options, &httpclientpool.Configuration{CookieReuse: true})
+	if err != nil {
+		return err
+	}
+	c.client = client
+	return nil
+}
+
+// GetHeader returns the value of a response header (case-insensitive)
+func (r *Response) GetHeader(name string) string {
+	return r.header.Get(name)
+}
+
+// JSON decodes the body of the response as JSON
+func (r *Response) JSON() (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(r.Body), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Cookies returns the cookies set by the response
+func (r *Response) Cookies() map[string]string {
+	cookies := make(map[string]string)
+	for _, cookie := range (&http.Response{Header: r.header}).Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+	return cookies
+}
+
+func setHeader(req *retryablehttp.Request, key, value string) {
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	req.Header.Set(key, value)
+	if strings.EqualFold(key, "Host") {
+		req.Request.Host = value
+	}
+}
+
+// getLimiter returns the rate limiter of the engine, or a rate limiter based on
+// the configured rate limits if the library is used without the engine
+func getLimiter() *ratelimit.Limiter {
+	if protocolstate.RateLimiter != nil {
+		return protocolstate.RateLimiter
+	}
+	limiterOnce.Do(func() {
+		options := protocolstate.GetOptions()
+		switch {
+		case options.RateLimitMinute > 0:
+			limiter = ratelimit.New(context.Background(), uint(options.RateLimitMinute), time.Minute)
+		case options.RateLimit > 0:
+			limiter = ratelimit.New(context.Background(), uint(options.RateLimit), time.Second)
+		default:
+			limiter = ratelimit.NewUnlimited(context.Background())
+		}
+	})
+	return limiter
+}
@@ -0,0 +1,136 @@
+package kafka
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
+	kafkanew "github.com/praetorian-inc/fingerprintx/pkg/plugins/services/kafka/kafkaNew"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// KafkaClient is a client for Kafka brokers.
+//
+// Internally client uses github.com/segmentio/kafka-go driver.
+type KafkaClient struct{}
+
+// IsKafka checks if the given host is running a Kafka broker (>= 0.10.0.0).
+//
+// Brokers requiring authentication are also detected.
+func (c *KafkaClient) IsKafka(host string, port int) (bool, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return false, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	kafkaPlugin := kafkanew.Plugin{}
+	service, err := kafkaPlugin.Run(conn, defaultTimeout, plugins.Target{Host: host})
+	if err != nil {
+		return false, err
+	}
+	return service != nil, nil
+}
+
+// IsUnauthenticated checks if the topics of the broker can be listed without authentication.
+func (c *KafkaClient) IsUnauthenticated(host string, port int) (bool, error) {
+	if _, err := c.ListTopics(host, port, "", ""); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Connect tries to connect to the broker with the given credentials
+// using the SASL PLAIN mechanism.
+func (c *KafkaClient) Connect(host string, port int, username, password string) (bool, error) {
+	conn, err := dial(host, port, username, password)
+	if err != nil {
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
+
+// ListTopics returns the topics of the cluster. If username is not empty
+// the connection is authenticated using the SASL PLAIN mechanism.
+func (c *KafkaClient) ListTopics(host string, port int, username, password string) ([]string, error) {
+	conn, err := dial(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions()
+	if err != nil {
+		return nil, err
+	}
+	unique := make(map[string]struct{})
+	for _, partition := range partitions {
+		unique[partition.Topic] = struct{}{}
+	}
+	topics := make([]string, 0, len(unique))
+	for topic := range unique {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics, nil
+}
+
+// ListBrokers returns the addresses of the brokers of the cluster. If username
+// is not empty the connection is authenticated using the SASL PLAIN mechanism.
+func (c *KafkaClient) ListBrokers(host string, port int, username, password string) ([]string, error) {
+	conn, err := dial(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	brokers, err := conn.Brokers()
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(brokers))
+	for _, broker := range brokers {
+		addresses = append(addresses, net.JoinHostPort(broker.Host, strconv.Itoa(broker.Port)))
+	}
+	return addresses, nil
+}
+
+func dial(host string, port int, username, password string) (*kafkago.Conn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	dialer := &kafkago.Dialer{
+		Timeout:  defaultTimeout,
+		ClientID: "vulmap",
+		DialFunc: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return protocolstate.Dialer.Dial(ctx, network, address)
+		},
+	}
+	if username != "" {
+		dialer.SASLMechanism = plain.Mechanism{Username: username, Password: password}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+	return conn, nil
+}
@@ -0,0 +1,133 @@
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// MemcachedClient is a client for Memcached servers.
+//
+// Internally client uses the memcached text protocol.
+type MemcachedClient struct{}
+
+// IsMemcached checks if the given host is running a Memcached server.
+//
+// Returns the version of the server if it is running Memcached.
+func (c *MemcachedClient) IsMemcached(host string, port int) (string, error) {
+	lines, err := execute(host, port, "version")
+	if err != nil {
+		return "", err
+	}
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "VERSION ") {
+		return "", fmt.Errorf("not a memcached server")
+	}
+	return strings.TrimPrefix(lines[0], "VERSION "), nil
+}
+
+// IsUnauthenticated checks if the Memcached server allows
+// reading statistics without authentication.
+func (c *MemcachedClient) IsUnauthenticated(host string, port int) (bool, error) {
+	stats, err := c.Stats(host, port)
+	if err != nil {
+		return false, err
+	}
+	return len(stats) > 0, nil
+}
+
+// Stats returns the general statistics of the Memcached server.
+func (c *MemcachedClient) Stats(host string, port int) (map[string]string, error) {
+	lines, err := execute(host, port, "stats")
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]string)
+	for _, line := range lines {
+		parts := strings.SplitN(line, " ", 3)
+		if len(parts) == 3 && parts[0] == "STAT" {
+			stats[parts[1]] = parts[2]
+		}
+	}
+	return stats, nil
+}
+
+// ListKeys returns the keys stored in the Memcached server
+// using the lru_crawler metadump command (memcached >= 1.4.31).
+func (c *MemcachedClient) ListKeys(host string, port int) ([]string, error) {
+	lines, err := execute(host, port, "lru_crawler metadump all")
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "key=") {
+			continue
+		}
+		key, _, _ := strings.Cut(strings.TrimPrefix(line, "key="), " ")
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Get returns the value of a key stored in the Memcached server.
+func (c *MemcachedClient) Get(host string, port int, key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, " \r\n") {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	lines, err := execute(host, port, "get "+key)
+	if err != nil {
+		return "", err
+	}
+	// VALUE <key> <flags> <bytes>\r\n<data>\r\nEND
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "VALUE ") {
+		return "", fmt.Errorf("key %v not found", key)
+	}
+	return lines[1], nil
+}
+
+// execute sends a command to the server and returns the response lines
+// until the command terminator is received.
+func execute(host string, port int, command string) ([]string, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+
+	if _, err := conn.Write([]byte(command + "\r\n")); err != nil {
+		return nil, err
+	}
+	var lines []string
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return lines, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "END", line == "OK":
+			return lines, nil
+		case strings.HasPrefix(line, "VERSION "):
+			return append(lines, line), nil
+		case line == "ERROR", strings.HasPrefix(line, "CLIENT_ERROR"), strings.HasPrefix(line, "SERVER_ERROR"):
+			return lines, fmt.Errorf("memcached error: %v", line)
+		}
+		lines = append(lines, line)
+	}
+}
@@ -0,0 +1,299 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
+	"github.com/praetorian-inc/fingerprintx/pkg/plugins/services/modbus"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+	transactionID  uint32
+)
+
+// Modbus function codes
+const (
+	functionReadCoils              = 0x01
+	functionReadDiscreteInputs     = 0x02
+	functionReadHoldingRegisters   = 0x03
+	functionReadInputRegisters     = 0x04
+	functionWriteSingleCoil        = 0x05
+	functionWriteSingleRegister    = 0x06
+	functionEncapsulatedInterface  = 0x2b
+	meiReadDeviceIdentification    = 0x0e
+	maxReadBits                    = 2000
+	maxReadRegisters               = 125
+	exceptionFunctionCodeIndicator = 0x80
+)
+
+// exceptionCodes are the Modbus exception codes
+var exceptionCodes = map[byte]string{
+	1:  "illegal function",
+	2:  "illegal data address",
+	3:  "illegal data value",
+	4:  "server device failure",
+	6:  "server device busy",
+	10: "gateway path unavailable",
+	11: "gateway target device failed to respond",
+}
+
+// deviceObjects are the names of the device identification objects
+var deviceObjects = map[byte]string{
+	0: "VendorName",
+	1: "ProductCode",
+	2: "MajorMinorRevision",
+	3: "VendorUrl",
+	4: "ProductName",
+	5: "ModelName",
+	6: "UserApplicationName",
+}
+
+// ModbusClient is a client for Modbus/TCP devices.
+//
+// Only read operations are allowed by default, write operations
+// must be explicitly enabled using EnableWrites.
+type ModbusClient struct {
+	writesEnabled bool
+}
+
+// EnableWrites allows write operations (e.g. WriteSingleCoil) to be
+// performed by the client. Writing to industrial devices may change
+// physical processes and should only be enabled when explicitly intended.
+func (c *ModbusClient) EnableWrites() {
+	c.writesEnabled = true
+}
+
+// IsModbus checks if the given host is running a Modbus/TCP server.
+func (c *ModbusClient) IsModbus(host string, port int) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	modbusPlugin := modbus.MODBUSPlugin{}
+	service, err := modbusPlugin.Run(conn, defaultTimeout, plugins.Target{Host: host})
+	if err != nil {
+		return false, err
+	}
+	return service != nil, nil
+}
+
+// ReadCoils reads quantity coils starting at address from the unit.
+func (c *ModbusClient) ReadCoils(host string, port, unitID, address, quantity int) ([]bool, error) {
+	return readBits(host, port, unitID, functionReadCoils, address, quantity)
+}
+
+// ReadDiscreteInputs reads quantity discrete inputs starting at address from the unit.
+func (c *ModbusClient) ReadDiscreteInputs(host string, port, unitID, address, quantity int) ([]bool, error) {
+	return readBits(host, port, unitID, functionReadDiscreteInputs, address, quantity)
+}
+
+// ReadHoldingRegisters reads quantity holding registers starting at address from the unit.
+func (c *ModbusClient) ReadHoldingRegisters(host string, port, unitID, address, quantity int) ([]int, error) {
+	return readRegisters(host, port, unitID, functionReadHoldingRegisters, address, quantity)
+}
+
+// ReadInputRegisters reads quantity input registers starting at address from the unit.
+func (c *ModbusClient) ReadInputRegisters(host string, port, unitID, address, quantity int) ([]int, error) {
+	return readRegisters(host, port, unitID, functionReadInputRegisters, address, quantity)
+}
+
+// ReadDeviceIdentification reads the basic and regular device identification
+// objects (e.g. VendorName, ProductCode, MajorMinorRevision) of the unit.
+func (c *ModbusClient) ReadDeviceIdentification(host string, port, unitID int) (map[string]string, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	identification := make(map[string]string)
+	// read device id code 2 (regular) includes the basic objects
+	var objectID byte
+	for i := 0; i < 16; i++ {
+		response, err := execute(conn, unitID, functionEncapsulatedInterface, []byte{meiReadDeviceIdentification, 0x02, objectID})
+		if err != nil {
+			return identification, err
+		}
+		// mei type, read device id code, conformity level, more follows, next object id, number of objects
+		if len(response) < 6 {
+			return identification, fmt.Errorf("invalid device identification response")
+		}
+		moreFollows, nextObjectID, count := response[3], response[4], int(response[5])
+		offset := 6
+		for j := 0; j < count && offset+2 <= len(response); j++ {
+			id, length := response[offset], int(response[offset+1])
+			offset += 2
+			if offset+length > len(response) {
+				break
+			}
+			name, ok := deviceObjects[id]
+			if !ok {
+				name = fmt.Sprintf("Object%d", id)
+			}
+			identification[name] = string(response[offset : offset+length])
+			offset += length
+		}
+		if moreFollows != 0xff {
+			break
+		}
+		objectID = nextObjectID
+	}
+	return identification, nil
+}
+
+// WriteSingleCoil writes a single coil at address of the unit.
+//
+// Writes must be enabled using EnableWrites.
+func (c *ModbusClient) WriteSingleCoil(host string, port, unitID, address int, value bool) (bool, error) {
+	if !c.writesEnabled {
+		return false, fmt.Errorf("write operations are disabled, call EnableWrites() to allow them")
+	}
+	data := []byte{0x00, 0x00}
+	if value {
+		data[0] = 0xff
+	}
+	return write(host, port, unitID, functionWriteSingleCoil, address, data)
+}
+
+// WriteSingleRegister writes a single holding register at address of the unit.
+//
+// Writes must be enabled using EnableWrites.
+func (c *ModbusClient) WriteSingleRegister(host string, port, unitID, address, value int) (bool, error) {
+	if !c.writesEnabled {
+		return false, fmt.Errorf("write operations are disabled, call EnableWrites() to allow them")
+	}
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, uint16(value))
+	return write(host, port, unitID, functionWriteSingleRegister, address, data)
+}
+
+func readBits(host string, port, unitID int, function byte, address, quantity int) ([]bool, error) {
+	if quantity < 1 || quantity > maxReadBits {
+		return nil, fmt.Errorf("quantity must be between 1 and %d", maxReadBits)
+	}
+	response, err := read(host, port, unitID, function, address, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) < 1 || int(response[0]) != len(response)-1 || len(response)-1 < (quantity+7)/8 {
+		return nil, fmt.Errorf("invalid modbus response")
+	}
+	bits := make([]bool, quantity)
+	for i := range bits {
+		bits[i] = response[1+i/8]&(1<<(i%8)) != 0
+	}
+	return bits, nil
+}
+
+func readRegisters(host string, port, unitID int, function byte, address, quantity int) ([]int, error) {
+	if quantity < 1 || quantity > maxReadRegisters {
+		return nil, fmt.Errorf("quantity must be between 1 and %d", maxReadRegisters)
+	}
+	response, err := read(host, port, unitID, function, address, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) < 1 || int(response[0]) != len(response)-1 || len(response)-1 < quantity*2 {
+		return nil, fmt.Errorf("invalid modbus response")
+	}
+	registers := make([]int, quantity)
+	for i := range registers {
+		registers[i] = int(binary.BigEndian.Uint16(response[1+i*2:]))
+	}
+	return registers, nil
+}
+
+func read(host string, port, unitID int, function byte, address, quantity int) ([]byte, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint16(data, uint16(address))
+	binary.BigEndian.PutUint16(data[2:], uint16(quantity))
+	return execute(conn, unitID, function, data)
+}
+
+func write(host string, port, unitID int, function byte, address int, value []byte) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	data := make([]byte, 2, 4)
+	binary.BigEndian.PutUint16(data, uint16(address))
+	if _, err := execute(conn, unitID, function, append(data, value...)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// execute sends a request PDU to the unit and returns the response data
+func execute(conn net.Conn, unitID int, function byte, data []byte) ([]byte, error) {
+	id := uint16(atomic.AddUint32(&transactionID, 1))
+
+	// MBAP header: transaction id, protocol id, length, unit id
+	request := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint16(request, id)
+	binary.BigEndian.PutUint16(request[4:], uint16(len(data)+2))
+	request[6] = byte(unitID)
+	request[7] = function
+	if _, err := conn.Write(append(request, data...)); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	length := int(binary.BigEndian.Uint16(header[4:]))
+	if binary.BigEndian.Uint16(header) != id || binary.BigEndian.Uint16(header[2:]) != 0 || length < 2 || length > 260 {
+		return nil, fmt.Errorf("invalid modbus response")
+	}
+	pdu := make([]byte, length-1)
+	if _, err := io.ReadFull(conn, pdu); err != nil {
+		return nil, err
+	}
+	switch pdu[0] {
+	case function:
+		return pdu[1:], nil
+	case function | exceptionFunctionCodeIndicator:
+		if len(pdu) < 2 {
+			return nil, fmt.Errorf("invalid modbus exception response")
+		}
+		message, ok := exceptionCodes[pdu[1]]
+		if !ok {
+			message = "unknown exception"
+		}
+		return nil, fmt.Errorf("modbus exception: %v (%d)", message, pdu[1])
+	default:
+		return nil, fmt.Errorf("unexpected modbus function code %d", pdu[0])
+	}
+}
+
+func dial(host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+	return conn, nil
+}
@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// codeUnauthorized is the error code returned for commands requiring authentication
+const codeUnauthorized = 13
+
+// MongoDBClient is a client for MongoDB servers.
+//
+// Internally client uses go.mongodb.org/mongo-driver driver.
+type MongoDBClient struct{}
+
+// IsMongoDB checks if the given host is running a MongoDB server.
+func (c *MongoDBClient) IsMongoDB(host string, port int) (bool, error) {
+	var result bson.M
+	err := runCommand(host, port, "", "", "admin", bson.D{{Key: "isMaster", Value: 1}}, &result)
+	if err != nil {
+		return false, err
+	}
+	_, ok := result["ismaster"]
+	return ok, nil
+}
+
+// GetVersion returns the version of the MongoDB server using the buildInfo command.
+func (c *MongoDBClient) GetVersion(host string, port int) (string, error) {
+	var result struct {
+		Version string `bson:"version"`
+	}
+	if err := runCommand(host, port, "", "", "admin", bson.D{{Key: "buildInfo", Value: 1}}, &result); err != nil {
+		return "", err
+	}
+	return result.Version, nil
+}
+
+// IsUnauthenticated checks if the databases can be listed without authentication.
+func (c *MongoDBClient) IsUnauthenticated(host string, port int) (bool, error) {
+	_, err := c.ListDatabases(host, port, "", "")
+	if err != nil {
+		var commandErr mongo.CommandError
+		if errors.As(err, &commandErr) && commandErr.Code == codeUnauthorized {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Connect tries to connect to the server with the given credentials.
+func (c *MongoDBClient) Connect(host string, port int, username, password string) (bool, error) {
+	if err := runCommand(host, port, username, password, "admin", bson.D{{Key: "ping", Value: 1}}, &bson.M{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListDatabases returns the names of the databases of the server. If username
+// is not empty the connection is authenticated against the admin database.
+func (c *MongoDBClient) ListDatabases(host string, port int, username, password string) ([]string, error) {
+	var names []string
+	err := withClient(host, port, username, password, func(ctx context.Context, client *mongo.Client) error {
+		var err error
+		names, err = client.ListDatabaseNames(ctx, bson.D{})
+		return err
+	})
+	return names, err
+}
+
+// ListCollections returns the names of the collections of a database. If username
+// is not empty the connection is authenticated against the admin database.
+func (c *MongoDBClient) ListCollections(host string, port int, username, password, database string) ([]string, error) {
+	var names []string
+	err := withClient(host, port, username, password, func(ctx context.Context, client *mongo.Client) error {
+		var err error
+		names, err = client.Database(database).ListCollectionNames(ctx, bson.D{})
+		return err
+	})
+	return names, err
+}
+
+// runCommand runs a command on a database and decodes the result
+func runCommand(host string, port int, username, password, database string, command bson.D, result interface{}) error {
+	return withClient(host, port, username, password, func(ctx context.Context, client *mongo.Client) error {
+		return client.Database(database).RunCommand(ctx, command).Decode(result)
+	})
+}
+
+// withClient connects to the server and calls fn with the connected client
+func withClient(host string, port int, username, password string, fn func(ctx context.Context, client *mongo.Client) error) error {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return protocolstate.ErrHostDenied.Msgf(host)
+	}
+	opts := options.Client().
+		ApplyURI(fmt.Sprintf("mongodb://%s", net.JoinHostPort(host, strconv.Itoa(port)))).
+		SetDirect(true).
+		SetDialer(contextDialer{}).
+		SetConnectTimeout(defaultTimeout).
+		SetServerSelectionTimeout(defaultTimeout)
+	if username != "" {
+		opts.SetAuth(options.Credential{Username: username, Password: password})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*defaultTimeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Disconnect(context.Background())
+	}()
+	return fn(ctx, client)
+}
+
+// contextDialer dials connections using the vulmap dialer
+type contextDialer struct{}
+
+func (contextDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return protocolstate.Dialer.Dial(ctx, network, address)
+}
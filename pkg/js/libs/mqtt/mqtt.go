@@ -0,0 +1,240 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// MQTT control packet types
+const (
+	packetConnect   = 1
+	packetConnAck   = 2
+	packetPublish   = 3
+	packetSubscribe = 8
+	packetSubAck    = 9
+)
+
+// connAckCodes are the MQTT 3.1.1 CONNACK return codes
+var connAckCodes = map[byte]string{
+	0: "connection accepted",
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad username or password",
+	5: "not authorized",
+}
+
+// MQTTClient is a client for MQTT brokers.
+//
+// Internally client uses the MQTT 3.1.1 protocol.
+type MQTTClient struct{}
+
+// IsMQTT checks if the given host is running a MQTT broker.
+//
+// Brokers refusing unauthenticated connections are also detected.
+func (c *MQTTClient) IsMQTT(host string, port int) (bool, error) {
+	conn, reader, err := connect(host, port, "", "")
+	if conn != nil {
+		conn.Close()
+		return true, nil
+	}
+	// a CONNACK refusing the connection was received
+	if reader != nil {
+		return true, nil
+	}
+	return false, err
+}
+
+// IsUnauthenticated checks if the broker accepts connections without credentials.
+func (c *MQTTClient) IsUnauthenticated(host string, port int) (bool, error) {
+	return c.Connect(host, port, "", "")
+}
+
+// Connect tries to connect to the broker with the given credentials.
+//
+// Returns true if the connection was accepted, false and an error
+// describing the CONNACK return code otherwise.
+func (c *MQTTClient) Connect(host string, port int, username, password string) (bool, error) {
+	conn, _, err := connect(host, port, username, password)
+	if err != nil {
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
+
+// Subscribe connects to the broker with the given credentials, subscribes
+// to the topic filter (e.g. '#') and returns messages received until the
+// timeout (in seconds) expires.
+//
+// Returned messages are a map of topic and last received payload.
+func (c *MQTTClient) Subscribe(host string, port int, username, password, topic string, timeout int) (map[string]string, error) {
+	conn, reader, err := connect(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	payload := &bytes.Buffer{}
+	_ = binary.Write(payload, binary.BigEndian, uint16(1)) // packet identifier
+	writeString(payload, topic)
+	payload.WriteByte(0) // QoS 0
+	if _, err := conn.Write(encodePacket(packetSubscribe<<4|0x02, payload.Bytes())); err != nil {
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = int(defaultTimeout.Seconds())
+	}
+	_ = conn.SetDeadline(time.Now().Add(time.Duration(timeout) * time.Second))
+	messages := make(map[string]string)
+	for {
+		packetType, data, err := readPacket(reader)
+		if err != nil {
+			// deadline exceeded ends the subscription
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return messages, nil
+			}
+			return messages, err
+		}
+		switch packetType >> 4 {
+		case packetSubAck:
+			if len(data) >= 3 && data[2] == 0x80 {
+				return nil, fmt.Errorf("subscription to %v refused", topic)
+			}
+		case packetPublish:
+			if len(data) < 2 {
+				continue
+			}
+			length := int(binary.BigEndian.Uint16(data))
+			if len(data) < 2+length {
+				continue
+			}
+			offset := 2 + length
+			// skip packet identifier for QoS > 0
+			if (packetType>>1)&0x03 > 0 {
+				offset += 2
+			}
+			if offset > len(data) {
+				continue
+			}
+			messages[string(data[2:2+length])] = string(data[offset:])
+		}
+	}
+}
+
+// connect establishes a MQTT session and returns the connection if accepted
+func connect(host string, port int, username, password string) (net.Conn, *bufio.Reader, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+
+	var flags byte = 0x02 // clean session
+	payload := &bytes.Buffer{}
+	writeString(payload, "vulmap-"+strconv.FormatInt(time.Now().UnixNano()%100000, 10))
+	if username != "" {
+		flags |= 0x80
+		writeString(payload, username)
+		if password != "" {
+			flags |= 0x40
+			writeString(payload, password)
+		}
+	}
+	variable := &bytes.Buffer{}
+	writeString(variable, "MQTT")
+	variable.WriteByte(4) // protocol level 3.1.1
+	variable.WriteByte(flags)
+	_ = binary.Write(variable, binary.BigEndian, uint16(60)) // keep alive
+	variable.Write(payload.Bytes())
+
+	if _, err := conn.Write(encodePacket(packetConnect<<4, variable.Bytes())); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	reader := bufio.NewReader(conn)
+	packetType, data, err := readPacket(reader)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if packetType>>4 != packetConnAck || len(data) < 2 {
+		conn.Close()
+		return nil, nil, fmt.Errorf("not a mqtt broker")
+	}
+	if code := data[1]; code != 0 {
+		conn.Close()
+		message, ok := connAckCodes[code]
+		if !ok {
+			message = "unknown error"
+		}
+		return nil, reader, fmt.Errorf("connection refused: %v (%d)", message, code)
+	}
+	return conn, reader, nil
+}
+
+func writeString(buffer *bytes.Buffer, value string) {
+	_ = binary.Write(buffer, binary.BigEndian, uint16(len(value)))
+	buffer.WriteString(value)
+}
+
+// encodePacket encodes a packet with the fixed header and remaining length
+func encodePacket(header byte, data []byte) []byte {
+	packet := []byte{header}
+	length := len(data)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, data...)
+}
+
+// readPacket reads a packet and returns its fixed header and data
+func readPacket(reader *bufio.Reader) (byte, []byte, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; i < 4; i++ {
+		digit, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return 0, nil, err
+	}
+	return header, data, nil
+}
@@ -0,0 +1,235 @@
+package s7comm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// S7comm requests sent over ISO-on-TCP (TPKT + COTP)
+var (
+	// connection requests with destination TSAP for rack 0 slot 2 and the alternate 0x0200 TSAP
+	cotpConnectionRequest          = mustDecode("0300001611e00000001400c1020100c2020102c0010a")
+	cotpAlternateConnectionRequest = mustDecode("0300001611e00000000500c1020100c2020200c0010a")
+	setupCommunicationRequest      = mustDecode("0300001902f08032010000000000080000f0000001000101e0")
+	// read SZL 0x0011 (module identification) and 0x001c (component identification)
+	readModuleIdentificationRequest    = mustDecode("0300002102f080320700000000000800080001120411440100ff09000400110001")
+	readComponentIdentificationRequest = mustDecode("0300002102f080320700000000000800080001120411440100ff090004001c0001")
+)
+
+// S7commClient is a client for Siemens S7 PLCs.
+//
+// Internally client uses the S7comm protocol over ISO-on-TCP and only
+// performs read operations of the system status list (SZL).
+type S7commClient struct{}
+
+// S7Info contains identification information about a S7 PLC.
+type S7Info struct {
+	// Module is the order number of the module
+	Module string
+	// BasicHardware is the order number of the basic hardware
+	BasicHardware string
+	// Version is the firmware version of the module
+	Version string
+	// SystemName is the name of the automation system
+	SystemName string
+	// ModuleType is the name of the module type
+	ModuleType string
+	// PlantIdentification is the plant identification
+	PlantIdentification string
+	// Copyright is the copyright entry
+	Copyright string
+	// SerialNumber is the serial number of the module
+	SerialNumber string
+}
+
+// IsS7comm checks if the given host is running a S7comm server.
+func (c *S7commClient) IsS7comm(host string, port int) (bool, error) {
+	conn, err := connect(host, port)
+	if err != nil {
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
+
+// GetModuleIdentification returns the module and component
+// identification of the PLC.
+func (c *S7commClient) GetModuleIdentification(host string, port int) (*S7Info, error) {
+	conn, err := connect(host, port)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	info := &S7Info{}
+	records, recordLength, err := readSZL(conn, readModuleIdentificationRequest)
+	if err != nil {
+		return nil, err
+	}
+	// module identification records: index(2), order number(20), module type(2), version(4)
+	for _, record := range records {
+		if recordLength < 28 {
+			break
+		}
+		switch binary.BigEndian.Uint16(record) {
+		case 0x0001:
+			info.Module = cstring(record[2:22])
+		case 0x0006:
+			info.BasicHardware = cstring(record[2:22])
+		case 0x0007:
+			info.Version = fmt.Sprintf("%d.%d.%d", record[25], record[26], record[27])
+		}
+	}
+
+	records, recordLength, err = readSZL(conn, readComponentIdentificationRequest)
+	if err != nil {
+		// component identification is not supported by all PLCs
+		return info, nil
+	}
+	// component identification records: index(2), name(32)
+	for _, record := range records {
+		if recordLength < 34 {
+			break
+		}
+		value := cstring(record[2:34])
+		switch binary.BigEndian.Uint16(record) {
+		case 0x0001:
+			info.SystemName = value
+		case 0x0002:
+			info.ModuleType = value
+		case 0x0003:
+			info.PlantIdentification = value
+		case 0x0004:
+			info.Copyright = value
+		case 0x0005:
+			info.SerialNumber = value
+		}
+	}
+	return info, nil
+}
+
+// connect establishes a COTP connection and sets up the S7 communication
+func connect(host string, port int) (net.Conn, error) {
+	var lastErr error
+	for _, request := range [][]byte{cotpConnectionRequest, cotpAlternateConnectionRequest} {
+		conn, err := dial(host, port)
+		if err != nil {
+			return nil, err
+		}
+		response, err := exchange(conn, request)
+		// COTP connection confirm
+		if err != nil || len(response) < 6 || response[5] != 0xd0 {
+			conn.Close()
+			lastErr = fmt.Errorf("cotp connection refused")
+			if err != nil {
+				lastErr = err
+			}
+			continue
+		}
+		response, err = exchange(conn, setupCommunicationRequest)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if len(response) < 8 || response[7] != 0x32 {
+			conn.Close()
+			return nil, fmt.Errorf("not a s7comm server")
+		}
+		return conn, nil
+	}
+	return nil, lastErr
+}
+
+// readSZL sends a SZL read request and returns the SZL records
+func readSZL(conn net.Conn, request []byte) ([][]byte, int, error) {
+	response, err := exchange(conn, request)
+	if err != nil {
+		return nil, 0, err
+	}
+	// tpkt(4) + cotp(3) + s7 userdata header(10)
+	if len(response) < 17 || response[7] != 0x32 {
+		return nil, 0, fmt.Errorf("invalid s7comm response")
+	}
+	parameterLength := int(binary.BigEndian.Uint16(response[13:15]))
+	offset := 17 + parameterLength
+	// data: return code(1), transport size(1), length(2), szl id(2), szl index(2), record length(2), record count(2)
+	if len(response) < offset+12 {
+		return nil, 0, fmt.Errorf("invalid s7comm response")
+	}
+	if response[offset] != 0xff {
+		return nil, 0, fmt.Errorf("s7comm szl read failed with return code %d", response[offset])
+	}
+	recordLength := int(binary.BigEndian.Uint16(response[offset+8:]))
+	count := int(binary.BigEndian.Uint16(response[offset+10:]))
+	data := response[offset+12:]
+	var records [][]byte
+	for i := 0; i < count && recordLength > 0 && len(data) >= recordLength; i++ {
+		records = append(records, data[:recordLength])
+		data = data[recordLength:]
+	}
+	return records, recordLength, nil
+}
+
+// exchange sends a TPKT packet and returns the TPKT response
+func exchange(conn net.Conn, request []byte) ([]byte, error) {
+	if _, err := conn.Write(request); err != nil {
+		return nil, err
+	}
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	length := int(binary.BigEndian.Uint16(header[2:]))
+	if header[0] != 0x03 || length < 4 {
+		return nil, fmt.Errorf("invalid tpkt response")
+	}
+	response := make([]byte, length)
+	copy(response, header)
+	if _, err := io.ReadFull(conn, response[4:]); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func dial(host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+	return conn, nil
+}
+
+// cstring returns the null terminated and space padded string of data
+func cstring(data []byte) string {
+	if index := bytes.IndexByte(data, 0); index >= 0 {
+		data = data[:index]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func mustDecode(value string) []byte {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
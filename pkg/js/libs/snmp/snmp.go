@@ -0,0 +1,284 @@
+package snmp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// authProtocols are the supported SNMPv3 authentication protocols
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"":       gosnmp.NoAuth,
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+// privProtocols are the supported SNMPv3 privacy protocols
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"":        gosnmp.NoPriv,
+	"DES":     gosnmp.DES,
+	"AES":     gosnmp.AES,
+	"AES192":  gosnmp.AES192,
+	"AES256":  gosnmp.AES256,
+	"AES192C": gosnmp.AES192C,
+	"AES256C": gosnmp.AES256C,
+}
+
+// SNMPClient is a client for SNMP agents.
+//
+// Internally client uses github.com/gosnmp/gosnmp driver. Only read
+// operations are allowed by default, write operations must be
+// explicitly enabled using EnableWrites.
+type SNMPClient struct {
+	writesEnabled bool
+}
+
+// EnableWrites allows write operations (e.g. Set) to be performed by the client.
+// Writing to devices may change their configuration and should only be enabled
+// when explicitly intended.
+func (c *SNMPClient) EnableWrites() {
+	c.writesEnabled = true
+}
+
+// IsSNMP checks if the given host is running a SNMP agent accepting
+// the community using SNMPv2c.
+//
+// Returns the system description (sysDescr) of the agent.
+func (c *SNMPClient) IsSNMP(host string, port int, community string) (string, error) {
+	values, err := c.Get(host, port, "2c", community, []string{"1.3.6.1.2.1.1.1.0"})
+	if err != nil {
+		return "", err
+	}
+	return values["1.3.6.1.2.1.1.1.0"], nil
+}
+
+// Get returns the values of the oids using SNMP version 1 or 2c and the community.
+func (c *SNMPClient) Get(host string, port int, version, community string, oids []string) (map[string]string, error) {
+	client, err := newClient(host, port, version, community)
+	if err != nil {
+		return nil, err
+	}
+	return get(client, oids)
+}
+
+// Walk returns the values of the subtree of the oid using SNMP
+// version 1 or 2c and the community.
+func (c *SNMPClient) Walk(host string, port int, version, community, oid string) (map[string]string, error) {
+	client, err := newClient(host, port, version, community)
+	if err != nil {
+		return nil, err
+	}
+	return walk(client, oid)
+}
+
+// GetV3 returns the values of the oids using SNMPv3 with the user credentials.
+//
+// Supported authentication protocols are MD5, SHA, SHA224, SHA256, SHA384 and SHA512,
+// supported privacy protocols are DES, AES, AES192, AES256, AES192C and AES256C.
+// Empty protocols disable authentication or privacy.
+func (c *SNMPClient) GetV3(host string, port int, username, authProtocol, authPassword, privProtocol, privPassword string, oids []string) (map[string]string, error) {
+	client, err := newClientV3(host, port, username, authProtocol, authPassword, privProtocol, privPassword)
+	if err != nil {
+		return nil, err
+	}
+	return get(client, oids)
+}
+
+// WalkV3 returns the values of the subtree of the oid using SNMPv3 with the user credentials.
+func (c *SNMPClient) WalkV3(host string, port int, username, authProtocol, authPassword, privProtocol, privPassword, oid string) (map[string]string, error) {
+	client, err := newClientV3(host, port, username, authProtocol, authPassword, privProtocol, privPassword)
+	if err != nil {
+		return nil, err
+	}
+	return walk(client, oid)
+}
+
+// Set sets the string value of the oid using SNMP version 1 or 2c and the community.
+//
+// Writes must be enabled using EnableWrites.
+func (c *SNMPClient) Set(host string, port int, version, community, oid, value string) (bool, error) {
+	if !c.writesEnabled {
+		return false, fmt.Errorf("write operations are disabled, call EnableWrites() to allow them")
+	}
+	client, err := newClient(host, port, version, community)
+	if err != nil {
+		return false, err
+	}
+	if err := connect(client); err != nil {
+		return false, err
+	}
+	defer client.Conn.Close()
+
+	result, err := client.Set([]gosnmp.SnmpPDU{{Name: oid, Type: gosnmp.OctetString, Value: value}})
+	if err != nil {
+		return false, err
+	}
+	if result.Error != gosnmp.NoError {
+		return false, fmt.Errorf("snmp set failed: %v", result.Error)
+	}
+	return true, nil
+}
+
+func newClient(host string, port int, version, community string) (*gosnmp.GoSNMP, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	client := &gosnmp.GoSNMP{
+		Target:    host,
+		Port:      uint16(port),
+		Community: community,
+		Timeout:   defaultTimeout,
+		Retries:   1,
+		MaxOids:   gosnmp.MaxOids,
+	}
+	switch version {
+	case "1":
+		client.Version = gosnmp.Version1
+	case "2c", "2", "":
+		client.Version = gosnmp.Version2c
+	default:
+		return nil, fmt.Errorf("unsupported snmp version %q, use GetV3/WalkV3 for version 3", version)
+	}
+	return client, nil
+}
+
+func newClientV3(host string, port int, username, authProtocol, authPassword, privProtocol, privPassword string) (*gosnmp.GoSNMP, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	auth, ok := authProtocols[strings.ToUpper(authProtocol)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported snmp authentication protocol %q", authProtocol)
+	}
+	priv, ok := privProtocols[strings.ToUpper(privProtocol)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported snmp privacy protocol %q", privProtocol)
+	}
+	flags := gosnmp.NoAuthNoPriv
+	if auth != gosnmp.NoAuth {
+		flags = gosnmp.AuthNoPriv
+		if priv != gosnmp.NoPriv {
+			flags = gosnmp.AuthPriv
+		}
+	}
+	return &gosnmp.GoSNMP{
+		Target:        host,
+		Port:          uint16(port),
+		Version:       gosnmp.Version3,
+		Timeout:       defaultTimeout,
+		Retries:       1,
+		MaxOids:       gosnmp.MaxOids,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      flags,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 username,
+			AuthenticationProtocol:   auth,
+			AuthenticationPassphrase: authPassword,
+			PrivacyProtocol:          priv,
+			PrivacyPassphrase:        privPassword,
+		},
+	}, nil
+}
+
+// connect connects the client to the agent through the dialer of vulmap.
+//
+// gosnmp always opens its own socket, which is replaced by the connection of
+// the dialer. The socket is dialed to the resolved address and no packet is sent
+// through it as udp sockets don't perform any handshake.
+func connect(client *gosnmp.GoSNMP) error {
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "udp", net.JoinHostPort(client.Target, strconv.Itoa(int(client.Port))))
+	if err != nil {
+		return err
+	}
+	if addr, ok := conn.RemoteAddr().(*net.UDPAddr); ok {
+		client.Target = addr.IP.String()
+	}
+	if err := client.Connect(); err != nil {
+		conn.Close()
+		return err
+	}
+	_ = client.Conn.Close()
+	client.Conn = conn
+	return nil
+}
+
+func get(client *gosnmp.GoSNMP, oids []string) (map[string]string, error) {
+	if len(oids) == 0 {
+		return nil, fmt.Errorf("no oids specified")
+	}
+	if err := connect(client); err != nil {
+		return nil, err
+	}
+	defer client.Conn.Close()
+
+	result, err := client.Get(oids)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != gosnmp.NoError {
+		return nil, fmt.Errorf("snmp get failed: %v", result.Error)
+	}
+	values := make(map[string]string, len(result.Variables))
+	for _, variable := range result.Variables {
+		if value, ok := formatValue(variable); ok {
+			values[strings.TrimPrefix(variable.Name, ".")] = value
+		}
+	}
+	return values, nil
+}
+
+func walk(client *gosnmp.GoSNMP, oid string) (map[string]string, error) {
+	if err := connect(client); err != nil {
+		return nil, err
+	}
+	defer client.Conn.Close()
+
+	var variables []gosnmp.SnmpPDU
+	var err error
+	if client.Version == gosnmp.Version1 {
+		variables, err = client.WalkAll(oid)
+	} else {
+		variables, err = client.BulkWalkAll(oid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(variables))
+	for _, variable := range variables {
+		if value, ok := formatValue(variable); ok {
+			values[strings.TrimPrefix(variable.Name, ".")] = value
+		}
+	}
+	return values, nil
+}
+
+// formatValue returns the string representation of a variable value
+func formatValue(variable gosnmp.SnmpPDU) (string, bool) {
+	switch variable.Type {
+	case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView, gosnmp.Null:
+		return "", false
+	case gosnmp.OctetString:
+		if value, ok := variable.Value.([]byte); ok {
+			return string(value), true
+		}
+	case gosnmp.Integer, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		return gosnmp.ToBigInt(variable.Value).String(), true
+	}
+	return strings.TrimPrefix(fmt.Sprint(variable.Value), "."), true
+}
@@ -0,0 +1,287 @@
+package zookeeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+var (
+	defaultTimeout = 5 * time.Second
+)
+
+// ZooKeeper request opcodes and error codes
+const (
+	opGetChildren = 8
+	opGetData     = 4
+	opAuth        = 100
+
+	xidAuth = -4
+
+	errNoAuth   = -102
+	errNoNode   = -101
+	errAuthFail = -115
+)
+
+// ZooKeeperClient is a client for ZooKeeper servers.
+//
+// Internally client uses the four letter word commands and
+// the ZooKeeper client (jute) protocol.
+type ZooKeeperClient struct{}
+
+// IsZooKeeper checks if the given host is running a ZooKeeper server.
+//
+// Servers with the ruok command disabled by the whitelist are also detected.
+func (c *ZooKeeperClient) IsZooKeeper(host string, port int) (bool, error) {
+	response, err := c.FourLetterWord(host, port, "ruok")
+	if err != nil {
+		return false, err
+	}
+	return response == "imok" || strings.Contains(response, "is not executed because it is not in the whitelist"), nil
+}
+
+// GetVersion returns the version of the ZooKeeper server using the srvr command.
+func (c *ZooKeeperClient) GetVersion(host string, port int) (string, error) {
+	response, err := c.FourLetterWord(host, port, "srvr")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(response, "\n") {
+		if version, ok := strings.CutPrefix(line, "Zookeeper version: "); ok {
+			return strings.TrimSpace(version), nil
+		}
+	}
+	return "", fmt.Errorf("could not get zookeeper version: %v", response)
+}
+
+// FourLetterWord sends a four letter word command (e.g. stat, envi, conf)
+// to the ZooKeeper server and returns the response.
+func (c *ZooKeeperClient) FourLetterWord(host string, port int, command string) (string, error) {
+	if len(command) != 4 {
+		return "", fmt.Errorf("invalid four letter word %q", command)
+	}
+	conn, err := dial(host, port)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(command)); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(conn, 1024*1024))
+	if err != nil && len(data) == 0 {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// IsUnauthenticated checks if the root znode can be listed without authentication.
+func (c *ZooKeeperClient) IsUnauthenticated(host string, port int) (bool, error) {
+	_, err := c.ListChildren(host, port, "", "", "/")
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListChildren returns the children of a znode path. If username is not
+// empty the session is authenticated using the digest scheme.
+func (c *ZooKeeperClient) ListChildren(host string, port int, username, password, path string) ([]string, error) {
+	session, err := newSession(host, port, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	data, err := session.request(opGetChildren, path)
+	if err != nil {
+		return nil, err
+	}
+	reader := bytes.NewReader(data)
+	var count int32
+	if err := binary.Read(reader, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	children := make([]string, 0, max(count, 0))
+	for i := int32(0); i < count; i++ {
+		child, err := readBuffer(reader)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, string(child))
+	}
+	return children, nil
+}
+
+// GetData returns the data of a znode path. If username is not empty
+// the session is authenticated using the digest scheme.
+func (c *ZooKeeperClient) GetData(host string, port int, username, password, path string) (string, error) {
+	session, err := newSession(host, port, username, password)
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	data, err := session.request(opGetData, path)
+	if err != nil {
+		return "", err
+	}
+	value, err := readBuffer(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// session is a ZooKeeper client session
+type session struct {
+	net.Conn
+	xid int32
+}
+
+// newSession connects to the server and optionally authenticates the session
+func newSession(host string, port int, username, password string) (*session, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{Conn: conn}
+
+	// ConnectRequest: protocolVersion, lastZxidSeen, timeOut, sessionId, passwd, readOnly
+	request := &bytes.Buffer{}
+	_ = binary.Write(request, binary.BigEndian, int32(0))
+	_ = binary.Write(request, binary.BigEndian, int64(0))
+	_ = binary.Write(request, binary.BigEndian, int32(defaultTimeout.Milliseconds()))
+	_ = binary.Write(request, binary.BigEndian, int64(0))
+	writeBuffer(request, make([]byte, 16))
+	request.WriteByte(0)
+	if err := s.write(request.Bytes()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := s.read(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("not a zookeeper server: %w", err)
+	}
+
+	if username != "" {
+		auth := &bytes.Buffer{}
+		_ = binary.Write(auth, binary.BigEndian, int32(xidAuth))
+		_ = binary.Write(auth, binary.BigEndian, int32(opAuth))
+		_ = binary.Write(auth, binary.BigEndian, int32(0)) // type
+		writeBuffer(auth, []byte("digest"))
+		writeBuffer(auth, []byte(username+":"+password))
+		if err := s.write(auth.Bytes()); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if _, err := s.reply(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// request sends a path request (without watch) and returns the reply body
+func (s *session) request(opcode int32, path string) ([]byte, error) {
+	s.xid++
+	request := &bytes.Buffer{}
+	_ = binary.Write(request, binary.BigEndian, s.xid)
+	_ = binary.Write(request, binary.BigEndian, opcode)
+	writeBuffer(request, []byte(path))
+	request.WriteByte(0) // watch
+	if err := s.write(request.Bytes()); err != nil {
+		return nil, err
+	}
+	return s.reply()
+}
+
+// reply reads a reply and returns its body if the reply has no error
+func (s *session) reply() ([]byte, error) {
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	// ReplyHeader: xid, zxid, err
+	if len(data) < 16 {
+		return nil, fmt.Errorf("invalid zookeeper reply")
+	}
+	switch code := int32(binary.BigEndian.Uint32(data[12:16])); code {
+	case 0:
+		return data[16:], nil
+	case errNoAuth:
+		return nil, fmt.Errorf("not authenticated")
+	case errAuthFail:
+		return nil, fmt.Errorf("authentication failed")
+	case errNoNode:
+		return nil, fmt.Errorf("node does not exist")
+	default:
+		return nil, fmt.Errorf("zookeeper error code %d", code)
+	}
+}
+
+func (s *session) write(data []byte) error {
+	packet := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint32(packet, uint32(len(data)))
+	_, err := s.Write(append(packet, data...))
+	return err
+}
+
+func (s *session) read() ([]byte, error) {
+	var length int32
+	if err := binary.Read(s, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < 0 || length > 4*1024*1024 {
+		return nil, fmt.Errorf("invalid zookeeper packet length %d", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(s, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func dial(host string, port int) (net.Conn, error) {
+	if !protocolstate.IsHostAllowed(host) {
+		// host is not valid according to network policy
+		return nil, protocolstate.ErrHostDenied.Msgf(host)
+	}
+	conn, err := protocolstate.Dialer.Dial(context.TODO(), "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(defaultTimeout))
+	return conn, nil
+}
+
+func writeBuffer(buffer *bytes.Buffer, data []byte) {
+	_ = binary.Write(buffer, binary.BigEndian, int32(len(data)))
+	buffer.Write(data)
+}
+
+func readBuffer(reader *bytes.Reader) ([]byte, error) {
+	var length int32
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return nil, nil
+	}
+	if int(length) > reader.Len() {
+		return nil, fmt.Errorf("invalid zookeeper buffer length %d", length)
+	}
+	data := make([]byte, length)
+	_, err := io.ReadFull(reader, data)
+	return data, err
+}
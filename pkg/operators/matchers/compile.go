@@ -69,6 +69,13 @@ func (matcher *Matcher) CompileMatchers() error {
 		matcher.dslCompiled = append(matcher.dslCompiled, compiledExpression)
 	}
 
+	// Compile the ignore patterns of the diff matcher
+	if matcher.Diff != nil {
+		if err := matcher.compileDiff(); err != nil {
+			return err
+		}
+	}
+
 	// Set up the condition type, if any.
 	if matcher.Condition != "" {
 		matcher.condition, ok = ConditionTypes[matcher.Condition]
@@ -0,0 +1,239 @@
+package matchers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Diff compares a response with a baseline response
+type Diff struct {
+	// description: |
+	//   Baseline is the number of a previous request of the template whose response is the baseline.
+	//
+	//   By default the baseline is captured automatically by sending the unmodified request, which is
+	//   the request before mutation for fuzzing rules and a GET request to the input otherwise.
+	// examples:
+	//   - value: 1
+	Baseline int `yaml:"baseline,omitempty" json:"baseline,omitempty" jsonschema:"title=number of the baseline request,description=Number of a previous request whose response is the baseline"`
+	// description: |
+	//   Status matches if the status code differs from the baseline.
+	Status bool `yaml:"status,omitempty" json:"status,omitempty" jsonschema:"title=match changed status code,description=Matches if the status code differs from the baseline"`
+	// description: |
+	//   Headers matches if the headers differ from the baseline ignoring headers with dynamic values like Date.
+	Headers bool `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=match changed headers,description=Matches if the headers differ from the baseline"`
+	// description: |
+	//   MinSimilarity is the minimum similarity of the part to the baseline between 0 and 1.
+	// examples:
+	//   - value: 0.95
+	MinSimilarity float64 `yaml:"min-similarity,omitempty" json:"min-similarity,omitempty" jsonschema:"title=minimum similarity,description=Minimum similarity of the part to the baseline between 0 and 1"`
+	// description: |
+	//   MaxSimilarity is the maximum similarity of the part to the baseline between 0 and 1.
+	// examples:
+	//   - value: 0.8
+	MaxSimilarity float64 `yaml:"max-similarity,omitempty" json:"max-similarity,omitempty" jsonschema:"title=maximum similarity,description=Maximum similarity of the part to the baseline between 0 and 1"`
+	// description: |
+	//   Ignore contains regex patterns of dynamic content removed before comparing the responses.
+	//
+	//   Numbers, UUIDs, hashes, timestamps and long tokens are always normalised.
+	// examples:
+	//   - value: >
+	//       []string{`csrf_token" value="[^"]+"`}
+	Ignore []string `yaml:"ignore,omitempty" json:"ignore,omitempty" jsonschema:"title=dynamic content to ignore,description=Regex patterns of dynamic content removed before comparing the responses"`
+}
+
+// DiffResponse is a response compared by diff matchers
+type DiffResponse struct {
+	StatusCode int
+	// Headers contains the raw headers of the response
+	Headers string
+	// Part is the part of the response compared by similarity
+	Part string
+}
+
+// dynamicHeaders are headers whose values change between responses
+var dynamicHeaders = map[string]struct{}{
+	"age":                           {},
+	"cf-ray":                        {},
+	"content-length":                {},
+	"date":                          {},
+	"etag":                          {},
+	"expires":                       {},
+	"last-modified":                 {},
+	"nel":                           {},
+	"report-to":                     {},
+	"server-timing":                 {},
+	"set-cookie":                    {},
+	"traceparent":                   {},
+	"x-amz-cf-id":                   {},
+	"x-amzn-requestid":              {},
+	"x-amzn-trace-id":               {},
+	"x-b3-traceid":                  {},
+	"x-cache-hits":                  {},
+	"x-correlation-id":              {},
+	"x-envoy-upstream-service-time": {},
+	"x-request-id":                  {},
+	"x-response-time":               {},
+	"x-runtime":                     {},
+	"x-served-by":                   {},
+	"x-timer":                       {},
+	"x-trace-id":                    {},
+	"x-varnish":                     {},
+}
+
+// dynamicContent matches content normalised before comparing responses
+var dynamicContent = []*regexp.Regexp{
+	// uuids
+	regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`),
+	// timestamps
+	regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`),
+	regexp.MustCompile(`(?i)(Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{2} [a-z]{3} \d{4} \d{2}:\d{2}:\d{2} [a-z]+`),
+	// hashes and long tokens
+	regexp.MustCompile(`(?i)\b[0-9a-f]{16,}\b`),
+	regexp.MustCompile(`[A-Za-z0-9+/_-]{32,}={0,2}`),
+	// numbers
+	regexp.MustCompile(`\d+`),
+}
+
+// compileDiff compiles the ignore patterns of the diff matcher
+func (matcher *Matcher) compileDiff() error {
+	for _, ignore := range matcher.Diff.Ignore {
+		compiled, err := regexp.Compile(ignore)
+		if err != nil {
+			return fmt.Errorf("could not compile diff ignore regex: %s", ignore)
+		}
+		matcher.diffIgnoreCompiled = append(matcher.diffIgnoreCompiled, compiled)
+	}
+	return nil
+}
+
+// validateDiff validates the configuration of the diff matcher
+func (matcher *Matcher) validateDiff() error {
+	diff := matcher.Diff
+	if diff == nil || !diff.Status && !diff.Headers && diff.MinSimilarity == 0 && diff.MaxSimilarity == 0 {
+		return errors.New("diff matcher requires status, headers, min-similarity or max-similarity")
+	}
+	if diff.Baseline < 0 {
+		return fmt.Errorf("invalid diff baseline request: %d", diff.Baseline)
+	}
+	if diff.MinSimilarity < 0 || diff.MinSimilarity > 1 || diff.MaxSimilarity < 0 || diff.MaxSimilarity > 1 {
+		return errors.New("diff similarity must be between 0 and 1")
+	}
+	if diff.MaxSimilarity > 0 && diff.MinSimilarity > diff.MaxSimilarity {
+		return errors.New("diff min-similarity can't be greater than max-similarity")
+	}
+	return nil
+}
+
+// MatchDiff matches the differences of a response to the baseline response
+func (matcher *Matcher) MatchDiff(response, baseline DiffResponse) bool {
+	var results []bool
+	if matcher.Diff.Status {
+		results = append(results, response.StatusCode != baseline.StatusCode)
+	}
+	if matcher.Diff.Headers {
+		results = append(results, len(matcher.ChangedHeaders(response.Headers, baseline.Headers)) > 0)
+	}
+	if matcher.Diff.MinSimilarity > 0 || matcher.Diff.MaxSimilarity > 0 {
+		similarity := matcher.Similarity(response.Part, baseline.Part)
+		matched := similarity >= matcher.Diff.MinSimilarity
+		if matcher.Diff.MaxSimilarity > 0 {
+			matched = matched && similarity <= matcher.Diff.MaxSimilarity
+		}
+		results = append(results, matched)
+	}
+
+	for _, result := range results {
+		if !result && matcher.condition == ANDCondition {
+			return false
+		}
+		if result && matcher.condition == ORCondition {
+			return true
+		}
+	}
+	return len(results) > 0 && matcher.condition == ANDCondition
+}
+
+// Similarity returns the similarity between 0 and 1 of the tokens of the
+// normalised contents, 1 means the contents contain the same tokens.
+func (matcher *Matcher) Similarity(content, baseline string) float64 {
+	tokens := tokenCounts(matcher.normalise(content))
+	baselineTokens := tokenCounts(matcher.normalise(baseline))
+	var intersection, union int
+	for token, count := range tokens {
+		baselineCount := baselineTokens[token]
+		intersection += min(count, baselineCount)
+		union += max(count, baselineCount)
+	}
+	for token, count := range baselineTokens {
+		if _, ok := tokens[token]; !ok {
+			union += count
+		}
+	}
+	if union == 0 {
+		return 1
+	}
+	return float64(intersection) / float64(union)
+}
+
+// ChangedHeaders returns the names of the headers which differ from the baseline
+func (matcher *Matcher) ChangedHeaders(headers, baseline string) []string {
+	values := matcher.parseHeaders(headers)
+	baselineValues := matcher.parseHeaders(baseline)
+	var changed []string
+	for name, value := range values {
+		if baselineValue, ok := baselineValues[name]; !ok || value != baselineValue {
+			changed = append(changed, name)
+		}
+	}
+	for name := range baselineValues {
+		if _, ok := values[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// parseHeaders returns the normalised values of raw headers without dynamic headers
+func (matcher *Matcher) parseHeaders(headers string) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(headers, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(name, "HTTP/") {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := dynamicHeaders[name]; ok {
+			continue
+		}
+		if previous, ok := values[name]; ok {
+			value = previous + "," + value
+		}
+		values[name] = matcher.normalise(strings.TrimSpace(value))
+	}
+	return values
+}
+
+// normalise removes dynamic content from the content
+func (matcher *Matcher) normalise(content string) string {
+	for _, ignore := range matcher.diffIgnoreCompiled {
+		content = ignore.ReplaceAllString(content, "")
+	}
+	for _, dynamic := range dynamicContent {
+		content = dynamic.ReplaceAllString(content, "0")
+	}
+	return content
+}
+
+// tokenCounts returns the number of occurrences of the alphanumeric tokens of the content
+func tokenCounts(content string) map[string]int {
+	counts := make(map[string]int)
+	for _, token := range strings.FieldsFunc(content, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		counts[token]++
+	}
+	return counts
+}
@@ -209,3 +209,43 @@ func TestMatcher_MatchXPath_XML(t *testing.T) {
 	isMatched = m.MatchXPath("<h1> not right <q id=2/>notvalid")
 	require.False(t, isMatched, "Invalid xpath did not return false")
 }
+
+func TestMatchDiff(t *testing.T) {
+	baseline := DiffResponse{
+		StatusCode: 200,
+		Headers:    "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nDate: Mon, 02 Jan 2023 10:00:00 GMT\r\nX-Request-Id: 1\r\n",
+		Part:       `<html><p>Welcome back, john</p><p>Generated at 2023-01-02T10:00:00Z, request 4a2f13c9e8d7b6a5f4e3d2c1</p></html>`,
+	}
+	same := DiffResponse{
+		StatusCode: 200,
+		Headers:    "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nDate: Tue, 03 Jan 2023 11:00:00 GMT\r\nX-Request-Id: 2\r\n",
+		Part:       `<html><p>Welcome back, john</p><p>Generated at 2023-01-03T11:22:33Z, request 9f8e7d6c5b4a39281706f5e4</p></html>`,
+	}
+	changed := DiffResponse{
+		StatusCode: 500,
+		Headers:    "HTTP/1.1 500 Internal Server Error\r\nContent-Type: application/json\r\n",
+		Part:       `{"error": "syntax error near 'john'"}`,
+	}
+
+	m := &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Diff: &Diff{MinSimilarity: 0.99}}
+	require.Nil(t, m.CompileMatchers())
+	require.Equal(t, 1.0, m.Similarity(same.Part, baseline.Part), "dynamic content was not normalised")
+	require.True(t, m.MatchDiff(same, baseline), "could not match similar response")
+	require.False(t, m.MatchDiff(changed, baseline), "matched different response")
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Condition: "and", Diff: &Diff{Status: true, Headers: true, MaxSimilarity: 0.5}}
+	require.Nil(t, m.CompileMatchers())
+	require.True(t, m.MatchDiff(changed, baseline), "could not match changed response")
+	require.False(t, m.MatchDiff(same, baseline), "matched unchanged response")
+	require.ElementsMatch(t, []string{"content-type"}, m.ChangedHeaders(changed.Headers, baseline.Headers))
+	require.Empty(t, m.ChangedHeaders(same.Headers, baseline.Headers))
+
+	m = &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Diff: &Diff{MinSimilarity: 1, Ignore: []string{`john|jane`}}}
+	require.Nil(t, m.CompileMatchers())
+	require.True(t, m.MatchDiff(DiffResponse{Part: "Welcome back, jane"}, DiffResponse{Part: "Welcome back, john"}), "ignored content was compared")
+
+	for _, diff := range []*Diff{nil, {}, {MinSimilarity: 1.5}, {MinSimilarity: 0.9, MaxSimilarity: 0.5}, {Status: true, Ignore: []string{"("}}} {
+		m = &Matcher{Type: MatcherTypeHolder{MatcherType: DiffMatcher}, Diff: diff}
+		require.NotNil(t, m.CompileMatchers(), "invalid diff matcher compiled: %+v", diff)
+	}
+}
@@ -104,6 +104,16 @@ type Matcher struct {
 	//       []string{"//a[@target="_blank"]"}
 	XPath []string `yaml:"xpath,omitempty" json:"xpath,omitempty" jsonschema:"title=xpath queries to match in response,description=xpath are the XPath queries that will be evaluated against the response part of vulmap matching rules"`
 	// description: |
+	//   Diff compares the response with a baseline response.
+	//
+	//   The baseline is the response to the unmodified request or to a previous request of the template.
+	//   Status code, headers and the similarity of the part to the baseline can be compared.
+	// examples:
+	//   - name: Match responses differing from the baseline
+	//     value: >
+	//       &Diff{Status: true, MaxSimilarity: 0.8}
+	Diff *Diff `yaml:"diff,omitempty" json:"diff,omitempty" jsonschema:"title=diff with baseline response,description=Diff compares the response with a baseline response"`
+	// description: |
 	//   Encoding specifies the encoding for the words field if any.
 	// values:
 	//   - "hex"
@@ -127,6 +137,8 @@ type Matcher struct {
 	binaryDecoded []string
 	regexCompiled []*regexp.Regexp
 	dslCompiled   []*govaluate.EvaluableExpression
+	// diffIgnoreCompiled contains the compiled ignore patterns of the diff matcher
+	diffIgnoreCompiled []*regexp.Regexp
 }
 
 // ConditionType is the type of condition for matcher
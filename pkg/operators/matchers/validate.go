@@ -41,12 +41,20 @@ func (matcher *Matcher) Validate() error {
 		expectedFields = append(commonExpectedFields, "Regex", "Part", "Encoding", "CaseInsensitive")
 	case XPathMatcher:
 		expectedFields = append(commonExpectedFields, "XPath", "Part")
+	case DiffMatcher:
+		expectedFields = append(commonExpectedFields, "Diff", "Part")
 	}
 
 	if err = checkFields(matcher, matcherMap, expectedFields...); err != nil {
 		return err
 	}
 
+	if matcher.matcherType == DiffMatcher {
+		if err := matcher.validateDiff(); err != nil {
+			return err
+		}
+	}
+
 	// validate the XPath query
 	if matcher.matcherType == XPathMatcher {
 		for _, query := range matcher.XPath {
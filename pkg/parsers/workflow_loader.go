@@ -28,6 +28,7 @@ func NewLoader(options *protocols.ExecutorOptions) (model.WorkflowLoader, error)
 		Protocols:         options.Options.Protocols,
 		ExcludeProtocols:  options.Options.ExcludeProtocols,
 		IncludeConditions: options.Options.IncludeConditions,
+		Queries:           options.Options.TemplateQuery,
 	})
 	if err != nil {
 		return nil, err
@@ -23,6 +23,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
 	protocolutils "github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	errorutil "github.com/khulnasoft-lab/utils/errors"
@@ -58,6 +59,11 @@ type Request struct {
 func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	request.options = options
 
+	// code requests are not recorded, so replayed scans would send them
+	if options.Replay.Replaying() {
+		return errors.Wrap(replay.ErrNotReplayable, "code")
+	}
+
 	gozeroOptions := &gozero.Options{
 		Engines:                  request.Engine,
 		Args:                     request.Args,
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/md5"
 	"fmt"
+	"maps"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
@@ -15,10 +16,23 @@ type MetaInput struct {
 	Input string `json:"input,omitempty"`
 	// CustomIP to use for connection
 	CustomIP string `json:"customIP,omitempty"`
+	// Request is the full request of the input discovered by crawling, used
+	// as base request by fuzzing templates
+	Request *Request `json:"request,omitempty"`
 	// hash of the input
 	hash string `json:"-"`
 }
 
+// Request is a full http request used as input
+type Request struct {
+	// Method is the method of the request
+	Method string `json:"method"`
+	// Headers contains the headers of the request
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the body of the request
+	Body string `json:"body,omitempty"`
+}
+
 func (metaInput *MetaInput) marshalToBuffer() (bytes.Buffer, error) {
 	var b bytes.Buffer
 	err := jsoniter.NewEncoder(&b).Encode(metaInput)
@@ -58,10 +72,18 @@ func (metaInput *MetaInput) Unmarshal(data string) error {
 }
 
 func (metaInput *MetaInput) Clone() *MetaInput {
-	return &MetaInput{
+	cloned := &MetaInput{
 		Input:    metaInput.Input,
 		CustomIP: metaInput.CustomIP,
 	}
+	if metaInput.Request != nil {
+		cloned.Request = &Request{
+			Method:  metaInput.Request.Method,
+			Headers: maps.Clone(metaInput.Request.Headers),
+			Body:    metaInput.Request.Body,
+		}
+	}
+	return cloned
 }
 
 func (metaInput *MetaInput) PrettyPrint() string {
@@ -77,7 +99,11 @@ func (metaInput *MetaInput) GetScanHash(templateId string) string {
 	// but that totally changes the scanID/hash so to avoid that we compute hash only once
 	// and reuse it for all subsequent calls
 	if metaInput.hash == "" {
-		metaInput.hash = getMd5Hash(templateId + ":" + metaInput.Input + ":" + metaInput.CustomIP)
+		data := templateId + ":" + metaInput.Input + ":" + metaInput.CustomIP
+		if metaInput.Request != nil {
+			data += ":" + metaInput.Request.Method + ":" + metaInput.Request.Body
+		}
+		metaInput.hash = getMd5Hash(data)
 	}
 	return metaInput.hash
 }
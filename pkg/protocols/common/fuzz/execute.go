@@ -1,6 +1,7 @@
 package fuzz
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -8,8 +9,12 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/khulnasoft-lab/retryablehttp-go"
 	errorutil "github.com/khulnasoft-lab/utils/errors"
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
+	urlutil "github.com/khulnasoft-lab/utils/url"
 )
 
 // ExecuteRuleInput is the input for rule Execute function
@@ -24,6 +29,9 @@ type ExecuteRuleInput struct {
 	Values map[string]interface{}
 	// BaseRequest is the base http request for fuzzing rule
 	BaseRequest *retryablehttp.Request
+	// Timing is the timing analysis of blind payloads, requests are
+	// generated for each delay of payloads using the delay variable.
+	Timing *timing.Config
 }
 
 // GeneratedRequest is a single generated request for rule
@@ -34,6 +42,9 @@ type GeneratedRequest struct {
 	InteractURLs []string
 	// DynamicValues contains dynamic values map
 	DynamicValues map[string]interface{}
+	// TimingRequests contains the request for each delay of the timing
+	// analysis including the control request without delay
+	TimingRequests map[int]*retryablehttp.Request
 }
 
 // Execute executes a fuzzing rule accepting a callback on which
@@ -80,12 +91,22 @@ func (rule *Rule) isExecutable(req *retryablehttp.Request) bool {
 	if len(req.Header) > 0 && rule.partType == headersPartType {
 		return true
 	}
+	if rule.partType == graphqlPartType {
+		_, _, err := graphqlVariables(req)
+		return err == nil
+	}
 	return false
 }
 
 // executeRuleValues executes a rule with a set of values
 func (rule *Rule) executeRuleValues(input *ExecuteRuleInput) error {
 	for _, payload := range rule.Fuzz {
+		if input.Timing != nil && strings.Contains(payload, input.Timing.GetVariable()) {
+			if err := rule.executeTimingPartRule(input, payload); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := rule.executePartRule(input, payload); err != nil {
 			return err
 		}
@@ -93,6 +114,50 @@ func (rule *Rule) executeRuleValues(input *ExecuteRuleInput) error {
 	return nil
 }
 
+// executeTimingPartRule executes a part rule for each delay of the timing analysis
+// and returns the requests for the same position with the requests for all delays.
+func (rule *Rule) executeTimingPartRule(input *ExecuteRuleInput, payload string) error {
+	values, callback := input.Values, input.Callback
+	defer func() {
+		input.Values, input.Callback = values, callback
+	}()
+
+	delays := append([]int{0}, input.Timing.GetDelays()...)
+	generated := make([][]GeneratedRequest, len(delays))
+	for i, delay := range delays {
+		i := i
+		input.Values = input.Timing.Values(values, delay)
+		input.Callback = func(gr GeneratedRequest) bool {
+			// the values of generated requests are reset after the callback
+			gr.Request = gr.Request.Clone(context.TODO())
+			params := urlutil.NewOrderedParams()
+			gr.Request.URL.Query().Iterate(func(key string, values []string) bool {
+				params.Add(key, sliceutil.Clone(values)...)
+				return true
+			})
+			gr.Request.URL.Params = params
+			generated[i] = append(generated[i], gr)
+			return true
+		}
+		if err := rule.executePartRule(input, payload); err != nil {
+			return err
+		}
+	}
+
+	for i, request := range generated[0] {
+		request.TimingRequests = make(map[int]*retryablehttp.Request, len(delays))
+		for j, delay := range delays {
+			if i < len(generated[j]) {
+				request.TimingRequests[delay] = generated[j][i].Request
+			}
+		}
+		if !callback(request) {
+			return types.ErrNoMoreRequests
+		}
+	}
+	return nil
+}
+
 // Compile compiles a fuzzing rule and initializes it for operation
 func (rule *Rule) Compile(generator *generators.PayloadGenerator, options *protocols.ExecutorOptions) error {
 	// If a payload generator is specified from base request, use it
@@ -25,10 +25,13 @@ type Rule struct {
 	// description: |
 	//   Part is the part of request to fuzz.
 	//
-	//   query fuzzes the query part of url. More parts will be added later.
+	//   query fuzzes the query part of url. headers fuzzes the request headers.
+	//   graphql fuzzes the variables of a GraphQL request body.
 	// values:
 	//   - "query"
-	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query"`
+	//   - "headers"
+	//   - "graphql"
+	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query,enum=headers,enum=graphql"`
 	partType partType
 	// description: |
 	//   Mode is the mode of fuzzing to perform.
@@ -100,11 +103,13 @@ type partType int
 const (
 	queryPartType partType = iota + 1
 	headersPartType
+	graphqlPartType
 )
 
 var stringToPartType = map[string]partType{
 	"query":   queryPartType,
 	"headers": headersPartType,
+	"graphql": graphqlPartType,
 }
 
 // modeType is the mode of rule enum declaration
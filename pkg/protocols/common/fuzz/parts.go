@@ -2,8 +2,10 @@ package fuzz
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -25,6 +27,8 @@ func (rule *Rule) executePartRule(input *ExecuteRuleInput, payload string) error
 		return rule.executeQueryPartRule(input, payload)
 	case headersPartType:
 		return rule.executeHeadersPartRule(input, payload)
+	case graphqlPartType:
+		return rule.executeGraphQLPartRule(input, payload)
 	}
 	return nil
 }
@@ -37,7 +41,15 @@ func (rule *Rule) executeHeadersPartRule(input *ExecuteRuleInput, payload string
 	// Also clone headers
 	headers := req.Header.Clone()
 
-	for key, values := range originalRequest.Header {
+	// headers are fuzzed in a stable order so requests can be generated repeatedly
+	keys := make([]string, 0, len(originalRequest.Header))
+	for key := range originalRequest.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		values := originalRequest.Header[key]
 		cloned := sliceutil.Clone(values)
 		for i, value := range values {
 			if !rule.matchKeyOrValue(key, value) {
@@ -111,6 +123,43 @@ func (rule *Rule) executeQueryPartRule(input *ExecuteRuleInput, payload string)
 	return err
 }
 
+// executeGraphQLPartRule executes graphql variables part rules
+func (rule *Rule) executeGraphQLPartRule(input *ExecuteRuleInput, payload string) error {
+	body, variables, err := graphqlVariables(input.BaseRequest)
+	if err != nil {
+		return err
+	}
+
+	var buildErr error
+	walkGraphQLVariables(variables, func(key string, value interface{}, set func(interface{})) bool {
+		original := types.ToString(value)
+		if !rule.matchKeyOrValue(key, original) {
+			return true
+		}
+		var evaluated string
+		evaluated, input.InteractURLs = rule.executeEvaluate(input, key, original, payload, input.InteractURLs)
+		set(evaluated)
+
+		if rule.modeType == singleModeType {
+			if buildErr = rule.buildGraphQLInput(input, body, variables, input.InteractURLs); buildErr != nil {
+				return false
+			}
+			set(value) // change back to previous value for variables
+		}
+		return true
+	})
+	if buildErr != nil {
+		return buildErr
+	}
+
+	if rule.modeType == multipleModeType {
+		if err := rule.buildGraphQLInput(input, body, variables, input.InteractURLs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // buildHeadersInput returns created request for a Headers Input
 func (rule *Rule) buildHeadersInput(input *ExecuteRuleInput, headers http.Header, interactURLs []string) error {
 	var req *retryablehttp.Request
@@ -159,6 +208,90 @@ func (rule *Rule) buildQueryInput(input *ExecuteRuleInput, parsed *urlutil.URL,
 	return nil
 }
 
+// buildGraphQLInput returns created request for a GraphQL variables Input
+func (rule *Rule) buildGraphQLInput(input *ExecuteRuleInput, body, variables map[string]interface{}, interactURLs []string) error {
+	body["variables"] = variables
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	base := input.BaseRequest
+	req, err := retryablehttp.NewRequestFromURL(base.Method, base.URL.Clone(), data)
+	if err != nil {
+		return err
+	}
+	req.Header = base.Header.Clone()
+	req.Request.Host = base.Request.Host
+
+	request := GeneratedRequest{
+		Request:       req,
+		InteractURLs:  interactURLs,
+		DynamicValues: input.Values,
+	}
+	if !input.Callback(request) {
+		return types.ErrNoMoreRequests
+	}
+	return nil
+}
+
+// graphqlVariables returns the decoded JSON body of a GraphQL request
+// along with its variables object.
+func graphqlVariables(req *retryablehttp.Request) (map[string]interface{}, map[string]interface{}, error) {
+	data, err := req.BodyBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	body := make(map[string]interface{})
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, nil, errors.Wrap(err, "could not decode graphql body")
+	}
+	variables, ok := body["variables"].(map[string]interface{})
+	if !ok || len(variables) == 0 {
+		return nil, nil, errors.New("graphql body does not contain variables")
+	}
+	return body, variables, nil
+}
+
+// walkGraphQLVariables calls the callback for each scalar value in the
+// variables, recursing into input objects and lists in a stable order.
+func walkGraphQLVariables(variables map[string]interface{}, callback func(key string, value interface{}, set func(interface{})) bool) bool {
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		key := key
+		switch value := variables[key].(type) {
+		case map[string]interface{}:
+			if !walkGraphQLVariables(value, callback) {
+				return false
+			}
+		case []interface{}:
+			for i := range value {
+				i := i
+				if nested, ok := value[i].(map[string]interface{}); ok {
+					if !walkGraphQLVariables(nested, callback) {
+						return false
+					}
+					continue
+				}
+				if !callback(key, value[i], func(v interface{}) { value[i] = v }) {
+					return false
+				}
+			}
+		case nil:
+			continue
+		default:
+			if !callback(key, value, func(v interface{}) { variables[key] = v }) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // executeEvaluate executes evaluation of payload on a key and value and
 // returns completed values to be replaced and processed
 // for fuzzing.
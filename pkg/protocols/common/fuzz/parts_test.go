@@ -8,6 +8,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
 	"github.com/stretchr/testify/require"
 )
 
@@ -142,3 +143,77 @@ func TestExecuteReplaceRule(t *testing.T) {
 		require.Equal(t, test.expected, returned, "could not get correct value")
 	}
 }
+
+func TestExecuteGraphQLPartRule(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	body := `{"query":"query user($id: ID!, $name: String) { user(id: $id, name: $name) { id } }","variables":{"id":"1","name":"test"}}`
+	req, err := retryablehttp.NewRequest("POST", "http://localhost:8080/graphql", body)
+	require.NoError(t, err, "can't build request")
+	req.Header.Set("Content-Type", "application/json")
+
+	t.Run("single", func(t *testing.T) {
+		rule := &Rule{
+			ruleType: postfixRuleType,
+			partType: graphqlPartType,
+			modeType: singleModeType,
+			options:  options,
+		}
+		require.True(t, rule.isExecutable(req), "could not detect graphql variables")
+
+		var generatedBodies []string
+		err := rule.executeGraphQLPartRule(&ExecuteRuleInput{
+			Input:       contextargs.New(),
+			BaseRequest: req,
+			Callback: func(gr GeneratedRequest) bool {
+				data, _ := gr.Request.BodyBytes()
+				generatedBodies = append(generatedBodies, string(data))
+				require.Equal(t, "application/json", gr.Request.Header.Get("Content-Type"), "could not get headers")
+				return true
+			},
+		}, "1337'")
+		require.NoError(t, err, "could not execute part rule")
+		require.Len(t, generatedBodies, 2, "could not get generated bodies")
+		require.Contains(t, generatedBodies[0], `"variables":{"id":"11337'","name":"test"}`)
+		require.Contains(t, generatedBodies[1], `"variables":{"id":"1","name":"test1337'"}`)
+	})
+
+	t.Run("not-executable", func(t *testing.T) {
+		rule := &Rule{partType: graphqlPartType}
+		req, err := retryablehttp.NewRequest("POST", "http://localhost:8080/graphql", `{"query":"{ __typename }"}`)
+		require.NoError(t, err, "can't build request")
+		require.False(t, rule.isExecutable(req), "could execute rule without variables")
+	})
+}
+
+func TestExecuteTimingPartRule(t *testing.T) {
+	options := &protocols.ExecutorOptions{
+		Interactsh: &interactsh.Client{},
+	}
+	rule := &Rule{
+		ruleType: postfixRuleType,
+		partType: queryPartType,
+		modeType: singleModeType,
+		options:  options,
+	}
+	input := contextargs.NewWithInput("http://localhost:8080/?id=1&name=test")
+	var generated []GeneratedRequest
+	err := rule.executeTimingPartRule(&ExecuteRuleInput{
+		Input:  input,
+		Timing: &timing.Config{Delays: []int{2, 4}},
+		Callback: func(gr GeneratedRequest) bool {
+			generated = append(generated, gr)
+			return true
+		},
+	}, "' AND SLEEP({{delay}})-- ")
+	require.NoError(t, err, "could not execute part rule")
+	require.Len(t, generated, 2, "could not get generated requests")
+
+	first := generated[0].TimingRequests
+	require.Len(t, first, 3, "could not get requests for delays")
+	require.Equal(t, "1' AND SLEEP(0)-- ", first[0].URL.Query().Get("id"), "could not get control request")
+	require.Equal(t, "1' AND SLEEP(4)-- ", first[4].URL.Query().Get("id"), "could not get delayed request")
+	require.Equal(t, "test", first[4].URL.Query().Get("name"), "could not keep other parameters")
+	require.Equal(t, "test' AND SLEEP(2)-- ", generated[1].TimingRequests[2].URL.Query().Get("name"), "could not get delayed request for second parameter")
+}
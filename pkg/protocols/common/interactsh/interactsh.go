@@ -97,6 +97,7 @@ func (c *Client) poll() error {
 	gologger.Info().Msgf("Using Interactsh Server: %s", interactDomain)
 
 	c.setHostname(interactDomain)
+	c.recordServer(interactDomain)
 
 	err = interactsh.StartPolling(c.pollDuration, func(interaction *server.Interaction) {
 		request, err := c.requests.Get(interaction.UniqueID)
@@ -151,6 +152,8 @@ func requestShouldStopAtFirstMatch(request *RequestData) bool {
 
 // processInteractionForRequest processes an interaction for a request
 func (c *Client) processInteractionForRequest(interaction *server.Interaction, data *RequestData) bool {
+	c.recordInteraction(interaction, data)
+
 	data.Event.Lock()
 	data.Event.InternalEvent["interactsh_protocol"] = interaction.Protocol
 	data.Event.InternalEvent["interactsh_request"] = interaction.RawRequest
@@ -212,11 +215,18 @@ func (c *Client) URL() (string, error) {
 	// first time initialization
 	var err error
 	c.Do(func() {
-		err = c.poll()
+		if c.options.Replay.Replaying() {
+			err = c.initReplay()
+		} else {
+			err = c.poll()
+		}
 	})
 	if err != nil {
 		return "", errorutil.NewWithErr(err).Wrap(ErrInteractshClientNotInitialized)
 	}
+	if c.options.Replay.Replaying() {
+		return c.replayURL()
+	}
 
 	if c.interactsh == nil {
 		return "", ErrInteractshClientNotInitialized
@@ -309,6 +319,11 @@ type RequestData struct {
 
 // RequestEvent is the event for a network request sent by vulmap.
 func (c *Client) RequestEvent(interactshURLs []string, data *RequestData) {
+	if c.options.Replay.Replaying() {
+		// recorded interactions are processed right away
+		c.replayInteractions(data)
+		return
+	}
 	for _, interactshURL := range interactshURLs {
 		id := strings.TrimRight(strings.TrimSuffix(interactshURL, c.getHostname()), ".")
 
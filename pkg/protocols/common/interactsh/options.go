@@ -6,6 +6,7 @@ import (
 	"github.com/projectdiscovery/interactsh/pkg/client"
 	"github.com/khulnasoft-lab/vulmap/pkg/output"
 	"github.com/khulnasoft-lab/vulmap/pkg/progress"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
 	"github.com/khulnasoft-lab/vulmap/pkg/reporting"
 	"github.com/khulnasoft-lab/retryablehttp-go"
 )
@@ -48,6 +49,8 @@ type Options struct {
 
 	StopAtFirstMatch bool
 	HTTPClient       *retryablehttp.Client
+	// Replay records or replays interactions if enabled
+	Replay *replay.Store
 }
 
 // DefaultOptions returns the default options for interactsh client
@@ -0,0 +1,101 @@
+package interactsh
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/projectdiscovery/interactsh/pkg/server"
+)
+
+const (
+	// replayProtocol is the protocol interactions are recorded as
+	replayProtocol = "interactsh"
+	// replayServerKey is the key the interactsh server domain is recorded as
+	replayServerKey = "server"
+	// defaultReplayServer is the domain of replayed urls if no server was recorded
+	defaultReplayServer = "oast.replay"
+)
+
+// initReplay initializes the client for replaying recorded interactions
+// without polling an interactsh server.
+func (c *Client) initReplay() error {
+	if c.options.NoInteractsh {
+		return ErrInteractshClientNotInitialized
+	}
+	domain := defaultReplayServer
+	if entry, err := c.options.Replay.Replay(replayProtocol, replayServerKey); err == nil && len(entry.Data) > 0 {
+		domain = string(entry.Data[0])
+	}
+	c.options.Replay.AddDynamicDomain(domain)
+	c.setHostname(domain)
+	return nil
+}
+
+// recordServer records the domain of the interactsh server
+func (c *Client) recordServer(domain string) {
+	if !c.options.Replay.Recording() {
+		return
+	}
+	c.options.Replay.AddDynamicDomain(domain)
+	if err := c.options.Replay.Record(replayProtocol, replayServerKey, []byte(domain)); err != nil {
+		gologger.Warning().Msgf("Could not record interactsh server: %s", err)
+	}
+}
+
+// replayURL returns a new url for the replayed interactsh server
+func (c *Client) replayURL() (string, error) {
+	id := make([]byte, 17)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", hex.EncodeToString(id)[:33], c.getHostname()), nil
+}
+
+// replayKey returns the key identifying the interactions of a request in
+// recordings. Interactions are recorded per template and host.
+func replayKey(data *RequestData) string {
+	data.Event.RLock()
+	defer data.Event.RUnlock()
+
+	templateId, _ := data.Event.InternalEvent[templateIdAttribute].(string)
+	host, _ := data.Event.InternalEvent["host"].(string)
+	return fmt.Sprintf("interaction\n%s\n%s", templateId, host)
+}
+
+// recordInteraction records an interaction received for a request
+func (c *Client) recordInteraction(interaction *server.Interaction, data *RequestData) {
+	if !c.options.Replay.Recording() {
+		return
+	}
+	marshaled, err := json.Marshal(interaction)
+	if err == nil {
+		err = c.options.Replay.Record(replayProtocol, replayKey(data), marshaled)
+	}
+	if err != nil {
+		gologger.Warning().Msgf("Could not record interaction: %s", err)
+	}
+}
+
+// replayInteractions processes the interactions recorded for a request
+func (c *Client) replayInteractions(data *RequestData) {
+	entries, err := c.options.Replay.ReplayAll(replayProtocol, replayKey(data))
+	if err != nil {
+		gologger.Warning().Msgf("Could not replay interactions: %s", err)
+		return
+	}
+	for _, entry := range entries {
+		if len(entry.Data) == 0 {
+			continue
+		}
+		interaction := &server.Interaction{}
+		if err := json.Unmarshal(entry.Data[0], interaction); err != nil {
+			continue
+		}
+		if c.processInteractionForRequest(interaction, data) {
+			return
+		}
+	}
+}
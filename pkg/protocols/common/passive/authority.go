@@ -0,0 +1,164 @@
+package passive
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// CertificateFile is the name of the certificate file of the authority
+	CertificateFile = "passive-proxy-ca.crt"
+	// KeyFile is the name of the private key file of the authority
+	KeyFile = "passive-proxy-ca.key"
+)
+
+// Authority is a local certificate authority issuing the certificates of
+// the intercepted hosts.
+type Authority struct {
+	// CertificatePath is the path of the certificate to trust in the browser
+	CertificatePath string
+
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+
+	mu           sync.Mutex
+	certificates map[string]*tls.Certificate
+}
+
+// LoadOrCreateAuthority loads the certificate authority from the directory,
+// generating a new one if it doesn't exist yet.
+func LoadOrCreateAuthority(directory string) (*Authority, error) {
+	certificatePath := filepath.Join(directory, CertificateFile)
+	keyPath := filepath.Join(directory, KeyFile)
+
+	if _, err := os.Stat(certificatePath); os.IsNotExist(err) {
+		if err := createAuthority(certificatePath, keyPath); err != nil {
+			return nil, errors.Wrap(err, "could not create certificate authority")
+		}
+	}
+
+	pair, err := tls.LoadX509KeyPair(certificatePath, keyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load certificate authority")
+	}
+	certificate, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse certificate authority")
+	}
+	key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("certificate authority key is not an ecdsa key")
+	}
+	return &Authority{
+		CertificatePath: certificatePath,
+		certificate:     certificate,
+		key:             key,
+		certificates:    make(map[string]*tls.Certificate),
+	}, nil
+}
+
+// createAuthority generates a certificate authority and writes it to the paths
+func createAuthority(certificatePath, keyPath string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "vulmap passive proxy CA", Organization: []string{"vulmap"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(certificatePath), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(certificatePath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// Certificate returns the certificate of the host issued by the authority
+func (authority *Authority) Certificate(host string) (*tls.Certificate, error) {
+	authority.mu.Lock()
+	defer authority.mu.Unlock()
+
+	if certificate, ok := authority.certificates[host]; ok {
+		return certificate, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, authority.certificate, &key.PublicKey, authority.key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not issue certificate for %s", host)
+	}
+
+	certificate := &tls.Certificate{
+		Certificate: [][]byte{der, authority.certificate.Raw},
+		PrivateKey:  key,
+	}
+	authority.certificates[host] = certificate
+	return certificate, nil
+}
+
+// CertPool returns a certificate pool trusting the authority
+func (authority *Authority) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(authority.certificate)
+	return pool
+}
+
+// serialNumber returns a random serial number for a certificate
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
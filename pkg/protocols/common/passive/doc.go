@@ -0,0 +1,15 @@
+// Package passive implements the passive proxy mode of vulmap.
+//
+// An intercepting HTTP(S) proxy is run while testers browse the targets. Every
+// observed request and response pair is matched with the passive templates,
+// which are the templates compatible with offline matching (requests only to
+// {{BaseURL}}), without sending any additional request. This reports issues
+// like missing security headers, leaked secrets or verbose errors.
+//
+// HTTPS traffic is intercepted using certificates issued by a local
+// certificate authority generated in the vulmap configuration directory on
+// first use, which has to be trusted by the browser.
+//
+// Optionally the observed requests are used as inputs of the fuzzing
+// templates, each unique URL being fuzzed once.
+package passive
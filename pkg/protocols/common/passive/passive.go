@@ -0,0 +1,189 @@
+package passive
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/core"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/offlinehttp"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// Service is a service running the passive proxy
+type Service struct {
+	opts          protocols.ExecutorOptions
+	engine        *core.Engine
+	authority     *Authority
+	server        *http.Server
+	transport     *http.Transport
+	childExecuter *core.ChildExecuter
+
+	passiveTemplates []*templates.Template
+	fuzzTemplates    []*templates.Template
+
+	results *atomic.Bool
+	fuzzed  sync.Map
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Options contains configuration options for the passive proxy service
+type Options struct {
+	ExecuterOpts protocols.ExecutorOptions
+	Templates    []*templates.Template
+	Engine       *core.Engine
+	// Address is the listen address of the proxy
+	Address string
+	// Directory is the directory of the certificate authority
+	Directory string
+}
+
+// New takes options and returns a new passive proxy service
+func New(opts Options) (*Service, error) {
+	authority, err := LoadOrCreateAuthority(opts.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		opts:          opts.ExecuterOpts,
+		engine:        opts.Engine,
+		authority:     authority,
+		childExecuter: opts.Engine.ChildExecuter(),
+		results:       &atomic.Bool{},
+		closed:        make(chan struct{}),
+	}
+	// templates with fuzzing rules are only loaded when fuzzing is enabled,
+	// the other templates are compiled for offline matching
+	for _, template := range opts.Templates {
+		if template.HasFuzzingRules() {
+			s.fuzzTemplates = append(s.fuzzTemplates, template)
+		} else {
+			s.passiveTemplates = append(s.passiveTemplates, template)
+		}
+	}
+	if len(s.passiveTemplates)+len(s.fuzzTemplates) == 0 {
+		return nil, errors.New("no passive templates provided for passive proxy")
+	}
+
+	s.transport = &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		TLSClientConfig:       newClientTLSConfig(),
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		ResponseHeaderTimeout: time.Duration(opts.ExecuterOpts.Options.Timeout) * time.Second,
+	}
+	if types.ProxyURL != "" {
+		if proxyURL, err := url.Parse(types.ProxyURL); err == nil {
+			s.transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	s.server = &http.Server{
+		Addr:              opts.Address,
+		Handler:           s,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	return s, nil
+}
+
+// CertificatePath returns the path of the certificate authority to trust
+func (s *Service) CertificatePath() string {
+	return s.authority.CertificatePath
+}
+
+// ListenAndServe runs the proxy until the service is closed
+func (s *Service) ListenAndServe() error {
+	gologger.Info().Msgf("Running passive proxy on %s with %d passive and %d fuzzing templates", s.server.Addr, len(s.passiveTemplates), len(s.fuzzTemplates))
+	gologger.Info().Msgf("Trust the certificate authority %s in the browser to intercept HTTPS traffic", s.authority.CertificatePath)
+
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close stops the proxy, waits for the running templates and returns
+// true if results were found. The service is only closed once, the
+// next calls wait for the first one and return its result.
+func (s *Service) Close() bool {
+	s.closeOnce.Do(func() {
+		_ = s.server.Close()
+		s.transport.CloseIdleConnections()
+		s.engine.WorkPool().Wait()
+		if s.childExecuter.Close().Load() {
+			s.results.Store(true)
+		}
+		close(s.closed)
+	})
+	return s.Wait()
+}
+
+// Wait waits until the service is closed and returns true if results were found
+func (s *Service) Wait() bool {
+	<-s.closed
+	return s.results.Load()
+}
+
+// observe matches the observed request and response pair with the passive
+// templates and executes the fuzzing templates on new requests.
+func (s *Service) observe(req *http.Request, body []byte, observed *offlinehttp.Observed) {
+	input := req.URL.String()
+	for _, template := range s.passiveTemplates {
+		s.execute(template, input, observed)
+	}
+
+	if len(s.fuzzTemplates) == 0 {
+		return
+	}
+	if _, fuzzed := s.fuzzed.LoadOrStore(req.Method+" "+input+" "+string(body), struct{}{}); fuzzed {
+		return
+	}
+	for _, template := range s.fuzzTemplates {
+		s.childExecuter.Execute(template, observedInput(req, body))
+	}
+}
+
+// observedInput returns the input of the observed request with its method, headers and body
+func observedInput(req *http.Request, body []byte) *contextargs.MetaInput {
+	request := &contextargs.Request{
+		Method:  req.Method,
+		Headers: make(map[string]string, len(req.Header)),
+		Body:    string(body),
+	}
+	for name, values := range req.Header {
+		separator := ", "
+		if name == "Cookie" {
+			separator = "; "
+		}
+		request.Headers[name] = strings.Join(values, separator)
+	}
+	return &contextargs.MetaInput{Input: req.URL.String(), Request: request}
+}
+
+// execute matches the observed pair with a passive template
+func (s *Service) execute(template *templates.Template, input string, observed *offlinehttp.Observed) {
+	wg := s.engine.WorkPool().Default
+	wg.Add()
+
+	go func() {
+		defer wg.Done()
+
+		ctxArgs := contextargs.NewWithInput(input)
+		ctxArgs.Set(offlinehttp.ObservedArg, observed)
+		match, err := template.Executer.Execute(ctxArgs)
+		if err != nil {
+			gologger.Warning().Msgf("[%s] Could not match observed response of %s: %s\n", s.opts.Colorizer.BrightBlue(template.ID), input, err)
+		}
+		s.results.CompareAndSwap(false, match)
+	}()
+}
@@ -0,0 +1,138 @@
+package passive
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/core"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/templates"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
+)
+
+const passiveTemplate = `id: passive-stack-trace
+
+info:
+  name: Stack Trace Disclosure
+  author: pdteam
+  severity: low
+
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}"
+
+    matchers-condition: and
+    matchers:
+      - type: word
+        words:
+          - "Traceback (most recent call last)"
+
+      - type: dsl
+        dsl:
+          - "!contains(tolower(all_headers), 'content-security-policy')"
+`
+
+func TestPassiveProxy(t *testing.T) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+	options.PassiveProxy = "127.0.0.1:0"
+	defer func() {
+		options.PassiveProxy = ""
+	}()
+
+	directory := t.TempDir()
+	templatePath := filepath.Join(directory, "passive-stack-trace.yaml")
+	err := os.WriteFile(templatePath, []byte(passiveTemplate), 0644)
+	require.Nil(t, err, "could not write template")
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{})
+	executerOpts.Colorizer = aurora.NewAurora(false)
+	var mu sync.Mutex
+	var results []*output.ResultEvent
+	executerOpts.Output.(*testutils.MockOutputWriter).WriteCallback = func(event *output.ResultEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, event)
+	}
+	template, err := templates.Parse(templatePath, nil, *executerOpts)
+	require.Nil(t, err, "could not parse template")
+
+	engine := core.New(options)
+	engine.SetExecuterOptions(*executerOpts)
+	service, err := New(Options{
+		ExecuterOpts: *executerOpts,
+		Templates:    []*templates.Template{template},
+		Engine:       engine,
+		Directory:    directory,
+	})
+	require.Nil(t, err, "could not create passive proxy")
+	require.FileExists(t, service.CertificatePath(), "could not create certificate authority")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, "Traceback (most recent call last):\n  File \"app.py\", line 1")
+			return
+		}
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		_, _ = io.WriteString(w, "Traceback (most recent call last): mentioned in documentation")
+	})
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	secure := httptest.NewTLSServer(handler)
+	defer secure.Close()
+
+	proxy := httptest.NewServer(service)
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{RootCAs: service.authority.CertPool()},
+	}}
+
+	for _, target := range []string{plain.URL + "/error", plain.URL + "/docs", secure.URL + "/error"} {
+		resp, err := client.Get(target)
+		require.Nil(t, err, "could not send request through proxy")
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.Contains(t, string(body), "Traceback", "could not forward response body")
+	}
+	require.True(t, service.Close(), "could not match observed responses")
+	require.True(t, service.Close(), "could not close service again")
+	require.True(t, service.Wait(), "could not wait for closed service")
+
+	matched := make(map[string]struct{})
+	for _, result := range results {
+		require.Equal(t, "passive-stack-trace", result.TemplateID, "could not get template")
+		matched[result.Matched] = struct{}{}
+	}
+	require.Equal(t, map[string]struct{}{plain.URL + "/error": {}, secure.URL + "/error": {}}, matched, "could not get matched responses")
+}
+
+func TestObservedInput(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/login?next=/", strings.NewReader("user=admin"))
+	require.Nil(t, err, "could not create request")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Accept", "text/html")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Cookie", "a=1")
+	req.Header.Add("Cookie", "b=2")
+
+	input := observedInput(req, []byte("user=admin"))
+	require.Equal(t, "https://example.com/login?next=/", input.Input, "could not get input url")
+	require.Equal(t, http.MethodPost, input.Request.Method, "could not get request method")
+	require.Equal(t, "user=admin", input.Request.Body, "could not get request body")
+	require.Equal(t, map[string]string{"Content-Type": "application/x-www-form-urlencoded", "Accept": "text/html, application/json", "Cookie": "a=1; b=2"}, input.Request.Headers, "could not get request headers")
+}
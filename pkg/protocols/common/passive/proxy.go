@@ -0,0 +1,216 @@
+package passive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/tostring"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/offlinehttp"
+)
+
+// maxBodySize is the maximum size of the observed response bodies
+const maxBodySize = 5 * 1024 * 1024
+
+// hopHeaders are the headers of a connection which aren't forwarded
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// ServeHTTP forwards the proxied requests and intercepts the CONNECT tunnels
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		s.intercept(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "vulmap passive proxy only serves proxy requests", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.roundTrip(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// intercept terminates the TLS connection of a CONNECT tunnel using a certificate
+// issued by the authority and forwards the requests sent through the tunnel.
+func (s *Service) intercept(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "could not hijack connection", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		gologger.Verbose().Msgf("Could not hijack passive proxy connection for %s: %s\n", r.Host, err)
+		return
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		return
+	}
+
+	hostname := r.URL.Hostname()
+	tlsConn := tls.Server(conn, &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName != "" {
+				return s.authority.Certificate(hello.ServerName)
+			}
+			return s.authority.Certificate(hostname)
+		},
+		NextProtos: []string{"http/1.1"},
+	})
+	if err := tlsConn.Handshake(); err != nil {
+		gologger.Verbose().Msgf("Could not intercept TLS connection for %s: %s\n", r.Host, err)
+		return
+	}
+
+	host := strings.TrimSuffix(r.Host, ":443")
+	reader := bufio.NewReader(tlsConn)
+	for {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+		req.URL.Scheme = "https"
+		req.URL.Host = host
+		req.RemoteAddr = r.RemoteAddr
+
+		resp, err := s.roundTrip(req)
+		if err != nil {
+			resp = &http.Response{
+				StatusCode:    http.StatusBadGateway,
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        http.Header{},
+				Body:          io.NopCloser(strings.NewReader(err.Error())),
+				ContentLength: int64(len(err.Error())),
+			}
+		}
+		// responses are always written as HTTP/1.1 to the client
+		resp.Proto, resp.ProtoMajor, resp.ProtoMinor = "HTTP/1.1", 1, 1
+		if resp.ContentLength < 0 {
+			resp.TransferEncoding = []string{"chunked"}
+		}
+		err = resp.Write(tlsConn)
+		resp.Body.Close()
+		if err != nil || req.Close || resp.Close {
+			return
+		}
+	}
+}
+
+// roundTrip sends the request to the server and observes the request and
+// the response, the returned response body has to be closed.
+func (s *Service) roundTrip(r *http.Request) (*http.Response, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	req := r.Clone(r.Context())
+	req.RequestURI = ""
+	req.Body = http.NoBody
+	if len(body) > 0 {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	req.ContentLength = int64(len(body))
+	removeHopHeaders(req.Header)
+	// the transport negotiates the compression to observe decoded responses
+	req.Header.Del("Accept-Encoding")
+
+	start := time.Now()
+	resp, err := s.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	duration := time.Since(start)
+
+	captured, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	removeHopHeaders(resp.Header)
+
+	if observed, err := observation(req, body, resp, captured, duration); err != nil {
+		gologger.Verbose().Msgf("Could not dump observed response of %s: %s\n", req.URL, err)
+	} else {
+		s.observe(req, body, observed)
+	}
+
+	resp.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(captured), resp.Body), Closer: resp.Body}
+	return resp, nil
+}
+
+// observation returns the raw request and response pair observed by the proxy
+func observation(req *http.Request, body []byte, resp *http.Response, captured []byte, duration time.Duration) (*offlinehttp.Observed, error) {
+	rawRequest, err := httputil.DumpRequest(req, false)
+	if err != nil {
+		return nil, err
+	}
+	rawRequest = append(rawRequest, body...)
+
+	dumped := *resp
+	dumped.Body = io.NopCloser(bytes.NewReader(captured))
+	dumped.ContentLength = int64(len(captured))
+	dumped.TransferEncoding = nil
+	rawResponse, err := httputil.DumpResponse(&dumped, true)
+	if err != nil {
+		return nil, err
+	}
+	return &offlinehttp.Observed{
+		Request:  tostring.UnsafeToString(rawRequest),
+		Response: tostring.UnsafeToString(rawResponse),
+		Duration: duration,
+	}, nil
+}
+
+// removeHopHeaders removes the headers of the connection
+func removeHopHeaders(header http.Header) {
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+}
+
+// newClientTLSConfig returns the TLS configuration of the connections to the
+// servers, the certificates aren't verified like for the http templates.
+func newClientTLSConfig() *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
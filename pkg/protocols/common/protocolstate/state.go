@@ -10,17 +10,31 @@ import (
 
 	"github.com/khulnasoft-lab/fastdialer/fastdialer"
 	"github.com/khulnasoft-lab/networkpolicy"
+	"github.com/khulnasoft-lab/ratelimit"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 )
 
 // Dialer is a shared fastdialer instance for host DNS resolution
 var Dialer *fastdialer.Dialer
 
+// Session is the session state imported in the requests of the javascript
+// libraries, which are not bound to the executor options of a template.
+var Session *session.Store
+
+// RateLimiter is the rate limiter of the engine shared with the requests of the
+// javascript libraries, which are not bound to the executor options of a template.
+var RateLimiter *ratelimit.Limiter
+
+// initOptions are the options protocolstate was initialized with
+var initOptions *types.Options
+
 // Init creates the Dialer instance based on user configuration
 func Init(options *types.Options) error {
 	if Dialer != nil {
 		return nil
 	}
+	initOptions = options
 	lfaAllowed = options.AllowLocalFileAccess
 	opts := fastdialer.DefaultOptions
 	InitHeadless(options.RestrictLocalNetworkAccess, options.AllowLocalFileAccess)
@@ -107,6 +121,17 @@ func Init(options *types.Options) error {
 	return nil
 }
 
+// GetOptions returns the options protocolstate was initialized with.
+//
+// It is used by libraries which do not have access to executor options
+// (i.e javascript libs) and returns default options if not initialized.
+func GetOptions() *types.Options {
+	if initOptions == nil {
+		return types.DefaultOptions()
+	}
+	return initOptions
+}
+
 // isIpAssociatedWithInterface checks if the given IP is associated with the given interface.
 func isIpAssociatedWithInterface(sourceIP, interfaceName string) (bool, error) {
 	addrs, err := interfaceAddresses(interfaceName)
@@ -0,0 +1,52 @@
+package timing
+
+import (
+	"math"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/eventcreator"
+)
+
+// Measurer sends a request with the delay and passes its event to the callback,
+// it returns the latency of the request and the payload values of the event.
+type Measurer func(delay int, callback protocols.OutputEventCallback) (time.Duration, map[string]interface{}, error)
+
+// Execute analyzes the latencies of the requests sent by the measurer and passes the
+// event of the last request along with the results of the analysis to the callback.
+func (config *Config) Execute(request protocols.Request, options *protocols.ExecutorOptions, measure Measurer, callback protocols.OutputEventCallback) error {
+	var lastEvent *output.InternalWrappedEvent
+	var lastPayloads map[string]interface{}
+
+	result, err := config.Analyze(func(delay int) (time.Duration, error) {
+		var event *output.InternalWrappedEvent
+		latency, payloads, err := measure(delay, func(wrapped *output.InternalWrappedEvent) {
+			event = wrapped
+		})
+		if event != nil {
+			lastEvent, lastPayloads = event, payloads
+		}
+		return latency, err
+	})
+	if lastEvent == nil {
+		return err
+	}
+
+	event := eventcreator.CreateEventWithAdditionalOptions(request, generators.MergeMaps(lastEvent.InternalEvent, result.Event()), options.Options.Debug || options.Options.DebugResponse, func(wrappedEvent *output.InternalWrappedEvent) {
+		wrappedEvent.OperatorsResult.PayloadValues = lastPayloads
+	})
+	callback(event)
+	return err
+}
+
+// Latency returns the duration of the response of the event, which is the time
+// between sending the request and receiving the response.
+func Latency(event output.InternalEvent) time.Duration {
+	if duration, ok := event["duration"].(float64); ok {
+		// rounded as the seconds can't always be converted back exactly
+		return time.Duration(math.Round(duration * float64(time.Second)))
+	}
+	return 0
+}
@@ -0,0 +1,237 @@
+// Package timing implements statistical confirmation of time-based blind
+// payloads by modelling the response latency for multiple delays.
+package timing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultVariable is the default name of the variable holding the delay
+	DefaultVariable = "delay"
+	// DefaultSamples is the default number of requests sent for each delay
+	DefaultSamples = 2
+	// DefaultMinConfidence is the default confidence required to confirm the delay
+	DefaultMinConfidence = 0.9
+)
+
+// DefaultDelays are the default delays in seconds injected by the analysis
+var DefaultDelays = []int{1, 2, 3}
+
+// Config is the configuration of the timing analysis of blind payloads.
+type Config struct {
+	// description: |
+	//   Variable is the name of the variable holding the delay in seconds used by the payloads.
+	//
+	//   The variable is 0 for control requests. Defaults to delay.
+	// examples:
+	//   - value: "\"delay\""
+	Variable string `yaml:"variable,omitempty" json:"variable,omitempty" jsonschema:"title=name of the delay variable,description=Name of the variable holding the delay in seconds used by the payloads"`
+	// description: |
+	//   Delays are the delays in seconds injected by the analysis.
+	//
+	//   The delays must be lower than the timeout of the requests. Defaults to 1, 2 and 3 seconds.
+	// examples:
+	//   - value: >
+	//       []int{2, 4, 6}
+	Delays []int `yaml:"delays,omitempty" json:"delays,omitempty" jsonschema:"title=delays in seconds,description=Delays in seconds injected by the analysis"`
+	// description: |
+	//   Samples is the number of requests sent for each delay and the control. Defaults to 2.
+	// examples:
+	//   - value: 3
+	Samples int `yaml:"samples,omitempty" json:"samples,omitempty" jsonschema:"title=number of samples,description=Number of requests sent for each delay and the control"`
+	// description: |
+	//   MinConfidence is the confidence between 0 and 1 required to confirm the delay. Defaults to 0.9.
+	// examples:
+	//   - value: 0.95
+	MinConfidence float64 `yaml:"min-confidence,omitempty" json:"min-confidence,omitempty" jsonschema:"title=minimum confidence,description=Confidence between 0 and 1 required to confirm the delay"`
+}
+
+// Sender sends a request with the delay in seconds and returns its latency
+type Sender func(delay int) (time.Duration, error)
+
+// Sample is the latency of a request sent with a delay
+type Sample struct {
+	Delay   int
+	Latency time.Duration
+}
+
+// Result is the result of a timing analysis
+type Result struct {
+	Samples []Sample
+	// Baseline is the mean latency of the control requests
+	Baseline time.Duration
+	// Deviation is the standard deviation of the latency of the control requests
+	Deviation time.Duration
+	// Slope is the increase of the latency in seconds for each second of delay
+	Slope float64
+	// RSquared is the coefficient of determination of the linear regression
+	RSquared float64
+	// Confidence is the confidence between 0 and 1 that the payload delays the response
+	Confidence float64
+	// Confirmed is true if the confidence reached the minimum confidence
+	Confirmed bool
+}
+
+// Validate validates the configuration of the timing analysis
+func (config *Config) Validate() error {
+	for _, delay := range config.Delays {
+		if delay <= 0 {
+			return fmt.Errorf("invalid timing delay: %d", delay)
+		}
+	}
+	if config.Samples < 0 {
+		return fmt.Errorf("invalid timing samples: %d", config.Samples)
+	}
+	if config.MinConfidence < 0 || config.MinConfidence > 1 {
+		return errors.New("timing min-confidence must be between 0 and 1")
+	}
+	return nil
+}
+
+// GetVariable returns the name of the variable holding the delay
+func (config *Config) GetVariable() string {
+	if config.Variable == "" {
+		return DefaultVariable
+	}
+	return config.Variable
+}
+
+// GetDelays returns the delays in seconds injected by the analysis
+func (config *Config) GetDelays() []int {
+	if len(config.Delays) == 0 {
+		return DefaultDelays
+	}
+	return config.Delays
+}
+
+// Values returns a copy of the values with the delay variable set to the delay
+func (config *Config) Values(values map[string]interface{}, delay int) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		copied[k] = v
+	}
+	copied[config.GetVariable()] = delay
+	return copied
+}
+
+// Analyze sends control requests without delay and requests with each delay in
+// rounds, and confirms the delay by a linear regression of the latencies which
+// has to increase by one second for each second of delay.
+//
+// The analysis stops early if a delayed response is received before the delay
+// elapsed as the payload can't have delayed it. The result contains the samples
+// collected until an error was returned by the sender.
+func (config *Config) Analyze(send Sender) (*Result, error) {
+	samples := config.Samples
+	if samples == 0 {
+		samples = DefaultSamples
+	}
+	delays := append([]int{0}, config.GetDelays()...)
+
+	result := &Result{}
+	for round := 0; round < samples; round++ {
+		for _, delay := range delays {
+			latency, err := send(delay)
+			if err != nil {
+				result.analyze(config.minConfidence())
+				return result, err
+			}
+			result.Samples = append(result.Samples, Sample{Delay: delay, Latency: latency})
+			if latency < time.Duration(delay)*time.Second {
+				result.analyze(config.minConfidence())
+				result.Confidence, result.Confirmed = 0, false
+				return result, nil
+			}
+		}
+	}
+	result.analyze(config.minConfidence())
+	return result, nil
+}
+
+func (config *Config) minConfidence() float64 {
+	if config.MinConfidence == 0 {
+		return DefaultMinConfidence
+	}
+	return config.MinConfidence
+}
+
+// analyze computes the statistics of the samples
+func (result *Result) analyze(minConfidence float64) {
+	var controls []float64
+	var sumX, sumY float64
+	for _, sample := range result.Samples {
+		latency := sample.Latency.Seconds()
+		if sample.Delay == 0 {
+			controls = append(controls, latency)
+		}
+		sumX += float64(sample.Delay)
+		sumY += latency
+	}
+	if len(controls) > 0 {
+		mean, deviation := meanDeviation(controls)
+		result.Baseline = time.Duration(mean * float64(time.Second))
+		result.Deviation = time.Duration(deviation * float64(time.Second))
+	}
+
+	n := float64(len(result.Samples))
+	if n < 2 {
+		return
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var covariance, varianceX, varianceY float64
+	for _, sample := range result.Samples {
+		dx, dy := float64(sample.Delay)-meanX, sample.Latency.Seconds()-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return
+	}
+	result.Slope = covariance / varianceX
+	result.RSquared = covariance * covariance / (varianceX * varianceY)
+
+	// the latency of delayed responses increases by the delay, so the confidence
+	// decreases with the distance of the slope to one and the unexplained variance
+	slopeScore := math.Max(0, 1-math.Abs(result.Slope-1))
+	result.Confidence = result.RSquared * slopeScore
+	result.Confirmed = result.Confidence >= minConfidence
+}
+
+// Event returns the fields of the result provided to matchers
+func (result *Result) Event() map[string]interface{} {
+	delays := make([]int, len(result.Samples))
+	latencies := make([]float64, len(result.Samples))
+	for i, sample := range result.Samples {
+		delays[i] = sample.Delay
+		latencies[i] = sample.Latency.Seconds()
+	}
+	return map[string]interface{}{
+		"timing_confidence": result.Confidence,
+		"timing_confirmed":  result.Confirmed,
+		"timing_slope":      result.Slope,
+		"timing_r_squared":  result.RSquared,
+		"timing_baseline":   result.Baseline.Seconds(),
+		"timing_deviation":  result.Deviation.Seconds(),
+		"timing_delays":     delays,
+		"timing_latencies":  latencies,
+	}
+}
+
+// meanDeviation returns the mean and standard deviation of the values
+func meanDeviation(values []float64) (float64, float64) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
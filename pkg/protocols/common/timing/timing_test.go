@@ -0,0 +1,77 @@
+package timing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	jitter := []time.Duration{30 * time.Millisecond, 0, 80 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond}
+
+	t.Run("delayed", func(t *testing.T) {
+		var sent int
+		config := &Config{Samples: 3}
+		result, err := config.Analyze(func(delay int) (time.Duration, error) {
+			sent++
+			return 200*time.Millisecond + time.Duration(delay)*time.Second + jitter[sent%len(jitter)], nil
+		})
+		require.Nil(t, err, "could not analyze timing")
+		require.Equal(t, 12, sent, "could not send all samples")
+		require.True(t, result.Confirmed, "could not confirm delay")
+		require.InDelta(t, 1, result.Slope, 0.05, "could not get slope")
+		require.InDelta(t, 0.23, result.Baseline.Seconds(), 0.05, "could not get baseline")
+	})
+
+	t.Run("slow-host", func(t *testing.T) {
+		config := &Config{}
+		var sent int
+		result, err := config.Analyze(func(delay int) (time.Duration, error) {
+			sent++
+			return 4*time.Second + jitter[sent%len(jitter)]*10, nil
+		})
+		require.Nil(t, err, "could not analyze timing")
+		require.False(t, result.Confirmed, "could confirm delay of slow host")
+		require.Less(t, result.Confidence, 0.5, "could get confidence for slow host")
+	})
+
+	t.Run("not-delayed", func(t *testing.T) {
+		config := &Config{}
+		var sent int
+		result, err := config.Analyze(func(delay int) (time.Duration, error) {
+			sent++
+			return 100 * time.Millisecond, nil
+		})
+		require.Nil(t, err, "could not analyze timing")
+		require.Equal(t, 2, sent, "could not stop after response before delay")
+		require.False(t, result.Confirmed, "could confirm delay")
+		require.Zero(t, result.Confidence, "could get confidence")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		config := &Config{Delays: []int{2}}
+		result, err := config.Analyze(func(delay int) (time.Duration, error) {
+			if delay > 0 {
+				return 0, errors.New("timeout")
+			}
+			return 100 * time.Millisecond, nil
+		})
+		require.NotNil(t, err, "could not get sender error")
+		require.Len(t, result.Samples, 1, "could not get samples before error")
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.Nil(t, (&Config{Delays: []int{2, 4}, MinConfidence: 0.95}).Validate(), "could not validate config")
+	require.NotNil(t, (&Config{Delays: []int{0}}).Validate(), "could validate zero delay")
+	require.NotNil(t, (&Config{MinConfidence: 2}).Validate(), "could validate invalid confidence")
+}
+
+func TestLatency(t *testing.T) {
+	for _, duration := range []time.Duration{1000594571, 1000559335, 250 * time.Millisecond} {
+		require.Equal(t, duration, Latency(map[string]interface{}{"duration": duration.Seconds()}), "could not get latency of duration")
+	}
+	require.Zero(t, Latency(map[string]interface{}{}), "could get latency without duration")
+}
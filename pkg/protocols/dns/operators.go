@@ -104,6 +104,31 @@ func (request *Request) responseToDSLMap(req, resp *dns.Msg, host, matched strin
 	return ret
 }
 
+// FixtureToDSLMap converts dns answer records in zone file format (one record
+// per line) to a map for use in DSL matching.
+func (request *Request) FixtureToDSLMap(fixture string) (output.InternalEvent, error) {
+	resp := new(dns.Msg)
+	for _, line := range strings.Split(fixture, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		rr, err := dns.NewRR(line)
+		if err != nil {
+			return nil, err
+		}
+		if rr != nil {
+			resp.Answer = append(resp.Answer, rr)
+		}
+	}
+	req := new(dns.Msg)
+	if len(resp.Answer) > 0 {
+		req.SetQuestion(resp.Answer[0].Header().Name, request.question)
+	}
+	resp.SetReply(req)
+	return request.responseToDSLMap(req, resp, "", "", nil), nil
+}
+
 // MakeResultEvent creates a result event from internal wrapped event
 func (request *Request) MakeResultEvent(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
 	return protocols.MakeDefaultResultEvent(request, wrapped)
@@ -0,0 +1,99 @@
+package dns
+
+import (
+	"encoding/json"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/retryabledns"
+)
+
+const (
+	// replayProtocol is the protocol dns exchanges are recorded as
+	replayProtocol = "dns"
+	// replayTraceProtocol is the protocol dns traces are recorded as
+	replayTraceProtocol = "dns-trace"
+)
+
+// replayKey returns the key identifying the dns message in recordings
+func (request *Request) replayKey(msg *dns.Msg) string {
+	key := request.options.TemplateID
+	for _, question := range msg.Question {
+		key += "\n" + question.String()
+	}
+	return key
+}
+
+// exchange sends the dns message to the resolvers. The response is
+// served from the recording when replaying a recorded scan.
+func (request *Request) exchange(dnsClient *retryabledns.Client, msg *dns.Msg) (*dns.Msg, error) {
+	if request.options.Replay.Replaying() {
+		entry, err := request.options.Replay.Replay(replayProtocol, request.replayKey(msg))
+		if err != nil {
+			return nil, err
+		}
+		if len(entry.Data) == 0 {
+			return nil, errors.New("invalid recorded dns response")
+		}
+		response := &dns.Msg{}
+		if err := response.Unpack(entry.Data[0]); err != nil {
+			return nil, errors.Wrap(err, "could not unpack recorded dns response")
+		}
+		return response, nil
+	}
+
+	response, err := dnsClient.Do(msg)
+	if err == nil && response != nil && request.options.Replay.Recording() {
+		packed, packErr := response.Pack()
+		if packErr != nil {
+			return nil, errors.Wrap(packErr, "could not pack dns response")
+		}
+		if recordErr := request.options.Replay.Record(replayProtocol, request.replayKey(msg), packed); recordErr != nil {
+			return nil, errors.Wrap(recordErr, "could not record dns response")
+		}
+	}
+	return response, err
+}
+
+// trace traces the resolution of the domain. The trace is served from
+// the recording when replaying a recorded scan.
+func (request *Request) trace(domain string) (*retryabledns.TraceData, error) {
+	key := request.options.TemplateID + "\n" + domain + "\n" + dns.TypeToString[request.question]
+	if request.options.Replay.Replaying() {
+		entry, err := request.options.Replay.Replay(replayTraceProtocol, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(entry.Data) == 0 {
+			return nil, errors.New("invalid recorded dns trace")
+		}
+		traceData := &retryabledns.TraceData{}
+		if err := json.Unmarshal(entry.Data[0], traceData); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal recorded dns trace")
+		}
+		return traceData, nil
+	}
+
+	traceData, err := request.dnsClient.Trace(domain, request.question, request.TraceMaxRecursion)
+	if err == nil && traceData != nil && request.options.Replay.Recording() {
+		// raw responses can't be unmarshaled, the raw string is recorded instead
+		recorded := &retryabledns.TraceData{Host: traceData.Host}
+		for _, data := range traceData.DNSData {
+			if data == nil {
+				continue
+			}
+			withoutResponse := *data
+			withoutResponse.RawResp = nil
+			recorded.DNSData = append(recorded.DNSData, &withoutResponse)
+		}
+		marshaled, marshalErr := json.Marshal(recorded)
+		if marshalErr != nil {
+			return nil, errors.Wrap(marshalErr, "could not marshal dns trace")
+		}
+		if recordErr := request.options.Replay.Record(replayTraceProtocol, key, marshaled); recordErr != nil {
+			return nil, errors.Wrap(recordErr, "could not record dns trace")
+		}
+	}
+	return traceData, err
+}
@@ -125,7 +125,7 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 	request.options.RateLimiter.Take()
 
 	// Send the request to the target servers
-	response, err := dnsClient.Do(compiledRequest)
+	response, err := request.exchange(dnsClient, compiledRequest)
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, domain, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
@@ -142,7 +142,7 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 	// perform trace if necessary
 	var traceData *retryabledns.TraceData
 	if request.Trace {
-		traceData, err = request.dnsClient.Trace(domain, request.question, request.TraceMaxRecursion)
+		traceData, err = request.trace(domain)
 		if err != nil {
 			request.options.Output.Request(request.options.TemplatePath, domain, "dns", err)
 		}
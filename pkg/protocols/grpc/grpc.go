@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/fastdialer/fastdialer"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/network/networkclientpool"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
+	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// Request is a request for the gRPC protocol
+type Request struct {
+	// Operators for the current request go here.
+	operators.Operators `yaml:",inline,omitempty" json:",inline,omitempty"`
+	CompiledOperators   *operators.Operators `yaml:"-" json:"-"`
+
+	// ID is the optional id of the request
+	ID string `yaml:"id,omitempty" json:"id,omitempty" jsonschema:"title=id of the request,description=ID of the request"`
+	// description: |
+	//   Address contains address for the request
+	// examples:
+	//   - value: "\"{{Host}}:{{Port}}\""
+	Address string `yaml:"address,omitempty" json:"address,omitempty" jsonschema:"title=address for the grpc request,description=Address contains address for the request"`
+	// description: |
+	//   TLS enables tls for the connection to the gRPC server.
+	TLS bool `yaml:"tls,omitempty" json:"tls,omitempty" jsonschema:"title=use tls for the grpc connection,description=TLS enables tls for the connection to the gRPC server"`
+	// description: |
+	//   Reflection lists the services exposed by the server using
+	//   server reflection and makes them available as `services`.
+	Reflection bool `yaml:"reflection,omitempty" json:"reflection,omitempty" jsonschema:"title=list services using server reflection,description=Reflection lists the services exposed by the server using server reflection"`
+	// description: |
+	//   Method is the fully qualified name of the unary method to invoke.
+	//
+	//   Method descriptors are resolved using server reflection.
+	// examples:
+	//   - value: "\"grpc.health.v1.Health/Check\""
+	Method string `yaml:"method,omitempty" json:"method,omitempty" jsonschema:"title=method to invoke,description=Method is the fully qualified name of the unary method to invoke"`
+	// description: |
+	//   Message is the JSON encoded request message for the method.
+	//
+	//   It supports DSL Helper Functions as well as normal expressions.
+	// examples:
+	//   - value: "\"{\\\"service\\\": \\\"\\\"}\""
+	Message string `yaml:"message,omitempty" json:"message,omitempty" jsonschema:"title=json encoded request message,description=Message is the JSON encoded request message for the method"`
+	// description: |
+	//   Metadata contains metadata (headers) sent with the request.
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty" jsonschema:"title=metadata sent with the request,description=Metadata contains metadata (headers) sent with the request"`
+
+	// description: |
+	//   Attack is the type of payload combinations to perform.
+	//
+	//   Sniper is each payload once, pitchfork combines multiple payload sets and clusterbomb generates
+	//   permutations and combinations for all payloads.
+	AttackType generators.AttackTypeHolder `yaml:"attack,omitempty" json:"attack,omitempty" jsonschema:"title=attack is the payload combination,description=Attack is the type of payload combinations to perform,enum=sniper,enum=pitchfork,enum=clusterbomb"`
+	// description: |
+	//   Payloads contains any payloads for the current request.
+	//
+	//   Payloads support both key-values combinations where a list
+	//   of payloads is provided, or optionally a single file can also
+	//   be provided as payload which will be read on run-time.
+	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the grpc request,description=Payloads contains any payloads for the current request"`
+
+	generator *generators.PayloadGenerator
+
+	// cache any variables that may be needed for operation.
+	dialer  *fastdialer.Dialer
+	options *protocols.ExecutorOptions
+}
+
+var _ protocols.Request = &Request{}
+
+// Compile compiles the request generators preparing any requests possible.
+func (request *Request) Compile(options *protocols.ExecutorOptions) error {
+	request.options = options
+
+	// grpc requests are not recorded, so replayed scans would send them
+	if options.Replay.Replaying() {
+		return errors.Wrap(replay.ErrNotReplayable, "grpc")
+	}
+
+	if request.Method == "" && !request.Reflection {
+		return errors.New("either method or reflection must be specified")
+	}
+	if request.Method != "" {
+		if _, _, err := parseMethod(request.Method); err != nil {
+			return err
+		}
+	}
+	if request.Address == "" {
+		request.Address = "{{Hostname}}"
+	}
+
+	client, err := networkclientpool.Get(options.Options, &networkclientpool.Configuration{})
+	if err != nil {
+		return errors.Wrap(err, "could not get network client")
+	}
+	request.dialer = client
+
+	if len(request.Payloads) > 0 {
+		request.generator, err = generators.New(request.Payloads, request.AttackType.Value, request.options.TemplatePath, options.Catalog, options.Options.AttackType, types.DefaultOptions())
+		if err != nil {
+			return errors.Wrap(err, "could not parse payloads")
+		}
+	}
+
+	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
+		compiled := &request.Operators
+		compiled.ExcludeMatchers = options.ExcludeMatchers
+		compiled.TemplateID = options.TemplateID
+		if err := compiled.Compile(); err != nil {
+			return errors.Wrap(err, "could not compile operators")
+		}
+		request.CompiledOperators = compiled
+	}
+	return nil
+}
+
+// Requests returns the total number of requests the rule will perform
+func (request *Request) Requests() int {
+	if request.generator != nil {
+		return request.generator.NewIterator().Total()
+	}
+	return 1
+}
+
+// GetID returns the ID for the request if any.
+func (request *Request) GetID() string {
+	return request.ID
+}
+
+// Options returns executer options for grpc request
+func (request *Request) Options() *protocols.ExecutorOptions {
+	return request.options
+}
+
+// Type returns the type of the protocol request
+func (request *Request) Type() templateTypes.ProtocolType {
+	return templateTypes.GRPCProtocol
+}
+
+// parseMethod splits a fully qualified method name into service and method.
+//
+// Both `package.Service/Method` and `package.Service.Method` forms are accepted.
+func parseMethod(value string) (string, string, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "/")
+	index := strings.LastIndex(value, "/")
+	if index == -1 {
+		index = strings.LastIndex(value, ".")
+	}
+	if index <= 0 || index == len(value)-1 {
+		return "", "", errors.Errorf("invalid grpc method %q, expected package.Service/Method", value)
+	}
+	return value[:index], value[index+1:], nil
+}
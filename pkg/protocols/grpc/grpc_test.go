@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/model"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators/matchers"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
+)
+
+func TestGRPCProtocol(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not create listener")
+
+	server := grpcgo.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-grpc"
+	request := &Request{
+		ID:         templateID,
+		Address:    "{{Hostname}}",
+		Reflection: true,
+		Method:     "grpc.health.v1.Health/Check",
+		Message:    `{"service": ""}`,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Name:  "serving",
+				Part:  "response",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"SERVING"},
+			}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile grpc request")
+
+	var finalEvent *output.InternalWrappedEvent
+	ctxArgs := contextargs.NewWithInput(listener.Addr().String())
+	err = request.ExecuteWithResults(ctxArgs, nil, nil, func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not run grpc request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, "OK", finalEvent.InternalEvent["status"], "could not get correct status")
+	require.Contains(t, finalEvent.InternalEvent["services"], "grpc.health.v1.Health", "could not list services")
+	require.True(t, finalEvent.OperatorsResult.Matched, "could not match response")
+
+	t.Run("unimplemented-status", func(t *testing.T) {
+		request := &Request{Method: "grpc.health.v1.Health/Check", Message: `{"service": "missing"}`}
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile grpc request")
+
+		var finalEvent *output.InternalWrappedEvent
+		err = request.ExecuteWithResults(ctxArgs, nil, nil, func(event *output.InternalWrappedEvent) {
+			finalEvent = event
+		})
+		require.Nil(t, err, "could not run grpc request")
+		require.Equal(t, "NotFound", finalEvent.InternalEvent["status"], "could not get correct status")
+		require.Equal(t, 5, finalEvent.InternalEvent["status_code"], "could not get correct status code")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		player, err := replay.New(&replay.Options{Path: t.TempDir(), Replay: true})
+		require.Nil(t, err, "could not create player")
+		replayOpts := *executerOpts
+		replayOpts.Replay = player
+
+		request := &Request{Method: "grpc.health.v1.Health/Check", Message: `{"service": ""}`}
+		err = request.Compile(&replayOpts)
+		require.ErrorIs(t, err, replay.ErrNotReplayable, "could compile grpc request while replaying")
+	})
+}
+
+func TestParseMethod(t *testing.T) {
+	service, method, err := parseMethod("/grpc.health.v1.Health/Check")
+	require.Nil(t, err, "could not parse method")
+	require.Equal(t, "grpc.health.v1.Health", service)
+	require.Equal(t, "Check", method)
+
+	service, method, err = parseMethod("grpc.health.v1.Health.Check")
+	require.Nil(t, err, "could not parse method")
+	require.Equal(t, "grpc.health.v1.Health", service)
+	require.Equal(t, "Check", method)
+
+	_, _, err = parseMethod("Check")
+	require.NotNil(t, err, "could parse invalid method")
+}
@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	grpcgo "google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionClient resolves services and method descriptors from a
+// server using the gRPC server reflection protocol.
+type reflectionClient struct {
+	stream reflectionpb.ServerReflection_ServerReflectionInfoClient
+	// protos contains raw file descriptors received from the server
+	protos map[string]*descriptorpb.FileDescriptorProto
+	files  *protoregistry.Files
+}
+
+// newReflectionClient creates a reflection stream on the connection
+func newReflectionClient(ctx context.Context, conn *grpcgo.ClientConn) (*reflectionClient, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create reflection stream")
+	}
+	return &reflectionClient{
+		stream: stream,
+		protos: make(map[string]*descriptorpb.FileDescriptorProto),
+		files:  new(protoregistry.Files),
+	}, nil
+}
+
+// Close closes the underlying reflection stream
+func (c *reflectionClient) Close() {
+	_ = c.stream.CloseSend()
+}
+
+// ListServices returns the sorted list of services exposed by the server
+func (c *reflectionClient) ListServices() ([]string, error) {
+	resp, err := c.send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := resp.GetListServicesResponse()
+	if list == nil {
+		return nil, errors.New("unexpected reflection response for list services")
+	}
+	services := make([]string, 0, len(list.GetService()))
+	for _, service := range list.GetService() {
+		services = append(services, service.GetName())
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// ResolveMethod returns the descriptor of a method exposed by the server
+func (c *reflectionClient) ResolveMethod(service, method string) (protoreflect.MethodDescriptor, error) {
+	resp, err := c.send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+	names, err := c.addFileDescriptors(resp)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := c.register(name); err != nil {
+			return nil, err
+		}
+	}
+
+	descriptor, err := c.files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find service %s", service)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, errors.Errorf("%s is not a service", service)
+	}
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(method))
+	if methodDescriptor == nil {
+		return nil, errors.Errorf("service %s does not have method %s", service, method)
+	}
+	if methodDescriptor.IsStreamingClient() || methodDescriptor.IsStreamingServer() {
+		return nil, errors.Errorf("method %s/%s is not unary", service, method)
+	}
+	return methodDescriptor, nil
+}
+
+// register builds and registers the named file descriptor along with
+// all of its dependencies, fetching missing ones from the server.
+func (c *reflectionClient) register(name string) error {
+	if _, err := c.files.FindFileByPath(name); err == nil {
+		return nil
+	}
+	fileProto, ok := c.protos[name]
+	if !ok {
+		// well known types are compiled in and need not be fetched
+		if fd, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+			return c.files.RegisterFile(fd)
+		}
+		resp, err := c.send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := c.addFileDescriptors(resp); err != nil {
+			return err
+		}
+		if fileProto, ok = c.protos[name]; !ok {
+			return errors.Errorf("server did not return descriptor for %s", name)
+		}
+	}
+	for _, dependency := range fileProto.GetDependency() {
+		if err := c.register(dependency); err != nil {
+			return err
+		}
+	}
+	fd, err := protodesc.NewFile(fileProto, c.files)
+	if err != nil {
+		return errors.Wrapf(err, "could not build descriptor for %s", name)
+	}
+	return c.files.RegisterFile(fd)
+}
+
+// addFileDescriptors decodes file descriptors from a reflection response
+// and returns the names of the decoded files.
+func (c *reflectionClient) addFileDescriptors(resp *reflectionpb.ServerReflectionResponse) ([]string, error) {
+	fdResponse := resp.GetFileDescriptorResponse()
+	if fdResponse == nil {
+		return nil, errors.New("unexpected reflection response for file descriptor")
+	}
+	var names []string
+	for _, raw := range fdResponse.GetFileDescriptorProto() {
+		fileProto := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(raw, fileProto); err != nil {
+			return nil, errors.Wrap(err, "could not decode file descriptor")
+		}
+		c.protos[fileProto.GetName()] = fileProto
+		names = append(names, fileProto.GetName())
+	}
+	return names, nil
+}
+
+func (c *reflectionClient) send(req *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	if err := c.stream.Send(req); err != nil {
+		return nil, errors.Wrap(err, "could not send reflection request")
+	}
+	resp, err := c.stream.Recv()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not receive reflection response")
+	}
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, errors.Errorf("reflection error: %s", errResp.GetErrorMessage())
+	}
+	return resp, nil
+}
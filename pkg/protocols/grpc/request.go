@@ -0,0 +1,298 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/khulnasoft-lab/gologger"
+	urlutil "github.com/khulnasoft-lab/utils/url"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/expressions"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/eventcreator"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
+	protocolutils "github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+)
+
+const evaluateTemplateExpressionErrorMessage = "could not evaluate template expressions"
+
+// ExecuteWithResults executes the protocol requests and returns results instead of writing them.
+func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	hostPort, err := request.getAddress(input.MetaInput.Input)
+	if err != nil {
+		return err
+	}
+
+	if request.generator != nil {
+		iterator := request.generator.NewIterator()
+		for {
+			value, ok := iterator.Value()
+			if !ok {
+				break
+			}
+			value = generators.MergeMaps(value, dynamicValues)
+			if err := request.executeRequestWithPayloads(input, hostPort, value, previous, callback); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	value := make(map[string]interface{})
+	for k, v := range dynamicValues {
+		value[k] = v
+	}
+	return request.executeRequestWithPayloads(input, hostPort, value, previous, callback)
+}
+
+func (request *Request) executeRequestWithPayloads(input *contextargs.Context, hostPort string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	requestOptions := request.options
+	hostname, port, _ := net.SplitHostPort(hostPort)
+
+	payloadValues := generators.BuildPayloadFromOptions(requestOptions.Options)
+	for k, v := range dynamicValues {
+		payloadValues[k] = v
+	}
+	payloadValues["Hostname"] = hostPort
+	payloadValues["Host"] = hostname
+	payloadValues["Port"] = port
+
+	hostnameVariables := protocolutils.GenerateDNSVariables(hostname)
+	// add template context variables to varMap
+	values := generators.MergeMaps(payloadValues, hostnameVariables, requestOptions.GetTemplateCtx(input.MetaInput).GetAll())
+	variablesMap := requestOptions.Variables.Evaluate(values)
+	payloadValues = generators.MergeMaps(variablesMap, payloadValues, requestOptions.Constants)
+
+	if vardump.EnableVarDump {
+		gologger.Debug().Msgf("gRPC Protocol request variables: \n%s\n", vardump.DumpVariables(payloadValues))
+	}
+
+	finalAddress, err := expressions.Evaluate(request.Address, payloadValues)
+	if err != nil {
+		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+		requestOptions.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, evaluateTemplateExpressionErrorMessage)
+	}
+	message, err := expressions.Evaluate(request.Message, payloadValues)
+	if err != nil {
+		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+		requestOptions.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, evaluateTemplateExpressionErrorMessage)
+	}
+	md := metadata.MD{}
+	for key, value := range request.Metadata {
+		finalValue, err := expressions.Evaluate(value, payloadValues)
+		if err != nil {
+			requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+			requestOptions.Progress.IncrementFailedRequestsBy(1)
+			return errors.Wrap(err, evaluateTemplateExpressionErrorMessage)
+		}
+		md.Append(key, finalValue)
+	}
+
+	timeout := time.Duration(requestOptions.Options.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := request.dial(ctx, finalAddress, hostname)
+	if err != nil {
+		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+		requestOptions.Progress.IncrementFailedRequestsBy(1)
+		return errors.Wrap(err, "could not connect to server")
+	}
+	defer conn.Close()
+
+	data := make(output.InternalEvent)
+	for k, v := range payloadValues {
+		data[k] = v
+	}
+
+	reflection, err := newReflectionClient(ctx, conn)
+	if err != nil {
+		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+		requestOptions.Progress.IncrementFailedRequestsBy(1)
+		return err
+	}
+	defer reflection.Close()
+
+	if request.Reflection {
+		services, err := reflection.ListServices()
+		if err != nil {
+			requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+			requestOptions.Progress.IncrementFailedRequestsBy(1)
+			return errors.Wrap(err, "could not list services")
+		}
+		data["services"] = services
+	}
+
+	var requestDump strings.Builder
+	if request.Method != "" {
+		service, method, _ := parseMethod(request.Method)
+		fullMethod := "/" + service + "/" + method
+		requestDump.WriteString(fullMethod + "\n")
+		requestDump.WriteString(formatMetadata(md))
+		requestDump.WriteString("\n" + message)
+
+		methodDescriptor, err := reflection.ResolveMethod(service, method)
+		if err != nil {
+			requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+			requestOptions.Progress.IncrementFailedRequestsBy(1)
+			return errors.Wrap(err, "could not resolve method")
+		}
+		reqMessage := dynamicpb.NewMessage(methodDescriptor.Input())
+		if strings.TrimSpace(message) != "" {
+			if err := protojson.Unmarshal([]byte(message), reqMessage); err != nil {
+				requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
+				requestOptions.Progress.IncrementFailedRequestsBy(1)
+				return errors.Wrap(err, "could not decode json message")
+			}
+		}
+		respMessage := dynamicpb.NewMessage(methodDescriptor.Output())
+
+		var header, trailer metadata.MD
+		invokeCtx := metadata.NewOutgoingContext(ctx, md)
+		start := time.Now()
+		invokeErr := conn.Invoke(invokeCtx, fullMethod, reqMessage, respMessage, grpcgo.Header(&header), grpcgo.Trailer(&trailer))
+		data["duration"] = time.Since(start).Seconds()
+
+		// non-OK status codes are responses and are exposed to matchers
+		responseStatus := status.Convert(invokeErr)
+		data["status_code"] = int(responseStatus.Code())
+		data["status"] = responseStatus.Code().String()
+		data["status_message"] = responseStatus.Message()
+		data["header"] = formatMetadata(header)
+		data["trailers"] = formatMetadata(trailer)
+		if invokeErr == nil {
+			response, err := protojson.Marshal(respMessage)
+			if err != nil {
+				return errors.Wrap(err, "could not encode response message")
+			}
+			data["response"] = string(response)
+		}
+		data["method"] = fullMethod
+	}
+	requestOptions.Progress.IncrementRequests()
+
+	requestOptions.Output.Request(requestOptions.TemplateID, hostPort, request.Type().String(), nil)
+	gologger.Verbose().Msgf("[%s] Sent gRPC request to %s", requestOptions.TemplateID, finalAddress)
+
+	if requestOptions.Options.Debug || requestOptions.Options.DebugRequests || requestOptions.Options.StoreResponse {
+		msg := fmt.Sprintf("[%s] Dumped gRPC request for %s", requestOptions.TemplateID, input.MetaInput.Input)
+		if requestOptions.Options.Debug || requestOptions.Options.DebugRequests {
+			gologger.Debug().Str("address", input.MetaInput.Input).Msg(msg)
+			gologger.Print().Msgf("%s", requestDump.String())
+		}
+		if requestOptions.Options.StoreResponse {
+			requestOptions.Output.WriteStoreDebugData(input.MetaInput.Input, requestOptions.TemplateID, request.Type().String(), fmt.Sprintf("%s\n%s", msg, requestDump.String()))
+		}
+	}
+
+	data["type"] = request.Type().String()
+	data["request"] = requestDump.String()
+	data["host"] = input.MetaInput.Input
+	data["matched"] = finalAddress
+	data["ip"] = request.dialer.GetDialedIP(hostname)
+	data["template-path"] = requestOptions.TemplatePath
+	data["template-id"] = requestOptions.TemplateID
+	data["template-info"] = requestOptions.TemplateInfo
+	if _, ok := data["response"]; !ok {
+		data["response"] = ""
+	}
+
+	// add response fields to template context and merge templatectx variables to output event
+	requestOptions.AddTemplateVars(input.MetaInput, request.Type(), request.ID, data)
+	data = generators.MergeMaps(data, requestOptions.GetTemplateCtx(input.MetaInput).GetAll())
+	for k, v := range previous {
+		data[k] = v
+	}
+
+	event := eventcreator.CreateEventWithAdditionalOptions(request, data, requestOptions.Options.Debug || requestOptions.Options.DebugResponse, func(internalWrappedEvent *output.InternalWrappedEvent) {
+		internalWrappedEvent.OperatorsResult.PayloadValues = dynamicValues
+	})
+	if requestOptions.Options.Debug || requestOptions.Options.DebugResponse || requestOptions.Options.StoreResponse {
+		msg := fmt.Sprintf("[%s] Dumped gRPC response for %s", requestOptions.TemplateID, input.MetaInput.Input)
+		responseDump := fmt.Sprintf("%v %v\n%v%v\n%v", data["status"], data["status_message"], data["header"], data["trailers"], data["response"])
+		if requestOptions.Options.Debug || requestOptions.Options.DebugResponse {
+			gologger.Debug().Msg(msg)
+			gologger.Print().Msgf("%s", responsehighlighter.Highlight(event.OperatorsResult, responseDump, requestOptions.Options.NoColor, false))
+		}
+		if requestOptions.Options.StoreResponse {
+			requestOptions.Output.WriteStoreDebugData(input.MetaInput.Input, requestOptions.TemplateID, request.Type().String(), fmt.Sprintf("%s\n%s", msg, responseDump))
+		}
+	}
+	callback(event)
+	return nil
+}
+
+// dial creates a blocking client connection to the given address
+func (request *Request) dial(ctx context.Context, address, hostname string) (*grpcgo.ClientConn, error) {
+	transportCredentials := insecure.NewCredentials()
+	if request.TLS {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         hostname,
+			MinVersion:         tls.VersionTLS10,
+		}
+		if request.options.Options.SNI != "" {
+			tlsConfig.ServerName = request.options.Options.SNI
+		}
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+	return grpcgo.DialContext(ctx, address,
+		grpcgo.WithBlock(),
+		grpcgo.WithTransportCredentials(transportCredentials),
+		grpcgo.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return request.dialer.Dial(ctx, "tcp", addr)
+		}),
+	)
+}
+
+// getAddress returns the address of the host to make request to
+func (request *Request) getAddress(toTest string) (string, error) {
+	urlx, err := urlutil.Parse(toTest)
+	if err != nil {
+		// use given input instead of url parsing failure
+		return toTest, nil
+	}
+	if urlx.Port() == "" {
+		if request.TLS {
+			urlx.UpdatePort("443")
+		} else {
+			urlx.UpdatePort("80")
+		}
+	}
+	return urlx.Host, nil
+}
+
+// formatMetadata formats metadata in header format sorted by key
+func formatMetadata(md metadata.MD) string {
+	keys := make([]string, 0, len(md))
+	for key := range md {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		for _, value := range md[key] {
+			builder.WriteString(key + ": " + value + "\n")
+		}
+	}
+	return builder.String()
+}
@@ -80,6 +80,33 @@ const (
 	// ActionWaitVisible waits until an element appears.
 	// name:waitvisible
 	ActionWaitVisible
+	// ActionHover moves the mouse over an element.
+	// name:hover
+	ActionHover
+	// ActionDragDrop drags an element and drops it on another element.
+	// name:dragdrop
+	ActionDragDrop
+	// ActionFrame switches the following actions into an iframe or back to the page.
+	// name:frame
+	ActionFrame
+	// ActionDialog handles the dialogs opened by the page.
+	// name:dialog
+	ActionDialog
+	// ActionGetStorage reads the local, session or IndexedDB storage of the page.
+	// name:getstorage
+	ActionGetStorage
+	// ActionSetStorage writes the local, session or IndexedDB storage of the page.
+	// name:setstorage
+	ActionSetStorage
+	// ActionWaitIdle waits until the page stops sending requests.
+	// name:waitidle
+	ActionWaitIdle
+	// ActionWaitResponse waits for a response to a request matching a URL regex.
+	// name:waitresponse
+	ActionWaitResponse
+	// ActionDumpDOM dumps the DOM of the page after JS execution.
+	// name:dumpdom
+	ActionDumpDOM
 	// limit
 	limit
 )
@@ -108,6 +135,15 @@ var ActionStringToAction = map[string]ActionType{
 	"debug":        ActionDebug,
 	"sleep":        ActionSleep,
 	"waitvisible":  ActionWaitVisible,
+	"hover":        ActionHover,
+	"dragdrop":     ActionDragDrop,
+	"frame":        ActionFrame,
+	"dialog":       ActionDialog,
+	"getstorage":   ActionGetStorage,
+	"setstorage":   ActionSetStorage,
+	"waitidle":     ActionWaitIdle,
+	"waitresponse": ActionWaitResponse,
+	"dumpdom":      ActionDumpDOM,
 }
 
 // ActionToActionString converts an action from  internal representation to string
@@ -134,6 +170,15 @@ var ActionToActionString = map[ActionType]string{
 	ActionDebug:        "debug",
 	ActionSleep:        "sleep",
 	ActionWaitVisible:  "waitvisible",
+	ActionHover:        "hover",
+	ActionDragDrop:     "dragdrop",
+	ActionFrame:        "frame",
+	ActionDialog:       "dialog",
+	ActionGetStorage:   "getstorage",
+	ActionSetStorage:   "setstorage",
+	ActionWaitIdle:     "waitidle",
+	ActionWaitResponse: "waitresponse",
+	ActionDumpDOM:      "dumpdom",
 }
 
 // GetSupportedActionTypes returns list of supported types
@@ -0,0 +1,314 @@
+package engine
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+// crawlFormValue is the dummy value of the text fields of submitted forms
+const crawlFormValue = "vulmap"
+
+// crawlIdleTime is the time without requests after which a page is loaded
+const crawlIdleTime = 500 * time.Millisecond
+
+// crawlLinksScript returns the links and the number of forms of the page
+const crawlLinksScript = `() => ({
+	links: Array.from(document.querySelectorAll('a[href], area[href], iframe[src], frame[src]'))
+		.map(element => element.href || element.src)
+		.filter(link => typeof link === 'string' && /^https?:/i.test(link)),
+	forms: document.forms.length,
+})`
+
+// crawlSubmitFormScript fills the fields of a form with dummy data and submits it
+const crawlSubmitFormScript = `(index, value) => {
+	const form = document.forms[index];
+	if (!form) {
+		return;
+	}
+	for (const element of form.elements) {
+		if (!element.name || element.disabled) {
+			continue;
+		}
+		switch (element.type) {
+		case 'hidden': case 'submit': case 'button': case 'reset': case 'image': case 'file':
+			break;
+		case 'checkbox': case 'radio':
+			element.checked = true;
+			break;
+		case 'email':
+			element.value = element.value || value + '@example.com';
+			break;
+		case 'number': case 'range':
+			element.value = element.value || '1';
+			break;
+		case 'url':
+			element.value = element.value || 'https://example.com/';
+			break;
+		case 'tel':
+			element.value = element.value || '5555555555';
+			break;
+		case 'date':
+			element.value = element.value || '2024-01-01';
+			break;
+		case 'select-one': case 'select-multiple':
+			if (element.selectedIndex < 0 && element.options.length > 0) {
+				element.selectedIndex = 0;
+			}
+			break;
+		default:
+			element.value = element.value || value;
+		}
+	}
+	setTimeout(() => form.requestSubmit ? form.requestSubmit() : form.submit(), 0);
+}`
+
+// CrawlOptions contains the configuration of the crawler
+type CrawlOptions struct {
+	// Timeout is the maximum time to wait for each page
+	Timeout time.Duration
+	// MaxDepth is the maximum depth of the followed links
+	MaxDepth int
+	// Scope contains the regexes of the in scope URLs, the URLs with the
+	// hostname of the input are in scope by default.
+	Scope []*regexp.Regexp
+	// OutOfScope contains the regexes of the out of scope URLs
+	OutOfScope []*regexp.Regexp
+}
+
+// crawler is the state of the crawling of an input
+type crawler struct {
+	instance *Instance
+	options  *CrawlOptions
+	hostname string
+	callback func(*contextargs.MetaInput)
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// crawlItem is a page queued for crawling
+type crawlItem struct {
+	URL   string
+	depth int
+}
+
+// Crawl explores the pages of the input following the in scope links up to the
+// maximum depth and submitting the forms with dummy data.
+//
+// The in scope documents, XHR and fetch requests sent by the pages are passed
+// to the callback once each as inputs with their full request, the out of scope
+// ones are blocked.
+func (i *Instance) Crawl(input string, options *CrawlOptions, callback func(*contextargs.MetaInput)) error {
+	parsed, err := url.Parse(input)
+	if err != nil || parsed.Hostname() == "" {
+		return errors.Errorf("could not parse url %s", input)
+	}
+	c := &crawler{
+		instance: i,
+		options:  options,
+		hostname: parsed.Hostname(),
+		callback: callback,
+		seen:     make(map[string]struct{}),
+	}
+
+	queue := []crawlItem{{URL: input}}
+	visited := map[string]struct{}{input: {}}
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		links, forms, err := c.visit(item.URL)
+		if err != nil {
+			gologger.Verbose().Msgf("Could not crawl %s: %s\n", item.URL, err)
+			continue
+		}
+		for index := 0; index < forms; index++ {
+			if err := c.submitForm(item.URL, index); err != nil {
+				gologger.Verbose().Msgf("Could not submit form %d of %s: %s\n", index, item.URL, err)
+			}
+		}
+		if item.depth >= options.MaxDepth {
+			continue
+		}
+		for _, link := range links {
+			if index := strings.Index(link, "#"); index != -1 {
+				link = link[:index]
+			}
+			if _, ok := visited[link]; ok || !c.inScope(link) {
+				continue
+			}
+			visited[link] = struct{}{}
+			queue = append(queue, crawlItem{URL: link, depth: item.depth + 1})
+		}
+	}
+	return nil
+}
+
+// visit navigates to the page and returns its links and number of forms
+func (c *crawler) visit(target string) ([]string, int, error) {
+	page, closePage, err := c.newPage()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer closePage()
+
+	if err := c.navigate(page, target); err != nil {
+		return nil, 0, err
+	}
+	result, err := page.Eval(crawlLinksScript)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "could not get links")
+	}
+	var links []string
+	for _, link := range result.Value.Get("links").Arr() {
+		links = append(links, link.Str())
+	}
+	return links, result.Value.Get("forms").Int(), nil
+}
+
+// submitForm navigates to the page and submits the form with dummy data
+func (c *crawler) submitForm(target string, index int) error {
+	page, closePage, err := c.newPage()
+	if err != nil {
+		return err
+	}
+	defer closePage()
+
+	if err := c.navigate(page, target); err != nil {
+		return err
+	}
+	wait := page.WaitRequestIdle(crawlIdleTime, nil, nil, nil)
+	if _, err := page.Eval(crawlSubmitFormScript, index, crawlFormValue); err != nil {
+		return err
+	}
+	wait()
+	return nil
+}
+
+// navigate navigates to the URL and waits until the page stops sending requests
+func (c *crawler) navigate(page *rod.Page, target string) error {
+	wait := page.WaitRequestIdle(crawlIdleTime, nil, nil, nil)
+	if err := page.Navigate(target); err != nil {
+		return err
+	}
+	if err := page.WaitLoad(); err != nil {
+		return err
+	}
+	wait()
+	return nil
+}
+
+// newPage creates a page recording the requests it sends, the returned
+// function closes the page.
+func (c *crawler) newPage() (*rod.Page, func(), error) {
+	page, err := c.instance.engine.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.instance.browser.customAgent != "" {
+		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: c.instance.browser.customAgent}); err != nil {
+			page.Close()
+			return nil, nil, err
+		}
+	}
+
+	// dialogs opened by the pages would block the crawling
+	dialogs, cancel := page.WithCancel()
+	go dialogs.EachEvent(func(e *proto.PageJavascriptDialogOpening) {
+		_ = proto.PageHandleJavaScriptDialog{Accept: true}.Call(page)
+	})()
+
+	hijack := NewHijack(page)
+	hijack.SetPattern(&proto.FetchRequestPattern{
+		URLPattern:   "*",
+		RequestStage: proto.FetchRequestStageRequest,
+	})
+	hijackHandler := hijack.Start(func(e *proto.FetchRequestPaused) error {
+		if err := protocolstate.ValidateNFailRequest(page, e); err != nil {
+			return err
+		}
+		if crawledResource(e.ResourceType) {
+			if !c.inScope(e.Request.URL) {
+				// out of scope pages and API calls are not sent, e.g. logout links
+				return FetchFailRequest(page, e, proto.NetworkErrorReasonBlockedByClient)
+			}
+			c.record(e)
+		}
+		return FetchContinueRequest(page, e)
+	})
+	go func() {
+		_ = hijackHandler()
+	}()
+
+	closePage := func() {
+		cancel()
+		_ = hijack.Stop()
+		page.Close()
+	}
+	return page.Timeout(c.options.Timeout), closePage, nil
+}
+
+// crawledResource returns true for the documents, XHR and fetch requests
+// which are restricted to the scope and recorded by the crawler
+func crawledResource(resourceType proto.NetworkResourceType) bool {
+	switch resourceType {
+	case proto.NetworkResourceTypeDocument, proto.NetworkResourceTypeXHR, proto.NetworkResourceTypeFetch:
+		return true
+	}
+	return false
+}
+
+// record passes the in scope request to the callback once
+func (c *crawler) record(e *proto.FetchRequestPaused) {
+	request := &contextargs.Request{
+		Method:  e.Request.Method,
+		Headers: make(map[string]string, len(e.Request.Headers)),
+		Body:    e.Request.PostData,
+	}
+	for name, value := range e.Request.Headers {
+		request.Headers[name] = value.String()
+	}
+
+	key := request.Method + " " + e.Request.URL + " " + request.Body
+	c.mu.Lock()
+	_, seen := c.seen[key]
+	c.seen[key] = struct{}{}
+	c.mu.Unlock()
+	if seen {
+		return
+	}
+	c.callback(&contextargs.MetaInput{Input: e.Request.URL, Request: request})
+}
+
+// inScope returns true if the URL is in the scope of the crawler
+func (c *crawler) inScope(target string) bool {
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	for _, outOfScope := range c.options.OutOfScope {
+		if outOfScope.MatchString(target) {
+			return false
+		}
+	}
+	if len(c.options.Scope) == 0 {
+		return parsed.Hostname() == c.hostname
+	}
+	for _, scope := range c.options.Scope {
+		if scope.MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils/testheadless"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+func TestCrawl(t *testing.T) {
+	_ = protocolstate.Init(&types.Options{})
+
+	browser, err := New(&types.Options{ShowBrowser: false, UseInstalledChrome: testheadless.HeadlessLocal})
+	require.Nil(t, err, "could not create browser")
+	defer browser.Close()
+
+	instance, err := browser.NewInstance()
+	require.Nil(t, err, "could not create browser instance")
+	defer instance.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `<html><body>
+			<a href="/page?id=1">page</a>
+			<a href="http://example.com/">external</a>
+			<a href="/logout">logout</a>
+			<form method="POST" action="/login">
+				<input type="text" name="username">
+				<input type="password" name="password">
+			</form>
+			<script>fetch('/api/items?q=1'); fetch('/logout')</script>
+		</body></html>`)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `<html><body><a href="/deep">deep</a></body></html>`)
+	})
+	mux.HandleFunc("/api/items", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `[]`)
+	})
+	var loggedOut atomic.Bool
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		loggedOut.Store(true)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `<html><body>welcome</body></html>`)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	var mu sync.Mutex
+	discovered := make(map[string]*contextargs.Request)
+	err = instance.Crawl(ts.URL+"/", &CrawlOptions{
+		Timeout:    20 * time.Second,
+		MaxDepth:   1,
+		OutOfScope: []*regexp.Regexp{regexp.MustCompile(`/logout`)},
+	}, func(input *contextargs.MetaInput) {
+		mu.Lock()
+		defer mu.Unlock()
+		discovered[input.Request.Method+" "+input.Input] = input.Request
+	})
+	require.Nil(t, err, "could not crawl input")
+
+	require.Contains(t, discovered, "GET "+ts.URL+"/", "could not discover input")
+	require.Contains(t, discovered, "GET "+ts.URL+"/page?id=1", "could not discover link")
+	require.Contains(t, discovered, "GET "+ts.URL+"/api/items?q=1", "could not discover fetch request")
+	require.Contains(t, discovered, "POST "+ts.URL+"/login", "could not discover form")
+	require.Equal(t, "username=vulmap&password=vulmap", discovered["POST "+ts.URL+"/login"].Body, "could not submit form with dummy data")
+	require.NotContains(t, discovered, "GET "+ts.URL+"/deep", "could discover link beyond max depth")
+	require.NotContains(t, discovered, "GET "+ts.URL+"/logout", "could discover out of scope link")
+	require.False(t, loggedOut.Load(), "could send out of scope request")
+	require.NotContains(t, discovered, "GET http://example.com/", "could discover link of other host")
+}
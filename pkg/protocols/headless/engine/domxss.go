@@ -0,0 +1,328 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// DOMXSSMarkerVariable is the variable containing the random marker of the
+// payloads identifying the tainted values by default.
+const DOMXSSMarkerVariable = "dom_xss_marker"
+
+// domXSSBinding is the name of the binding receiving the taint reports
+const domXSSBinding = "__vulmapDOMXSS"
+
+// DOMXSSSinks contains the sinks instrumented by the DOM XSS hooks
+var DOMXSSSinks = []string{
+	"innerHTML",
+	"outerHTML",
+	"insertAdjacentHTML",
+	"document.write",
+	"eval",
+	"Function",
+	"setTimeout",
+	"setInterval",
+	"setAttribute",
+	"location",
+	"postMessage",
+	"alert",
+}
+
+// DOMXSS contains the configuration of the DOM XSS detection of a headless request.
+//
+// The sinks are instrumented by a hook script injected before the page load, which
+// reports the values containing a marker reaching the sinks.
+type DOMXSS struct {
+	// description: |
+	//   Sinks is the list of instrumented sinks, all the sinks are instrumented by default.
+	// values:
+	//   - "innerHTML"
+	//   - "outerHTML"
+	//   - "insertAdjacentHTML"
+	//   - "document.write"
+	//   - "eval"
+	//   - "Function"
+	//   - "setTimeout"
+	//   - "setInterval"
+	//   - "setAttribute"
+	//   - "location"
+	//   - "postMessage"
+	//   - "alert"
+	Sinks []string `yaml:"sinks,omitempty" json:"sinks,omitempty" jsonschema:"title=instrumented sinks,description=List of the instrumented sinks"`
+	// description: |
+	//   Markers are the strings identifying the tainted values reaching the sinks.
+	//
+	//   By default, the random value of the dom_xss_marker variable is used as marker
+	//   and has to be included in the payloads.
+	// examples:
+	//   - value: >
+	//       []string{"{{dom_xss_marker}}"}
+	Markers []string `yaml:"markers,omitempty" json:"markers,omitempty" jsonschema:"title=markers of tainted values,description=Strings identifying the tainted values reaching the sinks"`
+	// description: |
+	//   Script is a custom hook script executed before the page load, which can
+	//   report the tainted values of other sinks with the report(sink, value) function.
+	// examples:
+	//   - value: "\"const html = $.fn.html; $.fn.html = function(value) { report('jquery.html', value); return html.apply(this, arguments); }\""
+	Script string `yaml:"script,omitempty" json:"script,omitempty" jsonschema:"title=custom hook script,description=Custom hook script reporting tainted values with the report(sink, value) function"`
+}
+
+// DOMXSSReport is a tainted value reaching a sink
+type DOMXSSReport struct {
+	Sink    string `json:"sink"`
+	Payload string `json:"payload"`
+	Stack   string `json:"stack"`
+	URL     string `json:"url"`
+}
+
+// Validate validates the DOM XSS configuration
+func (d *DOMXSS) Validate() error {
+	for _, sink := range d.Sinks {
+		if !slices.Contains(DOMXSSSinks, sink) {
+			return errors.Errorf("invalid dom xss sink %s", sink)
+		}
+	}
+	return nil
+}
+
+// domXSSHookScript instruments the sinks of the page, the values containing
+// a marker are sent to the binding with the sink and the stack trace.
+const domXSSHookScript = `(config) => {
+	const send = window[config.binding];
+	if (typeof send !== 'function') {
+		return;
+	}
+	try {
+		delete window[config.binding];
+	} catch (e) {}
+
+	const enabled = (sink) => config.sinks.length === 0 || config.sinks.includes(sink);
+	const report = (sink, value) => {
+		let payload;
+		try {
+			payload = typeof value === 'string' ? value : String(value);
+		} catch (e) {
+			return;
+		}
+		if (!config.markers.some((marker) => payload.includes(marker))) {
+			return;
+		}
+		const stack = (new Error().stack || '').split('\n').slice(2).map((line) => line.trim()).join('\n');
+		send(JSON.stringify({ sink: sink, payload: payload.slice(0, 4096), stack: stack, url: location.href }));
+	};
+	const hookSetter = (prototype, property, sink, value = (value) => value) => {
+		const descriptor = Object.getOwnPropertyDescriptor(prototype, property);
+		if (!descriptor || !descriptor.set || !descriptor.configurable) {
+			return false;
+		}
+		Object.defineProperty(prototype, property, Object.assign({}, descriptor, {
+			set(assigned) {
+				const tainted = value(assigned);
+				if (tainted !== undefined) {
+					report(sink, tainted);
+				}
+				return descriptor.set.call(this, assigned);
+			},
+		}));
+		return true;
+	};
+	const hookMethod = (object, method, sink, value) => {
+		const original = object[method];
+		if (typeof original !== 'function') {
+			return false;
+		}
+		const hooked = function (...args) {
+			const tainted = value(args);
+			if (tainted !== undefined) {
+				report(sink, tainted);
+			}
+			return original.apply(this, args);
+		};
+		hooked.prototype = original.prototype;
+		try {
+			object[method] = hooked;
+		} catch (e) {}
+		return object[method] === hooked;
+	};
+	// navigated returns the url if it executes script or contains a marker outside
+	// of its query, markers in the query are passed to the next page and not reported
+	const navigated = (value) => {
+		let url;
+		try {
+			url = new URL(String(value), location.href);
+		} catch (e) {
+			return undefined;
+		}
+		if (url.protocol === 'javascript:' || url.protocol === 'data:') {
+			return String(value);
+		}
+		const outside = url.protocol + '//' + url.host + url.pathname + url.hash;
+		return config.markers.some((marker) => outside.includes(marker)) ? String(value) : undefined;
+	};
+
+	if (enabled('innerHTML')) {
+		hookSetter(Element.prototype, 'innerHTML', 'innerHTML');
+		hookSetter(ShadowRoot.prototype, 'innerHTML', 'innerHTML');
+	}
+	if (enabled('outerHTML')) {
+		hookSetter(Element.prototype, 'outerHTML', 'outerHTML');
+	}
+	if (enabled('insertAdjacentHTML')) {
+		hookMethod(Element.prototype, 'insertAdjacentHTML', 'insertAdjacentHTML', (args) => args[1]);
+	}
+	if (enabled('document.write')) {
+		hookMethod(Document.prototype, 'write', 'document.write', (args) => args.join(''));
+		hookMethod(Document.prototype, 'writeln', 'document.write', (args) => args.join(''));
+	}
+	if (enabled('eval')) {
+		hookMethod(window, 'eval', 'eval', (args) => args[0]);
+	}
+	if (enabled('Function')) {
+		hookMethod(window, 'Function', 'Function', (args) => args.join(','));
+	}
+	for (const timer of ['setTimeout', 'setInterval']) {
+		if (enabled(timer)) {
+			hookMethod(window, timer, timer, (args) => typeof args[0] === 'string' ? args[0] : undefined);
+		}
+	}
+	if (enabled('setAttribute')) {
+		const attributes = /^(on.+|href|src|srcdoc|action|formaction|data)$/i;
+		hookMethod(Element.prototype, 'setAttribute', 'setAttribute', (args) => attributes.test(String(args[0])) ? args[1] : undefined);
+	}
+	if (enabled('location')) {
+		hookMethod(window, 'open', 'location', (args) => navigated(args[0]));
+		let hooked = hookMethod(location, 'assign', 'location', (args) => navigated(args[0]));
+		hooked = hookMethod(location, 'replace', 'location', (args) => navigated(args[0])) && hooked;
+		hooked = hookSetter(location, 'href', 'location', navigated) && hooked;
+		if (!hooked && window.navigation) {
+			// the members of location can't be redefined in chromium, so the
+			// navigations are reported when they start instead
+			window.navigation.addEventListener('navigate', (event) => {
+				const tainted = navigated(event.destination.url);
+				if (tainted !== undefined) {
+					report('location', tainted);
+				}
+			});
+		}
+	}
+	if (enabled('postMessage')) {
+		let handlers = 0;
+		hookMethod(EventTarget.prototype, 'addEventListener', 'postMessage', (args) => {
+			if (args[0] === 'message') {
+				handlers++;
+			}
+		});
+		const descriptor = Object.getOwnPropertyDescriptor(window, 'onmessage');
+		if (descriptor && descriptor.set) {
+			Object.defineProperty(window, 'onmessage', Object.assign({}, descriptor, {
+				set(value) {
+					handlers++;
+					return descriptor.set.call(this, value);
+				},
+			}));
+		}
+		window.addEventListener('message', (event) => {
+			if (handlers > 0) {
+				report('postMessage', typeof event.data === 'string' ? event.data : JSON.stringify(event.data));
+			}
+		}, true);
+	}
+	if (enabled('alert')) {
+		// the dialogs would block the page, the payloads executing them are reported instead
+		window.alert = (message) => report('alert', message);
+		window.confirm = (message) => {
+			report('alert', message);
+			return true;
+		};
+		window.prompt = (message) => {
+			report('alert', message);
+			return '';
+		};
+		window.print = () => {};
+	}
+
+	try {
+		((report) => {
+			%s
+		})(report);
+	} catch (e) {}
+}`
+
+// domXSSHookConfig is the configuration passed to the hook script
+type domXSSHookConfig struct {
+	Binding string   `json:"binding"`
+	Markers []string `json:"markers"`
+	Sinks   []string `json:"sinks"`
+}
+
+// buildDOMXSSHookScript returns the hook script instrumenting the sinks of the
+// configuration with the markers.
+func buildDOMXSSHookScript(config *DOMXSS, markers []string) (string, error) {
+	sinks := config.Sinks
+	if sinks == nil {
+		sinks = []string{}
+	}
+	data, err := json.Marshal(&domXSSHookConfig{Binding: domXSSBinding, Markers: markers, Sinks: sinks})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(%s)(%s)", fmt.Sprintf(domXSSHookScript, config.Script), data), nil
+}
+
+// hookDOMXSS injects the DOM XSS hooks in the page and records the reports
+func (p *Page) hookDOMXSS(config *DOMXSS) error {
+	var markers []string
+	for _, marker := range config.Markers {
+		if value := replaceWithValues(marker, p.payloads); value != "" {
+			markers = append(markers, value)
+		}
+	}
+	if len(config.Markers) == 0 {
+		if marker := types.ToString(p.payloads[DOMXSSMarkerVariable]); marker != "" {
+			markers = append(markers, marker)
+		}
+	}
+	if len(markers) == 0 {
+		return errors.New("no dom xss marker")
+	}
+	script, err := buildDOMXSSHookScript(config, markers)
+	if err != nil {
+		return errors.Wrap(err, "could not build dom xss hook script")
+	}
+
+	if err := (proto.RuntimeAddBinding{Name: domXSSBinding}).Call(p.root); err != nil {
+		return errors.Wrap(err, "could not add dom xss binding")
+	}
+	events, cancel := p.root.WithCancel()
+	p.cancelDOMXSS = cancel
+	go events.EachEvent(func(e *proto.RuntimeBindingCalled) {
+		if e.Name != domXSSBinding {
+			return
+		}
+		var report DOMXSSReport
+		if err := json.Unmarshal([]byte(e.Payload), &report); err != nil {
+			return
+		}
+		p.mutex.Lock()
+		p.domXSSReports = append(p.domXSSReports, report)
+		p.mutex.Unlock()
+	})()
+
+	if _, err := p.root.EvalOnNewDocument(script); err != nil {
+		return errors.Wrap(err, "could not inject dom xss hook script")
+	}
+	return nil
+}
+
+// DOMXSSReports returns the tainted values which reached the sinks of the page
+func (p *Page) DOMXSSReports() []DOMXSSReport {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return slices.Clone(p.domXSSReports)
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils/testheadless"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+func TestDOMXSSValidate(t *testing.T) {
+	require.Nil(t, (&DOMXSS{}).Validate(), "could not validate default sinks")
+	require.Nil(t, (&DOMXSS{Sinks: []string{"innerHTML", "document.write"}}).Validate(), "could not validate sinks")
+	require.Error(t, (&DOMXSS{Sinks: []string{"innerText"}}).Validate(), "could validate unknown sink")
+}
+
+func TestBuildDOMXSSHookScript(t *testing.T) {
+	script, err := buildDOMXSSHookScript(&DOMXSS{Script: "report('custom', window.name);"}, []string{"vulmap123"})
+	require.Nil(t, err, "could not build hook script")
+	require.Contains(t, script, `{"binding":"__vulmapDOMXSS","markers":["vulmap123"],"sinks":[]}`, "could not pass configuration")
+	require.Contains(t, script, "report('custom', window.name);", "could not include custom script")
+}
+
+func TestDOMXSS(t *testing.T) {
+	_ = protocolstate.Init(&types.Options{})
+
+	browser, err := New(&types.Options{ShowBrowser: false, UseInstalledChrome: testheadless.HeadlessLocal})
+	require.Nil(t, err, "could not create browser")
+	defer browser.Close()
+
+	instance, err := browser.NewInstance()
+	require.Nil(t, err, "could not create browser instance")
+	defer instance.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `<html><body><div id="search"></div><div id="safe"></div><script>
+			const query = decodeURIComponent(location.search.slice(3));
+			document.getElementById('search').innerHTML = 'Results for ' + query;
+			document.getElementById('safe').textContent = query;
+			document.getElementById('safe').innerHTML = 'static content';
+		</script></body></html>`)
+	}))
+	defer ts.Close()
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}/?q=<img src=x onerror=alert('{{dom_xss_marker}}')>"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+	}
+	payloads := map[string]interface{}{DOMXSSMarkerVariable: "vulmap123"}
+	_, page, err := instance.Run(contextargs.NewWithInput(ts.URL), actions, payloads, &Options{Timeout: 20 * time.Second, DOMXSS: &DOMXSS{}, Options: &types.Options{}})
+	require.Nil(t, err, "could not run page actions")
+	defer page.Close()
+
+	var sinks []string
+	for _, report := range page.DOMXSSReports() {
+		sinks = append(sinks, report.Sink)
+		require.Contains(t, report.Payload, "vulmap123", "could not get tainted payload")
+		require.NotEmpty(t, report.Stack, "could not get stack trace")
+	}
+	require.Equal(t, []string{"innerHTML", "alert"}, sinks, "could not detect tainted sinks")
+}
+
+func TestDOMXSSLocation(t *testing.T) {
+	_ = protocolstate.Init(&types.Options{})
+
+	browser, err := New(&types.Options{ShowBrowser: false, UseInstalledChrome: testheadless.HeadlessLocal})
+	require.Nil(t, err, "could not create browser")
+	defer browser.Close()
+
+	instance, err := browser.NewInstance()
+	require.Nil(t, err, "could not create browser instance")
+	defer instance.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, `<html><body><script>
+			const params = new URLSearchParams(location.search);
+			if (params.get('redirect')) {
+				location.href = params.get('redirect');
+			}
+			if (params.get('search')) {
+				location.assign('/results?q=' + encodeURIComponent(params.get('search')));
+			}
+		</script></body></html>`)
+	}))
+	defer ts.Close()
+
+	run := func(query string) []DOMXSSReport {
+		actions := []*Action{
+			{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}/?" + query}},
+			{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+			{ActionType: ActionTypeHolder{ActionType: ActionWaitIdle}},
+		}
+		payloads := map[string]interface{}{DOMXSSMarkerVariable: "vulmap123"}
+		_, page, err := instance.Run(contextargs.NewWithInput(ts.URL), actions, payloads, &Options{Timeout: 20 * time.Second, DOMXSS: &DOMXSS{Sinks: []string{"location"}}, Options: &types.Options{}})
+		require.Nil(t, err, "could not run page actions")
+		defer page.Close()
+		return page.DOMXSSReports()
+	}
+
+	reports := run("redirect=/{{dom_xss_marker}}")
+	require.Len(t, reports, 1, "could not detect tainted redirect")
+	require.Equal(t, "location", reports[0].Sink, "could not get location sink")
+	require.Contains(t, reports[0].Payload, "/vulmap123", "could not get tainted url")
+
+	require.Empty(t, run("search={{dom_xss_marker}}"), "could report marker in query of navigation")
+}
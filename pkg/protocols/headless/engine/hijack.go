@@ -97,3 +97,12 @@ func FetchContinueRequest(page *rod.Page, e *proto.FetchRequestPaused) error {
 	}
 	return m.Call(page)
 }
+
+// FetchFailRequest fails request with the reason
+func FetchFailRequest(page *rod.Page, e *proto.FetchRequestPaused, reason proto.NetworkErrorReason) error {
+	m := proto.FetchFailRequest{
+		RequestID:   e.RequestID,
+		ErrorReason: reason,
+	}
+	return m.Call(page)
+}
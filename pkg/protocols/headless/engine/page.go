@@ -13,6 +13,7 @@ import (
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 )
 
@@ -21,6 +22,11 @@ type Page struct {
 	input          *contextargs.Context
 	options        *Options
 	page           *rod.Page
+	root           *rod.Page
+	dialog         *dialogHandler
+	cancelDOMXSS   func()
+	domXSSReports  []DOMXSSReport
+	sessionHeaders map[string]map[string]string
 	rules          []rule
 	instance       *Instance
 	hijackRouter   *rod.HijackRouter
@@ -33,6 +39,7 @@ type Page struct {
 
 // HistoryData contains the page request/response pairs
 type HistoryData struct {
+	URL         string
 	RawRequest  string
 	RawResponse string
 }
@@ -41,7 +48,11 @@ type HistoryData struct {
 type Options struct {
 	Timeout     time.Duration
 	CookieReuse bool
-	Options     *types.Options
+	// DOMXSS enables the DOM XSS hooks of the page if not nil
+	DOMXSS *DOMXSS
+	// Session imports the session state in the page and exports the session state of the page if not nil
+	Session *session.Store
+	Options *types.Options
 }
 
 // Run runs a list of actions by creating a new page in the browser.
@@ -61,6 +72,7 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 	createdPage := &Page{
 		options:  options,
 		page:     page,
+		root:     page,
 		input:    input,
 		instance: i,
 		mutex:    &sync.RWMutex{},
@@ -96,6 +108,18 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 		return nil, nil, err
 	}
 
+	if options.DOMXSS != nil {
+		if err := createdPage.hookDOMXSS(options.DOMXSS); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if options.Session.Importing() {
+		if err := createdPage.importSession(options.Session); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if _, err := page.SetExtraHeaders([]string{"Accept-Language", "en, en-GB, en-us;"}); err != nil {
 		return nil, nil, err
 	}
@@ -160,6 +184,12 @@ func (i *Instance) Run(input *contextargs.Context, actions []*Action, payloads m
 		}
 	}
 
+	if options.Session.Exporting() {
+		if err := createdPage.exportSession(options.Session); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// The first item of history data will contain the very first request from the browser
 	// we assume it's the one matching the initial URL
 	if len(createdPage.History) > 0 {
@@ -182,12 +212,19 @@ func (p *Page) Close() {
 	if p.hijackNative != nil {
 		_ = p.hijackNative.Stop()
 	}
-	p.page.Close()
+	if p.dialog != nil {
+		p.dialog.cancel()
+	}
+	if p.cancelDOMXSS != nil {
+		p.cancelDOMXSS()
+	}
+	p.root.Close()
 }
 
-// Page returns the current page for the actions
+// Page returns the current page for the actions, the frames
+// the actions switched into are ignored.
 func (p *Page) Page() *rod.Page {
-	return p.page
+	return p.root
 }
 
 // Browser returns the browser that created the current page
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -37,6 +38,7 @@ var (
 const (
 	errCouldNotGetElement  = "could not get element"
 	errCouldNotScroll      = "could not scroll into view"
+	errCouldNotEvaluate    = "could not evaluate script"
 	errElementDidNotAppear = "Element did not appear in the given amount of time"
 )
 
@@ -94,16 +96,51 @@ func (p *Page) ExecuteActions(input *contextargs.Context, actions []*Action, var
 			err = p.SleepAction(act, outData)
 		case ActionWaitVisible:
 			err = p.WaitVisible(act, outData)
+		case ActionHover:
+			err = p.HoverElement(act, outData)
+		case ActionDragDrop:
+			err = p.DragDropElement(act, outData)
+		case ActionFrame:
+			err = p.SwitchFrame(act, outData)
+		case ActionDialog:
+			err = p.HandleDialog(act, outData)
+		case ActionGetStorage:
+			err = p.GetStorage(act, outData)
+		case ActionSetStorage:
+			err = p.SetStorage(act, outData)
+		case ActionWaitIdle:
+			err = p.WaitIdle(act, outData)
+		case ActionWaitResponse:
+			err = p.WaitResponse(act, outData)
+		case ActionDumpDOM:
+			err = p.DumpDOM(act, outData)
 		default:
 			continue
 		}
 		if err != nil {
+			if to := p.getActionArgWithDefaultValues(act, "failure-screenshot"); to != "" {
+				p.failureScreenshot(to)
+			}
 			return nil, errors.Wrap(err, "error occurred executing action")
 		}
 	}
+	if p.dialog != nil && p.dialog.name != "" {
+		outData[p.dialog.name] = p.dialog.String()
+	}
 	return outData, nil
 }
 
+// failureScreenshot takes a full page screenshot of the page after an action failed
+func (p *Page) failureScreenshot(to string) {
+	act := &Action{
+		ActionType: ActionTypeHolder{ActionType: ActionScreenshot},
+		Data:       map[string]string{"to": to, "fullpage": "true", "mkdir": "true"},
+	}
+	if err := p.Screenshot(act, make(map[string]string)); err != nil {
+		gologger.Warning().Msgf("Could not take screenshot of failed action: %s\n", err)
+	}
+}
+
 type rule struct {
 	*sync.Once
 	Action ActionType
@@ -140,7 +177,7 @@ func (p *Page) WaitVisible(act *Action, out map[string]string) error {
 
 func (p *Page) Sleeper(pollTimeout, timeout time.Duration) *Page {
 	page := *p
-	page.page = page.Page().Sleeper(func() utils.Sleeper {
+	page.page = page.page.Sleeper(func() utils.Sleeper {
 		return createBackOffSleeper(pollTimeout, timeout)
 	})
 	return &page
@@ -148,7 +185,7 @@ func (p *Page) Sleeper(pollTimeout, timeout time.Duration) *Page {
 
 func (p *Page) Timeout(timeout time.Duration) *Page {
 	page := *p
-	page.page = page.Page().Timeout(timeout)
+	page.page = page.page.Timeout(timeout)
 	return &page
 }
 
@@ -664,3 +701,417 @@ func (p *Page) getActionArgWithValues(action *Action, arg string, values map[str
 	}
 	return argValue
 }
+
+// HoverElement moves the mouse over an element.
+func (p *Page) HoverElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	if err = element.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+	if err = element.Hover(); err != nil {
+		return errors.Wrap(err, "could not hover element")
+	}
+	return nil
+}
+
+// dragDropScript dispatches the HTML5 drag and drop events from the source
+// to the target element, which are not triggered by emulated mouse events.
+const dragDropScript = `(source, target) => {
+	const dataTransfer = new DataTransfer();
+	const fire = (element, type) => element.dispatchEvent(new DragEvent(type, { bubbles: true, cancelable: true, dataTransfer }));
+	fire(source, 'dragstart');
+	fire(target, 'dragenter');
+	fire(target, 'dragover');
+	fire(target, 'drop');
+	fire(source, 'dragend');
+}`
+
+// DragDropElement drags an element and drops it on the target element
+// selected with the args prefixed with target- (ex: target-selector).
+func (p *Page) DragDropElement(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	targetData := make(map[string]string)
+	for key, value := range act.Data {
+		if strings.HasPrefix(key, "target-") {
+			targetData[strings.TrimPrefix(key, "target-")] = value
+		}
+	}
+	if !hasElementSelector(act.Data) || !hasElementSelector(targetData) {
+		return errinvalidArguments
+	}
+	source, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	target, err := p.pageElementBy(targetData)
+	if err != nil {
+		return errors.Wrap(err, "could not get target element")
+	}
+	if err = source.ScrollIntoView(); err != nil {
+		return errors.Wrap(err, errCouldNotScroll)
+	}
+
+	draggable, err := source.Property("draggable")
+	if err != nil {
+		return errors.Wrap(err, "could not get draggable property")
+	}
+	if draggable.Bool() {
+		if _, err := p.page.Evaluate(rod.Eval(dragDropScript, source.Object, target.Object)); err != nil {
+			return errors.Wrap(err, "could not drag element")
+		}
+		return nil
+	}
+
+	// elements which aren't draggable are moved with mouse events (ex: sliders, sortable lists)
+	from, err := elementCenter(source)
+	if err != nil {
+		return err
+	}
+	to, err := elementCenter(target)
+	if err != nil {
+		return err
+	}
+	mouse := p.page.Mouse
+	if err := mouse.MoveTo(from); err != nil {
+		return errors.Wrap(err, "could not move mouse")
+	}
+	if err := mouse.Down(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "could not press mouse button")
+	}
+	if err := mouse.MoveLinear(to, 10); err != nil {
+		return errors.Wrap(err, "could not move mouse")
+	}
+	if err := mouse.Up(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "could not release mouse button")
+	}
+	return nil
+}
+
+// elementCenter returns the center point of an element
+func elementCenter(element *rod.Element) (proto.Point, error) {
+	shape, err := element.Shape()
+	if err != nil {
+		return proto.Point{}, errors.Wrap(err, "could not get element shape")
+	}
+	point := shape.OnePointInside()
+	if point == nil {
+		return proto.Point{}, errors.New("element is not visible")
+	}
+	return *point, nil
+}
+
+// SwitchFrame switches the following actions into an iframe element, the
+// actions are switched back to the page when no element is selected.
+func (p *Page) SwitchFrame(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	if !hasElementSelector(act.Data) {
+		p.page = p.root
+		return nil
+	}
+	element, err := p.pageElementBy(act.Data)
+	if err != nil {
+		return errors.Wrap(err, errCouldNotGetElement)
+	}
+	frame, err := element.Frame()
+	if err != nil {
+		return errors.Wrap(err, "could not get frame")
+	}
+	if frame.FrameID == "" {
+		return errors.New("element is not a frame")
+	}
+	p.page = frame
+	return nil
+}
+
+// dialogHandler handles the dialogs opened by the page
+type dialogHandler struct {
+	mu       sync.Mutex
+	accept   bool
+	text     string
+	name     string
+	messages []string
+	cancel   func()
+}
+
+// String returns the messages of the handled dialogs
+func (d *dialogHandler) String() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return strings.Join(d.messages, "\n")
+}
+
+// HandleDialog accepts or dismisses the dialogs (alert, confirm, prompt) opened
+// by the page after the action, which would block the following actions otherwise.
+//
+// The messages of the dialogs are stored in the name of the action.
+func (p *Page) HandleDialog(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	accept := true
+	if value := p.getActionArgWithDefaultValues(act, "accept"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Wrap(err, "could not parse accept")
+		}
+		accept = parsed
+	}
+	text := p.getActionArgWithDefaultValues(act, "text")
+
+	if p.dialog != nil {
+		p.dialog.mu.Lock()
+		p.dialog.accept, p.dialog.text = accept, text
+		if act.Name != "" {
+			p.dialog.name = act.Name
+		}
+		p.dialog.mu.Unlock()
+		return nil
+	}
+
+	events, cancel := p.root.WithCancel()
+	p.dialog = &dialogHandler{accept: accept, text: text, name: act.Name, cancel: cancel}
+	go events.EachEvent(func(e *proto.PageJavascriptDialogOpening) {
+		p.dialog.mu.Lock()
+		p.dialog.messages = append(p.dialog.messages, e.Message)
+		handle := proto.PageHandleJavaScriptDialog{Accept: p.dialog.accept, PromptText: p.dialog.text}
+		p.dialog.mu.Unlock()
+		_ = handle.Call(events)
+	})()
+	return nil
+}
+
+// storageScript reads a key or all the keys of the local or session storage
+const storageScript = `(storage, key) => key ? window[storage].getItem(key) : JSON.stringify(Object.assign({}, window[storage]))`
+
+// setStorageScript writes a key of the local or session storage
+const setStorageScript = `(storage, key, value) => window[storage].setItem(key, value)`
+
+// indexedDBScript reads a key or all the records of an IndexedDB object store,
+// the records are returned as JSON.
+const indexedDBScript = `(database, store, key) => new Promise((resolve, reject) => {
+	const open = indexedDB.open(database);
+	open.onerror = () => reject(open.error);
+	open.onsuccess = () => {
+		const db = open.result;
+		if (!db.objectStoreNames.contains(store)) {
+			db.close();
+			reject(new Error('object store not found: ' + store));
+			return;
+		}
+		const objectStore = db.transaction(store, 'readonly').objectStore(store);
+		const request = key ? objectStore.get(key) : objectStore.getAll();
+		request.onerror = () => reject(request.error);
+		request.onsuccess = () => {
+			db.close();
+			resolve(JSON.stringify(request.result === undefined ? null : request.result));
+		};
+	};
+})`
+
+// setIndexedDBScript writes a key of an IndexedDB object store, the value
+// is stored as an object if it's valid JSON.
+const setIndexedDBScript = `(database, store, key, value) => new Promise((resolve, reject) => {
+	let data = value;
+	try {
+		data = JSON.parse(value);
+	} catch (e) {}
+	const open = indexedDB.open(database);
+	open.onerror = () => reject(open.error);
+	open.onsuccess = () => {
+		const db = open.result;
+		if (!db.objectStoreNames.contains(store)) {
+			db.close();
+			reject(new Error('object store not found: ' + store));
+			return;
+		}
+		const transaction = db.transaction(store, 'readwrite');
+		const objectStore = transaction.objectStore(store);
+		const request = objectStore.keyPath ? objectStore.put(data) : objectStore.put(data, key);
+		request.onerror = () => reject(request.error);
+		transaction.oncomplete = () => {
+			db.close();
+			resolve();
+		};
+	};
+})`
+
+// getStorageType returns the storage of the action, the local storage by default
+func (p *Page) getStorageType(act *Action) (string, error) {
+	switch value := p.getActionArgWithDefaultValues(act, "type"); value {
+	case "", "local":
+		return "localStorage", nil
+	case "session":
+		return "sessionStorage", nil
+	case "indexeddb":
+		return "indexedDB", nil
+	default:
+		return "", errors.Errorf("invalid storage type %s", value)
+	}
+}
+
+// GetStorage reads a key or all the keys of the storage of the page.
+func (p *Page) GetStorage(act *Action, out map[string]string) error {
+	if act.Name == "" {
+		return errinvalidArguments
+	}
+	storage, err := p.getStorageType(act)
+	if err != nil {
+		return err
+	}
+	key := p.getActionArgWithDefaultValues(act, "key")
+
+	var result *proto.RuntimeRemoteObject
+	if storage == "indexedDB" {
+		database := p.getActionArgWithDefaultValues(act, "database")
+		store := p.getActionArgWithDefaultValues(act, "store")
+		if database == "" || store == "" {
+			return errinvalidArguments
+		}
+		result, err = p.page.Eval(indexedDBScript, database, store, key)
+	} else {
+		result, err = p.page.Eval(storageScript, storage, key)
+	}
+	if err != nil {
+		return errors.Wrap(err, errCouldNotEvaluate)
+	}
+	if !result.Value.Nil() {
+		out[act.Name] = result.Value.Str()
+	}
+	return nil
+}
+
+// SetStorage writes a key of the storage of the page.
+func (p *Page) SetStorage(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	storage, err := p.getStorageType(act)
+	if err != nil {
+		return err
+	}
+	key := p.getActionArgWithDefaultValues(act, "key")
+	value := p.getActionArgWithDefaultValues(act, "value")
+
+	if storage == "indexedDB" {
+		database := p.getActionArgWithDefaultValues(act, "database")
+		store := p.getActionArgWithDefaultValues(act, "store")
+		if database == "" || store == "" {
+			return errinvalidArguments
+		}
+		_, err = p.page.Eval(setIndexedDBScript, database, store, key, value)
+	} else {
+		if key == "" {
+			return errinvalidArguments
+		}
+		_, err = p.page.Eval(setStorageScript, storage, key, value)
+	}
+	if err != nil {
+		return errors.Wrap(err, errCouldNotEvaluate)
+	}
+	return nil
+}
+
+// WaitIdle waits until the page doesn't send requests for the idle duration
+// in milliseconds, and fails after the timeout in seconds.
+func (p *Page) WaitIdle(act *Action, out map[string]string /*TODO review unused parameter*/) error {
+	idle, err := geTimeParameter(p, act, "idle", 500, time.Millisecond)
+	if err != nil {
+		return errors.Wrap(err, "Wrong idle time given")
+	}
+	timeout, err := geTimeParameter(p, act, "timeout", 10, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	page := p.page.Timeout(timeout)
+	defer page.CancelTimeout()
+	page.WaitRequestIdle(idle, nil, nil, nil)()
+	if err := page.GetContext().Err(); err != nil {
+		return errors.Wrap(err, "page did not become idle in the given amount of time")
+	}
+	return nil
+}
+
+// WaitResponse waits for the response of a request sent by the page with its URL
+// matching the url regex, the raw response is stored in the name of the action.
+//
+// Only the responses received after the start of the action are matched.
+func (p *Page) WaitResponse(act *Action, out map[string]string) error {
+	p.mutex.RLock()
+	start := len(p.History)
+	p.mutex.RUnlock()
+
+	value := p.getActionArgWithDefaultValues(act, "url")
+	if value == "" {
+		return errinvalidArguments
+	}
+	pattern, err := regexp.Compile(value)
+	if err != nil {
+		return errors.Wrap(err, "could not compile url regex")
+	}
+	timeout, err := geTimeParameter(p, act, "timeout", 10, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "Wrong timeout given")
+	}
+	pollTime, err := getPollTime(p, act)
+	if err != nil {
+		return errors.Wrap(err, "Wrong polling time given")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if response, ok := p.historyResponse(pattern, start); ok {
+			if act.Name != "" {
+				out[act.Name] = response
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("no response for url %s in the given amount of time", value)
+		}
+		time.Sleep(pollTime)
+	}
+}
+
+// historyResponse returns the first response of the history from the start
+// index with its URL matching the regex
+func (p *Page) historyResponse(pattern *regexp.Regexp, start int) (string, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, historyData := range p.History[start:] {
+		if pattern.MatchString(historyData.URL) {
+			return historyData.RawResponse, true
+		}
+	}
+	return "", false
+}
+
+// DumpDOM stores the HTML of the page or of an element after JS execution
+// in the name of the action.
+func (p *Page) DumpDOM(act *Action, out map[string]string) error {
+	if act.Name == "" {
+		return errinvalidArguments
+	}
+	var html string
+	var err error
+	if hasElementSelector(act.Data) {
+		element, elementErr := p.pageElementBy(act.Data)
+		if elementErr != nil {
+			return errors.Wrap(elementErr, errCouldNotGetElement)
+		}
+		html, err = element.HTML()
+	} else {
+		html, err = p.page.HTML()
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not get html")
+	}
+	out[act.Name] = html
+	return nil
+}
+
+// hasElementSelector returns true if the args select an element
+func hasElementSelector(data map[string]string) bool {
+	for _, key := range []string{"selector", "xpath", "js", "query"} {
+		if data[key] != "" {
+			return true
+		}
+	}
+	return false
+}
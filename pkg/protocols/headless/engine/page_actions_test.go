@@ -568,6 +568,239 @@ func TestActionWaitVisible(t *testing.T) {
 	})
 }
 
+func TestActionHover(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Vulmap Test Page</title>
+			</head>
+			<body>
+				<div id="menu" onmouseover='document.getElementById("item").style.display = "block"'>menu</div>
+				<a id="item" style="display: none" href="/admin">admin</a>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionHover}, Data: map[string]string{"selector": "#menu"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionClick}, Data: map[string]string{"selector": "#item"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.True(t, page.Page().MustElement("#item").MustVisible(), "could not hover element")
+	})
+}
+
+func TestActionDragDrop(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Vulmap Test Page</title>
+			</head>
+			<body>
+				<div id="source" draggable="true" ondragstart='event.dataTransfer.setData("text", "ok")'>drag me</div>
+				<div id="target" ondragover="event.preventDefault()" ondrop='this.setAttribute("a", event.dataTransfer.getData("text"))'>drop here</div>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionDragDrop}, Data: map[string]string{"selector": "#source", "target-selector": "#target"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		val := page.Page().MustElement("#target").MustAttribute("a")
+		require.Equal(t, "ok", *val, "could not drop element")
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		actions := []*Action{
+			{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+			{ActionType: ActionTypeHolder{ActionType: ActionDragDrop}, Data: map[string]string{"selector": "#source"}},
+		}
+		testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+			require.ErrorIs(t, err, errinvalidArguments)
+		})
+	})
+}
+
+func TestActionFrame(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionFrame}, Data: map[string]string{"selector": "iframe"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtract}, Name: "frame", Data: map[string]string{"selector": "h1"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionFrame}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtract}, Name: "page", Data: map[string]string{"selector": "h1"}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/frame" {
+			_, _ = fmt.Fprintln(w, `<html><body><h1>Vulmap Frame</h1></body></html>`)
+			return
+		}
+		_, _ = fmt.Fprintln(w, `<html><body><h1>Vulmap Page</h1><iframe src="/frame"></iframe></body></html>`)
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "Vulmap Frame", out["frame"], "could not switch into frame")
+		require.Equal(t, "Vulmap Page", out["page"], "could not switch back to page")
+	})
+}
+
+func TestActionDialog(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Vulmap Test Page</title>
+			</head>
+			<body>
+				<button onclick='alert("vulmap"); this.setAttribute("a", prompt("name"))'>click me</button>
+			</body>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionDialog}, Name: "dialogs", Data: map[string]string{"text": "ok"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionClick}, Data: map[string]string{"selector": "button"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "vulmap\nname", out["dialogs"], "could not get dialog messages")
+		val := page.Page().MustElement("button").MustAttribute("a")
+		require.Equal(t, "ok", *val, "could not answer prompt")
+	})
+}
+
+func TestActionStorage(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Vulmap Test Page</title>
+			</head>
+			<body>Vulmap Test Page</body>
+			<script>
+				sessionStorage.setItem('token', 'secret');
+				indexedDB.open('app', 1).onupgradeneeded = (e) => e.target.result.createObjectStore('settings');
+			</script>
+		</html>`
+
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSetStorage}, Data: map[string]string{"key": "role", "value": "admin"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionGetStorage}, Name: "local", Data: map[string]string{"key": "role"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionGetStorage}, Name: "session", Data: map[string]string{"type": "session"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSetStorage}, Data: map[string]string{"type": "indexeddb", "database": "app", "store": "settings", "key": "theme", "value": `{"dark":true}`}},
+		{ActionType: ActionTypeHolder{ActionType: ActionGetStorage}, Name: "indexeddb", Data: map[string]string{"type": "indexeddb", "database": "app", "store": "settings", "key": "theme"}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Equal(t, "admin", out["local"], "could not get local storage key")
+		require.Equal(t, `{"token":"secret"}`, out["session"], "could not get session storage")
+		require.Equal(t, `{"dark":true}`, out["indexeddb"], "could not get indexeddb key")
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		actions := []*Action{
+			{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+			{ActionType: ActionTypeHolder{ActionType: ActionGetStorage}, Name: "cookies", Data: map[string]string{"type": "cookies"}},
+		}
+		testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid storage type cookies")
+		})
+	})
+}
+
+func TestActionWaitResponse(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitResponse}, Name: "api", Data: map[string]string{"url": "/api/user$"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitIdle}},
+		{ActionType: ActionTypeHolder{ActionType: ActionDumpDOM}, Name: "dom", Data: map[string]string{"selector": "#user"}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/user" {
+			time.Sleep(200 * time.Millisecond)
+			_, _ = fmt.Fprintln(w, `{"name":"vulmap"}`)
+			return
+		}
+		_, _ = fmt.Fprintln(w, `<html><body><div id="user"></div><script>
+			setTimeout(() => fetch('/api/user').then(r => r.json()).then(user => document.getElementById('user').textContent = user.name), 100);
+		</script></body></html>`)
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Nil(t, err, "could not run page actions")
+		require.Contains(t, out["api"], `{"name":"vulmap"}`, "could not get xhr response")
+		require.Equal(t, `<div id="user">vulmap</div>`, out["dom"], "could not dump dom after js execution")
+	})
+}
+
+func TestActionWaitResponseHistory(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitResponse}, Data: map[string]string{"url": "/$", "timeout": "1"}},
+	}
+
+	testHeadlessSimpleResponse(t, "<html><body>Vulmap Test Page</body></html>", actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Error(t, err, "could match response received before the action")
+	})
+}
+
+func TestActionWaitIdleTimeout(t *testing.T) {
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitIdle}, Data: map[string]string{"idle": "1000", "timeout": "1"}},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/poll" {
+			_, _ = fmt.Fprintln(w, "ok")
+			return
+		}
+		_, _ = fmt.Fprintln(w, `<html><body><script>setInterval(() => fetch('/poll'), 200);</script></body></html>`)
+	}
+
+	testHeadless(t, actions, 20*time.Second, handler, func(page *Page, err error, out map[string]string) {
+		require.Error(t, err, "could wait for idle page sending requests")
+	})
+}
+
+func TestActionFailureScreenshot(t *testing.T) {
+	response := `
+		<html>
+			<head>
+				<title>Vulmap Test Page</title>
+			</head>
+			<body>Vulmap Test Page</body>
+		</html>`
+
+	filePath := filepath.Join(t.TempDir(), "failure.png")
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitResponse}, Data: map[string]string{"url": "/missing", "timeout": "1", "failure-screenshot": filePath}},
+	}
+
+	testHeadlessSimpleResponse(t, response, actions, 20*time.Second, func(page *Page, err error, out map[string]string) {
+		require.Error(t, err)
+		require.FileExists(t, filePath, "could not find failure screenshot file %v", filePath)
+	})
+}
+
 func testHeadlessSimpleResponse(t *testing.T, response string, actions []*Action, timeout time.Duration, assert func(page *Page, pageErr error, out map[string]string)) {
 	t.Helper()
 	testHeadless(t, actions, timeout, func(w http.ResponseWriter, r *http.Request) {
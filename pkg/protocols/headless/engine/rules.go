@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"net/http"
 	"net/http/httputil"
 	"strings"
 
@@ -45,6 +46,8 @@ func (p *Page) routingRuleHandler(ctx *rod.Hijack) {
 		}
 	}
 
+	p.recordSessionHeaders(ctx.Request.URL().String(), ctx.Request.Req().Header)
+
 	// perform the request
 	_ = ctx.LoadResponse(p.instance.browser.httpclient, true)
 
@@ -96,6 +99,7 @@ func (p *Page) routingRuleHandler(ctx *rod.Hijack) {
 
 	// dump request
 	historyData := HistoryData{
+		URL:         ctx.Request.URL().String(),
 		RawRequest:  rawReq,
 		RawResponse: rawResp.String(),
 	}
@@ -111,6 +115,12 @@ func (p *Page) routingRuleHandlerNative(e *proto.FetchRequestPaused) error {
 		return err
 	}
 	body, _ := FetchGetResponseBody(p.page, e)
+	requestHeaders := make(http.Header)
+	for name, value := range e.Request.Headers {
+		requestHeaders.Set(name, value.Str())
+	}
+	p.recordSessionHeaders(e.Request.URL, requestHeaders)
+
 	headers := make(map[string][]string)
 	for _, h := range e.ResponseHeaders {
 		headers[h.Name] = []string{h.Value}
@@ -142,6 +152,7 @@ func (p *Page) routingRuleHandlerNative(e *proto.FetchRequestPaused) error {
 
 	// dump request
 	historyData := HistoryData{
+		URL:         e.Request.URL,
 		RawRequest:  rawReq.String(),
 		RawResponse: rawResp.String(),
 	}
@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
+)
+
+// importStorageScript writes the imported storage items of the origin of the
+// document missing from the storage, the items written by the page are kept.
+const importStorageScript = `((origins) => {
+	const origin = origins.find((item) => item.origin === location.origin);
+	if (!origin) {
+		return;
+	}
+	const write = (storage, items) => {
+		for (const item of items || []) {
+			try {
+				if (window[storage].getItem(item.name) === null) {
+					window[storage].setItem(item.name, item.value);
+				}
+			} catch (e) {}
+		}
+	};
+	write('localStorage', origin.localStorage);
+	write('sessionStorage', origin.sessionStorage);
+})(%s)`
+
+// exportStorageScript reads the local and session storage of the document
+const exportStorageScript = `() => JSON.stringify({
+	origin: location.origin,
+	local: Object.assign({}, window.localStorage),
+	session: Object.assign({}, window.sessionStorage),
+})`
+
+// importSession sets the imported cookies in the browser instance and writes
+// the imported storage items in the documents of the page.
+func (p *Page) importSession(store *session.Store) error {
+	state := store.State()
+
+	var params []*proto.NetworkCookieParam
+	for _, cookie := range state.Cookies {
+		param := &proto.NetworkCookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+			SameSite: proto.NetworkCookieSameSite(cookie.SameSite),
+		}
+		if cookie.Expires > 0 {
+			param.Expires = proto.TimeSinceEpoch(cookie.Expires)
+		}
+		params = append(params, param)
+	}
+	// a nil list of cookies would clear the cookies of the instance
+	if len(params) > 0 {
+		if err := p.instance.engine.SetCookies(params); err != nil {
+			return errors.Wrap(err, "could not import session cookies")
+		}
+	}
+
+	if len(state.Origins) > 0 {
+		origins, err := json.Marshal(state.Origins)
+		if err != nil {
+			return err
+		}
+		if _, err := p.root.EvalOnNewDocument(fmt.Sprintf(importStorageScript, origins)); err != nil {
+			return errors.Wrap(err, "could not import session storage")
+		}
+	}
+	return nil
+}
+
+// recordSessionHeaders records the authentication headers of a request sent by the page
+func (p *Page) recordSessionHeaders(rawURL string, headers http.Header) {
+	if !p.options.Session.Exporting() {
+		return
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, name := range session.Headers {
+		value := headers.Get(name)
+		if value == "" {
+			continue
+		}
+		if p.sessionHeaders == nil {
+			p.sessionHeaders = make(map[string]map[string]string)
+		}
+		if p.sessionHeaders[u.Host] == nil {
+			p.sessionHeaders[u.Host] = make(map[string]string)
+		}
+		p.sessionHeaders[u.Host][name] = value
+	}
+}
+
+// exportSession exports the cookies of the browser instance, the storage of the
+// current document and the recorded authentication headers of the page.
+func (p *Page) exportSession(store *session.Store) error {
+	state := &session.State{}
+
+	cookies, err := p.instance.engine.GetCookies()
+	if err != nil {
+		return errors.Wrap(err, "could not get session cookies")
+	}
+	for _, cookie := range cookies {
+		expires := float64(cookie.Expires)
+		if cookie.Session {
+			expires = -1
+		}
+		state.Cookies = append(state.Cookies, &session.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Expires:  expires,
+			HTTPOnly: cookie.HTTPOnly,
+			Secure:   cookie.Secure,
+			SameSite: string(cookie.SameSite),
+		})
+	}
+
+	result, err := p.root.Eval(exportStorageScript)
+	if err != nil {
+		return errors.Wrap(err, "could not get session storage")
+	}
+	var storage struct {
+		Origin  string            `json:"origin"`
+		Local   map[string]string `json:"local"`
+		Session map[string]string `json:"session"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.Str()), &storage); err != nil {
+		return errors.Wrap(err, "could not parse session storage")
+	}
+	// opaque origins such as about:blank have no storage
+	if storage.Origin != "" && storage.Origin != "null" {
+		state.Origins = append(state.Origins, &session.Origin{
+			Origin:         storage.Origin,
+			LocalStorage:   storageItems(storage.Local),
+			SessionStorage: storageItems(storage.Session),
+		})
+	}
+
+	p.mutex.RLock()
+	for host, headers := range p.sessionHeaders {
+		if state.Headers == nil {
+			state.Headers = make(map[string]map[string]string)
+		}
+		state.Headers[host] = maps.Clone(headers)
+	}
+	p.mutex.RUnlock()
+
+	store.Export(state)
+	return nil
+}
+
+// storageItems returns the items of a storage sorted by name
+func storageItems(storage map[string]string) []*session.NameValue {
+	items := make([]*session.NameValue, 0, len(storage))
+	for name, value := range storage {
+		items = append(items, &session.NameValue{Name: name, Value: value})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils/testheadless"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+func TestSession(t *testing.T) {
+	_ = protocolstate.Init(&types.Options{})
+
+	browser, err := New(&types.Options{ShowBrowser: false, UseInstalledChrome: testheadless.HeadlessLocal})
+	require.Nil(t, err, "could not create browser")
+	defer browser.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/"})
+			_, _ = fmt.Fprintln(w, `<html><body><script>
+				localStorage.setItem('token', 'abc');
+				fetch('/api/me', {headers: {'Authorization': 'Bearer abc'}});
+			</script></body></html>`)
+		case "/dashboard":
+			cookie, _ := r.Cookie("session")
+			var value string
+			if cookie != nil {
+				value = cookie.Value
+			}
+			_, _ = fmt.Fprintf(w, `<html><body><div id="cookie">%s</div><div id="token"></div><script>
+				document.getElementById('token').textContent = localStorage.getItem('token');
+			</script></body></html>`, value)
+		default:
+			_, _ = fmt.Fprintln(w, `{}`)
+		}
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	exporter, err := session.New(&session.Options{ExportPath: path})
+	require.Nil(t, err, "could not create exporter")
+
+	instance, err := browser.NewInstance()
+	require.Nil(t, err, "could not create browser instance")
+	actions := []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}/login"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionSleep}, Data: map[string]string{"duration": "1"}},
+	}
+	_, page, err := instance.Run(contextargs.NewWithInput(ts.URL), actions, nil, &Options{Timeout: 20 * time.Second, Session: exporter, Options: &types.Options{}})
+	require.Nil(t, err, "could not run login actions")
+	page.Close()
+	_ = instance.Close()
+	require.Nil(t, exporter.Close(), "could not export session state")
+
+	importer, err := session.New(&session.Options{ImportPath: path})
+	require.Nil(t, err, "could not import session state")
+	parsed, _ := url.Parse(ts.URL)
+	require.Equal(t, map[string]string{"Authorization": "Bearer abc"}, importer.Headers(parsed.Host), "could not export auth headers")
+
+	instance, err = browser.NewInstance()
+	require.Nil(t, err, "could not create browser instance")
+	defer instance.Close()
+	actions = []*Action{
+		{ActionType: ActionTypeHolder{ActionType: ActionNavigate}, Data: map[string]string{"url": "{{BaseURL}}/dashboard"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionWaitLoad}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtract}, Name: "cookie", Data: map[string]string{"by": "x", "xpath": "//div[@id='cookie']"}},
+		{ActionType: ActionTypeHolder{ActionType: ActionExtract}, Name: "token", Data: map[string]string{"by": "x", "xpath": "//div[@id='token']"}},
+	}
+	out, page, err := instance.Run(contextargs.NewWithInput(ts.URL), actions, nil, &Options{Timeout: 20 * time.Second, Session: importer, Options: &types.Options{}})
+	require.Nil(t, err, "could not run dashboard actions")
+	defer page.Close()
+	require.Equal(t, "1", out["cookie"], "could not import session cookies")
+	require.Equal(t, "abc", out["token"], "could not import session storage")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless/engine"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
 	fileutil "github.com/khulnasoft-lab/utils/file"
 )
 
@@ -63,6 +64,15 @@ type Request struct {
 	//   SelfContained specifies if the request is self-contained.
 	SelfContained bool `yaml:"-" json:"-"`
 
+	// description: |
+	//   DOMXSS enables the DOM XSS detection by instrumenting the sinks of the pages
+	//   (innerHTML, eval, document.write, location, postMessage handlers, etc.).
+	//
+	//   The values containing a marker reaching the sinks are reported in the dom_xss_sink,
+	//   dom_xss_payload and dom_xss_stack parts. Combined with the fuzzing rules and payloads
+	//   including the {{dom_xss_marker}} variable, this confirms DOM XSS automatically.
+	DOMXSS *engine.DOMXSS `yaml:"dom-xss,omitempty" json:"dom-xss,omitempty" jsonschema:"title=dom xss detection,description=DOM XSS detection by instrumenting the sinks of the pages"`
+
 	// description: |
 	//   CookieReuse is an optional setting that enables cookie reuse
 	CookieReuse bool `yaml:"cookie-reuse,omitempty" json:"cookie-reuse,omitempty" jsonschema:"title=optional cookie reuse enable,description=Optional setting that enables cookie reuse"`
@@ -72,14 +82,18 @@ type Request struct {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":    "ID of the template executed",
-	"template-info":  "Info Block of the template executed",
-	"template-path":  "Path of the template executed",
-	"host":           "Host is the input to the template",
-	"matched":        "Matched is the input which was matched upon",
-	"type":           "Type is the type of request made",
-	"req":            "Headless request made from the client",
-	"resp,body,data": "Headless response received from client (default)",
+	"template-id":     "ID of the template executed",
+	"template-info":   "Info Block of the template executed",
+	"template-path":   "Path of the template executed",
+	"host":            "Host is the input to the template",
+	"matched":         "Matched is the input which was matched upon",
+	"type":            "Type is the type of request made",
+	"req":             "Headless request made from the client",
+	"resp,body,data":  "Headless response received from client (default)",
+	"dom_xss_sink":    "Sinks reached by tainted values, one per line (dom-xss)",
+	"dom_xss_payload": "Tainted values which reached the sinks, one per line (dom-xss)",
+	"dom_xss_stack":   "Stack traces of the tainted sink calls (dom-xss)",
+	"dom_xss":         "JSON list of the reports of tainted values reaching sinks (dom-xss)",
 }
 
 // Step is a headless protocol request step.
@@ -97,6 +111,11 @@ func (request *Request) GetID() string {
 func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	request.options = options
 
+	// headless requests are not recorded, so replayed scans would send them
+	if options.Replay.Replaying() {
+		return errors.Wrap(replay.ErrNotReplayable, "headless")
+	}
+
 	// TODO: logic similar to network + http => probably can be refactored
 	// Resolve payload paths from vars if they exists
 	for name, payload := range options.Options.Vars.AsMap() {
@@ -143,6 +162,12 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		request.CompiledOperators = compiled
 	}
 
+	if request.DOMXSS != nil {
+		if err := request.DOMXSS.Validate(); err != nil {
+			return errors.Wrap(err, "could not compile dom xss")
+		}
+	}
+
 	if len(request.Fuzzing) > 0 {
 		for _, rule := range request.Fuzzing {
 			if fuzzingMode := options.Options.FuzzingMode; fuzzingMode != "" {
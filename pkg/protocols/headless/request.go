@@ -1,6 +1,7 @@
 package headless
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/khulnasoft-lab/retryablehttp-go"
 
 	"github.com/pkg/errors"
+	"github.com/rs/xid"
 	"golang.org/x/exp/maps"
 
 	"github.com/khulnasoft-lab/gologger"
@@ -57,6 +59,10 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 	values := generators.MergeMaps(vars, metadata, payloads, request.options.GetTemplateCtx(input.MetaInput).GetAll())
 	variablesMap := request.options.Variables.Evaluate(values)
 	payloads = generators.MergeMaps(variablesMap, payloads, request.options.Constants)
+	if request.DOMXSS != nil {
+		// random marker identifying the payloads of this execution in the sinks
+		payloads[engine.DOMXSSMarkerVariable] = "vulmap" + xid.New().String()
+	}
 
 	// check for operator matches by wrapping callback
 	gotmatches := false
@@ -132,6 +138,8 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 	options := &engine.Options{
 		Timeout:     time.Duration(request.options.Options.PageTimeout) * time.Second,
 		CookieReuse: request.CookieReuse,
+		DOMXSS:      request.DOMXSS,
+		Session:     request.options.Session,
 		Options:     request.options.Options,
 	}
 
@@ -187,6 +195,9 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 	for k, v := range out {
 		outputEvent[k] = v
 	}
+	if request.DOMXSS != nil {
+		addDOMXSSReports(outputEvent, page.DOMXSSReports())
+	}
 	for k, v := range payloads {
 		outputEvent[k] = v
 	}
@@ -261,6 +272,24 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, payloads
 	return nil
 }
 
+// addDOMXSSReports adds the tainted values which reached sinks to the output event
+func addDOMXSSReports(outputEvent output.InternalEvent, reports []engine.DOMXSSReport) {
+	var sinks, payloads, stacks []string
+	for _, report := range reports {
+		sinks = append(sinks, report.Sink)
+		payloads = append(payloads, report.Payload)
+		stacks = append(stacks, report.Sink+"\n"+report.Stack)
+	}
+	outputEvent["dom_xss_sink"] = strings.Join(sinks, "\n")
+	outputEvent["dom_xss_payload"] = strings.Join(payloads, "\n")
+	outputEvent["dom_xss_stack"] = strings.Join(stacks, "\n\n")
+	if reports == nil {
+		reports = []engine.DOMXSSReport{}
+	}
+	data, _ := json.Marshal(reports)
+	outputEvent["dom_xss"] = string(data)
+}
+
 // getLastNavigationURL returns last successfully navigated URL
 func (request *Request) getLastNavigationURLWithLog(reqLog map[string]string) string {
 	for i := len(request.Steps) - 1; i >= 0; i-- {
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/retryablehttp-go"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators/matchers"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+)
+
+// needsDiffBaseline returns true if diff matchers of the request compare responses with a captured baseline
+func (request *Request) needsDiffBaseline() bool {
+	if request.CompiledOperators == nil {
+		return false
+	}
+	for _, matcher := range request.CompiledOperators.Matchers {
+		if matcher.GetType() == matchers.DiffMatcher && matcher.Diff.Baseline == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// diffBaseline sends the unmodified request and returns its response as baseline of diff
+// matchers, a GET request to the input is sent if no request is provided.
+func (request *Request) diffBaseline(input *contextargs.Context, baseRequest *retryablehttp.Request) output.InternalEvent {
+	ctx := request.newContext(input)
+	var req *retryablehttp.Request
+	if baseRequest == nil {
+		var err error
+		if req, err = retryablehttp.NewRequestWithContext(ctx, http.MethodGet, input.MetaInput.Input, nil); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not create baseline request for %s: %s", request.options.TemplateID, input.MetaInput.Input, err)
+			return nil
+		}
+	} else {
+		var err error
+		if req, err = cloneRequest(ctx, baseRequest); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not read baseline request body for %s: %s", request.options.TemplateID, input.MetaInput.Input, err)
+			return nil
+		}
+	}
+	readSize := int64(request.MaxSize)
+	if readSize == 0 {
+		readSize = int64(request.options.Options.ResponseReadSize)
+	}
+	responses, _, err := request.sendInputRequest(input, req, readSize)
+	if err != nil {
+		gologger.Verbose().Msgf("[%s] Could not send baseline request to %s: %s", request.options.TemplateID, input.MetaInput.Input, err)
+		return nil
+	}
+	response := responses[0]
+	return output.InternalEvent{
+		"baseline_status_code":    response.resp.StatusCode,
+		"baseline_body":           string(response.body),
+		"baseline_all_headers":    string(response.headers),
+		"baseline_header":         string(response.headers),
+		"baseline_response":       string(response.fullResponse),
+		"baseline_content_length": utils.CalculateContentLength(response.resp.ContentLength, int64(len(response.body))),
+	}
+}
@@ -47,6 +47,10 @@ type generatedRequest struct {
 	dynamicValues        map[string]interface{}
 	interactshURLs       []string
 	customCancelFunction context.CancelFunc
+	// raceResponse is the response received by a synchronised race condition attack
+	raceResponse *raceResponse
+	// connectionResponse is the response received on the connection of a desync probe or same-connection sequence
+	connectionResponse *connectionResponse
 }
 
 func (g *generatedRequest) URL() string {
@@ -267,18 +271,20 @@ func (r *requestGenerator) generateRawRequest(ctx context.Context, rawRequest st
 
 	var rawRequestData *raw.Request
 	var err error
+	// desync probes send the unsafe raw requests themselves
+	unsafe := r.request.Unsafe || r.request.Desync
 	if r.request.SelfContained {
 		// in self contained requests baseURL is extracted from raw request itself
-		rawRequestData, err = raw.ParseRawRequest(rawRequest, r.request.Unsafe)
+		rawRequestData, err = raw.ParseRawRequest(rawRequest, unsafe)
 	} else {
-		rawRequestData, err = raw.Parse(rawRequest, baseURL, r.request.Unsafe, r.request.DisablePathAutomerge)
+		rawRequestData, err = raw.Parse(rawRequest, baseURL, unsafe, r.request.DisablePathAutomerge)
 	}
 	if err != nil {
 		return nil, errorutil.NewWithErr(err).Msgf("failed to parse raw request")
 	}
 
 	// Unsafe option uses rawhttp library
-	if r.request.Unsafe {
+	if unsafe {
 		if len(r.options.Options.CustomHeaders) > 0 {
 			_ = rawRequestData.TryFillCustomHeaders(r.options.Options.CustomHeaders)
 		}
@@ -356,8 +362,8 @@ func (r *requestGenerator) fillRequest(req *retryablehttp.Request, values map[st
 		}
 	}
 
-	// In case of multiple threads the underlying connection should remain open to allow reuse
-	if r.request.Threads <= 0 && req.Header.Get("Connection") == "" && r.options.Options.ScanStrategy != scanstrategy.HostSpray.String() {
+	// In case of multiple threads or same-connection sequences the underlying connection should remain open to allow reuse
+	if r.request.Threads <= 0 && !r.request.SameConnection && req.Header.Get("Connection") == "" && r.options.Options.ScanStrategy != scanstrategy.HostSpray.String() {
 		req.Close = true
 	}
 
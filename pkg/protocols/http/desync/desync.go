@@ -0,0 +1,251 @@
+// Package desync implements probes for HTTP request smuggling and desync
+// detection sending raw requests on explicitly managed connections.
+package desync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Dialer dials the connections of desync probes
+type Dialer interface {
+	Dial(ctx context.Context, network, address string) (net.Conn, error)
+	DialTLSWithConfig(ctx context.Context, network, address string, config *tls.Config) (net.Conn, error)
+}
+
+// Request is a request of a desync probe
+type Request struct {
+	// Method is the method of the request used to read its response
+	Method string
+	// URL is the url of the request, all requests of a probe are sent to the host of the first one
+	URL *url.URL
+	// Raw contains the bytes of the request which are sent without any normalisation
+	Raw []byte
+}
+
+// Response is the response to a request of a desync probe
+type Response struct {
+	Response *http.Response
+	// Timing is the time from sending the request until the headers
+	// of the response were received or the timeout was reached
+	Timing time.Duration
+	// Connection is the index of the connection the request was sent on
+	Connection int
+	// Reused is true if the connection was used by a previous request
+	Reused bool
+	// TimedOut is true if no response was received before the timeout
+	TimedOut bool
+	Err      error
+}
+
+// Result is the result of a desync probe
+type Result struct {
+	// Responses contains the responses in the order the requests were sent
+	Responses []*Response
+	// Pending contains bytes which were received on the connection
+	// after the response to the last request, ex. a smuggled response
+	Pending []byte
+}
+
+// Protocols of the connections of desync probes
+const (
+	// HTTP1 sends the requests on HTTP/1.1 connections
+	HTTP1 = "http/1.1"
+	// HTTP2 sends the requests on HTTP/2 connections to detect desyncs of
+	// front-ends downgrading the requests to HTTP/1.1, ex. H2.CL and H2.TE.
+	HTTP2 = "h2"
+)
+
+// Prober sends sequences of requests on explicitly managed connections
+type Prober struct {
+	Dialer Dialer
+	// Timeout is the time to wait for each response, no timeout if zero
+	Timeout time.Duration
+	// SNI is the server name of tls connections
+	SNI string
+	// Protocol is the protocol of the connections, HTTP1 if empty
+	Protocol string
+	// Prepare is called before the i-th request is sent with the response to
+	// the previous request, nil for the first request, so requests can depend
+	// on the previous responses of the probe.
+	Prepare func(i int, req *Request, previous *Response) error
+}
+
+// probeConn is a connection requests of a probe are sent on
+type probeConn interface {
+	// roundTrip sends the request and reads its response returning the time
+	// until the headers of the response were received
+	roundTrip(req Request) (*http.Response, time.Duration, error)
+	// pending returns the data received after the last response
+	pending() []byte
+	Close() error
+}
+
+// Probe sends the requests in order on a single connection waiting for the
+// response to each request before sending the next one, so responses which
+// are affected by a previous request on the connection can be detected.
+//
+// A new connection is only opened when the server closed the connection or
+// didn't respond in time, as the state of the connection is unknown afterwards.
+func (p *Prober) Probe(ctx context.Context, requests []Request) *Result {
+	result := &Result{Responses: make([]*Response, len(requests))}
+	if len(requests) == 0 {
+		return result
+	}
+	target := requests[0].URL
+
+	var conn probeConn
+	closeConn := func() {
+		if conn != nil {
+			conn.Close()
+			conn = nil
+		}
+	}
+	defer closeConn()
+
+	connection := -1
+	for i, req := range requests {
+		response := &Response{Reused: conn != nil}
+		result.Responses[i] = response
+		if conn == nil {
+			newConn, err := p.connect(ctx, target)
+			if err != nil {
+				response.Err = err
+				continue
+			}
+			conn = newConn
+			connection++
+		}
+		response.Connection = connection
+
+		if p.Prepare != nil {
+			var previous *Response
+			if i > 0 {
+				previous = result.Responses[i-1]
+			}
+			if err := p.Prepare(i, &req, previous); err != nil {
+				response.Err = err
+				continue
+			}
+		}
+		resp, timing, err := conn.roundTrip(req)
+		response.Timing = timing
+		if err != nil {
+			var netErr net.Error
+			var resetErr *streamResetError
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				response.TimedOut = true
+			} else {
+				response.Err = err
+			}
+			if !errors.As(err, &resetErr) {
+				closeConn()
+			}
+			continue
+		}
+		response.Response = resp
+		if resp.Close {
+			closeConn()
+		}
+	}
+
+	if conn != nil {
+		result.Pending = conn.pending()
+	}
+	return result
+}
+
+// connect opens a connection of the protocol of the prober to the host of the url
+func (p *Prober) connect(ctx context.Context, target *url.URL) (probeConn, error) {
+	switch p.Protocol {
+	case "", HTTP1:
+		conn, err := p.dial(ctx, target, HTTP1)
+		if err != nil {
+			return nil, err
+		}
+		return &h1Conn{Conn: conn, reader: bufio.NewReader(conn), timeout: p.Timeout}, nil
+	case HTTP2:
+		return p.connectH2(ctx, target)
+	}
+	return nil, fmt.Errorf("unsupported desync protocol %s", p.Protocol)
+}
+
+// dial dials a connection to the host of the url negotiating the protocol for tls connections
+func (p *Prober) dial(ctx context.Context, target *url.URL, protocol string) (net.Conn, error) {
+	host, port := target.Hostname(), target.Port()
+	if target.Scheme != "https" {
+		if port == "" {
+			port = "80"
+		}
+		return p.Dialer.Dial(ctx, "tcp", net.JoinHostPort(host, port))
+	}
+	if port == "" {
+		port = "443"
+	}
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		ServerName:         p.SNI,
+		NextProtos:         []string{protocol},
+	}
+	if config.ServerName == "" && net.ParseIP(host) == nil {
+		config.ServerName = host
+	}
+	return p.Dialer.DialTLSWithConfig(ctx, "tcp", net.JoinHostPort(host, port), config)
+}
+
+// setDeadline sets the deadline of the connection for the next response,
+// connections have no deadline without timeout.
+func setDeadline(conn net.Conn, timeout time.Duration) {
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+	}
+}
+
+// h1Conn is a HTTP/1.1 connection
+type h1Conn struct {
+	net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+}
+
+// roundTrip writes the raw request on the connection and reads its response,
+// the response is marked as closing the connection if its body couldn't be read.
+func (c *h1Conn) roundTrip(req Request) (*http.Response, time.Duration, error) {
+	setDeadline(c.Conn, c.timeout)
+	start := time.Now()
+	if _, err := c.Write(req.Raw); err != nil {
+		return nil, 0, err
+	}
+	resp, err := http.ReadResponse(c.reader, &http.Request{Method: req.Method, URL: req.URL})
+	timing := time.Since(start)
+	if err != nil {
+		return nil, timing, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil && len(body) == 0 {
+		return nil, timing, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.Close = resp.Close || err != nil
+	return resp, timing, nil
+}
+
+// pending returns the data buffered after the last response
+func (c *h1Conn) pending() []byte {
+	if c.reader.Buffered() == 0 {
+		return nil
+	}
+	data, _ := c.reader.Peek(c.reader.Buffered())
+	return data
+}
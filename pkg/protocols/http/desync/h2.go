@@ -0,0 +1,292 @@
+package desync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+const (
+	// maxFrameSize is the default maximum size of http2 frames
+	maxFrameSize = 16384
+	// maxBodySize is the maximum size of request bodies which is
+	// limited by the default flow-control window of the server
+	maxBodySize = 65535
+	// windowSize is the flow-control window announced to the server
+	windowSize = 1 << 30
+)
+
+// ErrHTTP2Unsupported is returned when the server doesn't support HTTP/2
+var ErrHTTP2Unsupported = errors.New("server doesn't support http2")
+
+// streamResetError is returned when the server reset the stream of a request,
+// the connection can still be used by the next requests.
+type streamResetError struct {
+	code http2.ErrCode
+}
+
+func (e *streamResetError) Error() string {
+	return fmt.Sprintf("stream reset by server: %s", e.code)
+}
+
+// h2Conn is a HTTP/2 connection sending a single stream at a time
+type h2Conn struct {
+	conn    net.Conn
+	timeout time.Duration
+	buf     bytes.Buffer
+	framer  *http2.Framer
+	encoder *hpack.Encoder
+	block   bytes.Buffer
+	// streamID is the id of the next stream
+	streamID uint32
+	// closed is true once the server sent a GOAWAY frame
+	closed bool
+}
+
+// connectH2 opens a HTTP/2 connection to the host of the url and exchanges the connection settings
+func (p *Prober) connectH2(ctx context.Context, target *url.URL) (probeConn, error) {
+	if target.Scheme != "https" {
+		return nil, fmt.Errorf("%w: http2 probes require https", ErrHTTP2Unsupported)
+	}
+	conn, err := p.dial(ctx, target, http2.NextProtoTLS)
+	if err != nil {
+		return nil, err
+	}
+	if state, ok := conn.(interface{ ConnectionState() tls.ConnectionState }); !ok || state.ConnectionState().NegotiatedProtocol != http2.NextProtoTLS {
+		conn.Close()
+		return nil, ErrHTTP2Unsupported
+	}
+
+	c := &h2Conn{conn: conn, timeout: p.Timeout, streamID: 1}
+	c.framer = http2.NewFramer(&c.buf, conn)
+	c.framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	c.encoder = hpack.NewEncoder(&c.block)
+	if err := c.handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// handshake sends the client preface and settings and acknowledges the settings of the server
+func (c *h2Conn) handshake() error {
+	setDeadline(c.conn, c.timeout)
+	c.buf.WriteString(http2.ClientPreface)
+	if err := c.framer.WriteSettings(http2.Setting{ID: http2.SettingEnablePush, Val: 0}, http2.Setting{ID: http2.SettingInitialWindowSize, Val: windowSize}); err != nil {
+		return err
+	}
+	if err := c.framer.WriteWindowUpdate(0, windowSize); err != nil {
+		return err
+	}
+	if err := c.flush(); err != nil {
+		return err
+	}
+	for {
+		frame, err := c.framer.ReadFrame()
+		if err != nil {
+			return err
+		}
+		if settings, ok := frame.(*http2.SettingsFrame); ok && !settings.IsAck() {
+			if err := c.framer.WriteSettingsAck(); err != nil {
+				return err
+			}
+			return c.flush()
+		}
+	}
+}
+
+// flush writes the buffered frames to the connection
+func (c *h2Conn) flush() error {
+	_, err := c.conn.Write(c.buf.Bytes())
+	c.buf.Reset()
+	return err
+}
+
+// roundTrip sends the raw request as a stream and reads its response.
+//
+// The headers of the request are sent as they are, including content-length
+// and transfer-encoding, and the body is sent regardless of its declared length.
+func (c *h2Conn) roundTrip(req Request) (*http.Response, time.Duration, error) {
+	method, target, header, body, err := parseRaw(req.Raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(body) > maxBodySize {
+		return nil, 0, fmt.Errorf("request body exceeds %d bytes", maxBodySize)
+	}
+	if parsed, err := url.Parse(target); err == nil && parsed.IsAbs() {
+		target = parsed.RequestURI()
+	}
+	authority := req.URL.Host
+	fields := []hpack.HeaderField{
+		{Name: ":method", Value: method},
+		{Name: ":scheme", Value: "https"},
+		{Name: ":authority", Value: authority},
+		{Name: ":path", Value: target},
+	}
+	for _, field := range header {
+		if field.Name == "host" {
+			fields[2].Value = field.Value
+			continue
+		}
+		fields = append(fields, field)
+	}
+	c.block.Reset()
+	for _, field := range fields {
+		if err := c.encoder.WriteField(field); err != nil {
+			return nil, 0, err
+		}
+	}
+	if c.block.Len() > maxFrameSize {
+		return nil, 0, fmt.Errorf("request headers exceed %d bytes", maxFrameSize)
+	}
+
+	streamID := c.streamID
+	c.streamID += 2
+	setDeadline(c.conn, c.timeout)
+	if err := c.framer.WriteHeaders(http2.HeadersFrameParam{StreamID: streamID, BlockFragment: c.block.Bytes(), EndHeaders: true, EndStream: len(body) == 0}); err != nil {
+		return nil, 0, err
+	}
+	for offset := 0; offset < len(body); offset += maxFrameSize {
+		end := min(offset+maxFrameSize, len(body))
+		if err := c.framer.WriteData(streamID, end == len(body), body[offset:end]); err != nil {
+			return nil, 0, err
+		}
+	}
+	start := time.Now()
+	if err := c.flush(); err != nil {
+		return nil, 0, err
+	}
+
+	var timing time.Duration
+	var status int
+	responseHeader := make(http.Header)
+	var data bytes.Buffer
+	for {
+		frame, err := c.framer.ReadFrame()
+		if err != nil {
+			if timing == 0 {
+				timing = time.Since(start)
+			}
+			return nil, timing, err
+		}
+		switch frame := frame.(type) {
+		case *http2.MetaHeadersFrame:
+			if frame.StreamID != streamID {
+				continue
+			}
+			code, _ := strconv.Atoi(frame.PseudoValue("status"))
+			if code >= 100 && code < 200 {
+				continue
+			}
+			if status == 0 {
+				status = code
+				timing = time.Since(start)
+			}
+			for _, field := range frame.RegularFields() {
+				responseHeader.Add(field.Name, field.Value)
+			}
+		case *http2.DataFrame:
+			if frame.StreamID != streamID {
+				continue
+			}
+			data.Write(frame.Data())
+		case *http2.RSTStreamFrame:
+			if frame.StreamID == streamID {
+				return nil, time.Since(start), &streamResetError{code: frame.ErrCode}
+			}
+			continue
+		case *http2.GoAwayFrame:
+			c.closed = true
+			if frame.LastStreamID < streamID {
+				return nil, time.Since(start), fmt.Errorf("connection closed by server: %s", frame.ErrCode)
+			}
+			continue
+		case *http2.SettingsFrame:
+			if !frame.IsAck() {
+				if err := c.framer.WriteSettingsAck(); err == nil {
+					_ = c.flush()
+				}
+			}
+			continue
+		case *http2.PingFrame:
+			if !frame.IsAck() {
+				if err := c.framer.WritePing(true, frame.Data); err == nil {
+					_ = c.flush()
+				}
+			}
+			continue
+		default:
+			continue
+		}
+		if frame.Header().Flags.Has(http2.FlagDataEndStream) {
+			break
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/2.0",
+		ProtoMajor:    2,
+		Header:        responseHeader,
+		Body:          io.NopCloser(bytes.NewReader(data.Bytes())),
+		ContentLength: int64(data.Len()),
+		Close:         c.closed,
+		Request:       &http.Request{Method: method, URL: req.URL},
+	}, timing, nil
+}
+
+// pending returns no data as responses of http2 connections are framed
+func (c *h2Conn) pending() []byte {
+	return nil
+}
+
+// Close closes the connection
+func (c *h2Conn) Close() error {
+	return c.conn.Close()
+}
+
+// parseRaw parses the method, target, header fields and body of a raw HTTP/1.1 request,
+// the header names are lowercased as required by http2.
+func parseRaw(raw []byte) (string, string, []hpack.HeaderField, []byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(raw))
+	requestLine, err := reader.ReadString('\n')
+	if err != nil {
+		return "", "", nil, nil, errors.New("could not read request line")
+	}
+	parts := strings.Fields(requestLine)
+	if len(parts) < 2 {
+		return "", "", nil, nil, fmt.Errorf("invalid request line %q", strings.TrimSpace(requestLine))
+	}
+
+	var fields []hpack.HeaderField
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			fields = append(fields, hpack.HeaderField{Name: strings.ToLower(strings.TrimSpace(name)), Value: strings.TrimSpace(value)})
+		}
+		if err != nil {
+			break
+		}
+	}
+	body, _ := io.ReadAll(reader)
+	return parts[0], parts[1], fields, body, nil
+}
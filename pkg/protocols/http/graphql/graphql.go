@@ -0,0 +1,180 @@
+// Package graphql implements GraphQL schema introspection, security
+// probes and operation generation for the http protocol.
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IntrospectionQuery is the query used to retrieve the schema of a GraphQL endpoint
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    types {
+      kind
+      name
+      fields(includeDeprecated: true) {
+        name
+        args { name type { ...TypeRef } defaultValue }
+        type { ...TypeRef }
+      }
+      inputFields { name type { ...TypeRef } defaultValue }
+      enumValues(includeDeprecated: true) { name }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+        }
+      }
+    }
+  }
+}`
+
+// Schema is a GraphQL schema built from an introspection response
+type Schema struct {
+	QueryType    *NamedType  `json:"queryType"`
+	MutationType *NamedType  `json:"mutationType"`
+	Types        []*FullType `json:"types"`
+
+	types map[string]*FullType
+}
+
+// NamedType is a reference to a type by its name
+type NamedType struct {
+	Name string `json:"name"`
+}
+
+// FullType is a type declared in the schema
+type FullType struct {
+	Kind        string        `json:"kind"`
+	Name        string        `json:"name"`
+	Fields      []*Field      `json:"fields"`
+	InputFields []*InputValue `json:"inputFields"`
+	EnumValues  []*NamedType  `json:"enumValues"`
+}
+
+// Field is a field of an object or interface type
+type Field struct {
+	Name string        `json:"name"`
+	Args []*InputValue `json:"args"`
+	Type *TypeRef      `json:"type"`
+}
+
+// InputValue is an argument or a field of an input object type
+type InputValue struct {
+	Name         string   `json:"name"`
+	Type         *TypeRef `json:"type"`
+	DefaultValue *string  `json:"defaultValue"`
+}
+
+// TypeRef is a possibly wrapped (list, non-null) reference to a type
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// Named returns the innermost named type of the reference
+func (t *TypeRef) Named() *TypeRef {
+	current := t
+	for current != nil && current.OfType != nil && current.Name == "" {
+		current = current.OfType
+	}
+	return current
+}
+
+// IsNonNull returns true if the reference is a non-null type
+func (t *TypeRef) IsNonNull() bool {
+	return t != nil && t.Kind == "NON_NULL"
+}
+
+// IsList returns true if the reference is a list, ignoring non-null wrapping
+func (t *TypeRef) IsList() bool {
+	if t.IsNonNull() {
+		return t.OfType.IsList()
+	}
+	return t != nil && t.Kind == "LIST"
+}
+
+// String returns the reference in GraphQL SDL notation (e.g. `[ID!]!`)
+func (t *TypeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.String() + "!"
+	case "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// introspectionResponse is the response returned by a GraphQL server
+type introspectionResponse struct {
+	Data struct {
+		Schema *Schema `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// ErrIntrospectionDisabled is returned when the endpoint refuses introspection queries
+var ErrIntrospectionDisabled = errors.New("graphql introspection is disabled")
+
+// ParseIntrospection parses an introspection response body into a schema
+func ParseIntrospection(body []byte) (*Schema, error) {
+	response := &introspectionResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, errors.Wrap(err, "could not decode introspection response")
+	}
+	if response.Data.Schema == nil {
+		if len(response.Errors) > 0 {
+			return nil, errors.Wrap(ErrIntrospectionDisabled, response.Errors[0].Message)
+		}
+		return nil, ErrIntrospectionDisabled
+	}
+	schema := response.Data.Schema
+	schema.types = make(map[string]*FullType, len(schema.Types))
+	for _, t := range schema.Types {
+		schema.types[t.Name] = t
+	}
+	return schema, nil
+}
+
+// Type returns a type of the schema by its name
+func (s *Schema) Type(name string) *FullType {
+	return s.types[name]
+}
+
+// TypeNames returns the names of all non-builtin types in the schema
+func (s *Schema) TypeNames() []string {
+	var names []string
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		names = append(names, t.Name)
+	}
+	return names
+}
@@ -0,0 +1,53 @@
+package graphql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaOperations(t *testing.T) {
+	data, err := os.ReadFile("testdata/introspection.json")
+	require.Nil(t, err, "could not read introspection response")
+
+	schema, err := ParseIntrospection(data)
+	require.Nil(t, err, "could not parse introspection response")
+	require.NotContains(t, schema.TypeNames(), "__Schema", "could get builtin types")
+	require.Equal(t, []string{"createUser", "user", "version"}, schema.FieldNames(), "could not get field names")
+
+	operations := schema.Operations()
+	require.Len(t, operations, 3, "could not get operations")
+
+	require.Equal(t, "query", operations[0].Type)
+	require.Equal(t, "query user($id: ID!) { user(id: $id) { id name role friends { id name role friends { id name role } } } }", operations[0].Query)
+	require.Equal(t, map[string]interface{}{"id": "1"}, operations[0].Variables)
+
+	require.Equal(t, "query version { version }", operations[1].Query)
+	require.Empty(t, operations[1].Variables)
+
+	require.Equal(t, "mutation", operations[2].Type)
+	require.Equal(t, "mutation createUser($input: UserInput!) { createUser(input: $input) { id name role friends { id name role friends { id name role } } } }", operations[2].Query)
+	require.Equal(t, map[string]interface{}{"input": map[string]interface{}{"name": "test", "age": 1, "role": "ADMIN"}}, operations[2].Variables)
+}
+
+func TestParseIntrospectionDisabled(t *testing.T) {
+	_, err := ParseIntrospection([]byte(`{"errors":[{"message":"GraphQL introspection is not allowed"}]}`))
+	require.ErrorIs(t, err, ErrIntrospectionDisabled, "could not detect disabled introspection")
+}
+
+func TestDepthLimitProbe(t *testing.T) {
+	var probe *Probe
+	for _, p := range Probes {
+		if p.Name == VariableDepthLimit {
+			probe = p
+		}
+	}
+	require.NotNil(t, probe, "could not get depth limit probe")
+
+	require.True(t, probe.Check([]byte(`{"errors":[{"message":"Query exceeds maximum depth of 5"}]}`)), "could not detect depth limit")
+	require.True(t, probe.Check([]byte(`{"data":null,"errors":[{"message":"Query is too complex: complexity 120 exceeds 100"}]}`)), "could not detect complexity limit")
+	require.False(t, probe.Check([]byte(`<html><title>502 Bad Gateway</title></html>`)), "could detect depth limit in non-json body")
+	require.False(t, probe.Check([]byte(`{"data":null}`)), "could detect depth limit without error")
+	require.False(t, probe.Check([]byte(`{"data":null,"errors":[{"message":"Not authorized"}]}`)), "could detect depth limit with unrelated error")
+}
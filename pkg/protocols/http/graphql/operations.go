@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSelectionDepth is the maximum depth of generated selection sets
+const maxSelectionDepth = 3
+
+// Operation is a generated query or mutation for a single schema field
+type Operation struct {
+	// Type is the type of the operation (query or mutation)
+	Type string
+	// Field is the name of the root field of the operation
+	Field string
+	// Query is the generated GraphQL document
+	Query string
+	// Variables contains placeholder values for the field arguments
+	Variables map[string]interface{}
+}
+
+// Operations generates a query or mutation for every root field of the schema
+// with a variable placeholder for each of its arguments.
+func (s *Schema) Operations() []*Operation {
+	var operations []*Operation
+	if s.QueryType != nil {
+		operations = append(operations, s.operationsFor("query", s.QueryType.Name)...)
+	}
+	if s.MutationType != nil {
+		operations = append(operations, s.operationsFor("mutation", s.MutationType.Name)...)
+	}
+	return operations
+}
+
+func (s *Schema) operationsFor(operationType, typeName string) []*Operation {
+	root := s.Type(typeName)
+	if root == nil {
+		return nil
+	}
+	operations := make([]*Operation, 0, len(root.Fields))
+	for _, field := range root.Fields {
+		operations = append(operations, s.buildOperation(operationType, field))
+	}
+	return operations
+}
+
+func (s *Schema) buildOperation(operationType string, field *Field) *Operation {
+	operation := &Operation{Type: operationType, Field: field.Name, Variables: make(map[string]interface{})}
+
+	var definitions, arguments []string
+	for _, arg := range field.Args {
+		definitions = append(definitions, "$"+arg.Name+": "+arg.Type.String())
+		arguments = append(arguments, arg.Name+": $"+arg.Name)
+		operation.Variables[arg.Name] = s.placeholder(arg.Type, 0)
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(operationType)
+	builder.WriteString(" ")
+	builder.WriteString(field.Name)
+	if len(definitions) > 0 {
+		builder.WriteString("(" + strings.Join(definitions, ", ") + ")")
+	}
+	builder.WriteString(" { ")
+	builder.WriteString(field.Name)
+	if len(arguments) > 0 {
+		builder.WriteString("(" + strings.Join(arguments, ", ") + ")")
+	}
+	builder.WriteString(s.selectionSet(field.Type, 0))
+	builder.WriteString(" }")
+	operation.Query = builder.String()
+	return operation
+}
+
+// selectionSet returns the selection set for a type, selecting scalar
+// fields and recursing into object fields up to maxSelectionDepth.
+func (s *Schema) selectionSet(ref *TypeRef, depth int) string {
+	named := ref.Named()
+	if named == nil {
+		return ""
+	}
+	t := s.Type(named.Name)
+	if t == nil || (t.Kind != "OBJECT" && t.Kind != "INTERFACE" && t.Kind != "UNION") {
+		return ""
+	}
+	var selections []string
+	for _, field := range t.Fields {
+		if hasRequiredArgs(field) {
+			continue
+		}
+		fieldType := field.Type.Named()
+		if fieldType == nil {
+			continue
+		}
+		switch fieldType.Kind {
+		case "SCALAR", "ENUM":
+			selections = append(selections, field.Name)
+		case "OBJECT", "INTERFACE":
+			if depth+1 >= maxSelectionDepth {
+				continue
+			}
+			if nested := s.selectionSet(field.Type, depth+1); nested != "" {
+				selections = append(selections, field.Name+nested)
+			}
+		}
+	}
+	if len(selections) == 0 {
+		selections = append(selections, "__typename")
+	}
+	return " { " + strings.Join(selections, " ") + " }"
+}
+
+// placeholder returns a default value for an argument based on its type
+func (s *Schema) placeholder(ref *TypeRef, depth int) interface{} {
+	if ref == nil {
+		return nil
+	}
+	if ref.IsNonNull() {
+		return s.placeholder(ref.OfType, depth)
+	}
+	if ref.Kind == "LIST" {
+		return []interface{}{s.placeholder(ref.OfType, depth)}
+	}
+	switch ref.Name {
+	case "Int":
+		return 1
+	case "Float":
+		return 1.0
+	case "Boolean":
+		return true
+	case "ID":
+		return "1"
+	case "String":
+		return "test"
+	}
+	t := s.Type(ref.Name)
+	if t == nil {
+		return "test"
+	}
+	switch t.Kind {
+	case "ENUM":
+		if len(t.EnumValues) > 0 {
+			return t.EnumValues[0].Name
+		}
+	case "INPUT_OBJECT":
+		values := make(map[string]interface{})
+		if depth >= maxSelectionDepth {
+			return values
+		}
+		for _, field := range t.InputFields {
+			values[field.Name] = s.placeholder(field.Type, depth+1)
+		}
+		return values
+	}
+	return "test"
+}
+
+func hasRequiredArgs(field *Field) bool {
+	for _, arg := range field.Args {
+		if arg.Type.IsNonNull() && arg.DefaultValue == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldNames returns the sorted names of all root query and mutation fields
+func (s *Schema) FieldNames() []string {
+	var names []string
+	for _, operation := range s.Operations() {
+		names = append(names, operation.Field)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Probe is a request sent to a GraphQL endpoint to detect a misconfiguration
+type Probe struct {
+	// Name is the name of the variable the result of the probe is stored as
+	Name string
+	// Body is the request body sent to the endpoint
+	Body []byte
+	// Check returns true if the response body indicates the probe succeeded
+	Check func(body []byte) bool
+}
+
+const (
+	// VariableIntrospection is true if introspection is enabled on the endpoint
+	VariableIntrospection = "graphql_introspection"
+	// VariableFieldSuggestions is true if the endpoint leaks field suggestions
+	VariableFieldSuggestions = "graphql_field_suggestions"
+	// VariableBatching is true if the endpoint accepts batched queries
+	VariableBatching = "graphql_batching"
+	// VariableDepthLimit is true if the endpoint rejects deeply nested queries
+	// with an error about their depth or complexity
+	VariableDepthLimit = "graphql_depth_limit"
+)
+
+// deepQuery is a deeply nested query built from introspection types, which
+// are available on every endpoint regardless of its schema.
+const deepQuery = `query { __schema { types { fields { type { fields { type { fields { type { fields { type { name } } } } } } } } } } }`
+
+// Probes are the probes sent to every GraphQL endpoint
+var Probes = []*Probe{
+	{
+		Name:  VariableFieldSuggestions,
+		Body:  NewBody(`query { __typenam }`, nil),
+		Check: func(body []byte) bool { return bytes.Contains(body, []byte("Did you mean")) },
+	},
+	{
+		Name: VariableBatching,
+		Body: []byte(`[` + string(NewBody(`query { __typename }`, nil)) + `,` + string(NewBody(`query { __typename }`, nil)) + `]`),
+		Check: func(body []byte) bool {
+			var responses []json.RawMessage
+			if err := json.Unmarshal(body, &responses); err != nil {
+				return false
+			}
+			return len(responses) == 2
+		},
+	},
+	{
+		Name: VariableDepthLimit,
+		Body: NewBody(deepQuery, nil),
+		Check: func(body []byte) bool {
+			response := &struct {
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}{}
+			if err := json.Unmarshal(body, response); err != nil {
+				return false
+			}
+			for _, e := range response.Errors {
+				message := strings.ToLower(e.Message)
+				if strings.Contains(message, "depth") || strings.Contains(message, "complexity") {
+					return true
+				}
+			}
+			return false
+		},
+	},
+}
+
+// NewBody returns the JSON request body for a query and its variables
+func NewBody(query string, variables map[string]interface{}) []byte {
+	body := map[string]interface{}{"query": query}
+	if len(variables) > 0 {
+		body["variables"] = variables
+	}
+	data, _ := json.Marshal(body)
+	return data
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/expressions"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
 	httputil "github.com/khulnasoft-lab/vulmap/pkg/protocols/utils/http"
 	"github.com/khulnasoft-lab/rawhttp"
@@ -123,6 +124,24 @@ type Request struct {
 	// Fuzzing describes schema to fuzz http requests
 	Fuzzing []*fuzz.Rule `yaml:"fuzzing,omitempty" json:"fuzzing,omitempty" jsonschema:"title=fuzzin rules for http fuzzing,description=Fuzzing describes rule schema to fuzz http requests"`
 
+	// description: |
+	//   Timing confirms time-based blind payloads by statistical analysis of response latencies.
+	//
+	//   Each request is sent repeatedly with its delay variable set to 0 as control and to each
+	//   delay, the delay is confirmed if the latency increases by the delay. The event of the last
+	//   request contains timing_confidence and timing_confirmed along with the latency model.
+	//   Fuzzing payloads using the delay variable are analysed for each generated request.
+	Timing *timing.Config `yaml:"timing,omitempty" json:"timing,omitempty" jsonschema:"title=timing analysis of blind payloads,description=Confirms time-based blind payloads by statistical analysis of response latencies"`
+
+	// description: |
+	//   GraphQL treats the request as a GraphQL endpoint.
+	//
+	//   The schema is retrieved using introspection and a query or mutation is sent
+	//   for every field with placeholder arguments, which can be fuzzed using the graphql
+	//   fuzzing part. Field suggestions, batching and depth limits are probed and exposed
+	//   as graphql_field_suggestions, graphql_batching and graphql_depth_limit variables.
+	GraphQL bool `yaml:"graphql,omitempty" json:"graphql,omitempty" jsonschema:"title=graphql mode for http requests,description=GraphQL introspects the endpoint and sends a request for each schema field"`
+
 	CompiledOperators *operators.Operators `yaml:"-" json:"-"`
 
 	options           *protocols.ExecutorOptions
@@ -178,6 +197,48 @@ type Request struct {
 	//   The actual number of requests that will be sent is determined by the `race_count`  field.
 	Race bool `yaml:"race,omitempty" json:"race,omitempty" jsonschema:"title=perform race-http request coordination attack,description=Race determines if all the request have to be attempted at the same time (Race Condition)"`
 	// description: |
+	//   RaceMode is the synchronisation mode of race condition attacks.
+	//
+	//   last-byte sends every request on its own HTTP/1.1 connection withholding the last byte
+	//   until all requests were sent, single-packet completes all requests multiplexed on a HTTP/2
+	//   connection in a single packet falling back to last-byte if the server doesn't support HTTP/2.
+	//   The time until each response was received is available as race_timings.
+	// values:
+	//   - "last-byte"
+	//   - "single-packet"
+	RaceMode string `yaml:"race-mode,omitempty" json:"race-mode,omitempty" jsonschema:"title=race condition synchronisation mode,description=Synchronisation mode of race condition attacks,enum=last-byte,enum=single-packet"`
+	// description: |
+	//   Desync enables the request smuggling and desync detection mode.
+	//
+	//   The raw requests are sent unmodified in order on a single connection as a probe, a new
+	//   connection is only opened if the server closes the connection or doesn't respond in time.
+	//   The last request is also sent alone on a fresh connection as baseline before the probe.
+	//   Response timings, status codes and connections of the probe are available to matchers.
+	Desync bool `yaml:"desync,omitempty" json:"desync,omitempty" jsonschema:"title=request smuggling and desync detection,description=Sends the raw requests unmodified in order on a single connection to detect request smuggling"`
+	// description: |
+	//   DesyncTimeout is the time in seconds to wait for each response of a desync probe.
+	//
+	//   Requests which don't receive a response in time have a status code of 0 and desync_timeout set.
+	//   Defaults to the configured timeout.
+	DesyncTimeout int `yaml:"desync-timeout,omitempty" json:"desync-timeout,omitempty" jsonschema:"title=desync response timeout,description=Time in seconds to wait for each response of a desync probe"`
+	// description: |
+	//   DesyncProtocol is the protocol of the connections of desync probes.
+	//
+	//   With h2 the raw requests are sent as HTTP/2 streams on a single connection to detect
+	//   desyncs of front-ends downgrading requests to HTTP/1.1 (H2.CL and H2.TE). The headers
+	//   are sent as they are, including content-length and transfer-encoding. Requires https.
+	// values:
+	//   - "http/1.1"
+	//   - "h2"
+	DesyncProtocol string `yaml:"desync-protocol,omitempty" json:"desync-protocol,omitempty" jsonschema:"title=desync probe protocol,description=Protocol of the connections of desync probes,enum=http/1.1,enum=h2"`
+	// description: |
+	//   SameConnection sends the requests in order on a single keep-alive connection.
+	//
+	//   Each request is sent after the response to the previous one was received, a new connection
+	//   is only opened if the server closes the connection. The responses of the sequence are
+	//   available as resp_1..N along with their resp_N_status_code, resp_N_timing and resp_N_reused.
+	SameConnection bool `yaml:"same-connection,omitempty" json:"same-connection,omitempty" jsonschema:"title=send requests on the same connection,description=Sends the requests in order on a single keep-alive connection"`
+	// description: |
 	//   ReqCondition automatically assigns numbers to requests and preserves their history.
 	//
 	//   This allows matching on them later for multi-request conditions.
@@ -226,6 +287,35 @@ var RequestPartDefinitions = map[string]string{
 	"content_length":        "HTTP Response content length",
 	"header,all_headers":    "HTTP response headers",
 	"duration":              "HTTP request time duration",
+	"race_timings":          "Times in milliseconds until the responses of synchronised race requests were received",
+	"race_index":            "Index of the request in synchronised race requests",
+	"desync_index":          "Index of the request in the desync probe",
+	"desync_connection":     "Index of the connection the request of the desync probe was sent on",
+	"desync_connections":    "Indexes of the connections the requests of the desync probe were sent on",
+	"desync_timeout":        "Whether the request of the desync probe received no response in time",
+	"desync_timings":        "Times in milliseconds until the responses of the desync probe were received",
+	"desync_status_codes":   "Status codes of the responses of the desync probe in order, 0 for missing responses",
+	"desync_baseline":       "Time in milliseconds until the response to the last request sent alone was received",
+	"desync_baseline_code":  "Status code of the response to the last request sent alone",
+	"desync_pending":        "Data received on the connection after the last response of the desync probe",
+	"baseline_status_code":  "Status code of the baseline response of diff matchers",
+	"baseline_body":         "Body of the baseline response of diff matchers",
+	"baseline_header":       "Headers of the baseline response of diff matchers",
+	"baseline_response":     "Baseline response of diff matchers",
+	"connection_index":      "Index of the connection the request of a same-connection sequence was sent on",
+	"connection_reused":     "Whether the connection was used by a previous request of the same-connection sequence",
+	"<resp_N>":              "HTTP response N of the same-connection sequence",
+	"<resp_N_status_code>":  "Status code of response N of the same-connection sequence",
+	"<resp_N_timing>":       "Time in milliseconds until response N of the same-connection sequence was received",
+	"<resp_N_reused>":       "Whether request N of the same-connection sequence reused the connection",
+	"timing_confidence":     "Confidence between 0 and 1 that the payload delays the response in timing analysis",
+	"timing_confirmed":      "Whether the delay was confirmed by timing analysis",
+	"timing_slope":          "Increase of the latency in seconds for each second of delay in timing analysis",
+	"timing_r_squared":      "Coefficient of determination of the latency model of timing analysis",
+	"timing_baseline":       "Mean latency in seconds of the control requests of timing analysis",
+	"timing_deviation":      "Standard deviation of the latency in seconds of the control requests of timing analysis",
+	"timing_delays":         "Delays in seconds of the requests of timing analysis in order",
+	"timing_latencies":      "Latencies in seconds of the requests of timing analysis in order",
 	"all":                   "HTTP response body + headers",
 	"cookies_from_response": "HTTP response cookies in name:value format",
 	"headers_from_response": "HTTP response headers in name:value format",
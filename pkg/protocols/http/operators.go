@@ -1,7 +1,10 @@
 package http
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httputil"
 	"strings"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/output"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/offlinehttp"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 )
@@ -42,10 +46,38 @@ func (request *Request) Match(data map[string]interface{}, matcher *matchers.Mat
 		return matcher.Result(matcher.MatchDSL(data)), []string{}
 	case matchers.XPathMatcher:
 		return matcher.Result(matcher.MatchXPath(item)), []string{}
+	case matchers.DiffMatcher:
+		baseline, ok := getDiffBaseline(data, matcher.Diff.Baseline)
+		if !ok {
+			return false, []string{}
+		}
+		baselineItem, _ := request.getMatchPart(matcher.Part, baseline)
+		statusCode, _ := getStatusCode(data)
+		baselineStatusCode, _ := getStatusCode(baseline)
+		response := matchers.DiffResponse{StatusCode: statusCode, Headers: types.ToString(data["all_headers"]), Part: item}
+		baselineResponse := matchers.DiffResponse{StatusCode: baselineStatusCode, Headers: types.ToString(baseline["all_headers"]), Part: baselineItem}
+		return matcher.Result(matcher.MatchDiff(response, baselineResponse)), []string{}
 	}
 	return false, []string{}
 }
 
+// getDiffBaseline returns the baseline response of diff matchers which is either
+// the captured baseline or the response of a previous request of the template
+func getDiffBaseline(data map[string]interface{}, number int) (output.InternalEvent, bool) {
+	baseline := make(output.InternalEvent)
+	for _, key := range []string{"status_code", "body", "all_headers", "header", "response", "content_length"} {
+		baselineKey := "baseline_" + key
+		if number > 0 {
+			baselineKey = fmt.Sprintf("%s_%d", key, number)
+		}
+		if value, ok := data[baselineKey]; ok {
+			baseline[key] = value
+		}
+	}
+	_, ok := baseline["status_code"]
+	return baseline, ok
+}
+
 func getStatusCode(data map[string]interface{}) (int, bool) {
 	statusCodeValue, ok := data["status_code"]
 	if !ok {
@@ -139,6 +171,26 @@ func (request *Request) responseToDSLMap(resp *http.Response, host, matched, raw
 	return data
 }
 
+// FixtureToDSLMap converts a raw http response fixture to a map for use in DSL
+// matching. The fixture is read using the offline http response reader.
+func (request *Request) FixtureToDSLMap(fixture string) (output.InternalEvent, error) {
+	resp, err := offlinehttp.ReadResponseFromString(fixture)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	dumpedResponse, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return request.responseToDSLMap(resp, "", "", "", string(dumpedResponse), string(body), utils.HeadersToString(resp.Header), 0, nil), nil
+}
+
 // MakeResultEvent creates a result event from internal wrapped event
 func (request *Request) MakeResultEvent(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
 	return protocols.MakeDefaultResultEvent(request, wrapped)
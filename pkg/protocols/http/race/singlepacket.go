@@ -0,0 +1,305 @@
+package race
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+const (
+	// singlePacketDelay is the delay before the final frames are sent
+	// giving the server time to process the frames of the requests
+	singlePacketDelay = 100 * time.Millisecond
+	// maxSinglePacketBody is the maximum size of request bodies sent before the
+	// final frames which is limited by the default flow-control window
+	maxSinglePacketBody = 65535
+	// maxFrameSize is the default maximum size of http2 frames
+	maxFrameSize = 16384
+	// windowSize is the flow-control window announced to the server
+	windowSize = 1 << 30
+)
+
+// ErrSinglePacketUnsupported is returned when the requests can't be sent in a single packet
+var ErrSinglePacketUnsupported = errors.New("single-packet attack not supported")
+
+// connectionHeaders are connection-specific headers forbidden in http2
+var connectionHeaders = map[string]struct{}{
+	"connection":        {},
+	"host":              {},
+	"keep-alive":        {},
+	"proxy-connection":  {},
+	"transfer-encoding": {},
+	"upgrade":           {},
+}
+
+// h2Stream is a request multiplexed on the http2 connection
+type h2Stream struct {
+	request  *http.Request
+	body     []byte
+	response *SyncedResponse
+	header   http.Header
+	status   int
+	data     bytes.Buffer
+	done     bool
+}
+
+// SinglePacket multiplexes the requests on a single HTTP/2 connection. All
+// requests are sent without their final DATA frame, which are then sent
+// together in a single TCP packet so the requests complete at the same time.
+//
+// ErrSinglePacketUnsupported is returned if the server doesn't support HTTP/2
+// or the requests can't be multiplexed, a LastByte attack can be used instead.
+func (a *SyncedAttack) SinglePacket(ctx context.Context, requests []*http.Request) ([]*SyncedResponse, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	streams := make([]*h2Stream, len(requests))
+	for i, req := range requests {
+		if req.URL.Scheme != "https" || req.URL.Host != requests[0].URL.Host {
+			return nil, fmt.Errorf("%w: requests must be sent to the same https host", ErrSinglePacketUnsupported)
+		}
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > maxSinglePacketBody {
+			return nil, fmt.Errorf("%w: request body exceeds %d bytes", ErrSinglePacketUnsupported, maxSinglePacketBody)
+		}
+		streams[i] = &h2Stream{request: req, body: body, response: &SyncedResponse{}, header: make(http.Header)}
+	}
+
+	conn, err := a.dial(ctx, requests[0], http2.NextProtoTLS)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if state, ok := conn.(interface{ ConnectionState() tls.ConnectionState }); !ok || state.ConnectionState().NegotiatedProtocol != http2.NextProtoTLS {
+		return nil, fmt.Errorf("%w: server doesn't support http2", ErrSinglePacketUnsupported)
+	}
+	a.setDeadline(conn)
+
+	var buf bytes.Buffer
+	flush := func() error {
+		_, err := conn.Write(buf.Bytes())
+		buf.Reset()
+		return err
+	}
+	framer := http2.NewFramer(&buf, conn)
+	framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+
+	// exchange the connection settings before sending the requests
+	buf.WriteString(http2.ClientPreface)
+	if err := framer.WriteSettings(http2.Setting{ID: http2.SettingEnablePush, Val: 0}, http2.Setting{ID: http2.SettingInitialWindowSize, Val: windowSize}); err != nil {
+		return nil, err
+	}
+	if err := framer.WriteWindowUpdate(0, windowSize); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	for {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			return nil, err
+		}
+		settings, ok := frame.(*http2.SettingsFrame)
+		if !ok || settings.IsAck() {
+			continue
+		}
+		if maxStreams, ok := settings.Value(http2.SettingMaxConcurrentStreams); ok && int(maxStreams) < len(streams) {
+			return nil, fmt.Errorf("%w: server allows %d concurrent streams", ErrSinglePacketUnsupported, maxStreams)
+		}
+		if err := framer.WriteSettingsAck(); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	// send the requests without their last byte
+	var headerBlock bytes.Buffer
+	encoder := hpack.NewEncoder(&headerBlock)
+	for i, stream := range streams {
+		headerBlock.Reset()
+		for _, field := range h2HeaderFields(stream.request, len(stream.body)) {
+			if err := encoder.WriteField(field); err != nil {
+				return nil, err
+			}
+		}
+		if headerBlock.Len() > maxFrameSize {
+			return nil, fmt.Errorf("%w: request headers exceed %d bytes", ErrSinglePacketUnsupported, maxFrameSize)
+		}
+		streamID := streamID(i)
+		if err := framer.WriteHeaders(http2.HeadersFrameParam{StreamID: streamID, BlockFragment: headerBlock.Bytes(), EndHeaders: true}); err != nil {
+			return nil, err
+		}
+		for offset := 0; offset < len(stream.body)-1; offset += maxFrameSize {
+			if err := framer.WriteData(streamID, false, stream.body[offset:min(offset+maxFrameSize, len(stream.body)-1)]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	time.Sleep(singlePacketDelay)
+
+	// complete all requests with a single packet
+	for i, stream := range streams {
+		var last []byte
+		if len(stream.body) > 0 {
+			last = stream.body[len(stream.body)-1:]
+		}
+		if err := framer.WriteData(streamID(i), true, last); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	pending := len(streams)
+	for pending > 0 {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			for _, stream := range streams {
+				if !stream.done {
+					stream.response.Err = err
+				}
+			}
+			break
+		}
+		var stream *h2Stream
+		if id := frame.Header().StreamID; id != 0 && id%2 == 1 && int(id/2) < len(streams) {
+			stream = streams[id/2]
+		}
+		switch frame := frame.(type) {
+		case *http2.MetaHeadersFrame:
+			if stream == nil || stream.done {
+				continue
+			}
+			status, _ := strconv.Atoi(frame.PseudoValue("status"))
+			if status >= 100 && status < 200 {
+				continue
+			}
+			if stream.status == 0 {
+				stream.status = status
+				stream.response.Timing = time.Since(start)
+			}
+			for _, field := range frame.RegularFields() {
+				stream.header.Add(field.Name, field.Value)
+			}
+		case *http2.DataFrame:
+			if stream == nil || stream.done {
+				continue
+			}
+			stream.data.Write(frame.Data())
+		case *http2.RSTStreamFrame:
+			if stream != nil && !stream.done {
+				stream.response.Err = fmt.Errorf("stream reset by server: %s", frame.ErrCode)
+				stream.done = true
+				pending--
+			}
+			continue
+		case *http2.GoAwayFrame:
+			for i, stream := range streams {
+				if !stream.done && streamID(i) > frame.LastStreamID {
+					stream.response.Err = fmt.Errorf("connection closed by server: %s", frame.ErrCode)
+					stream.done = true
+					pending--
+				}
+			}
+			continue
+		case *http2.SettingsFrame:
+			if !frame.IsAck() {
+				if err := framer.WriteSettingsAck(); err == nil {
+					_ = flush()
+				}
+			}
+			continue
+		case *http2.PingFrame:
+			if !frame.IsAck() {
+				if err := framer.WritePing(true, frame.Data); err == nil {
+					_ = flush()
+				}
+			}
+			continue
+		default:
+			continue
+		}
+		if frame.Header().Flags.Has(http2.FlagDataEndStream) {
+			stream.done = true
+			pending--
+		}
+	}
+
+	responses := make([]*SyncedResponse, len(streams))
+	for i, stream := range streams {
+		responses[i] = stream.response
+		if stream.response.Err != nil {
+			continue
+		}
+		stream.response.Response = &http.Response{
+			Status:        fmt.Sprintf("%d %s", stream.status, http.StatusText(stream.status)),
+			StatusCode:    stream.status,
+			Proto:         "HTTP/2.0",
+			ProtoMajor:    2,
+			Header:        stream.header,
+			Body:          io.NopCloser(bytes.NewReader(stream.data.Bytes())),
+			ContentLength: int64(stream.data.Len()),
+			Request:       stream.request,
+		}
+	}
+	return responses, nil
+}
+
+// streamID returns the id of the client stream of the i-th request
+func streamID(i int) uint32 {
+	return uint32(2*i + 1)
+}
+
+// h2HeaderFields returns the http2 header fields of the request
+func h2HeaderFields(req *http.Request, bodyLength int) []hpack.HeaderField {
+	authority := req.Host
+	if authority == "" {
+		authority = req.URL.Host
+	}
+	fields := []hpack.HeaderField{
+		{Name: ":method", Value: req.Method},
+		{Name: ":scheme", Value: req.URL.Scheme},
+		{Name: ":authority", Value: authority},
+		{Name: ":path", Value: req.URL.RequestURI()},
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hasContentLength := false
+	for _, name := range names {
+		lowerName := strings.ToLower(name)
+		if _, ok := connectionHeaders[lowerName]; ok {
+			continue
+		}
+		hasContentLength = hasContentLength || lowerName == "content-length"
+		for _, value := range req.Header[name] {
+			fields = append(fields, hpack.HeaderField{Name: lowerName, Value: value})
+		}
+	}
+	if bodyLength > 0 && !hasContentLength {
+		fields = append(fields, hpack.HeaderField{Name: "content-length", Value: strconv.Itoa(bodyLength)})
+	}
+	return fields
+}
@@ -0,0 +1,177 @@
+package race
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Modes of synchronised race condition attacks
+const (
+	// LastByteMode sends every request on its own HTTP/1.1 connection
+	// withholding the last byte until all requests were sent
+	LastByteMode = "last-byte"
+	// SinglePacketMode multiplexes the requests on a HTTP/2 connection
+	// completing all of them in a single packet
+	SinglePacketMode = "single-packet"
+)
+
+// Dialer dials the connections of synchronised race condition attacks
+type Dialer interface {
+	Dial(ctx context.Context, network, address string) (net.Conn, error)
+	DialTLSWithConfig(ctx context.Context, network, address string, config *tls.Config) (net.Conn, error)
+}
+
+// SyncedResponse is the response to a request of a synchronised race condition attack
+type SyncedResponse struct {
+	Response *http.Response
+	// Timing is the time from the release of the requests until
+	// the headers of the response were received
+	Timing time.Duration
+	Err    error
+}
+
+// SyncedAttack sends requests synchronised to arrive at the server at the same time
+type SyncedAttack struct {
+	Dialer Dialer
+	// Timeout is the timeout of the connections, no timeout if zero
+	Timeout time.Duration
+	// SNI is the server name of tls connections
+	SNI string
+}
+
+// LastByte sends the requests on separate HTTP/1.1 connections and withholds
+// the last byte of every request until all other bytes were sent. The last
+// bytes are then released at once so network jitter only affects a single byte.
+func (a *SyncedAttack) LastByte(ctx context.Context, requests []*http.Request) []*SyncedResponse {
+	responses := make([]*SyncedResponse, len(requests))
+	conns := make([]net.Conn, len(requests))
+	payloads := make([][]byte, len(requests))
+	defer func() {
+		for _, conn := range conns {
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		responses[i] = &SyncedResponse{}
+		wg.Add(1)
+		go func(i int, req *http.Request) {
+			defer wg.Done()
+			var buf bytes.Buffer
+			if _, err := readBody(req); err != nil {
+				responses[i].Err = err
+				return
+			}
+			if err := req.Write(&buf); err != nil {
+				responses[i].Err = err
+				return
+			}
+			payloads[i] = buf.Bytes()
+			conn, err := a.dial(ctx, req, "http/1.1")
+			if err != nil {
+				responses[i].Err = err
+				return
+			}
+			conns[i] = conn
+			a.setDeadline(conn)
+			if _, err := conn.Write(payloads[i][:len(payloads[i])-1]); err != nil {
+				responses[i].Err = err
+			}
+		}(i, req)
+	}
+	wg.Wait()
+
+	start := time.Now()
+	for i, conn := range conns {
+		if responses[i].Err != nil {
+			continue
+		}
+		if _, err := conn.Write(payloads[i][len(payloads[i])-1:]); err != nil {
+			responses[i].Err = err
+		}
+	}
+
+	for i, conn := range conns {
+		if responses[i].Err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, conn net.Conn) {
+			defer wg.Done()
+			resp, err := http.ReadResponse(bufio.NewReader(conn), requests[i])
+			if err != nil {
+				responses[i].Err = err
+				return
+			}
+			responses[i].Timing = time.Since(start)
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil && len(body) == 0 {
+				responses[i].Err = err
+				return
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			responses[i].Response = resp
+		}(i, conn)
+	}
+	wg.Wait()
+	return responses
+}
+
+// dial dials a connection to the host of the request negotiating the protocol for tls connections
+func (a *SyncedAttack) dial(ctx context.Context, req *http.Request, protocol string) (net.Conn, error) {
+	host, port := req.URL.Hostname(), req.URL.Port()
+	if req.URL.Scheme != "https" {
+		if port == "" {
+			port = "80"
+		}
+		return a.Dialer.Dial(ctx, "tcp", net.JoinHostPort(host, port))
+	}
+	if port == "" {
+		port = "443"
+	}
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		ServerName:         a.SNI,
+		NextProtos:         []string{protocol},
+	}
+	if config.ServerName == "" && net.ParseIP(host) == nil {
+		config.ServerName = host
+	}
+	return a.Dialer.DialTLSWithConfig(ctx, "tcp", net.JoinHostPort(host, port), config)
+}
+
+// setDeadline sets the deadline of the connection, connections have no
+// deadline without timeout.
+func (a *SyncedAttack) setDeadline(conn net.Conn) {
+	if a.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(a.Timeout))
+	}
+}
+
+// readBody reads the body of the request replacing it with a reusable copy
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return body, nil
+}
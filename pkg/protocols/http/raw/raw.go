@@ -262,3 +262,48 @@ func (r *Request) TryFillCustomHeaders(headers []string) error {
 
 	return errors.New("no host header found")
 }
+
+// SetUnsafeHeader sets a header of the unsafe raw request, replacing the value
+// of the first header with the same name or adding it after the Host header.
+func (r *Request) SetUnsafeHeader(name, value string) error {
+	lineEnd := []byte("\r\n")
+	end := bytes.Index(r.UnsafeRawBytes, []byte("\r\n\r\n"))
+	if lfEnd := bytes.Index(r.UnsafeRawBytes, []byte("\n\n")); lfEnd != -1 && (end == -1 || lfEnd < end) {
+		lineEnd, end = []byte("\n"), lfEnd
+	}
+	if end == -1 {
+		end = len(r.UnsafeRawBytes)
+	}
+	lines := bytes.Split(r.UnsafeRawBytes[:end], lineEnd)
+	if len(lines) < 2 {
+		return errors.New("no headers found")
+	}
+
+	insert := len(lines)
+	replaced := false
+	for i, line := range lines[1:] {
+		key, _, ok := bytes.Cut(line, []byte(":"))
+		if !ok {
+			continue
+		}
+		key = bytes.TrimSpace(key)
+		if strings.EqualFold(string(key), name) {
+			lines[i+1] = []byte(fmt.Sprintf("%s: %s", key, value))
+			replaced = true
+			break
+		}
+		if strings.EqualFold(string(key), "host") {
+			insert = i + 2
+		}
+	}
+	if !replaced {
+		header := []byte(fmt.Sprintf("%s: %s", name, value))
+		lines = append(lines[:insert], append([][]byte{header}, lines[insert:]...)...)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(bytes.Join(lines, lineEnd))
+	buf.Write(r.UnsafeRawBytes[end:])
+	r.UnsafeRawBytes = buf.Bytes()
+	return nil
+}
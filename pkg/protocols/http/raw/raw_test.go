@@ -114,3 +114,17 @@ func parseURL(t *testing.T, inputurl string) *urlutil.URL {
 	}
 	return urlx
 }
+
+func TestSetUnsafeHeader(t *testing.T) {
+	request, err := Parse("GET / HTTP/1.1\r\nHost: example.com\r\ncookie: a=1\r\n\r\nbody", parseURL(t, "https://example.com"), true, false)
+	require.Nil(t, err, "could not parse unsafe request")
+
+	require.Nil(t, request.SetUnsafeHeader("Cookie", "a=1; b=2"), "could not replace header")
+	require.Nil(t, request.SetUnsafeHeader("Authorization", "Bearer a"), "could not add header")
+	require.Equal(t, "GET / HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer a\r\ncookie: a=1; b=2\r\n\r\nbody", string(request.UnsafeRawBytes))
+
+	request, err = Parse("GET / HTTP/1.1\nHost: example.com\n\n", parseURL(t, "https://example.com"), true, false)
+	require.Nil(t, err, "could not parse unsafe request")
+	require.Nil(t, request.SetUnsafeHeader("Cookie", "a=1"), "could not add header")
+	require.Equal(t, "GET / HTTP/1.1\nHost: example.com\nCookie: a=1\n\n", string(request.UnsafeRawBytes))
+}
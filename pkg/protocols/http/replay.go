@@ -0,0 +1,187 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/desync"
+)
+
+const (
+	// replayProtocol is the protocol http exchanges are recorded as
+	replayProtocol = "http"
+	// replayConnectionProtocol is the protocol results of desync probes and
+	// same-connection sequences are recorded as
+	replayConnectionProtocol = "http-connection"
+)
+
+// replayKey returns the key identifying the generated request in recordings
+func replayKey(generatedRequest *generatedRequest, dumpedRequest []byte) string {
+	switch {
+	case len(dumpedRequest) > 0:
+		return string(dumpedRequest)
+	case generatedRequest.request != nil:
+		// race requests are not dumped before being sent
+		return generatedRequest.request.Method + " " + generatedRequest.request.URL.String()
+	case generatedRequest.rawRequest != nil:
+		return generatedRequest.rawRequest.Method + " " + generatedRequest.rawRequest.FullURL
+	}
+	return ""
+}
+
+// replayURL returns the url and hostname of the generated request
+func replayURL(generatedRequest *generatedRequest) (string, string) {
+	if generatedRequest.request != nil {
+		return generatedRequest.request.URL.String(), generatedRequest.request.URL.Host
+	}
+	if generatedRequest.rawRequest != nil {
+		if parsed, err := url.Parse(generatedRequest.rawRequest.FullURL); err == nil {
+			return generatedRequest.rawRequest.FullURL, parsed.Host
+		}
+		return generatedRequest.rawRequest.FullURL, ""
+	}
+	return "", ""
+}
+
+// recordResponse records the responses of the redirect chain of a request.
+//
+// Each response is recorded as its url, dumped headers and body, starting
+// with the final response.
+func (request *Request) recordResponse(key string, responses []redirectedResponse, body []byte) error {
+	data := make([][]byte, 0, len(responses)*3)
+	for i, response := range responses {
+		if response.resp == nil {
+			continue
+		}
+		var responseURL string
+		if response.resp.Request != nil && response.resp.Request.URL != nil {
+			responseURL = response.resp.Request.URL.String()
+		}
+		responseBody := response.body
+		if i == 0 {
+			// the final response body is recorded before normalization
+			responseBody = body
+		}
+		data = append(data, []byte(responseURL), response.headers, responseBody)
+	}
+	return request.options.Replay.Record(replayProtocol, key, data...)
+}
+
+// replayResponse returns the recorded response of a request with
+// its redirect chain.
+func (request *Request) replayResponse(key string) (*http.Response, error) {
+	entry, err := request.options.Replay.Replay(replayProtocol, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(entry.Data) == 0 || len(entry.Data)%3 != 0 {
+		return nil, errors.New("invalid recorded http response")
+	}
+
+	var next *http.Response
+	for i := len(entry.Data) - 3; i >= 0; i -= 3 {
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(entry.Data[i+1])), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read recorded http response")
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(entry.Data[i+2]))
+		resp.Request = &http.Request{Header: make(http.Header), Response: next}
+		if responseURL, err := url.Parse(string(entry.Data[i])); err == nil {
+			resp.Request.URL = responseURL
+		}
+		next = resp
+	}
+	return next, nil
+}
+
+// recordedConnectionResponse is the recorded state of a response of a connection sequence
+type recordedConnectionResponse struct {
+	Timing     time.Duration `json:"timing"`
+	Connection int           `json:"connection"`
+	Reused     bool          `json:"reused,omitempty"`
+	TimedOut   bool          `json:"timed_out,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// connectionReplayKey returns the key identifying the requests of a connection sequence in recordings
+func connectionReplayKey(requests []desync.Request) string {
+	raws := make([][]byte, len(requests))
+	for i, req := range requests {
+		raws[i] = req.Raw
+	}
+	return string(bytes.Join(raws, []byte("\n---\n")))
+}
+
+// recordConnectionResult records the result of a connection sequence.
+//
+// The result is recorded as the state of the responses and the pending
+// data, followed by the dumped response of each request.
+func (request *Request) recordConnectionResult(key string, result *desync.Result) error {
+	recorded := make([]recordedConnectionResponse, len(result.Responses))
+	dumped := make([][]byte, len(result.Responses))
+	for i, response := range result.Responses {
+		recorded[i] = recordedConnectionResponse{Timing: response.Timing, Connection: response.Connection, Reused: response.Reused, TimedOut: response.TimedOut}
+		if response.Err != nil {
+			recorded[i].Error = response.Err.Error()
+		}
+		if response.Response == nil {
+			continue
+		}
+		body, _ := io.ReadAll(response.Response.Body)
+		response.Response.Body = io.NopCloser(bytes.NewReader(body))
+		// the body was already decoded, so it is dumped with its length
+		resp := *response.Response
+		resp.TransferEncoding = nil
+		resp.ContentLength = int64(len(body))
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		data, err := httputil.DumpResponse(&resp, true)
+		if err != nil {
+			return errors.Wrap(err, "could not dump connection response")
+		}
+		dumped[i] = data
+	}
+	marshaled, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	return request.options.Replay.Record(replayConnectionProtocol, key, append([][]byte{marshaled, result.Pending}, dumped...)...)
+}
+
+// replayConnectionResult returns the recorded result of a connection sequence
+func (request *Request) replayConnectionResult(key string, requests []desync.Request) (*desync.Result, error) {
+	entry, err := request.options.Replay.Replay(replayConnectionProtocol, key)
+	if err != nil {
+		return nil, err
+	}
+	var recorded []recordedConnectionResponse
+	if len(entry.Data) != len(requests)+2 || json.Unmarshal(entry.Data[0], &recorded) != nil || len(recorded) != len(requests) {
+		return nil, errors.New("invalid recorded connection sequence")
+	}
+
+	result := &desync.Result{Responses: make([]*desync.Response, len(requests)), Pending: entry.Data[1]}
+	for i, state := range recorded {
+		response := &desync.Response{Timing: state.Timing, Connection: state.Connection, Reused: state.Reused, TimedOut: state.TimedOut}
+		if state.Error != "" {
+			response.Err = errors.New(state.Error)
+		}
+		if dumped := entry.Data[i+2]; len(dumped) > 0 {
+			resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(dumped)), &http.Request{Method: requests[i].Method, URL: requests[i].URL})
+			if err != nil {
+				return nil, errors.Wrap(err, "could not read recorded connection response")
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			response.Response = resp
+		}
+		result.Responses[i] = response
+	}
+	return result, nil
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -34,9 +36,11 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/signer"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/signerpool"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	"github.com/khulnasoft-lab/rawhttp"
+	"github.com/khulnasoft-lab/retryablehttp-go"
 	"github.com/khulnasoft-lab/utils/reader"
 	sliceutil "github.com/khulnasoft-lab/utils/slice"
 	stringsutil "github.com/khulnasoft-lab/utils/strings"
@@ -69,6 +73,7 @@ func (request *Request) executeRaceRequest(input *contextargs.Context, previous
 		return err
 	}
 	request.setCustomHeaders(requestForDump)
+	request.setSessionState(input, requestForDump)
 	dumpedRequest, err := dump(requestForDump, reqURL)
 	if err != nil {
 		return err
@@ -99,6 +104,9 @@ func (request *Request) executeRaceRequest(input *contextargs.Context, previous
 		}
 		generatedRequests = append(generatedRequests, generatedRequest)
 	}
+	if request.RaceMode != "" {
+		return request.executeSyncedRaceRequest(input, generatedRequests, previous, callback)
+	}
 
 	wg := sync.WaitGroup{}
 	var requestErr error
@@ -239,21 +247,88 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 			return errors.Wrap(err, "could not parse url")
 		}
 	}
-	fuzzRequestCallback := func(gr fuzz.GeneratedRequest) bool {
+	needsDiffBaseline := request.needsDiffBaseline()
+
+	// Iterate through all requests for template and queue them for fuzzing
+	generator := request.newGenerator(true)
+	for {
+		value, payloads, result := generator.nextValue()
+		if !result {
+			break
+		}
+		generated, err := generator.Make(context.Background(), input, value, payloads, nil)
+		if err != nil {
+			continue
+		}
+		// requests discovered by crawling are fuzzed with their method, headers and body
+		if input.MetaInput.Request != nil {
+			if generated.request, err = inputBaseRequest(input.MetaInput.Request, generated.request); err != nil {
+				return errors.Wrap(err, "could not build request from input")
+			}
+		}
+		// mutations are compared with the response to the request before mutation by diff matchers
+		var baseline output.InternalEvent
+		if needsDiffBaseline {
+			baseline = request.diffBaseline(input, generated.request)
+		}
+		fuzzRequestCallback := request.newFuzzRequestCallback(input, baseline, callback)
+		for _, rule := range request.Fuzzing {
+			err = rule.Execute(&fuzz.ExecuteRuleInput{
+				Input:       input,
+				Callback:    fuzzRequestCallback,
+				Values:      generated.dynamicValues,
+				BaseRequest: generated.request,
+				Timing:      request.Timing,
+			})
+			if err == types.ErrNoMoreRequests {
+				return nil
+			}
+			if err != nil {
+				return errors.Wrap(err, "could not execute rule")
+			}
+		}
+	}
+	return nil
+}
+
+// inputBaseRequest returns the full request of the input as base request of fuzzing rules,
+// the headers of the generated request like custom headers override the input headers.
+func inputBaseRequest(input *contextargs.Request, generated *retryablehttp.Request) (*retryablehttp.Request, error) {
+	var body io.Reader
+	if input.Body != "" {
+		body = strings.NewReader(input.Body)
+	}
+	req, err := retryablehttp.NewRequestWithContext(generated.Context(), input.Method, generated.URL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range input.Headers {
+		if stringsutil.EqualFoldAny(name, "Host", "Content-Length") {
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	for name, values := range generated.Header {
+		req.Header[name] = values
+	}
+	return req, nil
+}
+
+// newFuzzRequestCallback returns a callback executing the requests generated by fuzzing rules
+// with the baseline response of diff matchers if any
+func (request *Request) newFuzzRequestCallback(input *contextargs.Context, baseline output.InternalEvent, callback protocols.OutputEventCallback) func(fuzz.GeneratedRequest) bool {
+	return func(gr fuzz.GeneratedRequest) bool {
 		hasInteractMatchers := interactsh.HasMatchers(request.CompiledOperators)
 		hasInteractMarkers := len(gr.InteractURLs) > 0
 		if request.options.HostErrorsCache != nil && request.options.HostErrorsCache.Check(input.MetaInput.Input) {
 			return false
 		}
-		request.options.RateLimiter.Take()
-		req := &generatedRequest{
-			request:        gr.Request,
-			dynamicValues:  gr.DynamicValues,
-			interactshURLs: gr.InteractURLs,
-			original:       request,
-		}
 		var gotMatches bool
-		requestErr := request.executeRequest(input, req, gr.DynamicValues, hasInteractMatchers, func(event *output.InternalWrappedEvent) {
+		previous := gr.DynamicValues
+		if baseline != nil {
+			previous = generators.MergeMaps(gr.DynamicValues, baseline)
+		}
+		eventCallback := func(event *output.InternalWrappedEvent) {
 			if hasInteractMarkers && hasInteractMatchers && request.options.Interactsh != nil {
 				requestData := &interactsh.RequestData{
 					MakeResultFunc: request.MakeResultEvent,
@@ -271,7 +346,37 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 			if event.OperatorsResult != nil {
 				gotMatches = event.OperatorsResult.Matched
 			}
-		}, 0)
+		}
+		var requestErr error
+		if gr.TimingRequests != nil {
+			// blind payloads using the delay variable are confirmed by timing analysis
+			requestErr = request.executeTimingRequest(input, func(ctx context.Context, delay int) (*generatedRequest, error) {
+				timingRequest, ok := gr.TimingRequests[delay]
+				if !ok {
+					return nil, errors.Errorf("no fuzzing request generated for delay %d", delay)
+				}
+				req, err := cloneRequest(ctx, timingRequest)
+				if err != nil {
+					return nil, err
+				}
+				return &generatedRequest{
+					request:        req,
+					dynamicValues:  request.Timing.Values(gr.DynamicValues, delay),
+					interactshURLs: gr.InteractURLs,
+					original:       request,
+				}, nil
+			}, previous, eventCallback, 0)
+		} else {
+			request.options.RateLimiter.Take()
+			req := &generatedRequest{
+				request:        gr.Request,
+				dynamicValues:  gr.DynamicValues,
+				interactshURLs: gr.InteractURLs,
+				original:       request,
+			}
+			requestErr = request.executeRequest(input, req, previous, hasInteractMatchers, eventCallback, 0)
+			request.options.Progress.IncrementRequests()
+		}
 		// If a variable is unresolved, skip all further requests
 		if errors.Is(requestErr, errStopExecution) {
 			return false
@@ -282,7 +387,6 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 			}
 			gologger.Verbose().Msgf("[%s] Error occurred in request: %s\n", request.options.TemplateID, requestErr)
 		}
-		request.options.Progress.IncrementRequests()
 
 		// If this was a match, and we want to stop at first match, skip all further requests.
 		shouldStopAtFirstMatch := request.options.Options.StopAtFirstMatch || request.StopAtFirstMatch
@@ -291,34 +395,6 @@ func (request *Request) executeFuzzingRule(input *contextargs.Context, previous
 		}
 		return true
 	}
-
-	// Iterate through all requests for template and queue them for fuzzing
-	generator := request.newGenerator(true)
-	for {
-		value, payloads, result := generator.nextValue()
-		if !result {
-			break
-		}
-		generated, err := generator.Make(context.Background(), input, value, payloads, nil)
-		if err != nil {
-			continue
-		}
-		for _, rule := range request.Fuzzing {
-			err = rule.Execute(&fuzz.ExecuteRuleInput{
-				Input:       input,
-				Callback:    fuzzRequestCallback,
-				Values:      generated.dynamicValues,
-				BaseRequest: generated.request,
-			})
-			if err == types.ErrNoMoreRequests {
-				return nil
-			}
-			if err != nil {
-				return errors.Wrap(err, "could not execute rule")
-			}
-		}
-	}
-	return nil
 }
 
 // ExecuteWithResults executes the final request on a URL
@@ -327,6 +403,10 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		variablesMap := request.options.Variables.Evaluate(generators.MergeMaps(dynamicValues, previous))
 		dynamicValues = generators.MergeMaps(variablesMap, dynamicValues, request.options.Constants)
 	}
+	// capture the baseline of diff matchers, fuzzing rules capture it for each request before mutation
+	if len(request.Fuzzing) == 0 && !request.GraphQL && request.needsDiffBaseline() {
+		previous = generators.MergeMaps(previous, request.diffBaseline(input, nil))
+	}
 	// verify if pipeline was requested
 	if request.Pipeline {
 		return request.executeTurboHTTP(input, dynamicValues, previous, callback)
@@ -337,11 +417,21 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		return request.executeRaceRequest(input, dynamicValues, callback)
 	}
 
+	// verify if requests have to be sent on a single connection
+	if request.Desync || request.SameConnection {
+		return request.executeConnectionRequest(input, dynamicValues, previous, callback)
+	}
+
 	// verify if parallel elaboration was requested
 	if request.Threads > 0 {
 		return request.executeParallelHTTP(input, dynamicValues, callback)
 	}
 
+	// verify if graphql elaboration was requested
+	if request.GraphQL {
+		return request.executeGraphQLRequest(input, dynamicValues, callback)
+	}
+
 	// verify if fuzz elaboration was requested
 	if len(request.Fuzzing) > 0 {
 		return request.executeFuzzingRule(input, dynamicValues, callback)
@@ -357,7 +447,15 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		executeFunc := func(data string, payloads, dynamicValue map[string]interface{}) (bool, error) {
 			hasInteractMatchers := interactsh.HasMatchers(request.CompiledOperators)
 
-			request.options.RateLimiter.Take()
+			var timingPayloads map[string]interface{}
+			if request.Timing != nil {
+				// payloads are evaluated again for each delay of the timing analysis,
+				// which takes a token of the rate limit for each request it sends
+				timingPayloads = maps.Clone(payloads)
+				dynamicValue = request.Timing.Values(dynamicValue, 0)
+			} else {
+				request.options.RateLimiter.Take()
+			}
 
 			ctx := request.newContext(input)
 			ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(request.options.Options.Timeout)*time.Second)
@@ -384,7 +482,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 				return true, nil
 			}
 			var gotMatches bool
-			err = request.executeRequest(input, generatedHttpRequest, previous, hasInteractMatchers, func(event *output.InternalWrappedEvent) {
+			eventCallback := func(event *output.InternalWrappedEvent) {
 				// a special case where operators has interactsh matchers and multiple request are made
 				// ex: status_code_2 , interactsh_protocol (from 1st request) etc
 				needsRequestEvent := interactsh.HasMatchers(request.CompiledOperators) && request.NeedsRequestCondition()
@@ -412,7 +510,14 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 				// Note: this only happens if requests > 1 and interactsh matcher is used
 				// TODO: interactsh logic in vulmap needs to be refactored to avoid such situations
 				callback(event)
-			}, generator.currentIndex)
+			}
+			if request.Timing != nil {
+				err = request.executeTimingRequest(input, func(ctx context.Context, delay int) (*generatedRequest, error) {
+					return generator.Make(ctx, input, data, maps.Clone(timingPayloads), request.Timing.Values(dynamicValue, delay))
+				}, previous, eventCallback, generator.currentIndex)
+			} else {
+				err = request.executeRequest(input, generatedHttpRequest, previous, hasInteractMatchers, eventCallback, generator.currentIndex)
+			}
 
 			// If a variable is unresolved, skip all further requests
 			if errors.Is(err, errStopExecution) {
@@ -424,7 +529,9 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 				}
 				requestErr = err
 			}
-			request.options.Progress.IncrementRequests()
+			if request.Timing == nil {
+				request.options.Progress.IncrementRequests()
+			}
 
 			// If this was a match, and we want to stop at first match, skip all further requests.
 			shouldStopAtFirstMatch := generatedHttpRequest.original.options.Options.StopAtFirstMatch || generatedHttpRequest.original.options.StopAtFirstMatch || request.StopAtFirstMatch
@@ -467,6 +574,7 @@ var errStopExecution = errors.New("stop execution due to unresolved variables")
 // executeRequest executes the actual generated request and returns error if occurred
 func (request *Request) executeRequest(input *contextargs.Context, generatedRequest *generatedRequest, previousEvent output.InternalEvent, hasInteractMatchers bool, callback protocols.OutputEventCallback, requestCount int) error {
 	request.setCustomHeaders(generatedRequest)
+	request.setSessionState(input, generatedRequest)
 
 	// Try to evaluate any payloads before replacement
 	finalMap := generators.MergeMaps(generatedRequest.dynamicValues, generatedRequest.meta)
@@ -546,7 +654,21 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	var formedURL string
 	var hostname string
 	timeStart := time.Now()
-	if generatedRequest.original.Pipeline {
+	if request.options.Replay.Replaying() {
+		// if replaying a recorded scan, serve the recorded response instead of sending the request
+		formedURL, hostname = replayURL(generatedRequest)
+		resp, err = request.replayResponse(replayKey(generatedRequest, dumpedRequest))
+	} else if generatedRequest.raceResponse != nil {
+		// responses of synchronised race requests were received by the attack
+		hostname = generatedRequest.request.URL.Host
+		formedURL = generatedRequest.request.URL.String()
+		resp, err = generatedRequest.raceResponse.Response, generatedRequest.raceResponse.Err
+	} else if generatedRequest.connectionResponse != nil {
+		// responses of desync probes and same-connection sequences were received on the connection
+		formedURL = generatedRequest.connectionResponse.url.String()
+		hostname = generatedRequest.connectionResponse.url.Host
+		resp, err = generatedRequest.connectionResponse.Response.Response, generatedRequest.connectionResponse.Err
+	} else if generatedRequest.original.Pipeline {
 		// if request is a pipeline request, use the pipelined client
 		if generatedRequest.rawRequest != nil {
 			formedURL = generatedRequest.rawRequest.FullURL
@@ -605,15 +727,9 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				return errSignature
 			}
 
-			httpclient := request.httpClient
-			if input.CookieJar != nil {
-				connConfiguration := request.connConfiguration
-				connConfiguration.Connection.SetCookieJar(input.CookieJar)
-				client, err := httpclientpool.Get(request.options.Options, connConfiguration)
-				if err != nil {
-					return errors.Wrap(err, "could not get http client")
-				}
-				httpclient = client
+			var httpclient *retryablehttp.Client
+			if httpclient, err = request.inputHTTPClient(input); err != nil {
+				return err
 			}
 			resp, err = httpclient.Do(generatedRequest.request)
 		}
@@ -666,7 +782,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			if input.MetaInput.CustomIP != "" {
 				outputEvent["ip"] = input.MetaInput.CustomIP
 			} else {
-				outputEvent["ip"] = httpclientpool.Dialer.GetDialedIP(hostname)
+				outputEvent["ip"] = request.options.Replay.DialedIP(hostname, httpclientpool.Dialer.GetDialedIP)
 			}
 
 			event := &output.InternalWrappedEvent{InternalEvent: outputEvent}
@@ -698,6 +814,11 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	request.options.Output.Request(request.options.TemplatePath, formedURL, request.Type().String(), err)
 
 	duration := time.Since(timeStart)
+	if generatedRequest.raceResponse != nil {
+		duration = generatedRequest.raceResponse.Timing
+	} else if generatedRequest.connectionResponse != nil {
+		duration = generatedRequest.connectionResponse.Timing
+	}
 
 	dumpedResponseHeaders, err := httputil.DumpResponse(resp, false)
 	if err != nil {
@@ -742,6 +863,11 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			return errors.Wrap(err, "could not store in project file")
 		}
 	}
+	if request.options.Replay.Recording() {
+		if err := request.recordResponse(replayKey(generatedRequest, dumpedRequest), dumpedResponse, gotData); err != nil {
+			return errors.Wrap(err, "could not record http response")
+		}
+	}
 
 	for _, response := range dumpedResponse {
 		if response.resp == nil {
@@ -774,10 +900,34 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			hostname = hostname[:i]
 		}
 		outputEvent["curl-command"] = curlCommand
+		if generatedRequest.raceResponse != nil {
+			outputEvent["race_index"] = generatedRequest.raceResponse.index
+			outputEvent["race_timings"] = generatedRequest.raceResponse.timings
+		}
+		if response := generatedRequest.connectionResponse; response != nil && request.Desync {
+			outputEvent["desync_index"] = response.index
+			outputEvent["desync_connection"] = response.Connection
+			outputEvent["desync_connections"] = response.sequence.connections
+			outputEvent["desync_timeout"] = response.TimedOut
+			outputEvent["desync_timings"] = response.sequence.timings
+			outputEvent["desync_status_codes"] = response.sequence.statusCodes
+			outputEvent["desync_baseline"] = response.sequence.baseline
+			outputEvent["desync_baseline_code"] = response.sequence.baselineCode
+			outputEvent["desync_pending"] = response.sequence.pending
+		} else if response != nil {
+			outputEvent["connection_index"] = response.Connection
+			outputEvent["connection_reused"] = response.Reused
+			for i := range response.sequence.responses {
+				outputEvent[fmt.Sprintf("resp_%d", i+1)] = response.sequence.responses[i]
+				outputEvent[fmt.Sprintf("resp_%d_status_code", i+1)] = response.sequence.statusCodes[i]
+				outputEvent[fmt.Sprintf("resp_%d_timing", i+1)] = response.sequence.timings[i]
+				outputEvent[fmt.Sprintf("resp_%d_reused", i+1)] = response.sequence.reused[i]
+			}
+		}
 		if input.MetaInput.CustomIP != "" {
 			outputEvent["ip"] = input.MetaInput.CustomIP
 		} else {
-			outputEvent["ip"] = httpclientpool.Dialer.GetDialedIP(hostname)
+			outputEvent["ip"] = request.options.Replay.DialedIP(hostname, httpclientpool.Dialer.GetDialedIP)
 		}
 		if request.options.Interactsh != nil {
 			request.options.Interactsh.MakePlaceholders(generatedRequest.interactshURLs, outputEvent)
@@ -862,6 +1012,146 @@ func (request *Request) setCustomHeaders(req *generatedRequest) {
 	}
 }
 
+// inputHTTPClient returns the http client of the request for the input,
+// using the cookie jar of the input if available.
+func (request *Request) inputHTTPClient(input *contextargs.Context) (*retryablehttp.Client, error) {
+	if input.CookieJar == nil {
+		return request.httpClient, nil
+	}
+	connConfiguration := request.connConfiguration
+	connConfiguration.Connection.SetCookieJar(input.CookieJar)
+	client, err := httpclientpool.Get(request.options.Options, connConfiguration)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get http client")
+	}
+	return client, nil
+}
+
+// sendInputRequest sends a request made by the engine for the input, such as a
+// diff baseline or a graphql probe, with the custom headers and the session state
+// through the client of the input. The response is served from the recording or
+// the project file if available, and stored otherwise.
+//
+// The body of the final response is read up to readSize bytes if not zero and
+// returned along with the responses of the redirect chain.
+func (request *Request) sendInputRequest(input *contextargs.Context, req *retryablehttp.Request, readSize int64) ([]redirectedResponse, []byte, error) {
+	generated := &generatedRequest{request: req, original: request}
+	request.setCustomHeaders(generated)
+	request.setSessionState(input, generated)
+	dumpedRequest, err := dump(generated, input.MetaInput.Input)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not dump request")
+	}
+
+	var resp *http.Response
+	var fromCache bool
+	if request.options.Replay.Replaying() {
+		resp, err = request.replayResponse(replayKey(generated, dumpedRequest))
+	} else {
+		if request.options.ProjectFile != nil {
+			if resp, err = request.options.ProjectFile.Get(dumpedRequest); err == nil {
+				fromCache = true
+			}
+		}
+		if !fromCache {
+			var client *retryablehttp.Client
+			if client, err = request.inputHTTPClient(input); err != nil {
+				return nil, nil, err
+			}
+			request.options.RateLimiter.Take()
+			resp, err = client.Do(req)
+			request.options.Progress.IncrementRequests()
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var bodyReader io.Reader = resp.Body
+	if readSize > 0 {
+		bodyReader = io.LimitReader(resp.Body, readSize)
+	}
+	data, err := io.ReadAll(bodyReader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read http body")
+	}
+	responses, err := dumpResponseWithRedirectChain(resp, data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read http response with redirect chain")
+	}
+	if len(responses) == 0 {
+		return nil, nil, errors.New("no http response")
+	}
+	if request.options.ProjectFile != nil && !fromCache {
+		if err := request.options.ProjectFile.Set(dumpedRequest, resp, data); err != nil {
+			return nil, nil, errors.Wrap(err, "could not store in project file")
+		}
+	}
+	if request.options.Replay.Recording() {
+		if err := request.recordResponse(replayKey(generated, dumpedRequest), responses, data); err != nil {
+			return nil, nil, errors.Wrap(err, "could not record http response")
+		}
+	}
+	return responses, data, nil
+}
+
+// setSessionState sets the imported session cookies and authentication headers
+// for generated request, the cookies and headers of the template are kept.
+//
+// The imported cookies are seeded in the cookie jar of the input, so the cookies
+// set by the responses of the scan replace them.
+func (request *Request) setSessionState(input *contextargs.Context, req *generatedRequest) {
+	if !request.options.Session.Importing() {
+		return
+	}
+	if req.rawRequest != nil {
+		parsed, err := url.Parse(req.rawRequest.FullURL)
+		if err == nil && req.rawRequest.FullURL == "" {
+			// unsafe requests are sent to the input with the path of the raw request
+			if parsed, err = url.Parse(input.MetaInput.Input); err == nil {
+				parsed, err = parsed.Parse(req.rawRequest.Path)
+			}
+		}
+		if err != nil {
+			return
+		}
+		cookieHeader := "Cookie"
+		existing := make(map[string]struct{})
+		for k := range req.rawRequest.Headers {
+			existing[strings.ToLower(k)] = struct{}{}
+			if strings.EqualFold(k, cookieHeader) {
+				cookieHeader = k
+			}
+		}
+		// unsafe requests are sent as their raw bytes
+		setHeader := func(name, value string) {
+			req.rawRequest.Headers[name] = value
+			if len(req.rawRequest.UnsafeRawBytes) > 0 {
+				_ = req.rawRequest.SetUnsafeHeader(name, value)
+			}
+		}
+		for k, v := range request.options.Session.Headers(parsed.Host) {
+			if _, ok := existing[strings.ToLower(k)]; !ok {
+				setHeader(k, v)
+			}
+		}
+		// raw requests are not sent with the cookie jar
+		if cookies := request.options.Session.JarCookies(input.CookieJar, parsed); len(cookies) > 0 {
+			setHeader(cookieHeader, session.AddCookies(req.rawRequest.Headers[cookieHeader], cookies))
+		}
+		return
+	}
+
+	parsed := req.request.URL.URL
+	request.options.Session.SetHeaders(req.request.Header, parsed)
+	cookies := request.options.Session.JarCookies(input.CookieJar, parsed)
+	// requests reusing cookies are sent with the cookie jar of the input
+	if len(cookies) > 0 && !request.CookieReuse {
+		req.request.Header.Set("Cookie", session.AddCookies(req.request.Header.Get("Cookie"), cookies))
+	}
+}
+
 const CRLF = "\r\n"
 
 func dumpResponse(event *output.InternalWrappedEvent, request *Request, redirectedResponse []byte, formedURL string, responseContentType string, isResponseTruncated bool, reqURL string) {
@@ -15,6 +15,10 @@ func (request *Request) NeedsRequestCondition() bool {
 		if checkRequestConditionExpressions(matcher.DSL...) {
 			return true
 		}
+		// diff matchers can use the response of a previous request as baseline
+		if matcher.Diff != nil && matcher.Diff.Baseline > 0 {
+			return true
+		}
 		if checkRequestConditionExpressions(matcher.Part) {
 			return true
 		}
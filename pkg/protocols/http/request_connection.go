@@ -0,0 +1,343 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/desync"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// connectionResponse is the response to a request of a sequence sent on a single connection
+type connectionResponse struct {
+	*desync.Response
+	// index is the index of the request in the sequence
+	index    int
+	url      *url.URL
+	sequence *connectionSequence
+}
+
+// connectionSequence contains the results of a sequence of requests
+// sent on a single connection shared by all of its responses
+type connectionSequence struct {
+	// timings contains the timings of all requests of the sequence in milliseconds
+	timings     []float64
+	statusCodes []int
+	connections []int
+	reused      []bool
+	// responses contains the dumped responses of the sequence
+	responses []string
+	// baseline is the timing of the last request sent alone in milliseconds
+	baseline     float64
+	baselineCode int
+	pending      string
+}
+
+// executeConnectionRequest sends the requests of each payload combination
+// in order on a single connection as desync probe or same-connection sequence
+func (request *Request) executeConnectionRequest(input *contextargs.Context, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	generator := request.newGenerator(false)
+	sequenceLength := len(request.Raw)
+	if sequenceLength == 0 {
+		sequenceLength = len(request.Path)
+	}
+
+	var requestErr error
+	for {
+		var generatedRequests []*generatedRequest
+		for {
+			inputData, payloads, ok := generator.nextValue()
+			if !ok {
+				break
+			}
+			ctx := request.newContext(input)
+			generatedRequest, err := generator.Make(ctx, input, inputData, payloads, dynamicValues)
+			if err != nil {
+				if err == types.ErrNoMoreRequests {
+					break
+				}
+				request.options.Progress.IncrementFailedRequestsBy(int64(generator.Total()))
+				return err
+			}
+			generatedRequests = append(generatedRequests, generatedRequest)
+			// the sequence is complete once the last request was generated
+			if generator.currentIndex >= sequenceLength {
+				break
+			}
+		}
+		if len(generatedRequests) == 0 {
+			return requestErr
+		}
+		// Check if hosts keep erroring
+		if request.options.HostErrorsCache != nil && request.options.HostErrorsCache.Check(input.MetaInput.ID()) {
+			return requestErr
+		}
+		if err := request.executeConnectionSequence(input, generatedRequests, previous, callback); err != nil {
+			requestErr = multierr.Append(requestErr, err)
+		}
+	}
+}
+
+// executeConnectionSequence sends the generated requests in order on a single connection and processes their responses
+func (request *Request) executeConnectionSequence(input *contextargs.Context, generatedRequests []*generatedRequest, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	timeout := time.Duration(request.options.Options.Timeout) * time.Second
+	if request.DesyncTimeout > 0 {
+		timeout = time.Duration(request.DesyncTimeout) * time.Second
+	}
+	prober := &desync.Prober{
+		Dialer:   httpclientpool.Dialer,
+		Timeout:  timeout,
+		SNI:      request.options.Options.SNI,
+		Protocol: request.DesyncProtocol,
+	}
+
+	requests := make([]desync.Request, len(generatedRequests))
+	templateCookies := make([]string, len(generatedRequests))
+	for i, generatedRequest := range generatedRequests {
+		templateCookies[i] = connectionCookies(generatedRequest)
+		connectionRequest, err := request.connectionRequest(input, generatedRequest)
+		if err != nil {
+			request.options.Progress.IncrementFailedRequestsBy(int64(len(generatedRequests)))
+			return err
+		}
+		requests[i] = connectionRequest
+	}
+	// same-connection sequences send the cookies of the jar including the cookies set by the previous responses
+	if request.SameConnection && input.CookieJar != nil {
+		prober.Prepare = func(i int, req *desync.Request, previous *desync.Response) error {
+			if previous != nil && previous.Response != nil {
+				input.CookieJar.SetCookies(requests[i-1].URL, previous.Response.Cookies())
+			}
+			request.options.Session.SetCookies(input.CookieJar, req.URL)
+			cookies := input.CookieJar.Cookies(req.URL)
+			if len(cookies) == 0 {
+				return nil
+			}
+			setConnectionCookies(generatedRequests[i], session.AddCookies(templateCookies[i], cookies))
+			connectionRequest, err := request.writeConnectionRequest(input.MetaInput.Input, generatedRequests[i])
+			if err != nil {
+				return err
+			}
+			*req = connectionRequest
+			return nil
+		}
+	}
+
+	request.options.RateLimiter.Take()
+	ctx := request.newContext(input)
+	sequence := &connectionSequence{baseline: -1}
+	// the last request of desync probes is sent alone first so it isn't affected by the probe
+	if request.Desync && len(requests) > 1 {
+		baselineResult, err := request.probeConnection(ctx, prober, requests[len(requests)-1:])
+		if err != nil {
+			request.options.Progress.IncrementFailedRequestsBy(int64(len(generatedRequests)))
+			return err
+		}
+		baseline := baselineResult.Responses[0]
+		if baseline.Err == nil {
+			sequence.baseline = float64(baseline.Timing.Microseconds()) / 1000
+		}
+		if baseline.Response != nil {
+			sequence.baselineCode = baseline.Response.StatusCode
+		}
+		request.options.Progress.IncrementRequests()
+		request.options.RateLimiter.Take()
+	}
+	result, err := request.probeConnection(ctx, prober, requests)
+	if err != nil {
+		request.options.Progress.IncrementFailedRequestsBy(int64(len(generatedRequests)))
+		return err
+	}
+	sequence.pending = string(result.Pending)
+
+	// failed requests have a negative timing
+	sequence.timings = make([]float64, len(result.Responses))
+	sequence.statusCodes = make([]int, len(result.Responses))
+	sequence.connections = make([]int, len(result.Responses))
+	sequence.reused = make([]bool, len(result.Responses))
+	sequence.responses = make([]string, len(result.Responses))
+	for i, response := range result.Responses {
+		sequence.timings[i] = -1
+		if response.Err == nil {
+			sequence.timings[i] = float64(response.Timing.Microseconds()) / 1000
+		}
+		sequence.connections[i] = response.Connection
+		sequence.reused[i] = response.Reused
+		if response.Response != nil {
+			sequence.statusCodes[i] = response.Response.StatusCode
+			sequence.responses[i] = dumpConnectionResponse(response.Response)
+			if prober.Prepare != nil {
+				input.CookieJar.SetCookies(requests[i].URL, response.Response.Cookies())
+			}
+		}
+	}
+
+	var requestErr error
+	for i, generatedRequest := range generatedRequests {
+		response := result.Responses[i]
+		// requests without response in time are matched with an empty response
+		if response.TimedOut {
+			response.Response = &http.Response{
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     make(http.Header),
+				Body:       http.NoBody,
+				Request:    &http.Request{Method: requests[i].Method, URL: requests[i].URL},
+			}
+		}
+		generatedRequest.connectionResponse = &connectionResponse{Response: response, index: i, url: requests[i].URL, sequence: sequence}
+		if err := request.executeRequest(input, generatedRequest, previous, false, callback, i+1); err != nil {
+			requestErr = multierr.Append(requestErr, err)
+		}
+		request.options.Progress.IncrementRequests()
+	}
+	return requestErr
+}
+
+// probeConnection sends the requests on a single connection with the prober,
+// the results are recorded and served from the recording when replaying.
+func (request *Request) probeConnection(ctx context.Context, prober *desync.Prober, requests []desync.Request) (*desync.Result, error) {
+	key := connectionReplayKey(requests)
+	if request.options.Replay.Replaying() {
+		result, err := request.replayConnectionResult(key, requests)
+		if err != nil || prober.Prepare == nil {
+			return result, err
+		}
+		// the requests are prepared with the recorded responses as they were sent
+		for i := range requests {
+			var previous *desync.Response
+			if i > 0 {
+				previous = result.Responses[i-1]
+			}
+			req := requests[i]
+			if err := prober.Prepare(i, &req, previous); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	result := prober.Probe(ctx, requests)
+	if request.options.Replay.Recording() {
+		if err := request.recordConnectionResult(key, result); err != nil {
+			return nil, errors.Wrap(err, "could not record connection sequence")
+		}
+	}
+	return result, nil
+}
+
+// connectionRequest prepares a generated request to send on a connection and returns its bytes
+func (request *Request) connectionRequest(input *contextargs.Context, generatedRequest *generatedRequest) (desync.Request, error) {
+	if generatedRequest.rawRequest == nil {
+		request.setCustomHeaders(generatedRequest)
+	}
+	request.setSessionState(input, generatedRequest)
+	if generatedRequest.rawRequest == nil {
+		if err := request.handleSignature(generatedRequest); err != nil {
+			return desync.Request{}, err
+		}
+	}
+	return request.writeConnectionRequest(input.MetaInput.Input, generatedRequest)
+}
+
+// writeConnectionRequest returns the bytes of a generated request to send on a connection
+func (request *Request) writeConnectionRequest(input string, generatedRequest *generatedRequest) (desync.Request, error) {
+	if generatedRequest.rawRequest != nil {
+		return desync.Request{
+			Method: generatedRequest.rawRequest.Method,
+			URL:    rawRequestURL(input, generatedRequest),
+			Raw:    generatedRequest.rawRequest.UnsafeRawBytes,
+		}, nil
+	}
+
+	body, err := generatedRequest.request.BodyBytes()
+	if err != nil {
+		return desync.Request{}, err
+	}
+	httpRequest := generatedRequest.request.Request.Clone(context.Background())
+	httpRequest.Body = io.NopCloser(bytes.NewReader(body))
+	httpRequest.ContentLength = int64(len(body))
+	var buf bytes.Buffer
+	if err := httpRequest.Write(&buf); err != nil {
+		return desync.Request{}, err
+	}
+	return desync.Request{Method: httpRequest.Method, URL: httpRequest.URL, Raw: buf.Bytes()}, nil
+}
+
+// connectionCookies returns the value of the cookie header of a generated request
+func connectionCookies(generatedRequest *generatedRequest) string {
+	if generatedRequest.rawRequest == nil {
+		return generatedRequest.request.Header.Get("Cookie")
+	}
+	for k, v := range generatedRequest.rawRequest.Headers {
+		if strings.EqualFold(k, "Cookie") {
+			return v
+		}
+	}
+	return ""
+}
+
+// setConnectionCookies sets the value of the cookie header of a generated request
+func setConnectionCookies(generatedRequest *generatedRequest, value string) {
+	if generatedRequest.rawRequest == nil {
+		generatedRequest.request.Header.Set("Cookie", value)
+		return
+	}
+	name := "Cookie"
+	for k := range generatedRequest.rawRequest.Headers {
+		if strings.EqualFold(k, name) {
+			name = k
+		}
+	}
+	generatedRequest.rawRequest.Headers[name] = value
+	// unsafe requests are sent as their raw bytes
+	if len(generatedRequest.rawRequest.UnsafeRawBytes) > 0 {
+		_ = generatedRequest.rawRequest.SetUnsafeHeader(name, value)
+	}
+}
+
+// rawRequestURL returns the url of an unsafe raw request sent on a connection
+func rawRequestURL(input string, generatedRequest *generatedRequest) *url.URL {
+	// self-contained requests contain the full url
+	if generatedRequest.rawRequest.FullURL != "" {
+		input = generatedRequest.rawRequest.FullURL
+	}
+	parsed, err := url.Parse(input)
+	if err != nil {
+		return &url.URL{Scheme: "http", Host: input, Path: "/"}
+	}
+	if generatedRequest.rawRequest.FullURL != "" {
+		return parsed
+	}
+	base := &url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: "/"}
+	// the path of unsafe requests may not be a valid url path
+	if withPath, err := url.Parse(base.Scheme + "://" + base.Host + generatedRequest.rawRequest.Path); err == nil && strings.HasPrefix(generatedRequest.rawRequest.Path, "/") {
+		return withPath
+	}
+	return base
+}
+
+// dumpConnectionResponse dumps a response received on a connection keeping its body readable
+func dumpConnectionResponse(resp *http.Response) string {
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	headers, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return string(body)
+	}
+	return string(headers) + string(body)
+}
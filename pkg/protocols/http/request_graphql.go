@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/retryablehttp-go"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/graphql"
+	"github.com/khulnasoft-lab/vulmap/pkg/types"
+)
+
+// maxGraphQLResponseSize is the maximum size of introspection and probe responses to read
+const maxGraphQLResponseSize = 10 * 1024 * 1024
+
+// executeGraphQLRequest introspects the graphql endpoint and executes a request
+// for each operation of the schema, fuzzing them if fuzzing rules are specified.
+func (request *Request) executeGraphQLRequest(input *contextargs.Context, dynamicValues output.InternalEvent, callback protocols.OutputEventCallback) error {
+	fuzzRequestCallback := request.newFuzzRequestCallback(input, nil, callback)
+	needsDiffBaseline := len(request.Fuzzing) > 0 && request.needsDiffBaseline()
+
+	generator := request.newGenerator(true)
+	for {
+		value, payloads, result := generator.nextValue()
+		if !result {
+			break
+		}
+		ctx := request.newContext(input)
+		generated, err := generator.Make(ctx, input, value, payloads, dynamicValues)
+		if err != nil {
+			if err == types.ErrNoMoreRequests {
+				return nil
+			}
+			return err
+		}
+		if generated.request == nil {
+			return errors.New("graphql requests cannot be unsafe")
+		}
+
+		schema, values := request.probeGraphQL(input, generated)
+		operations := []*graphql.Operation{{Type: "query", Field: "__typename", Query: "query { __typename }"}}
+		if schema != nil {
+			if generatedOperations := schema.Operations(); len(generatedOperations) > 0 {
+				operations = generatedOperations
+			}
+			values["graphql_fields"] = schema.FieldNames()
+			values["graphql_types"] = schema.TypeNames()
+		}
+		values["graphql_operations"] = len(operations)
+
+		for _, operation := range operations {
+			operationRequest, err := newGraphQLRequest(generated.request, graphql.NewBody(operation.Query, operation.Variables))
+			if err != nil {
+				return err
+			}
+			operationValues := generators.MergeMaps(generated.dynamicValues, values, map[string]interface{}{
+				"graphql_operation": operation.Type,
+				"graphql_field":     operation.Field,
+				"graphql_query":     operation.Query,
+			})
+
+			if len(request.Fuzzing) == 0 {
+				if !fuzzRequestCallback(fuzz.GeneratedRequest{Request: operationRequest, DynamicValues: operationValues}) {
+					return nil
+				}
+				continue
+			}
+			// mutations are compared with the response to the operation by diff matchers
+			operationCallback := fuzzRequestCallback
+			if needsDiffBaseline {
+				operationCallback = request.newFuzzRequestCallback(input, request.diffBaseline(input, operationRequest), callback)
+			}
+			for _, rule := range request.Fuzzing {
+				err = rule.Execute(&fuzz.ExecuteRuleInput{
+					Input:       input,
+					Callback:    operationCallback,
+					Values:      operationValues,
+					BaseRequest: operationRequest,
+					Timing:      request.Timing,
+				})
+				if err == types.ErrNoMoreRequests {
+					return nil
+				}
+				if err != nil {
+					// operations without arguments cannot be fuzzed
+					gologger.Verbose().Msgf("[%s] Could not fuzz graphql %s %s: %s\n", request.options.TemplateID, operation.Type, operation.Field, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// probeGraphQL introspects the schema of the endpoint and probes it for
+// common misconfigurations, returning the schema if introspection is enabled.
+func (request *Request) probeGraphQL(input *contextargs.Context, generated *generatedRequest) (*graphql.Schema, map[string]interface{}) {
+	values := make(map[string]interface{})
+
+	var schema *graphql.Schema
+	if body, err := request.sendGraphQLProbe(input, generated, graphql.NewBody(graphql.IntrospectionQuery, nil)); err == nil {
+		if schema, err = graphql.ParseIntrospection(body); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not introspect graphql schema: %s\n", request.options.TemplateID, err)
+		}
+	}
+	values[graphql.VariableIntrospection] = schema != nil
+
+	for _, probe := range graphql.Probes {
+		body, err := request.sendGraphQLProbe(input, generated, probe.Body)
+		values[probe.Name] = err == nil && probe.Check(body)
+	}
+	return schema, values
+}
+
+// sendGraphQLProbe sends a graphql request body to the endpoint of the generated request
+func (request *Request) sendGraphQLProbe(input *contextargs.Context, generated *generatedRequest, body []byte) ([]byte, error) {
+	probeRequest, err := newGraphQLRequest(generated.request, body)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(request.options.Options.Timeout)*time.Second)
+	defer cancel()
+	_, data, err := request.sendInputRequest(input, probeRequest.WithContext(ctx), maxGraphQLResponseSize)
+	return data, err
+}
+
+// newGraphQLRequest creates a POST request with a graphql body based on the generated request
+func newGraphQLRequest(base *retryablehttp.Request, body []byte) (*retryablehttp.Request, error) {
+	req, err := retryablehttp.NewRequestFromURLWithContext(base.Context(), http.MethodPost, base.URL.Clone(), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create graphql request")
+	}
+	req.Header = base.Header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.Request.Host = base.Request.Host
+	return req, nil
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/model"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators/matchers"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
+)
+
+func TestGraphQLRequest(t *testing.T) {
+	introspection, err := os.ReadFile("graphql/testdata/introspection.json")
+	require.Nil(t, err, "could not read introspection response")
+
+	var mutex sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			_, _ = w.Write([]byte(`[{"data":{"__typename":"Query"}},{"data":{"__typename":"Query"}}]`))
+			return
+		}
+		query, _ := body["query"].(string)
+		switch {
+		case strings.Contains(query, "__schema"):
+			if strings.HasPrefix(query, "query IntrospectionQuery") {
+				_, _ = w.Write(introspection)
+			} else {
+				_, _ = w.Write([]byte(`{"errors":[{"message":"query exceeds maximum depth"}]}`))
+			}
+		case strings.Contains(query, "__typenam"):
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Cannot query field \"__typenam\". Did you mean \"__typename\"?"}]}`))
+		default:
+			data, _ := json.Marshal(body["variables"])
+			mutex.Lock()
+			received = append(received, query)
+			mutex.Unlock()
+			_, _ = w.Write([]byte(`{"data":{"variables":` + string(data) + `}}`))
+		}
+	}))
+	defer ts.Close()
+
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+	templateID := "testing-graphql"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+
+	request := &Request{
+		ID:      templateID,
+		Path:    []string{"{{BaseURL}}/graphql"},
+		GraphQL: true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{`graphql_introspection == "true" && graphql_field_suggestions == "true" && graphql_batching == "true" && graphql_depth_limit == "true"`},
+			}},
+		},
+	}
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile graphql request")
+
+	var events []*output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), nil, nil, func(event *output.InternalWrappedEvent) {
+		events = append(events, event)
+	})
+	require.Nil(t, err, "could not execute graphql request")
+	require.Len(t, events, 3, "could not get an event for each operation")
+	require.Len(t, received, 3, "could not send each operation")
+	for _, event := range events {
+		require.True(t, event.OperatorsResult.Matched, "could not match graphql probes")
+	}
+	require.Equal(t, "user", events[0].InternalEvent["graphql_field"], "could not get operation field")
+
+	t.Run("fuzzing", func(t *testing.T) {
+		request := &Request{
+			ID:      templateID,
+			Path:    []string{"{{BaseURL}}/graphql"},
+			GraphQL: true,
+			Fuzzing: []*fuzz.Rule{{Part: "graphql", Type: "postfix", Keys: []string{"id"}, Fuzz: []string{"'"}}},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Part:  "body",
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Words: []string{`"id":"1'"`},
+				}},
+			},
+		}
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile graphql request")
+
+		var matched []string
+		err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), nil, nil, func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = append(matched, event.InternalEvent["graphql_field"].(string))
+			}
+		})
+		require.Nil(t, err, "could not execute graphql request")
+		require.Equal(t, []string{"user"}, matched, "could not fuzz graphql variables")
+	})
+}
+
+func TestGraphQLRequestSession(t *testing.T) {
+	introspection, err := os.ReadFile("graphql/testdata/introspection.json")
+	require.Nil(t, err, "could not read introspection response")
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`<html><title>Login</title></html>`))
+			return
+		}
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if query, _ := body["query"].(string); strings.HasPrefix(query, "query IntrospectionQuery") {
+			_, _ = w.Write(introspection)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":null}`))
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse url")
+	state := fmt.Sprintf(`{"cookies":[{"name":"session","value":"1","domain":%q,"path":"/","expires":-1}],"origins":[]}`, parsed.Hostname())
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.Nil(t, os.WriteFile(path, []byte(state), 0600), "could not write session file")
+
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+	templateID := "testing-graphql-session"
+	dir := t.TempDir()
+	run := func(store *replay.Store) []*output.InternalWrappedEvent {
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.Replay = store
+		executerOpts.Session, err = session.New(&session.Options{ImportPath: path})
+		require.Nil(t, err, "could not import session state")
+		request := &Request{
+			ID:      templateID,
+			Path:    []string{"{{BaseURL}}/graphql"},
+			GraphQL: true,
+		}
+		require.Nil(t, request.Compile(executerOpts), "could not compile graphql request")
+
+		var events []*output.InternalWrappedEvent
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), nil, nil, func(event *output.InternalWrappedEvent) {
+			events = append(events, event)
+		})
+		require.Nil(t, err, "could not execute graphql request")
+		require.Len(t, events, 3, "could not get an event for each operation")
+		return events
+	}
+
+	recorder, err := replay.New(&replay.Options{Path: dir})
+	require.Nil(t, err, "could not create recorder")
+	events := run(recorder)
+	require.Equal(t, "true", events[0].InternalEvent["graphql_introspection"], "could not introspect with session cookie")
+	require.Equal(t, "false", events[0].InternalEvent["graphql_depth_limit"], "could detect depth limit without error")
+	sent := requests.Load()
+
+	player, err := replay.New(&replay.Options{Path: dir, Replay: true})
+	require.Nil(t, err, "could not create player")
+	events = run(player)
+	require.Equal(t, "true", events[0].InternalEvent["graphql_introspection"], "could not replay introspection")
+	require.Equal(t, sent, requests.Load(), "could send graphql probes while replaying")
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"github.com/khulnasoft-lab/gologger"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/httpclientpool"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/race"
+)
+
+// raceResponse is the response to a request of a synchronised race condition attack
+type raceResponse struct {
+	*race.SyncedResponse
+	// index is the index of the request in the attack
+	index int
+	// timings contains the timings of all requests of the attack in milliseconds
+	timings []float64
+}
+
+// executeSyncedRaceRequest sends the generated requests synchronised by the race mode
+// of the request and processes their responses
+func (request *Request) executeSyncedRaceRequest(input *contextargs.Context, generatedRequests []*generatedRequest, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	for _, generatedRequest := range generatedRequests {
+		if generatedRequest.request == nil {
+			return errors.New("synchronised race requests require a http request")
+		}
+	}
+	// recorded responses are served by executeRequest without sending the attack
+	if request.options.Replay.Replaying() {
+		return request.processSyncedRaceResponses(input, generatedRequests, nil, previous, callback)
+	}
+
+	ctx := context.Background()
+	httpRequests := make([]*http.Request, 0, len(generatedRequests))
+	for _, generatedRequest := range generatedRequests {
+		// the requests are sent by the attack, the headers have to be set before cloning them
+		request.setCustomHeaders(generatedRequest)
+		request.setSessionState(input, generatedRequest)
+		if err := request.handleSignature(generatedRequest); err != nil {
+			return err
+		}
+		body, err := generatedRequest.request.BodyBytes()
+		if err != nil {
+			return err
+		}
+		httpRequest := generatedRequest.request.Request.Clone(ctx)
+		httpRequest.Body = io.NopCloser(bytes.NewReader(body))
+		httpRequest.ContentLength = int64(len(body))
+		httpRequests = append(httpRequests, httpRequest)
+	}
+
+	attack := &race.SyncedAttack{
+		Dialer:  httpclientpool.Dialer,
+		Timeout: time.Duration(request.options.Options.Timeout) * time.Second,
+		SNI:     request.options.Options.SNI,
+	}
+	var responses []*race.SyncedResponse
+	if request.RaceMode == race.SinglePacketMode {
+		var err error
+		if responses, err = attack.SinglePacket(ctx, httpRequests); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not send single-packet race requests to %s, using last-byte synchronisation: %s", request.options.TemplateID, input.MetaInput.Input, err)
+			responses = nil
+		}
+	}
+	if responses == nil {
+		responses = attack.LastByte(ctx, httpRequests)
+	}
+	return request.processSyncedRaceResponses(input, generatedRequests, responses, previous, callback)
+}
+
+// processSyncedRaceResponses processes the responses of a synchronised race condition
+// attack, the recorded responses are served when responses is nil.
+func (request *Request) processSyncedRaceResponses(input *contextargs.Context, generatedRequests []*generatedRequest, responses []*race.SyncedResponse, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	// failed requests have a negative timing
+	timings := make([]float64, len(responses))
+	for i, response := range responses {
+		timings[i] = -1
+		if response.Err == nil {
+			timings[i] = float64(response.Timing.Microseconds()) / 1000
+		}
+	}
+	var requestErr error
+	for i, generatedRequest := range generatedRequests {
+		if responses != nil {
+			generatedRequest.raceResponse = &raceResponse{SyncedResponse: responses[i], index: i, timings: timings}
+		}
+		if err := request.executeRequest(input, generatedRequest, previous, false, callback, 0); err != nil {
+			requestErr = multierr.Append(requestErr, err)
+		}
+		request.options.Progress.IncrementRequests()
+	}
+	return requestErr
+}
@@ -1,13 +1,27 @@
 package http
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/khulnasoft-lab/ratelimit"
 	"github.com/khulnasoft-lab/vulmap/pkg/model"
 	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
 	"github.com/khulnasoft-lab/vulmap/pkg/operators"
@@ -15,6 +29,11 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/operators/matchers"
 	"github.com/khulnasoft-lab/vulmap/pkg/output"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/fuzz"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/race"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
 	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
 )
 
@@ -184,3 +203,817 @@ func TestDisableTE(t *testing.T) {
 	require.NotNil(t, finalEvent, "could not get event output from request")
 	require.Equal(t, 2, matchCount, "could not get correct match count")
 }
+
+func TestConnectionErrorRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-connection-error"
+	request := &Request{
+		ID:     templateID,
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:   []string{"{{BaseURL}}"},
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	address := listener.Addr().String()
+	listener.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile http request")
+
+	err = request.ExecuteWithResults(contextargs.NewWithInput("http://"+address), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {})
+	require.Error(t, err, "could execute request to closed port")
+}
+
+func TestSyncedRaceRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-synced-race"
+
+	var mutex sync.Mutex
+	var protocols []string
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		protocols = append(protocols, r.Proto)
+		mutex.Unlock()
+		_, _ = w.Write([]byte("redeemed " + string(body)))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	for _, mode := range []string{race.SinglePacketMode, race.LastByteMode} {
+		t.Run(mode, func(t *testing.T) {
+			protocols = nil
+			request := &Request{
+				ID:                 templateID,
+				Method:             HTTPMethodTypeHolder{MethodType: HTTPPost},
+				Path:               []string{"{{BaseURL}}/redeem"},
+				Body:               "coupon=1",
+				Race:               true,
+				RaceNumberRequests: 5,
+				RaceMode:           mode,
+				Operators: operators.Operators{
+					Matchers: []*matchers.Matcher{{
+						Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+						Words: []string{"redeemed coupon=1"},
+					}},
+				},
+			}
+			executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+				ID:   templateID,
+				Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+			})
+			require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+			var matchCount int
+			indexes := map[interface{}]struct{}{}
+			err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+				if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+					matchCount++
+				}
+				indexes[event.InternalEvent["race_index"]] = struct{}{}
+				require.Len(t, event.InternalEvent["race_timings"], 5)
+			})
+			require.Nil(t, err, "could not execute http request")
+			require.Equal(t, 5, matchCount, "could not get correct match count")
+			require.Len(t, indexes, 5)
+
+			expectedProtocol := "HTTP/1.1"
+			if mode == race.SinglePacketMode {
+				expectedProtocol = "HTTP/2.0"
+			}
+			require.Equal(t, []string{expectedProtocol, expectedProtocol, expectedProtocol, expectedProtocol, expectedProtocol}, protocols)
+		})
+	}
+}
+
+func TestSyncedRaceRequestReplay(t *testing.T) {
+	options := *testutils.DefaultOptions
+	options.CustomHeaders = []string{"X-Custom: header"}
+
+	testutils.Init(&options)
+	templateID := "http-synced-race-replay"
+
+	var mutex sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		received = append(received, r.Header.Get("X-Custom"))
+		mutex.Unlock()
+		_, _ = w.Write([]byte("redeemed"))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	run := func(store *replay.Store) int {
+		request := &Request{
+			ID:                 templateID,
+			Path:               []string{"{{BaseURL}}/redeem"},
+			Race:               true,
+			RaceNumberRequests: 3,
+			RaceMode:           race.LastByteMode,
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Words: []string{"redeemed"},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(&options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.Replay = store
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var matchCount int
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matchCount++
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		return matchCount
+	}
+
+	recorder, err := replay.New(&replay.Options{Path: dir})
+	require.Nil(t, err, "could not create recorder")
+	require.Equal(t, 3, run(recorder), "could not get correct match count")
+	require.Equal(t, []string{"header", "header", "header"}, received, "could not send custom headers")
+
+	player, err := replay.New(&replay.Options{Path: dir, Replay: true})
+	require.Nil(t, err, "could not create player")
+	require.Equal(t, 3, run(player), "could not get correct replayed match count")
+	require.Len(t, received, 3, "could send race requests while replaying")
+}
+
+func TestDesyncRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-desync"
+
+	// backend which only supports transfer-encoding ignoring content-length
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					requestLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					headers := map[string]string{}
+					for {
+						line, err := reader.ReadString('\n')
+						if err != nil {
+							return
+						}
+						if line = strings.TrimSpace(line); line == "" {
+							break
+						}
+						if name, value, ok := strings.Cut(line, ":"); ok {
+							headers[strings.ToLower(name)] = strings.TrimSpace(value)
+						}
+					}
+					if headers["transfer-encoding"] == "chunked" {
+						for {
+							line, err := reader.ReadString('\n')
+							if err != nil {
+								return
+							}
+							size, _ := strconv.ParseInt(strings.TrimSpace(line), 16, 64)
+							if _, err := io.CopyN(io.Discard, reader, size+2); err != nil {
+								return
+							}
+							if size == 0 {
+								break
+							}
+						}
+					} else if length, _ := strconv.Atoi(headers["content-length"]); length > 0 {
+						if _, err := io.CopyN(io.Discard, reader, int64(length)); err != nil {
+							return
+						}
+					}
+					status := "200 OK"
+					if method, _, _ := strings.Cut(requestLine, " "); method != http.MethodGet && method != http.MethodPost {
+						status = "405 Method Not Allowed"
+					}
+					_, _ = fmt.Fprintf(conn, "HTTP/1.1 %s\r\nContent-Length: 2\r\n\r\nok", status)
+				}
+			}(conn)
+		}
+	}()
+
+	followUp := "GET / HTTP/1.1\r\nHost: {{Hostname}}\r\n\r\n"
+	t.Run("differential", func(t *testing.T) {
+		request := &Request{
+			ID: templateID,
+			Raw: []string{
+				"POST / HTTP/1.1\r\nHost: {{Hostname}}\r\nContent-Length: 6\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nG",
+				followUp,
+			},
+			Desync: true,
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+					DSL:  []string{"desync_index == 1 && status_code != desync_baseline_code"},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var events []output.InternalEvent
+		var matched bool
+		err := request.ExecuteWithResults(contextargs.NewWithInput("http://"+listener.Addr().String()), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			events = append(events, event.InternalEvent)
+			matched = matched || (event.OperatorsResult != nil && event.OperatorsResult.Matched)
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Len(t, events, 2)
+		require.True(t, matched, "could not detect desync")
+		require.Equal(t, []int{200, 405}, events[1]["desync_status_codes"])
+		require.Equal(t, []int{0, 0}, events[1]["desync_connections"])
+		require.Equal(t, 200, events[1]["desync_baseline_code"])
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		request := &Request{
+			ID: templateID,
+			Raw: []string{
+				"POST / HTTP/1.1\r\nHost: {{Hostname}}\r\nContent-Length: 10\r\n\r\nx",
+				followUp,
+			},
+			Desync:        true,
+			DesyncTimeout: 1,
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+					DSL:  []string{"desync_timeout && duration >= 1"},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var events []output.InternalEvent
+		err := request.ExecuteWithResults(contextargs.NewWithInput("http://"+listener.Addr().String()), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			events = append(events, event.InternalEvent)
+			if event.InternalEvent["desync_index"] == 0 {
+				require.True(t, event.OperatorsResult != nil && event.OperatorsResult.Matched, "could not detect timeout")
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Len(t, events, 2)
+		require.Equal(t, []int{0, 200}, events[1]["desync_status_codes"])
+		require.Equal(t, []int{0, 1}, events[1]["desync_connections"])
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		dir := t.TempDir()
+		run := func(store *replay.Store) []output.InternalEvent {
+			request := &Request{
+				ID: templateID,
+				Raw: []string{
+					"POST / HTTP/1.1\r\nHost: {{Hostname}}\r\nContent-Length: 6\r\nTransfer-Encoding: chunked\r\n\r\n0\r\n\r\nG",
+					followUp,
+				},
+				Desync: true,
+			}
+			executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+				ID:   templateID,
+				Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+			})
+			executerOpts.Replay = store
+			require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+			var events []output.InternalEvent
+			err := request.ExecuteWithResults(contextargs.NewWithInput("http://"+listener.Addr().String()), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+				events = append(events, event.InternalEvent)
+			})
+			require.Nil(t, err, "could not execute http request")
+			require.Len(t, events, 2)
+			return events
+		}
+
+		recorder, err := replay.New(&replay.Options{Path: dir})
+		require.Nil(t, err, "could not create recorder")
+		recorded := run(recorder)
+		connections := accepted.Load()
+
+		player, err := replay.New(&replay.Options{Path: dir, Replay: true})
+		require.Nil(t, err, "could not create player")
+		replayed := run(player)
+		require.Equal(t, connections, accepted.Load(), "could open connections while replaying")
+		for _, name := range []string{"desync_status_codes", "desync_connections", "desync_baseline_code", "desync_timings", "status_code", "body"} {
+			require.Equal(t, recorded[1][name], replayed[1][name], "could not replay %s", name)
+		}
+	})
+
+	t.Run("h2", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []string
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			received = append(received, fmt.Sprintf("%s %s %s", r.Proto, r.Method, r.URL.Path))
+			mu.Unlock()
+			_, _ = fmt.Fprint(w, "ok")
+		}))
+		ts.EnableHTTP2 = true
+		ts.StartTLS()
+		defer ts.Close()
+
+		request := &Request{
+			ID: templateID,
+			Raw: []string{
+				// the body exceeds the declared length, which http2 servers reject
+				"POST /probe HTTP/1.1\r\nHost: {{Hostname}}\r\nContent-Length: 0\r\n\r\nGET /smuggled HTTP/1.1\r\nX: x",
+				"GET /follow-up HTTP/1.1\r\nHost: {{Hostname}}\r\n\r\n",
+			},
+			Desync:         true,
+			DesyncProtocol: "h2",
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var events []output.InternalEvent
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			events = append(events, event.InternalEvent)
+		})
+		require.NotNil(t, err, "could not reset stream of request with mismatching length")
+		require.Len(t, events, 1)
+		require.Equal(t, []int{0, 200}, events[0]["desync_status_codes"])
+		require.Equal(t, []int{0, 0}, events[0]["desync_connections"], "could not send probe on single http2 connection")
+		require.Equal(t, 200, events[0]["desync_baseline_code"])
+		mu.Lock()
+		defer mu.Unlock()
+		require.ElementsMatch(t, []string{"HTTP/2.0 GET /follow-up", "HTTP/2.0 POST /probe", "HTTP/2.0 GET /follow-up"}, received)
+	})
+}
+
+func TestSameConnectionRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-same-connection"
+
+	var mutex sync.Mutex
+	connections := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		connections[r.RemoteAddr]++
+		count := connections[r.RemoteAddr]
+		mutex.Unlock()
+		if r.URL.Path == "/close" {
+			w.Header().Set("Connection", "close")
+		}
+		_, _ = fmt.Fprintf(w, "request %d on connection", count)
+	}))
+	defer ts.Close()
+
+	request := &Request{
+		ID:             templateID,
+		Path:           []string{"{{BaseURL}}/first", "{{BaseURL}}/close", "{{BaseURL}}/last"},
+		SameConnection: true,
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{`contains(resp_2, "request 2 on connection") && resp_2_reused && !resp_3_reused && resp_3_status_code == 200`},
+			}},
+		},
+	}
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+	var events []output.InternalEvent
+	var matchCount int
+	err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		events = append(events, event.InternalEvent)
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matchCount++
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Len(t, events, 3)
+	require.Equal(t, 3, matchCount, "could not get correct match count")
+	require.Equal(t, []interface{}{0, 0, 1}, []interface{}{events[0]["connection_index"], events[1]["connection_index"], events[2]["connection_index"]})
+	require.Equal(t, "request 1 on connection", events[2]["body"])
+	require.Len(t, connections, 2)
+}
+
+func TestSameConnectionCookieRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-same-connection-cookie"
+
+	var mutex sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		received = append(received, r.URL.Path+" "+r.Header.Get("Cookie"))
+		mutex.Unlock()
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "token", Value: "abc", Path: "/"})
+		}
+		_, _ = fmt.Fprintf(w, "cookies: %s", r.Header.Get("Cookie"))
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse url")
+	state := fmt.Sprintf(`{"cookies":[{"name":"session","value":"1","domain":%q,"path":"/","expires":-1}],"origins":[]}`, parsed.Hostname())
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.Nil(t, os.WriteFile(path, []byte(state), 0600), "could not write session file")
+
+	dir := t.TempDir()
+	run := func(store *replay.Store) []output.InternalEvent {
+		request := &Request{
+			ID:             templateID,
+			Path:           []string{"{{BaseURL}}/login", "{{BaseURL}}/account"},
+			SameConnection: true,
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.Replay = store
+		executerOpts.Session, err = session.New(&session.Options{ImportPath: path})
+		require.Nil(t, err, "could not import session state")
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var events []output.InternalEvent
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			events = append(events, event.InternalEvent)
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Len(t, events, 2)
+		return events
+	}
+
+	recorder, err := replay.New(&replay.Options{Path: dir})
+	require.Nil(t, err, "could not create recorder")
+	recorded := run(recorder)
+	require.Equal(t, []string{"/login session=1", "/account session=1; token=abc"}, received, "could not send cookies of previous responses")
+	require.Equal(t, "cookies: session=1; token=abc", recorded[1]["body"])
+
+	player, err := replay.New(&replay.Options{Path: dir, Replay: true})
+	require.Nil(t, err, "could not create player")
+	replayed := run(player)
+	require.Len(t, received, 2, "could send requests while replaying")
+	require.Equal(t, recorded[1]["body"], replayed[1]["body"], "could not replay same-connection sequence")
+	require.Equal(t, recorded[1]["resp_2"], replayed[1]["resp_2"], "could not replay same-connection sequence")
+}
+
+func TestDiffMatcherRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-diff"
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		id := r.URL.Query().Get("id")
+		switch {
+		case strings.Contains(id, "'"):
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, "You have an error in your SQL syntax")
+		case strings.HasSuffix(id, "AND 1=2"):
+			_, _ = fmt.Fprintf(w, "<html><title>Items</title><p>No results (request %d)</p></html>", requests)
+		default:
+			_, _ = fmt.Fprintf(w, "<html><title>Items</title><ul><li>Coffee mug</li><li>Tea pot</li><li>Milk jug</li></ul><p>request %d</p></html>", requests)
+		}
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+
+	t.Run("fuzzing", func(t *testing.T) {
+		request := &Request{
+			ID:      templateID,
+			Path:    []string{"{{BaseURL}}"},
+			Fuzzing: []*fuzz.Rule{{Part: "query", Type: "postfix", Fuzz: []string{"'", " AND 1=1", " AND 1=2"}}},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type: matchers.MatcherTypeHolder{MatcherType: matchers.DiffMatcher},
+					Diff: &matchers.Diff{Status: true, MaxSimilarity: 0.8},
+				}},
+			},
+		}
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var matched []string
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL+"/items?id=1"), nil, nil, func(event *output.InternalWrappedEvent) {
+			require.Equal(t, 200, event.InternalEvent["baseline_status_code"])
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = append(matched, event.InternalEvent["matched"].(string))
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Len(t, matched, 2, "could not match mutations differing from baseline")
+		for _, url := range matched {
+			require.NotContains(t, url, "1%3D1", "matched mutation equal to baseline")
+		}
+	})
+
+	t.Run("previous-request", func(t *testing.T) {
+		request := &Request{
+			ID:   templateID,
+			Path: []string{"{{BaseURL}}/items?id=1", "{{BaseURL}}/items?id=1%20AND%201=2"},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Type: matchers.MatcherTypeHolder{MatcherType: matchers.DiffMatcher},
+					Diff: &matchers.Diff{Baseline: 1, MaxSimilarity: 0.8},
+				}},
+			},
+		}
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var matched []string
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			require.NotContains(t, event.InternalEvent, "baseline_status_code")
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = append(matched, event.InternalEvent["matched"].(string))
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Equal(t, []string{ts.URL + "/items?id=1%20AND%201=2"}, matched)
+	})
+}
+
+func TestDiffMatcherSessionRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-diff-session"
+
+	var mu sync.Mutex
+	var baselineCookies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		if id == "1" {
+			mu.Lock()
+			baselineCookies = append(baselineCookies, r.Header.Get("Cookie"))
+			mu.Unlock()
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, "<html><title>Login</title><form><input name=password></form></html>")
+			return
+		}
+		if strings.HasSuffix(id, "'") {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, "You have an error in your SQL syntax")
+			return
+		}
+		_, _ = fmt.Fprint(w, "<html><title>Items</title><ul><li>Coffee mug</li><li>Tea pot</li></ul></html>")
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse url")
+	state := fmt.Sprintf(`{"cookies":[{"name":"session","value":"1","domain":%q,"path":"/","expires":-1}],"origins":[]}`, parsed.Hostname())
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.Nil(t, os.WriteFile(path, []byte(state), 0600), "could not write session file")
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.Session, err = session.New(&session.Options{ImportPath: path})
+	require.Nil(t, err, "could not import session state")
+	request := &Request{
+		ID:      templateID,
+		Path:    []string{"{{BaseURL}}"},
+		Fuzzing: []*fuzz.Rule{{Part: "query", Type: "postfix", Fuzz: []string{"'"}}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DiffMatcher},
+				Diff: &matchers.Diff{Status: true, MaxSimilarity: 0.8},
+			}},
+		},
+	}
+	require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+	var matched int
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL+"/items?id=1"), nil, nil, func(event *output.InternalWrappedEvent) {
+		require.Equal(t, 200, event.InternalEvent["baseline_status_code"], "could not send baseline with session cookie")
+		if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+			matched++
+		}
+	})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, 1, matched, "could not match mutation differing from authenticated baseline")
+	require.Equal(t, []string{"session=1"}, baselineCookies, "could not send session cookie with baseline")
+}
+
+func TestTimingRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-timing"
+
+	sleep := regexp.MustCompile(`SLEEP\((\d+)\)`)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// only the id parameter is injectable
+		if match := sleep.FindStringSubmatch(r.URL.Query().Get("id")); match != nil {
+			seconds, _ := strconv.Atoi(match[1])
+			time.Sleep(time.Duration(seconds) * time.Second)
+		}
+		_, _ = fmt.Fprint(w, "<html><title>Items</title></html>")
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	timingMatchers := operators.Operators{
+		Matchers: []*matchers.Matcher{{
+			Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+			DSL:  []string{"timing_confirmed"},
+		}},
+	}
+
+	t.Run("path", func(t *testing.T) {
+		request := &Request{
+			ID:        templateID,
+			Path:      []string{"{{BaseURL}}/items?id=1%20AND%20SLEEP({{delay}})"},
+			Timing:    &timing.Config{Delays: []int{1}},
+			Operators: timingMatchers,
+		}
+		// each of the four requests of the analysis takes a single token of the rate limit
+		rateLimiter := executerOpts.RateLimiter
+		executerOpts.RateLimiter = ratelimit.New(context.Background(), 5, time.Hour)
+		defer func() {
+			executerOpts.RateLimiter.Stop()
+			executerOpts.RateLimiter = rateLimiter
+		}()
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var events []*output.InternalWrappedEvent
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			events = append(events, event)
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Len(t, events, 1, "could not get single event for timing analysis")
+		require.True(t, events[0].OperatorsResult.Matched, "could not match confirmed delay")
+		require.Greater(t, events[0].InternalEvent["timing_confidence"], 0.9, "could not get timing confidence")
+		require.Equal(t, []int{0, 1, 0, 1}, events[0].InternalEvent["timing_delays"])
+		require.True(t, executerOpts.RateLimiter.CanTake(), "could not take a single token for each timing request")
+	})
+
+	t.Run("fuzzing", func(t *testing.T) {
+		request := &Request{
+			ID:        templateID,
+			Path:      []string{"{{BaseURL}}"},
+			Fuzzing:   []*fuzz.Rule{{Part: "query", Type: "postfix", Mode: "single", Fuzz: []string{" AND SLEEP({{delay}})"}}},
+			Timing:    &timing.Config{Delays: []int{1}},
+			Operators: timingMatchers,
+		}
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var matched []string
+		var confirmed []bool
+		err := request.ExecuteWithResults(contextargs.NewWithInput(ts.URL+"/items?id=1&name=mug"), nil, nil, func(event *output.InternalWrappedEvent) {
+			confirmed = append(confirmed, event.InternalEvent["timing_confirmed"].(bool))
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = append(matched, event.InternalEvent["matched"].(string))
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		require.Equal(t, []bool{true, false}, confirmed, "could not analyse fuzzed parameters")
+		require.Len(t, matched, 1, "could not match injectable parameter")
+		require.Contains(t, matched[0], "id=1+AND+SLEEP(1)", "could not match delayed request")
+	})
+}
+
+func TestFuzzingInputRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-fuzzing-input-request"
+
+	var mu sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, fmt.Sprintf("%s %s %s %s", r.Method, r.URL.RawQuery, r.Header.Get("Cookie"), body))
+		mu.Unlock()
+		_, _ = fmt.Fprint(w, "<html><title>Items</title></html>")
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	request := &Request{
+		ID:      templateID,
+		Path:    []string{"{{BaseURL}}"},
+		Fuzzing: []*fuzz.Rule{{Part: "query", Type: "postfix", Fuzz: []string{"'"}}},
+	}
+	require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+	input := contextargs.NewWithInput(ts.URL + "/items?id=1")
+	input.MetaInput.Request = &contextargs.Request{
+		Method:  http.MethodPost,
+		Headers: map[string]string{"Cookie": "session=1", "Content-Type": "application/x-www-form-urlencoded"},
+		Body:    "name=mug",
+	}
+	err := request.ExecuteWithResults(input, nil, nil, func(event *output.InternalWrappedEvent) {})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, []string{"POST id=1' session=1 name=mug"}, received, "could not fuzz input request")
+}
+
+func TestSessionImportRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-session-import-request"
+
+	var mu sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, fmt.Sprintf("%s %s %s", r.Header.Get("Authorization"), r.Header.Get("X-Csrf-Token"), strings.Join(r.Header.Values("Cookie"), ",")))
+		mu.Unlock()
+		_, _ = fmt.Fprint(w, "<html><title>Dashboard</title></html>")
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse url")
+	state := fmt.Sprintf(`{"cookies":[{"name":"session","value":"1","domain":%q,"path":"/","expires":-1}],"origins":[],"headers":{%q:{"Authorization":"Bearer token","X-Csrf-Token":"csrf"}}}`, parsed.Hostname(), parsed.Host)
+	path := filepath.Join(t.TempDir(), "session.json")
+	require.Nil(t, os.WriteFile(path, []byte(state), 0600), "could not write session file")
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.Session, err = session.New(&session.Options{ImportPath: path})
+	require.Nil(t, err, "could not import session state")
+	request := &Request{
+		ID:      templateID,
+		Path:    []string{"{{BaseURL}}"},
+		Headers: map[string]string{"X-Csrf-Token": "template"},
+	}
+	require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+	err = request.ExecuteWithResults(contextargs.NewWithInput(ts.URL), nil, nil, func(event *output.InternalWrappedEvent) {})
+	require.Nil(t, err, "could not execute http request")
+	require.Equal(t, []string{"Bearer token template session=1"}, received, "could not import session state")
+
+	// raw requests keep the cookies of the template with a single cookie header
+	received = nil
+	rawRequest := &Request{
+		ID:     templateID,
+		Unsafe: true,
+		Raw: []string{`GET / HTTP/1.1
+Host: {{Hostname}}
+cookie: sid=template
+
+`},
+	}
+	require.Nil(t, rawRequest.Compile(executerOpts), "could not compile http raw request")
+	err = rawRequest.ExecuteWithResults(contextargs.NewWithInput(ts.URL), nil, nil, func(event *output.InternalWrappedEvent) {})
+	require.Nil(t, err, "could not execute http raw request")
+	require.Equal(t, []string{"Bearer token csrf sid=template; session=1"}, received, "could not import session state in raw request")
+}
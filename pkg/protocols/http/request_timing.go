@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/khulnasoft-lab/retryablehttp-go"
+	readerutil "github.com/khulnasoft-lab/utils/reader"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
+)
+
+// executeTimingRequest sends the requests built for each delay of the timing analysis
+// and returns the event of the last request along with the results of the analysis.
+func (request *Request) executeTimingRequest(input *contextargs.Context, build func(ctx context.Context, delay int) (*generatedRequest, error), previous output.InternalEvent, callback protocols.OutputEventCallback, requestCount int) error {
+	return request.Timing.Execute(request, request.options, func(delay int, callback protocols.OutputEventCallback) (time.Duration, map[string]interface{}, error) {
+		request.options.RateLimiter.Take()
+		generatedRequest, err := build(request.newContext(input), delay)
+		if err != nil {
+			return 0, nil, err
+		}
+		if generatedRequest.customCancelFunction != nil {
+			defer generatedRequest.customCancelFunction()
+		}
+
+		var latency time.Duration
+		err = request.executeRequest(input, generatedRequest, previous, false, func(event *output.InternalWrappedEvent) {
+			latency = timing.Latency(event.InternalEvent)
+			callback(event)
+		}, requestCount)
+		request.options.Progress.IncrementRequests()
+		return latency, generatedRequest.meta, err
+	}, callback)
+}
+
+// cloneRequest clones the request along with its body so it can be sent again
+func cloneRequest(ctx context.Context, req *retryablehttp.Request) (*retryablehttp.Request, error) {
+	body, err := req.BodyBytes()
+	if err != nil {
+		return nil, err
+	}
+	cloned := req.Clone(ctx)
+	if len(body) > 0 {
+		if cloned.Body, err = readerutil.NewReusableReadCloser(body); err != nil {
+			return nil, err
+		}
+		cloned.ContentLength = int64(len(body))
+	}
+	return cloned, nil
+}
@@ -1,6 +1,11 @@
 package http
 
-import "github.com/pkg/errors"
+import (
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/desync"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http/race"
+)
 
 func (request *Request) validate() error {
 	if request.Race && request.NeedsRequestCondition() {
@@ -11,5 +16,56 @@ func (request *Request) validate() error {
 		return errors.New("'redirects' and 'host-redirects' can't be used together")
 	}
 
+	if request.GraphQL && (request.Unsafe || request.Pipeline || request.Race) {
+		return errors.New("'graphql' can't be used with 'unsafe', 'pipeline' or 'race'")
+	}
+
+	switch request.RaceMode {
+	case "":
+	case race.LastByteMode, race.SinglePacketMode:
+		if !request.Race {
+			return errors.New("'race-mode' requires 'race'")
+		}
+		if request.Unsafe || request.Pipeline || request.Threads > 0 {
+			return errors.New("'race-mode' can't be used with 'unsafe', 'pipeline' or 'threads'")
+		}
+	default:
+		return errors.Errorf("invalid 'race-mode' %s (last-byte, single-packet)", request.RaceMode)
+	}
+
+	if request.Desync {
+		if len(request.Raw) == 0 {
+			return errors.New("'desync' requires 'raw' requests")
+		}
+		if request.Race || request.Pipeline || request.Threads > 0 || len(request.Fuzzing) > 0 {
+			return errors.New("'desync' can't be used with 'race', 'pipeline', 'threads' or 'fuzzing'")
+		}
+		switch request.DesyncProtocol {
+		case "", desync.HTTP1, desync.HTTP2:
+		default:
+			return errors.Errorf("invalid 'desync-protocol' %s (http/1.1, h2)", request.DesyncProtocol)
+		}
+	} else if request.DesyncTimeout != 0 || request.DesyncProtocol != "" {
+		return errors.New("'desync-timeout' and 'desync-protocol' require 'desync'")
+	}
+
+	if request.SameConnection {
+		if request.Desync {
+			return errors.New("'same-connection' can't be used with 'desync' which already sends the requests on the same connection")
+		}
+		if request.Race || request.Pipeline || request.Threads > 0 || len(request.Fuzzing) > 0 {
+			return errors.New("'same-connection' can't be used with 'race', 'pipeline', 'threads' or 'fuzzing'")
+		}
+	}
+
+	if request.Timing != nil {
+		if request.Race || request.Pipeline || request.Threads > 0 || request.Desync || request.SameConnection {
+			return errors.New("'timing' can't be used with 'race', 'pipeline', 'threads', 'desync' or 'same-connection'")
+		}
+		if err := request.Timing.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
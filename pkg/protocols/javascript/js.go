@@ -27,8 +27,10 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/helpers/eventcreator"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/interactsh"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
 	protocolutils "github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	errorutil "github.com/khulnasoft-lab/utils/errors"
@@ -84,6 +86,13 @@ type Request struct {
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the webosocket request,description=Payloads contains any payloads for the current request"`
+	// description: |
+	//   Timing confirms time-based blind payloads by statistical analysis of response latencies.
+	//
+	//   The code is executed repeatedly with the delay variable set to 0 as control and to each
+	//   delay, the delay is confirmed if the execution time increases by the delay. The event of
+	//   the last execution contains timing_confidence and timing_confirmed along with the latency model.
+	Timing *timing.Config `yaml:"timing,omitempty" json:"timing,omitempty" jsonschema:"title=timing analysis of blind payloads,description=Confirms time-based blind payloads by statistical analysis of response latencies"`
 
 	generator *generators.PayloadGenerator
 
@@ -95,6 +104,11 @@ type Request struct {
 func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	request.options = options
 
+	// javascript requests are not recorded, so replayed scans would send them
+	if options.Replay.Replaying() {
+		return errors.Wrap(replay.ErrNotReplayable, "javascript")
+	}
+
 	var err error
 	if len(request.Payloads) > 0 {
 		request.generator, err = generators.New(request.Payloads, request.AttackType.Value, request.options.TemplatePath, options.Catalog, options.Options.AttackType, options.Options)
@@ -103,6 +117,15 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		}
 	}
 
+	if request.Timing != nil {
+		if request.Threads > 1 {
+			return errorutil.NewWithTag(request.TemplateID, "'timing' can't be used with 'threads'")
+		}
+		if err := request.Timing.Validate(); err != nil {
+			return errorutil.NewWithTag(request.TemplateID, "invalid timing: %v", err)
+		}
+	}
+
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
@@ -323,6 +346,11 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 		return nil
 	}
 
+	executeRequest := request.executeRequestWithPayloads
+	if request.Timing != nil {
+		executeRequest = request.executeTimingRequest
+	}
+
 	var gotMatches bool
 	if request.generator != nil {
 		iterator := request.generator.NewIterator()
@@ -333,7 +361,7 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 				return nil
 			}
 
-			if err := request.executeRequestWithPayloads(hostPort, input, hostname, value, payloadValues, func(result *output.InternalWrappedEvent) {
+			if err := executeRequest(hostPort, input, hostname, value, payloadValues, func(result *output.InternalWrappedEvent) {
 				if result.OperatorsResult != nil && result.OperatorsResult.Matched {
 					gotMatches = true
 					request.options.Progress.IncrementMatched()
@@ -353,7 +381,7 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 			}
 		}
 	}
-	return request.executeRequestWithPayloads(hostPort, input, hostname, nil, payloadValues, callback, requestOptions)
+	return executeRequest(hostPort, input, hostname, nil, payloadValues, callback, requestOptions)
 }
 
 func (request *Request) executeRequestParallel(ctxParent context.Context, hostPort, hostname string, input *contextargs.Context, payloadValues map[string]interface{}, callback protocols.OutputEventCallback) {
@@ -558,10 +586,18 @@ mainLoop:
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"type":     "Type is the type of request made",
-	"response": "Javascript protocol result response",
-	"host":     "Host is the input to the template",
-	"matched":  "Matched is the input which was matched upon",
+	"type":              "Type is the type of request made",
+	"response":          "Javascript protocol result response",
+	"host":              "Host is the input to the template",
+	"matched":           "Matched is the input which was matched upon",
+	"timing_confidence": "Confidence between 0 and 1 that the payload delays the response in timing analysis",
+	"timing_confirmed":  "Whether the delay was confirmed by timing analysis",
+	"timing_slope":      "Increase of the latency in seconds for each second of delay in timing analysis",
+	"timing_r_squared":  "Coefficient of determination of the latency model of timing analysis",
+	"timing_baseline":   "Mean latency in seconds of the control requests of timing analysis",
+	"timing_deviation":  "Standard deviation of the latency in seconds of the control requests of timing analysis",
+	"timing_delays":     "Delays in seconds of the requests of timing analysis in order",
+	"timing_latencies":  "Latencies in seconds of the requests of timing analysis in order",
 }
 
 // getAddress returns the address of the host to make request to
@@ -0,0 +1,35 @@
+package javascript
+
+import (
+	"context"
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/protocolstate"
+)
+
+// executeTimingRequest executes the code with each delay of the timing analysis, the
+// time to connect to the input is excluded from the latency of the executions.
+func (request *Request) executeTimingRequest(hostPort string, input *contextargs.Context, hostname string, payload map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback, requestOptions *protocols.ExecutorOptions) error {
+	return request.Timing.Execute(request, request.options, func(delay int, callback protocols.OutputEventCallback) (time.Duration, map[string]interface{}, error) {
+		values := request.Timing.Values(payload, delay)
+		connect := connectTime(hostPort)
+		start := time.Now()
+		err := request.executeRequestWithPayloads(hostPort, input, hostname, values, previous, callback, requestOptions)
+		return max(time.Since(start)-connect, 0), values, err
+	}, callback)
+}
+
+// connectTime returns the time to connect to the address, the connections are
+// opened by the scripts so the time is measured with a separate connection.
+func connectTime(address string) time.Duration {
+	start := time.Now()
+	conn, err := protocolstate.Dialer.Dial(context.Background(), "tcp", address)
+	if err != nil {
+		return 0
+	}
+	defer conn.Close()
+	return time.Since(start)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/expressions"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/generators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/network/networkclientpool"
 	fileutil "github.com/khulnasoft-lab/utils/file"
 )
@@ -69,6 +70,14 @@ type Request struct {
 	// examples:
 	//   - value: false
 	ReadAll bool `yaml:"read-all,omitempty" json:"read-all,omitempty" jsonschema:"title=read all response stream,description=Read all response stream till the server stops sending"`
+	// description: |
+	//   Timing confirms time-based blind payloads by statistical analysis of response latencies.
+	//
+	//   The inputs are sent repeatedly with the delay variable set to 0 as control and to each
+	//   delay, the delay is confirmed if the latency increases by the delay. Delays must be lower
+	//   than the read timeout of 5 seconds. The event of the last request contains timing_confidence
+	//   and timing_confirmed along with the latency model.
+	Timing *timing.Config `yaml:"timing,omitempty" json:"timing,omitempty" jsonschema:"title=timing analysis of blind payloads,description=Confirms time-based blind payloads by statistical analysis of response latencies"`
 
 	// description: |
 	//   SelfContained specifies if the request is self-contained.
@@ -88,15 +97,24 @@ type Request struct {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":   "ID of the template executed",
-	"template-info": "Info Block of the template executed",
-	"template-path": "Path of the template executed",
-	"host":          "Host is the input to the template",
-	"matched":       "Matched is the input which was matched upon",
-	"type":          "Type is the type of request made",
-	"request":       "Network request made from the client",
-	"body,all,data": "Network response received from server (default)",
-	"raw":           "Full Network protocol data",
+	"template-id":       "ID of the template executed",
+	"template-info":     "Info Block of the template executed",
+	"template-path":     "Path of the template executed",
+	"host":              "Host is the input to the template",
+	"matched":           "Matched is the input which was matched upon",
+	"type":              "Type is the type of request made",
+	"request":           "Network request made from the client",
+	"body,all,data":     "Network response received from server (default)",
+	"raw":               "Full Network protocol data",
+	"duration":          "Time in seconds between the first write and the last read of the connection",
+	"timing_confidence": "Confidence between 0 and 1 that the payload delays the response in timing analysis",
+	"timing_confirmed":  "Whether the delay was confirmed by timing analysis",
+	"timing_slope":      "Increase of the latency in seconds for each second of delay in timing analysis",
+	"timing_r_squared":  "Coefficient of determination of the latency model of timing analysis",
+	"timing_baseline":   "Mean latency in seconds of the control requests of timing analysis",
+	"timing_deviation":  "Standard deviation of the latency in seconds of the control requests of timing analysis",
+	"timing_delays":     "Delays in seconds of the requests of timing analysis in order",
+	"timing_latencies":  "Latencies in seconds of the requests of timing analysis in order",
 }
 
 type addressKV struct {
@@ -205,6 +223,12 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	}
 	request.dialer = client
 
+	if request.Timing != nil {
+		if err := request.Timing.Validate(); err != nil {
+			return errors.Wrap(err, "invalid timing")
+		}
+	}
+
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
@@ -84,6 +84,11 @@ func (request *Request) responseToDSLMap(req, resp, raw, host, matched string) o
 	}
 }
 
+// FixtureToDSLMap converts raw network response bytes to a map for use in DSL matching
+func (request *Request) FixtureToDSLMap(fixture string) (output.InternalEvent, error) {
+	return request.responseToDSLMap("", fixture, fixture, "", ""), nil
+}
+
 // MakeResultEvent creates a result event from internal wrapped event
 func (request *Request) MakeResultEvent(wrapped *output.InternalWrappedEvent) []*output.ResultEvent {
 	return protocols.MakeDefaultResultEvent(request, wrapped)
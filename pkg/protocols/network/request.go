@@ -37,6 +37,9 @@ var (
 	DefaultReadTimeout = time.Duration(5) * time.Second
 )
 
+// replayProtocol is the protocol network exchanges are recorded as
+const replayProtocol = "network"
+
 var _ protocols.Request = &Request{}
 
 // Type returns the type of the protocol request
@@ -105,6 +108,11 @@ func (request *Request) executeAddress(variables map[string]interface{}, actualA
 		return err
 	}
 
+	executeRequest := request.executeRequestWithPayloads
+	if request.Timing != nil {
+		executeRequest = request.executeTimingRequest
+	}
+
 	if request.generator != nil {
 		iterator := request.generator.NewIterator()
 
@@ -114,13 +122,13 @@ func (request *Request) executeAddress(variables map[string]interface{}, actualA
 				break
 			}
 			value = generators.MergeMaps(value, payloads)
-			if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, shouldUseTLS, value, previous, callback); err != nil {
+			if err := executeRequest(variables, actualAddress, address, input, shouldUseTLS, value, previous, callback); err != nil {
 				return err
 			}
 		}
 	} else {
 		value := maps.Clone(payloads)
-		if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, shouldUseTLS, value, previous, callback); err != nil {
+		if err := executeRequest(variables, actualAddress, address, input, shouldUseTLS, value, previous, callback); err != nil {
 			return err
 		}
 	}
@@ -137,7 +145,11 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		hostname = host
 	}
 
-	if shouldUseTLS {
+	// the bytes exchanged are recorded and replayed per template and address
+	replayKey := request.options.TemplateID + "\n" + actualAddress
+	if request.options.Replay.Replaying() {
+		conn, err = request.options.Replay.ReplayConn(replayProtocol, replayKey)
+	} else if shouldUseTLS {
 		conn, err = request.dialer.DialTLS(context.Background(), "tcp", actualAddress)
 	} else {
 		conn, err = request.dialer.Dial(context.Background(), "tcp", actualAddress)
@@ -147,8 +159,11 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		request.options.Progress.IncrementFailedRequestsBy(1)
 		return errors.Wrap(err, "could not connect to server")
 	}
+	conn = request.options.Replay.RecordConn(conn, replayProtocol, replayKey)
 	defer conn.Close()
 	_ = conn.SetDeadline(time.Now().Add(time.Duration(request.options.Options.Timeout) * time.Second))
+	// the duration of the exchange doesn't include the time to connect
+	start := time.Now()
 
 	var interactshURLs []string
 
@@ -257,13 +272,15 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		return errors.Wrap(err, "could not read from server")
 	}
 	responseBuilder.Write(final)
+	duration := time.Since(start)
 
 	response := responseBuilder.String()
 	outputEvent := request.responseToDSLMap(reqBuilder.String(), string(final), response, input.MetaInput.Input, actualAddress)
+	outputEvent["duration"] = duration.Seconds()
 	// add response fields to template context and merge templatectx variables to output event
 	request.options.AddTemplateVars(input.MetaInput, request.Type(), request.ID, outputEvent)
 	outputEvent = generators.MergeMaps(outputEvent, request.options.GetTemplateCtx(input.MetaInput).GetAll())
-	outputEvent["ip"] = request.dialer.GetDialedIP(hostname)
+	outputEvent["ip"] = request.options.Replay.DialedIP(hostname, request.dialer.GetDialedIP)
 	if request.options.StopAtFirstMatch {
 		outputEvent["stop-at-first-match"] = true
 	}
@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/operators/matchers"
 	"github.com/khulnasoft-lab/vulmap/pkg/output"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
 	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
 )
 
@@ -155,3 +158,49 @@ var exampleBody = `<!doctype html>
 </body>
 </html>
 `
+
+func TestNetworkTimingRequest(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-network-timing"
+	request := &Request{
+		ID:      templateID,
+		Address: []string{"{{Hostname}}"},
+		Timing:  &timing.Config{Delays: []int{1}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Type: matchers.MatcherTypeHolder{MatcherType: matchers.DSLMatcher},
+				DSL:  []string{"timing_confirmed"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seconds, _ := strconv.Atoi(r.URL.Query().Get("sleep"))
+		time.Sleep(time.Duration(seconds) * time.Second)
+		_, _ = w.Write([]byte(exampleBody))
+	}))
+	defer ts.Close()
+
+	parsed, err := url.Parse(ts.URL)
+	require.Nil(t, err, "could not parse url")
+
+	request.Inputs = append(request.Inputs, &Input{Data: fmt.Sprintf("GET /?sleep={{delay}} HTTP/1.1\r\nHost: %s\r\n\r\n", parsed.Host)})
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile network request")
+
+	var events []*output.InternalWrappedEvent
+	err = request.ExecuteWithResults(contextargs.NewWithInput(parsed.Host), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		events = append(events, event)
+	})
+	require.Nil(t, err, "could not execute network request")
+	require.Len(t, events, 1, "could not get single event for timing analysis")
+	require.True(t, events[0].OperatorsResult.Matched, "could not match confirmed delay")
+	require.Equal(t, []int{0, 1, 0, 1}, events[0].InternalEvent["timing_delays"])
+	latencies := events[0].InternalEvent["timing_latencies"].([]float64)
+	require.Equal(t, events[0].InternalEvent["duration"], latencies[len(latencies)-1], "could not use duration of exchange as latency")
+}
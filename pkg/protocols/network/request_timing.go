@@ -0,0 +1,24 @@
+package network
+
+import (
+	"time"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/timing"
+)
+
+// executeTimingRequest sends the inputs with each delay of the timing analysis, the
+// latency is the duration of the exchange after the connection was established.
+func (request *Request) executeTimingRequest(variables map[string]interface{}, actualAddress, address string, input *contextargs.Context, shouldUseTLS bool, payloads map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	return request.Timing.Execute(request, request.options, func(delay int, callback protocols.OutputEventCallback) (time.Duration, map[string]interface{}, error) {
+		values := request.Timing.Values(payloads, delay)
+		var latency time.Duration
+		err := request.executeRequestWithPayloads(variables, actualAddress, address, input, shouldUseTLS, values, previous, func(event *output.InternalWrappedEvent) {
+			latency = timing.Latency(event.InternalEvent)
+			callback(event)
+		})
+		return latency, values, err
+	}, callback)
+}
@@ -1,6 +1,8 @@
 package offlinehttp
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 
 	"github.com/khulnasoft-lab/vulmap/pkg/operators"
@@ -13,6 +15,20 @@ type Request struct {
 	compiledOperators []*operators.Operators
 }
 
+// ObservedArg is the context argument holding an observed request and response
+// pair which is matched instead of the responses read from the input paths.
+const ObservedArg = "offlinehttp-observed"
+
+// Observed is a request and response pair observed by a proxy
+type Observed struct {
+	// Request is the raw request sent by the client
+	Request string
+	// Response is the raw response received from the server
+	Response string
+	// Duration is the time taken to receive the response
+	Duration time.Duration
+}
+
 // RequestPartDefinitions contains a mapping of request part definitions and their
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
@@ -10,8 +10,8 @@ import (
 
 var noMinor = regexp.MustCompile(`HTTP/([0-9]) `)
 
-// readResponseFromString reads a raw http response from a string.
-func readResponseFromString(data string) (*http.Response, error) {
+// ReadResponseFromString reads a raw http response from a string.
+func ReadResponseFromString(data string) (*http.Response, error) {
 	// Check if "data" contains RFC compatible Request followed by a response
 	br := bufio.NewReader(strings.NewReader(data))
 	if req, err := http.ReadRequest(br); err == nil {
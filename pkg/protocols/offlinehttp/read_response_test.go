@@ -146,7 +146,7 @@ Server: Google Frontend
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resp, err := readResponseFromString(tt.data)
+			resp, err := ReadResponseFromString(tt.data)
 			require.Nil(t, err, "could not read response from string")
 
 			respData, err := io.ReadAll(resp.Body)
@@ -187,7 +187,7 @@ Server: Google Frontend
 		b, err := httputil.DumpResponse(data, true)
 		require.Nil(t, err, "could not dump response")
 
-		respData, err := readResponseFromString(string(b))
+		respData, err := ReadResponseFromString(string(b))
 		require.Nil(t, err, "could not read response from string")
 
 		_, err = io.ReadAll(respData.Body)
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http/httputil"
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/remeh/sizedwaitgroup"
@@ -30,6 +31,14 @@ func (request *Request) Type() templateTypes.ProtocolType {
 
 // ExecuteWithResults executes the protocol requests and returns results instead of writing them.
 func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata /*TODO review unused parameter*/, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	if value, ok := input.Get(ObservedArg); ok {
+		if observed, ok := value.(*Observed); ok {
+			request.executeResponse(input, input.MetaInput.Input, observed.Request, observed.Response, observed.Duration, previous, callback)
+			request.options.Progress.IncrementRequests()
+			return nil
+		}
+	}
+
 	wg := sizedwaitgroup.New(request.options.Options.BulkSize)
 
 	err := request.getInputPaths(input.MetaInput.Input, func(data string) {
@@ -60,43 +69,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata
 				gologger.Error().Msgf("Could not read file path %s: %s\n", data, err)
 				return
 			}
-			dataStr := tostring.UnsafeToString(buffer)
-
-			resp, err := readResponseFromString(dataStr)
-			if err != nil {
-				gologger.Error().Msgf("Could not read raw response %s: %s\n", data, err)
-				return
-			}
-
-			if request.options.Options.Debug || request.options.Options.DebugRequests {
-				gologger.Info().Msgf("[%s] Dumped offline-http request for %s", request.options.TemplateID, data)
-				gologger.Print().Msgf("%s", dataStr)
-			}
-			gologger.Verbose().Msgf("[%s] Sent OFFLINE-HTTP request to %s", request.options.TemplateID, data)
-
-			dumpedResponse, err := httputil.DumpResponse(resp, true)
-			if err != nil {
-				gologger.Error().Msgf("Could not dump raw http response %s: %s\n", data, err)
-				return
-			}
-
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				gologger.Error().Msgf("Could not read raw http response body %s: %s\n", data, err)
-				return
-			}
-
-			outputEvent := request.responseToDSLMap(resp, data, data, data, tostring.UnsafeToString(dumpedResponse), tostring.UnsafeToString(body), utils.HeadersToString(resp.Header), 0, nil)
-			// add response fields to template context and merge templatectx variables to output event
-			request.options.AddTemplateVars(input.MetaInput, request.Type(), request.GetID(), outputEvent)
-			outputEvent = generators.MergeMaps(outputEvent, request.options.GetTemplateCtx(input.MetaInput).GetAll())
-			outputEvent["ip"] = ""
-			for k, v := range previous {
-				outputEvent[k] = v
-			}
-
-			event := eventcreator.CreateEvent(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse)
-			callback(event)
+			request.executeResponse(input, data, data, tostring.UnsafeToString(buffer), 0, previous, callback)
 		}(data)
 	})
 	wg.Wait()
@@ -108,3 +81,42 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata
 	request.options.Progress.IncrementRequests()
 	return nil
 }
+
+// executeResponse matches the raw response read from the source
+func (request *Request) executeResponse(input *contextargs.Context, source, rawRequest, rawResponse string, duration time.Duration, previous output.InternalEvent, callback protocols.OutputEventCallback) {
+	resp, err := ReadResponseFromString(rawResponse)
+	if err != nil {
+		gologger.Error().Msgf("Could not read raw response %s: %s\n", source, err)
+		return
+	}
+
+	if request.options.Options.Debug || request.options.Options.DebugRequests {
+		gologger.Info().Msgf("[%s] Dumped offline-http request for %s", request.options.TemplateID, source)
+		gologger.Print().Msgf("%s", rawResponse)
+	}
+	gologger.Verbose().Msgf("[%s] Sent OFFLINE-HTTP request to %s", request.options.TemplateID, source)
+
+	dumpedResponse, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		gologger.Error().Msgf("Could not dump raw http response %s: %s\n", source, err)
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		gologger.Error().Msgf("Could not read raw http response body %s: %s\n", source, err)
+		return
+	}
+
+	outputEvent := request.responseToDSLMap(resp, source, source, rawRequest, tostring.UnsafeToString(dumpedResponse), tostring.UnsafeToString(body), utils.HeadersToString(resp.Header), duration, nil)
+	// add response fields to template context and merge templatectx variables to output event
+	request.options.AddTemplateVars(input.MetaInput, request.Type(), request.GetID(), outputEvent)
+	outputEvent = generators.MergeMaps(outputEvent, request.options.GetTemplateCtx(input.MetaInput).GetAll())
+	outputEvent["ip"] = ""
+	for k, v := range previous {
+		outputEvent[k] = v
+	}
+
+	event := eventcreator.CreateEvent(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse)
+	callback(event)
+}
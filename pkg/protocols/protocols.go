@@ -25,7 +25,9 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/excludematchers"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/variables"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless/engine"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
 	"github.com/khulnasoft-lab/vulmap/pkg/reporting"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 )
@@ -64,6 +66,10 @@ type ExecutorOptions struct {
 	Catalog catalog.Catalog
 	// ProjectFile is the project file for vulmap
 	ProjectFile *projectfile.ProjectFile
+	// Replay records or replays protocol exchanges if enabled
+	Replay *replay.Store
+	// Session imports and exports the session state of the scan if enabled
+	Session *session.Store
 	// Browser is a browser engine for running headless templates
 	Browser *engine.Browser
 	// Interactsh is a client for interactsh oob polling server
@@ -205,6 +211,14 @@ type Request interface {
 	Type() templateTypes.ProtocolType
 }
 
+// FixtureRequest is implemented by requests whose operators can be evaluated
+// offline against a recorded fixture response (used by template tests).
+type FixtureRequest interface {
+	// FixtureToDSLMap converts a raw fixture response to the internal event
+	// the request operators are executed on.
+	FixtureToDSLMap(fixture string) (output.InternalEvent, error)
+}
+
 // OutputEventCallback is a callback event for any results found during scanning.
 type OutputEventCallback func(result *output.InternalWrappedEvent)
 
@@ -0,0 +1,45 @@
+package ssl
+
+import (
+	"net"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/tlsx/pkg/tlsx/clients"
+)
+
+// replayProtocol is the protocol ssl handshakes are recorded as
+const replayProtocol = "ssl"
+
+// connect performs the tls handshake with the host. The handshake is
+// served from the recording when replaying a recorded scan.
+func (request *Request) connect(host, hostIp, port string) (*clients.Response, error) {
+	key := request.options.TemplateID + "\n" + net.JoinHostPort(host, port)
+	if request.options.Replay.Replaying() {
+		entry, err := request.options.Replay.Replay(replayProtocol, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(entry.Data) == 0 {
+			return nil, errors.New("invalid recorded ssl handshake")
+		}
+		response := &clients.Response{}
+		if err := jsoniter.Unmarshal(entry.Data[0], response); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal recorded ssl handshake")
+		}
+		return response, nil
+	}
+
+	response, err := request.tlsx.Connect(host, hostIp, port)
+	if err == nil && response != nil && request.options.Replay.Recording() {
+		data, marshalErr := jsoniter.Marshal(response)
+		if marshalErr != nil {
+			return nil, errors.Wrap(marshalErr, "could not marshal ssl handshake")
+		}
+		if recordErr := request.options.Replay.Record(replayProtocol, key, data); recordErr != nil {
+			return nil, errors.Wrap(recordErr, "could not record ssl handshake")
+		}
+	}
+	return response, err
+}
@@ -215,7 +215,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		hostIp = host
 	}
 
-	response, err := request.tlsx.Connect(host, hostIp, port)
+	response, err := request.connect(host, hostIp, port)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -249,7 +249,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	if input.MetaInput.CustomIP != "" {
 		data["ip"] = hostIp
 	} else {
-		data["ip"] = request.dialer.GetDialedIP(hostname)
+		data["ip"] = request.options.Replay.DialedIP(hostname, request.dialer.GetDialedIP)
 	}
 	data["template-path"] = requestOptions.TemplatePath
 	data["template-id"] = requestOptions.TemplateID
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"bufio"
 	"context"
 	"crypto/tls"
 	"fmt"
@@ -31,6 +32,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/utils/vardump"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/network/networkclientpool"
 	protocolutils "github.com/khulnasoft-lab/vulmap/pkg/protocols/utils"
+	"github.com/khulnasoft-lab/vulmap/pkg/session"
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 	"github.com/khulnasoft-lab/vulmap/pkg/types"
 	urlutil "github.com/khulnasoft-lab/utils/url"
@@ -95,6 +97,8 @@ type Input struct {
 const (
 	parseUrlErrorMessage                   = "could not parse input url"
 	evaluateTemplateExpressionErrorMessage = "could not evaluate template expressions"
+	// replayProtocol is the protocol websocket exchanges are recorded as
+	replayProtocol = "websocket"
 )
 
 // Compile compiles the request generators preparing any requests possible.
@@ -228,17 +232,47 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	parsedAddress.Path = path.Join(parsedAddress.Path, parsed.Path)
 	addressToDial = parsedAddress.String()
 
-	conn, readBuffer, _, err := websocketDialer.Dial(context.Background(), addressToDial)
+	if requestOptions.Session.Importing() {
+		// the imported session is sent with the handshake, cookie jars only hold http cookies
+		sessionURL := *parsedAddress
+		sessionURL.Scheme = strings.Replace(sessionURL.Scheme, "ws", "http", 1)
+		requestOptions.Session.SetHeaders(header, &sessionURL)
+		if cookies := requestOptions.Session.JarCookies(target.CookieJar, &sessionURL); len(cookies) > 0 {
+			header.Set("Cookie", session.AddCookies(header.Get("Cookie"), cookies))
+		}
+	}
+
+	var (
+		conn       net.Conn
+		readBuffer *bufio.Reader
+	)
+	// the frames exchanged after the handshake are recorded and replayed per template and address
+	replayKey := requestOptions.TemplateID + "\n" + addressToDial
+	if requestOptions.Replay.Replaying() {
+		conn, err = requestOptions.Replay.ReplayConn(replayProtocol, replayKey)
+	} else {
+		conn, readBuffer, _, err = websocketDialer.Dial(context.Background(), addressToDial)
+	}
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
 		return errors.Wrap(err, "could not connect to server")
 	}
+	// data sent along with the handshake response is read through the connection so it is recorded
+	var handshakeData int
+	if readBuffer != nil {
+		handshakeData = readBuffer.Buffered()
+		conn = &bufferedConn{Conn: conn, reader: readBuffer}
+	}
+	conn = requestOptions.Replay.RecordConn(conn, replayProtocol, replayKey)
 	defer conn.Close()
 
 	responseBuilder := &strings.Builder{}
-	if readBuffer != nil {
-		_, _ = io.Copy(responseBuilder, readBuffer) // Copy initial response
+	if requestOptions.Replay.Replaying() {
+		// the data read before the first write was sent along with the handshake response
+		_, _ = io.Copy(responseBuilder, conn)
+	} else if handshakeData > 0 {
+		_, _ = io.CopyN(responseBuilder, conn, int64(handshakeData)) // Copy initial response
 	}
 
 	events, requestOutput, err := request.readWriteInputWebsocket(conn, payloadValues, input, responseBuilder)
@@ -265,7 +299,7 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	data["response"] = responseBuilder.String()
 	data["host"] = input
 	data["matched"] = addressToDial
-	data["ip"] = request.dialer.GetDialedIP(hostname)
+	data["ip"] = requestOptions.Replay.DialedIP(hostname, request.dialer.GetDialedIP)
 
 	// add response fields to template context and merge templatectx variables to output event
 	request.options.AddTemplateVars(target.MetaInput, request.Type(), request.ID, data)
@@ -417,3 +451,13 @@ func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent
 func (request *Request) Type() templateTypes.ProtocolType {
 	return templateTypes.WebsocketProtocol
 }
+
+// bufferedConn is a connection reading the data buffered while reading the handshake response first
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,114 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/model"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/severity"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators/matchers"
+	"github.com/khulnasoft-lab/vulmap/pkg/output"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/contextargs"
+	"github.com/khulnasoft-lab/vulmap/pkg/replay"
+	"github.com/khulnasoft-lab/vulmap/pkg/testutils"
+)
+
+func TestWebsocketReplayHandshakeData(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-websocket"
+
+	// server sending a welcome frame along with the handshake response
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not create listener")
+	defer listener.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				req, err := http.ReadRequest(reader)
+				if err != nil {
+					return
+				}
+				sum := sha1.Sum([]byte(req.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+				frame, err := ws.CompileFrame(ws.NewTextFrame([]byte("welcome")))
+				if err != nil {
+					return
+				}
+				handshake := fmt.Sprintf("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+				if _, err := conn.Write(append([]byte(handshake), frame...)); err != nil {
+					return
+				}
+				msg, _, err := wsutil.ReadClientData(struct {
+					io.Reader
+					io.Writer
+				}{reader, conn})
+				if err != nil {
+					return
+				}
+				_ = wsutil.WriteServerText(conn, append([]byte("echo:"), msg...))
+			}(conn)
+		}
+	}()
+
+	dir := t.TempDir()
+	run := func(store *replay.Store) string {
+		request := &Request{
+			ID:      templateID,
+			Address: "ws://" + listener.Addr().String(),
+			Inputs:  []*Input{{Data: "hello"}},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Part:  "response",
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Words: []string{"echo:hello"},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.Replay = store
+		require.Nil(t, request.Compile(executerOpts), "could not compile websocket request")
+
+		var response string
+		err := request.ExecuteWithResults(contextargs.NewWithInput("ws://"+listener.Addr().String()), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			require.True(t, event.OperatorsResult != nil && event.OperatorsResult.Matched, "could not match websocket response")
+			response = event.InternalEvent["response"].(string)
+		})
+		require.Nil(t, err, "could not execute websocket request")
+		return response
+	}
+
+	recorder, err := replay.New(&replay.Options{Path: dir})
+	require.Nil(t, err, "could not create recorder")
+	recorded := run(recorder)
+	require.Contains(t, recorded, "welcome", "could not read handshake data")
+	require.Equal(t, int32(1), accepted.Load(), "could not get correct connection count")
+
+	player, err := replay.New(&replay.Options{Path: dir, Replay: true})
+	require.Nil(t, err, "could not create player")
+	require.Equal(t, recorded, run(player), "could not replay handshake data")
+	require.Equal(t, int32(1), accepted.Load(), "could connect while replaying")
+}
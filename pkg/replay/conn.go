@@ -0,0 +1,111 @@
+package replay
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// RecordConn returns a connection recording the data read from conn for
+// the protocol and key when it is closed. Data read after the nth write
+// is stored in the nth segment of the recorded exchange.
+func (s *Store) RecordConn(conn net.Conn, protocol, key string) net.Conn {
+	if !s.Recording() {
+		return conn
+	}
+	key = s.normalize(key)
+	return &recordConn{Conn: conn, store: s, protocol: protocol, key: key, index: s.next(protocol, key), segments: [][]byte{{}}}
+}
+
+// ReplayConn returns a connection serving the data recorded for the
+// protocol and key. Writes to the connection are discarded.
+func (s *Store) ReplayConn(protocol, key string) (net.Conn, error) {
+	entry, err := s.Replay(protocol, key)
+	if err != nil {
+		return nil, err
+	}
+	return &replayConn{segments: entry.Data}, nil
+}
+
+type recordConn struct {
+	net.Conn
+
+	store    *Store
+	protocol string
+	key      string
+	index    int
+
+	mu       sync.Mutex
+	segments [][]byte
+	closed   bool
+}
+
+func (c *recordConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.mu.Lock()
+		last := len(c.segments) - 1
+		c.segments[last] = append(c.segments[last], b[:n]...)
+		c.mu.Unlock()
+	}
+	return n, err
+}
+
+func (c *recordConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	c.segments = append(c.segments, []byte{})
+	c.mu.Unlock()
+	return c.Conn.Write(b)
+}
+
+func (c *recordConn) Close() error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		_ = c.store.write(c.protocol, c.key, c.index, &Entry{Key: c.key, Data: c.segments})
+	}
+	c.mu.Unlock()
+	return c.Conn.Close()
+}
+
+type replayConn struct {
+	mu       sync.Mutex
+	segments [][]byte
+	segment  int
+	offset   int
+}
+
+func (c *replayConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.segment >= len(c.segments) || c.offset >= len(c.segments[c.segment]) {
+		// nothing was read at this point of the recorded exchange
+		return 0, os.ErrDeadlineExceeded
+	}
+	n := copy(b, c.segments[c.segment][c.offset:])
+	c.offset += n
+	return n, nil
+}
+
+func (c *replayConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.segment++
+	c.offset = 0
+	return len(b), nil
+}
+
+func (c *replayConn) Close() error                       { return nil }
+func (c *replayConn) LocalAddr() net.Addr                { return replayAddr{} }
+func (c *replayConn) RemoteAddr() net.Addr               { return replayAddr{} }
+func (c *replayConn) SetDeadline(t time.Time) error      { return nil }
+func (c *replayConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *replayConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type replayAddr struct{}
+
+func (replayAddr) Network() string { return "replay" }
+func (replayAddr) String() string  { return "replay" }
@@ -0,0 +1,267 @@
+// Package replay records protocol exchanges of a scan to a directory
+// and serves them back without network access.
+//
+// Exchanges are stored per protocol using a key describing the request
+// (e.g. the dumped http request or the dns question). Identical keys are
+// stored in the order they were recorded and are replayed in the same order.
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotRecorded is returned when no exchange was recorded for a key
+	ErrNotRecorded = errors.New("no recorded exchange found")
+	// ErrNotReplayable is returned by protocols whose exchanges are not recorded
+	// when compiling their requests for a replayed scan
+	ErrNotReplayable = errors.New("requests are not recorded and can't be replayed")
+
+	regexUserAgent = regexp.MustCompile(`(?mi)\r?\nUser-Agent: .+\r?\n`)
+)
+
+// Options contains the configuration options for the store
+type Options struct {
+	// Path is the directory exchanges are recorded to or replayed from
+	Path string
+	// Replay replays recorded exchanges instead of recording them
+	Replay bool
+}
+
+// Entry is a recorded protocol exchange
+type Entry struct {
+	// Key is the normalized key of the exchange
+	Key string `json:"key"`
+	// Data contains the protocol specific recorded data
+	Data [][]byte `json:"data"`
+}
+
+// Store records and replays protocol exchanges.
+//
+// A nil store is valid and neither records nor replays exchanges.
+type Store struct {
+	path   string
+	replay bool
+
+	mu       sync.Mutex
+	counters map[string]int
+	domains  []*regexp.Regexp
+	ips      map[string]string
+}
+
+// New creates a new store for the options
+func New(options *Options) (*Store, error) {
+	if options.Path == "" {
+		return nil, errors.New("no record/replay directory specified")
+	}
+	if options.Replay {
+		info, err := os.Stat(options.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read replay directory")
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("replay path %s is not a directory", options.Path)
+		}
+	} else if err := os.MkdirAll(options.Path, 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create record directory")
+	}
+	store := &Store{
+		path:     options.Path,
+		replay:   options.Replay,
+		counters: make(map[string]int),
+		ips:      make(map[string]string),
+	}
+	if options.Replay {
+		if err := store.loadIPs(); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// Recording returns true if the store records exchanges
+func (s *Store) Recording() bool {
+	return s != nil && !s.replay
+}
+
+// Replaying returns true if the store replays recorded exchanges
+func (s *Store) Replaying() bool {
+	return s != nil && s.replay
+}
+
+// AddDynamicDomain registers a domain whose subdomains are generated
+// per scan (e.g. interactsh urls). Subdomains of it are replaced with
+// a placeholder in keys so recorded exchanges can be matched on replay.
+func (s *Store) AddDynamicDomain(domain string) {
+	if s == nil || domain == "" {
+		return
+	}
+	regex := regexp.MustCompile(`[a-zA-Z0-9-]+\.` + regexp.QuoteMeta(domain))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.domains {
+		if existing.String() == regex.String() {
+			return
+		}
+	}
+	s.domains = append(s.domains, regex)
+}
+
+// Record records data exchanged for the protocol and key
+func (s *Store) Record(protocol, key string, data ...[]byte) error {
+	if !s.Recording() {
+		return nil
+	}
+	key = s.normalize(key)
+	return s.write(protocol, key, s.next(protocol, key), &Entry{Key: key, Data: data})
+}
+
+// Replay returns the next recorded exchange for the protocol and key.
+//
+// If a key was replayed more often than it was recorded, the first
+// recorded exchange is returned.
+func (s *Store) Replay(protocol, key string) (*Entry, error) {
+	if !s.Replaying() {
+		return nil, ErrNotRecorded
+	}
+	key = s.normalize(key)
+	entry, err := s.read(protocol, key, s.next(protocol, key))
+	if errors.Is(err, ErrNotRecorded) {
+		entry, err = s.read(protocol, key, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ReplayAll returns all exchanges recorded for the protocol and key
+// in the order they were recorded.
+func (s *Store) ReplayAll(protocol, key string) ([]*Entry, error) {
+	if !s.Replaying() {
+		return nil, ErrNotRecorded
+	}
+	key = s.normalize(key)
+
+	var entries []*Entry
+	for index := 0; ; index++ {
+		entry, err := s.read(protocol, key, index)
+		if errors.Is(err, ErrNotRecorded) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// DialedIP returns the ip address dialed for the hostname. The address is
+// recorded using lookup and served from the recording on replay.
+func (s *Store) DialedIP(hostname string, lookup func(string) string) string {
+	if !s.Replaying() {
+		ip := lookup(hostname)
+		if s.Recording() && ip != "" {
+			s.recordIP(hostname, ip)
+		}
+		return ip
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ips[hostname]
+}
+
+// next returns the occurrence index of the protocol and key
+func (s *Store) next(protocol, key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counterKey := protocol + "\x00" + key
+	index := s.counters[counterKey]
+	s.counters[counterKey]++
+	return index
+}
+
+// normalize removes values changing between scans from the key
+func (s *Store) normalize(key string) string {
+	key = regexUserAgent.ReplaceAllString(key, "\r\n")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, regex := range s.domains {
+		key = regex.ReplaceAllString(key, "{{interactsh-url}}")
+	}
+	return key
+}
+
+func (s *Store) filename(protocol, key string, index int) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(s.path, protocol, fmt.Sprintf("%s-%d.json", hex.EncodeToString(hash[:]), index))
+}
+
+func (s *Store) write(protocol, key string, index int, entry *Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal recorded exchange")
+	}
+	filename := s.filename(protocol, key, index)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return errors.Wrap(err, "could not create record directory")
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+func (s *Store) read(protocol, key string, index int) (*Entry, error) {
+	data, err := os.ReadFile(s.filename(protocol, key, index))
+	if os.IsNotExist(err) {
+		return nil, errors.Wrapf(ErrNotRecorded, "%s %q", protocol, strings.TrimSpace(strings.SplitN(key, "\n", 2)[0]))
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry := &Entry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal recorded exchange")
+	}
+	return entry, nil
+}
+
+const ipsFilename = "ips.json"
+
+// recordIP records the ip address of the hostname
+func (s *Store) recordIP(hostname, ip string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ips[hostname] == ip {
+		return
+	}
+	s.ips[hostname] = ip
+	if data, err := json.MarshalIndent(s.ips, "", "  "); err == nil {
+		_ = os.WriteFile(filepath.Join(s.path, ipsFilename), data, 0644)
+	}
+}
+
+// loadIPs loads the recorded ip addresses of the hostnames
+func (s *Store) loadIPs() error {
+	data, err := os.ReadFile(filepath.Join(s.path, ipsFilename))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.ips)
+}
@@ -0,0 +1,81 @@
+package replay
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder, err := New(&Options{Path: dir})
+	require.Nil(t, err, "could not create recorder")
+	recorder.AddDynamicDomain("oast.fun")
+
+	request := "GET / HTTP/1.1\r\nHost: example.com\r\nUser-Agent: first\r\nX-Callback: abcdef.oast.fun\r\n\r\n"
+	require.Nil(t, recorder.Record("http", request, []byte("first")))
+	require.Nil(t, recorder.Record("http", request, []byte("second")))
+	require.Equal(t, "93.184.216.34", recorder.DialedIP("example.com", func(string) string { return "93.184.216.34" }))
+
+	player, err := New(&Options{Path: dir, Replay: true})
+	require.Nil(t, err, "could not create player")
+	player.AddDynamicDomain("oast.replay")
+
+	// user agents and interactsh urls differ between scans
+	replayed := "GET / HTTP/1.1\r\nHost: example.com\r\nUser-Agent: second\r\nX-Callback: 123456.oast.replay\r\n\r\n"
+	for _, expected := range []string{"first", "second", "first"} {
+		entry, err := player.Replay("http", replayed)
+		require.Nil(t, err, "could not replay exchange")
+		require.Equal(t, expected, string(entry.Data[0]))
+	}
+	entries, err := player.ReplayAll("http", replayed)
+	require.Nil(t, err, "could not replay exchanges")
+	require.Len(t, entries, 2)
+
+	_, err = player.Replay("http", "GET /missing HTTP/1.1\r\n\r\n")
+	require.ErrorIs(t, err, ErrNotRecorded)
+
+	require.Equal(t, "93.184.216.34", player.DialedIP("example.com", func(string) string { return "" }))
+}
+
+func TestStoreConn(t *testing.T) {
+	dir := t.TempDir()
+
+	server, client := net.Pipe()
+	go func() {
+		_, _ = server.Write([]byte("banner"))
+		buffer := make([]byte, 4)
+		_, _ = io.ReadFull(server, buffer)
+		_, _ = server.Write([]byte("pong"))
+		_ = server.Close()
+	}()
+
+	recorder, err := New(&Options{Path: dir})
+	require.Nil(t, err, "could not create recorder")
+	conn := recorder.RecordConn(client, "network", "example.com:80")
+	buffer := make([]byte, 16)
+	n, _ := conn.Read(buffer)
+	require.Equal(t, "banner", string(buffer[:n]))
+	_, err = conn.Write([]byte("ping"))
+	require.Nil(t, err)
+	n, _ = conn.Read(buffer)
+	require.Equal(t, "pong", string(buffer[:n]))
+	require.Nil(t, conn.Close())
+
+	player, err := New(&Options{Path: dir, Replay: true})
+	require.Nil(t, err, "could not create player")
+	conn, err = player.ReplayConn("network", "example.com:80")
+	require.Nil(t, err, "could not replay connection")
+	n, err = conn.Read(buffer)
+	require.Nil(t, err)
+	require.Equal(t, "banner", string(buffer[:n]))
+	_, err = conn.Read(buffer)
+	require.NotNil(t, err, "read past the recorded segment")
+	_, _ = conn.Write([]byte("ping"))
+	n, err = conn.Read(buffer)
+	require.Nil(t, err)
+	require.Equal(t, "pong", string(buffer[:n]))
+}
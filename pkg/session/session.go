@@ -0,0 +1,350 @@
+// Package session exports the state of the headless browser pages of a scan
+// (cookies, local and session storage, and authentication headers) to a file
+// and imports it into the http requests and headless pages of later scans.
+//
+// The file format is compatible with the storage state of Playwright, extended
+// with the session storage and the authentication headers of each host.
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Headers contains the names of the authentication headers exported from the
+// requests sent by the headless pages.
+var Headers = []string{"Authorization", "X-Auth-Token", "X-Api-Key", "X-Csrf-Token", "X-Xsrf-Token"}
+
+// Options contains the configuration options for the store
+type Options struct {
+	// ImportPath is the file the session state is imported from
+	ImportPath string
+	// ExportPath is the file the session state of the headless pages is exported to
+	ExportPath string
+}
+
+// State is the session state of a browser
+type State struct {
+	// Cookies contains the cookies of the browser
+	Cookies []*Cookie `json:"cookies"`
+	// Origins contains the storage of each origin
+	Origins []*Origin `json:"origins"`
+	// Headers contains the authentication headers sent to each host
+	Headers map[string]map[string]string `json:"headers,omitempty"`
+}
+
+// Cookie is a browser cookie
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Domain is the domain of the cookie, prefixed with a dot for the
+	// cookies sent to the subdomains.
+	Domain string `json:"domain"`
+	Path   string `json:"path"`
+	// Expires is the expiration unix time in seconds, -1 for session cookies
+	Expires  float64 `json:"expires"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	SameSite string  `json:"sameSite,omitempty"`
+}
+
+// Origin contains the storage of an origin
+type Origin struct {
+	Origin         string       `json:"origin"`
+	LocalStorage   []*NameValue `json:"localStorage"`
+	SessionStorage []*NameValue `json:"sessionStorage,omitempty"`
+}
+
+// NameValue is an item of a storage
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Store imports and exports session states.
+//
+// A nil store is valid and neither imports nor exports states.
+type Store struct {
+	exportPath string
+
+	mu       sync.RWMutex
+	imported *State
+	exported *State
+}
+
+// New creates a new store for the options, loading the imported state
+func New(options *Options) (*Store, error) {
+	store := &Store{exportPath: options.ExportPath}
+	if options.ImportPath != "" {
+		data, err := os.ReadFile(options.ImportPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read session file")
+		}
+		state := &State{}
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, errors.Wrap(err, "could not parse session file")
+		}
+		store.imported = state
+	}
+	if options.ExportPath != "" {
+		store.exported = &State{}
+	}
+	return store, nil
+}
+
+// Importing returns true if a session state was imported
+func (s *Store) Importing() bool {
+	return s != nil && s.imported != nil
+}
+
+// Exporting returns true if the session states are exported
+func (s *Store) Exporting() bool {
+	return s != nil && s.exported != nil
+}
+
+// Export merges the session state in the exported state, the cookies,
+// storage items and headers replace the previously exported ones.
+func (s *Store) Export(state *State) {
+	if !s.Exporting() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exported.merge(state)
+}
+
+// Close writes the exported state to the export file
+func (s *Store) Close() error {
+	if !s.Exporting() {
+		return nil
+	}
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.exported, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal session state")
+	}
+	if err := os.WriteFile(s.exportPath, data, 0600); err != nil {
+		return errors.Wrap(err, "could not write session file")
+	}
+	return nil
+}
+
+// State returns the imported session state
+func (s *Store) State() *State {
+	if !s.Importing() {
+		return nil
+	}
+	return s.imported
+}
+
+// Cookies returns the imported cookies sent to the URL
+func (s *Store) Cookies(u *url.URL) []*Cookie {
+	if !s.Importing() {
+		return nil
+	}
+	var cookies []*Cookie
+	for _, cookie := range s.imported.Cookies {
+		if cookie.matches(u) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
+
+// Headers returns the imported authentication headers of the host
+func (s *Store) Headers(host string) map[string]string {
+	if !s.Importing() {
+		return nil
+	}
+	return s.imported.Headers[host]
+}
+
+// SetCookies seeds the imported cookies sent to the URL missing from the jar,
+// the cookies set by the responses replace the imported ones.
+func (s *Store) SetCookies(jar http.CookieJar, u *url.URL) {
+	cookies := s.Cookies(u)
+	if len(cookies) == 0 || jar == nil {
+		return
+	}
+	existing := make(map[string]struct{})
+	for _, cookie := range jar.Cookies(u) {
+		existing[cookie.Name] = struct{}{}
+	}
+	var missing []*http.Cookie
+	for _, cookie := range cookies {
+		if _, ok := existing[cookie.Name]; ok {
+			continue
+		}
+		// cookies are seeded for the host of the URL as the jar may not have a public suffix list
+		missing = append(missing, &http.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HTTPOnly,
+		})
+	}
+	if len(missing) > 0 {
+		jar.SetCookies(u, missing)
+	}
+}
+
+// JarCookies seeds the imported cookies sent to the URL in the jar and returns
+// them with the values of the jar, which are newer than the imported ones.
+func (s *Store) JarCookies(jar http.CookieJar, u *url.URL) []*http.Cookie {
+	imported := s.Cookies(u)
+	if len(imported) == 0 {
+		return nil
+	}
+	values := make(map[string]string)
+	if jar != nil {
+		s.SetCookies(jar, u)
+		for _, cookie := range jar.Cookies(u) {
+			values[cookie.Name] = cookie.Value
+		}
+	}
+	cookies := make([]*http.Cookie, 0, len(imported))
+	for _, cookie := range imported {
+		value, ok := values[cookie.Name]
+		if !ok {
+			value = cookie.Value
+		}
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: value})
+	}
+	return cookies
+}
+
+// SetHeaders sets the imported authentication headers of the host of the URL
+// missing from the headers, the headers of the templates are kept.
+func (s *Store) SetHeaders(header http.Header, u *url.URL) {
+	for name, value := range s.Headers(u.Host) {
+		if header.Get(name) == "" {
+			header.Set(name, value)
+		}
+	}
+}
+
+// AddCookies returns the cookie header value with the cookies missing from it
+// appended, the cookies of the header value are kept.
+func AddCookies(value string, cookies []*http.Cookie) string {
+	existing := make(map[string]struct{})
+	for _, cookie := range (&http.Request{Header: http.Header{"Cookie": {value}}}).Cookies() {
+		existing[cookie.Name] = struct{}{}
+	}
+	pairs := []string{}
+	if value = strings.TrimSpace(value); value != "" {
+		pairs = append(pairs, strings.TrimSuffix(value, ";"))
+	}
+	for _, cookie := range cookies {
+		if _, ok := existing[cookie.Name]; ok {
+			continue
+		}
+		existing[cookie.Name] = struct{}{}
+		pairs = append(pairs, cookie.Name+"="+cookie.Value)
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// matches returns true if the cookie is sent to the URL
+func (c *Cookie) matches(u *url.URL) bool {
+	if c.Expires > 0 && time.Unix(int64(c.Expires), 0).Before(time.Now()) {
+		return false
+	}
+	if c.Secure && u.Scheme != "https" && u.Scheme != "wss" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	domain := strings.ToLower(c.Domain)
+	if strings.HasPrefix(domain, ".") {
+		domain = strings.TrimPrefix(domain, ".")
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+	} else if host != domain {
+		return false
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	cookiePath := c.Path
+	if cookiePath == "" {
+		cookiePath = "/"
+	}
+	if path != cookiePath && !strings.HasPrefix(path, strings.TrimSuffix(cookiePath, "/")+"/") {
+		return false
+	}
+	return true
+}
+
+// merge merges a state in the state
+func (s *State) merge(state *State) {
+	for _, cookie := range state.Cookies {
+		replaced := false
+		for i, existing := range s.Cookies {
+			if existing.Name == cookie.Name && existing.Domain == cookie.Domain && existing.Path == cookie.Path {
+				s.Cookies[i] = cookie
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.Cookies = append(s.Cookies, cookie)
+		}
+	}
+
+	for _, origin := range state.Origins {
+		var existing *Origin
+		for _, item := range s.Origins {
+			if item.Origin == origin.Origin {
+				existing = item
+				break
+			}
+		}
+		if existing == nil {
+			existing = &Origin{Origin: origin.Origin, LocalStorage: []*NameValue{}}
+			s.Origins = append(s.Origins, existing)
+		}
+		existing.LocalStorage = mergeItems(existing.LocalStorage, origin.LocalStorage)
+		existing.SessionStorage = mergeItems(existing.SessionStorage, origin.SessionStorage)
+	}
+
+	for host, headers := range state.Headers {
+		if s.Headers == nil {
+			s.Headers = make(map[string]map[string]string)
+		}
+		if s.Headers[host] == nil {
+			s.Headers[host] = make(map[string]string)
+		}
+		for name, value := range headers {
+			s.Headers[host][name] = value
+		}
+	}
+}
+
+// mergeItems merges storage items, the items replace the existing items with the same name
+func mergeItems(existing, items []*NameValue) []*NameValue {
+	for _, item := range items {
+		replaced := false
+		for i, value := range existing {
+			if value.Name == item.Name {
+				existing[i] = item
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, item)
+		}
+	}
+	return existing
+}
@@ -0,0 +1,108 @@
+package session
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreExportImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	exporter, err := New(&Options{ExportPath: path})
+	require.Nil(t, err, "could not create exporter")
+	require.False(t, exporter.Importing())
+	exporter.Export(&State{
+		Cookies: []*Cookie{{Name: "session", Value: "1", Domain: "example.com", Path: "/", Expires: -1}},
+		Origins: []*Origin{{Origin: "https://example.com", LocalStorage: []*NameValue{{Name: "token", Value: "a"}}}},
+		Headers: map[string]map[string]string{"example.com": {"Authorization": "Bearer a"}},
+	})
+	exporter.Export(&State{
+		Cookies: []*Cookie{{Name: "session", Value: "2", Domain: "example.com", Path: "/", Expires: -1}},
+		Origins: []*Origin{{Origin: "https://example.com", LocalStorage: []*NameValue{{Name: "token", Value: "b"}, {Name: "theme", Value: "dark"}}}},
+		Headers: map[string]map[string]string{"example.com": {"X-Csrf-Token": "c"}},
+	})
+	require.Nil(t, exporter.Close(), "could not export session state")
+
+	importer, err := New(&Options{ImportPath: path})
+	require.Nil(t, err, "could not create importer")
+	require.False(t, importer.Exporting())
+	state := importer.State()
+	require.Len(t, state.Cookies, 1)
+	require.Equal(t, "2", state.Cookies[0].Value, "could not merge cookies")
+	require.Equal(t, []*NameValue{{Name: "token", Value: "b"}, {Name: "theme", Value: "dark"}}, state.Origins[0].LocalStorage, "could not merge storage")
+	require.Equal(t, map[string]string{"Authorization": "Bearer a", "X-Csrf-Token": "c"}, importer.Headers("example.com"), "could not merge headers")
+
+	_, err = New(&Options{ImportPath: filepath.Join(t.TempDir(), "missing.json")})
+	require.Error(t, err, "could import missing session file")
+	require.Nil(t, os.WriteFile(path, []byte("{"), 0600))
+	_, err = New(&Options{ImportPath: path})
+	require.Error(t, err, "could import invalid session file")
+}
+
+func TestStoreCookies(t *testing.T) {
+	store := &Store{imported: &State{Cookies: []*Cookie{
+		{Name: "host", Value: "1", Domain: "example.com", Path: "/", Expires: -1},
+		{Name: "domain", Value: "2", Domain: ".example.com", Path: "/", Expires: -1},
+		{Name: "admin", Value: "3", Domain: "example.com", Path: "/admin", Expires: -1},
+		{Name: "secure", Value: "4", Domain: "example.com", Path: "/", Secure: true, Expires: -1},
+		{Name: "expired", Value: "5", Domain: "example.com", Path: "/", Expires: 1},
+	}}}
+
+	names := func(rawURL string) []string {
+		parsed, err := url.Parse(rawURL)
+		require.Nil(t, err, "could not parse url")
+		var names []string
+		for _, cookie := range store.Cookies(parsed) {
+			names = append(names, cookie.Name)
+		}
+		return names
+	}
+	require.Equal(t, []string{"host", "domain"}, names("http://example.com/"))
+	require.Equal(t, []string{"host", "domain", "admin", "secure"}, names("https://example.com/admin/users"))
+	require.Equal(t, []string{"host", "domain"}, names("http://example.com/administrator"))
+	require.Equal(t, []string{"domain"}, names("http://www.example.com/"))
+	require.Empty(t, names("http://notexample.com/"))
+
+	parsed, _ := url.Parse("http://example.com/")
+	jar, err := cookiejar.New(nil)
+	require.Nil(t, err, "could not create cookie jar")
+	jar.SetCookies(parsed, []*http.Cookie{{Name: "host", Value: "new"}})
+	require.Equal(t, []*http.Cookie{{Name: "host", Value: "new"}, {Name: "domain", Value: "2"}}, store.JarCookies(jar, parsed), "could not get jar cookies")
+	require.Equal(t, []*http.Cookie{{Name: "host", Value: "new"}, {Name: "domain", Value: "2"}}, jar.Cookies(parsed), "could not seed missing cookies")
+	require.Equal(t, []*http.Cookie{{Name: "host", Value: "1"}, {Name: "domain", Value: "2"}}, store.JarCookies(nil, parsed), "could not get imported cookies")
+
+	var empty *Store
+	require.False(t, empty.Importing())
+	require.False(t, empty.Exporting())
+	require.Nil(t, empty.Cookies(parsed))
+	require.Nil(t, empty.Close())
+	empty.SetCookies(jar, parsed)
+	empty.SetHeaders(http.Header{}, parsed)
+}
+
+func TestStoreHeaders(t *testing.T) {
+	store := &Store{imported: &State{Headers: map[string]map[string]string{"example.com": {"Authorization": "Bearer a", "X-Csrf-Token": "b"}}}}
+
+	parsed, _ := url.Parse("https://example.com/api")
+	header := http.Header{"X-Csrf-Token": {"template"}}
+	store.SetHeaders(header, parsed)
+	require.Equal(t, http.Header{"Authorization": {"Bearer a"}, "X-Csrf-Token": {"template"}}, header, "could not set missing headers")
+
+	parsed, _ = url.Parse("https://example.com:8443/api")
+	header = http.Header{}
+	store.SetHeaders(header, parsed)
+	require.Empty(t, header, "could set headers of other host")
+}
+
+func TestAddCookies(t *testing.T) {
+	cookies := []*http.Cookie{{Name: "id", Value: "1"}, {Name: "sid", Value: "2"}}
+	require.Equal(t, "id=1; sid=2", AddCookies("", cookies))
+	require.Equal(t, "sid=template; id=1", AddCookies("sid=template", cookies), "could not match cookie names")
+	require.Equal(t, "a=1; id=1; sid=2", AddCookies("a=1;", cookies))
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/utils"
 	"github.com/khulnasoft-lab/retryablehttp-go"
 	errorutil "github.com/khulnasoft-lab/utils/errors"
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
 	stringsutil "github.com/khulnasoft-lab/utils/strings"
 )
 
@@ -127,7 +128,8 @@ func (template *Template) Requests() int {
 		len(template.RequestsWebsocket) +
 		len(template.RequestsWHOIS) +
 		len(template.RequestsCode) +
-		len(template.RequestsJavascript)
+		len(template.RequestsJavascript) +
+		len(template.RequestsGRPC)
 }
 
 // compileProtocolRequests compiles all the protocol requests for the template
@@ -141,6 +143,16 @@ func (template *Template) compileProtocolRequests(options protocols.ExecutorOpti
 	if options.Options.OfflineHTTP {
 		return template.compileOfflineHTTPRequest(options)
 	}
+	// the passive proxy matches observed responses with the templates and executes
+	// the fuzzing templates on the observed requests
+	if options.Options.PassiveProxy != "" {
+		if !template.HasFuzzingRules() {
+			return template.compileOfflineHTTPRequest(options)
+		}
+		if !options.Options.PassiveProxyFuzz {
+			return ErrIncompatibleWithOfflineMatching
+		}
+	}
 
 	var requests []protocols.Request
 
@@ -182,6 +194,9 @@ func (template *Template) compileProtocolRequests(options protocols.ExecutorOpti
 		if len(template.RequestsJavascript) > 0 {
 			requests = append(requests, template.convertRequestToProtocolsRequest(template.RequestsJavascript)...)
 		}
+		if len(template.RequestsGRPC) > 0 {
+			requests = append(requests, template.convertRequestToProtocolsRequest(template.RequestsGRPC)...)
+		}
 	}
 	template.Executer = tmplexec.NewTemplateExecuter(requests, &options)
 	return nil
@@ -298,6 +313,7 @@ func ParseTemplateFromReader(reader io.Reader, preprocessor Preprocessor, option
 		return nil, err
 	}
 	reParsed.Verified = isVerified
+	reParsed.SignedBy = template.SignedBy
 	return reParsed, nil
 }
 
@@ -380,9 +396,15 @@ func parseTemplate(data []byte, options protocols.ExecutorOptions) (*Template, e
 
 	// check if the template is verified
 	// only valid templates can be verified or signed
+	protocolTypes := template.protocolTypes()
 	for _, verifier := range signer.DefaultTemplateVerifiers {
+		// signers are only trusted for templates in their scope
+		if !verifier.InScope(protocolTypes, options.TemplatePath) {
+			continue
+		}
 		template.Verified, _ = verifier.Verify(data, template)
 		if template.Verified {
+			template.SignedBy = verifier.Identifier()
 			SignatureStats[verifier.Identifier()].Add(1)
 			break
 		}
@@ -390,6 +412,17 @@ func parseTemplate(data []byte, options protocols.ExecutorOptions) (*Template, e
 	return template, nil
 }
 
+// protocolTypes returns the unique protocol types of the template requests
+func (template *Template) protocolTypes() []string {
+	var protocolTypes []string
+	for _, request := range template.RequestsQueue {
+		if protocolType := request.Type().String(); !sliceutil.Contains(protocolTypes, protocolType) {
+			protocolTypes = append(protocolTypes, protocolType)
+		}
+	}
+	return protocolTypes
+}
+
 var (
 	jsCompiler     *compiler.Compiler
 	jsCompilerOnce = sync.OnceFunc(func() {
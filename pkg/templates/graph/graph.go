@@ -0,0 +1,417 @@
+// Package graph builds the dependency graph of templates across workflows,
+// helper files and javascript modules to find the templates impacted by a change.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	fileutil "github.com/khulnasoft-lab/utils/file"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/model/types/stringslice"
+	"github.com/khulnasoft-lab/vulmap/pkg/workflows"
+)
+
+// NodeType is the type of a node of the template graph
+type NodeType string
+
+const (
+	// TemplateNode is a template or workflow
+	TemplateNode NodeType = "template"
+	// FileNode is a helper file such as a payload wordlist or code source
+	FileNode NodeType = "file"
+	// ModuleNode is a javascript module required by a template
+	ModuleNode NodeType = "module"
+)
+
+// Kinds of dependencies between the nodes of the template graph
+const (
+	WorkflowEdge    = "workflow"
+	SubtemplateEdge = "subtemplate"
+	TagEdge         = "tag"
+	PayloadEdge     = "payload"
+	CodeEdge        = "code"
+	FlowEdge        = "flow"
+	RequireEdge     = "require"
+)
+
+var reRequire = regexp.MustCompile(`require\(\s*['"]([^'"]+)['"]\s*\)`)
+
+// Node is a template, helper file or javascript module of the template graph
+type Node struct {
+	// ID is the path relative to the templates directory or the module name
+	ID         string   `json:"id"`
+	Type       NodeType `json:"type"`
+	TemplateID string   `json:"template-id,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	// Missing is true for helper files which do not exist
+	Missing bool `json:"missing,omitempty"`
+}
+
+// Edge is a dependency of a template on another node
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Graph is the dependency graph of templates
+type Graph struct {
+	Nodes map[string]*Node
+	Edges []Edge
+
+	catalog      catalog.Catalog
+	templatesDir string
+	edges        map[Edge]struct{}
+	dependents   map[string][]string
+}
+
+// templateRefs contains the fields of a template referencing other nodes
+type templateRefs struct {
+	ID   string `yaml:"id"`
+	Info struct {
+		Tags stringslice.StringSlice `yaml:"tags"`
+	} `yaml:"info"`
+	Flow      string                        `yaml:"flow"`
+	Workflows []*workflows.WorkflowTemplate `yaml:"workflows"`
+	Code      []struct {
+		Source string `yaml:"source"`
+	} `yaml:"code"`
+}
+
+// tagReference is a workflow step running the templates with tags
+type tagReference struct {
+	from string
+	tags []string
+	kind string
+}
+
+// Build builds the dependency graph of the templates at the given paths
+func Build(templateCatalog catalog.Catalog, templatePaths []string) (*Graph, error) {
+	g := &Graph{
+		Nodes:        make(map[string]*Node),
+		catalog:      templateCatalog,
+		templatesDir: config.DefaultConfig.TemplatesDirectory,
+		edges:        make(map[Edge]struct{}),
+		dependents:   make(map[string][]string),
+	}
+	var tagRefs []tagReference
+	for _, templatePath := range templatePaths {
+		refs, err := g.addTemplate(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		tagRefs = append(tagRefs, refs...)
+	}
+	// workflow steps with tags depend on all templates with the tags
+	for _, ref := range tagRefs {
+		for _, node := range g.sortedNodes() {
+			if node.Type == TemplateNode && node.ID != ref.from && hasAnyTag(node.Tags, ref.tags) {
+				g.addEdge(ref.from, node.ID, ref.kind)
+			}
+		}
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g, nil
+}
+
+// addTemplate adds the template and its dependencies to the graph. It returns
+// the workflow steps referencing templates with tags to be resolved later.
+func (g *Graph) addTemplate(templatePath string) ([]tagReference, error) {
+	if !strings.HasSuffix(templatePath, ".yaml") {
+		return nil, nil
+	}
+	bin, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	refs := &templateRefs{}
+	if err := yaml.Unmarshal(bin, refs); err != nil || refs.ID == "" {
+		// not a template
+		return nil, nil
+	}
+	id := g.addNode(templatePath, TemplateNode)
+	g.Nodes[id].TemplateID = refs.ID
+	g.Nodes[id].Tags = refs.Info.Tags.ToSlice()
+
+	var tagRefs []tagReference
+	var addWorkflow func(step *workflows.WorkflowTemplate, kind string)
+	addWorkflow = func(step *workflows.WorkflowTemplate, kind string) {
+		if step.Template != "" {
+			paths, _ := g.catalog.GetTemplatePath(step.Template)
+			for _, path := range paths {
+				g.addEdge(id, g.addNode(path, TemplateNode), kind)
+			}
+		} else if !step.Tags.IsEmpty() {
+			tagRefs = append(tagRefs, tagReference{from: id, tags: step.Tags.ToSlice(), kind: TagEdge})
+		}
+		for _, subtemplate := range step.Subtemplates {
+			addWorkflow(subtemplate, SubtemplateEdge)
+		}
+		for _, subtemplate := range step.OnFailure {
+			addWorkflow(subtemplate, SubtemplateEdge)
+		}
+		for _, matcher := range step.Matchers {
+			for _, subtemplate := range matcher.Subtemplates {
+				addWorkflow(subtemplate, SubtemplateEdge)
+			}
+		}
+	}
+	for _, step := range refs.Workflows {
+		addWorkflow(step, WorkflowEdge)
+	}
+
+	// helper files imported by the template
+	for _, code := range refs.Code {
+		if isFileReference(code.Source) {
+			g.addFileEdge(id, templatePath, code.Source, CodeEdge)
+		}
+	}
+	scripts := []string{string(bin)}
+	if filepath.Ext(refs.Flow) == ".js" && isFileReference(refs.Flow) {
+		if flowPath := g.addFileEdge(id, templatePath, refs.Flow, FlowEdge); flowPath != "" {
+			if flow, err := os.ReadFile(flowPath); err == nil {
+				scripts = append(scripts, string(flow))
+			}
+		}
+	}
+	var data interface{}
+	if err := yaml.Unmarshal(bin, &data); err == nil {
+		walkPayloads(data, func(payload string) {
+			g.addFileEdge(id, templatePath, payload, PayloadEdge)
+		})
+	}
+
+	// javascript modules required by scripts of the template
+	for _, script := range scripts {
+		for _, match := range reRequire.FindAllStringSubmatch(script, -1) {
+			g.addEdge(id, g.addNode(match[1], ModuleNode), RequireEdge)
+		}
+	}
+	return tagRefs, nil
+}
+
+// addFileEdge adds the helper file referenced by the template to the graph
+// and returns its absolute path if it exists
+func (g *Graph) addFileEdge(from, templatePath, reference, kind string) string {
+	path, err := fileutil.ResolveNClean(reference, g.templatesDir, filepath.Dir(templatePath))
+	if err != nil {
+		// missing files keep their reference as id
+		id := filepath.ToSlash(filepath.Clean(reference))
+		if _, ok := g.Nodes[id]; !ok {
+			g.Nodes[id] = &Node{ID: id, Type: FileNode, Missing: true}
+		}
+		g.addEdge(from, id, kind)
+		return ""
+	}
+	g.addEdge(from, g.addNode(path, FileNode), kind)
+	return path
+}
+
+// addNode adds the node for the path or module to the graph and returns its id
+func (g *Graph) addNode(path string, nodeType NodeType) string {
+	id := path
+	if nodeType != ModuleNode {
+		id = g.nodeID(path)
+	}
+	if node, ok := g.Nodes[id]; ok {
+		// templates referenced by workflows may be parsed later
+		if nodeType == TemplateNode {
+			node.Type = TemplateNode
+		}
+		return id
+	}
+	g.Nodes[id] = &Node{ID: id, Type: nodeType}
+	return id
+}
+
+// addEdge adds the dependency of a node on another node
+func (g *Graph) addEdge(from, to, kind string) {
+	edge := Edge{From: from, To: to, Kind: kind}
+	if _, ok := g.edges[edge]; ok {
+		return
+	}
+	g.edges[edge] = struct{}{}
+	g.Edges = append(g.Edges, edge)
+	g.dependents[to] = append(g.dependents[to], from)
+}
+
+// nodeID returns the id of a path which is relative to the templates directory if possible
+func (g *Graph) nodeID(path string) string {
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	if g.templatesDir != "" {
+		if relPath, err := filepath.Rel(g.templatesDir, path); err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(relPath)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// Resolve returns the ids of the nodes matching the query which is
+// a template or helper file path, a template id or a module name
+func (g *Graph) Resolve(query string) []string {
+	if _, ok := g.Nodes[query]; ok {
+		return []string{query}
+	}
+	for _, candidate := range []string{query, filepath.Join(g.templatesDir, query)} {
+		if fileutil.FileOrFolderExists(candidate) {
+			if id := g.nodeID(candidate); g.Nodes[id] != nil {
+				return []string{id}
+			}
+		}
+	}
+	var ids []string
+	for _, node := range g.sortedNodes() {
+		if node.TemplateID == query {
+			ids = append(ids, node.ID)
+		}
+	}
+	return ids
+}
+
+// Dependents returns the templates depending directly or transitively
+// on the given nodes including the given templates themselves
+func (g *Graph) Dependents(ids ...string) []string {
+	visited := make(map[string]struct{})
+	queue := append([]string{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+		queue = append(queue, g.dependents[id]...)
+	}
+	var templates []string
+	for id := range visited {
+		if node, ok := g.Nodes[id]; ok && node.Type == TemplateNode {
+			templates = append(templates, id)
+		}
+	}
+	sort.Strings(templates)
+	return templates
+}
+
+// WriteJSON writes the nodes and edges of the graph as json
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	edges := g.Edges
+	if edges == nil {
+		edges = []Edge{}
+	}
+	return encoder.Encode(struct {
+		Nodes []*Node `json:"nodes"`
+		Edges []Edge  `json:"edges"`
+	}{Nodes: g.sortedNodes(), Edges: edges})
+}
+
+// WriteDOT writes the graph in the graphviz dot format. Templates
+// without any dependency or dependent are omitted.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("digraph templates {\n\trankdir=LR;\n")
+	for _, node := range g.sortedNodes() {
+		if node.Type == TemplateNode && len(g.dependents[node.ID]) == 0 && !g.hasDependencies(node.ID) {
+			continue
+		}
+		shape := "box"
+		switch node.Type {
+		case FileNode:
+			shape = "note"
+		case ModuleNode:
+			shape = "component"
+		}
+		fmt.Fprintf(&builder, "\t%s [shape=%s];\n", strconv.Quote(node.ID), shape)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&builder, "\t%s -> %s [label=%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), strconv.Quote(edge.Kind))
+	}
+	builder.WriteString("}\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// hasDependencies returns true if the node depends on any other node
+func (g *Graph) hasDependencies(id string) bool {
+	for _, edge := range g.Edges {
+		if edge.From == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedNodes returns the nodes of the graph sorted by id
+func (g *Graph) sortedNodes() []*Node {
+	nodes := make([]*Node, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes
+}
+
+// walkPayloads calls the callback for every payload of the parsed template
+// which is loaded from a file
+func walkPayloads(data interface{}, callback func(payload string)) {
+	switch value := data.(type) {
+	case map[interface{}]interface{}:
+		for key, item := range value {
+			if key == "payloads" {
+				if payloads, ok := item.(map[interface{}]interface{}); ok {
+					for _, payload := range payloads {
+						if path, ok := payload.(string); ok && isFileReference(path) {
+							callback(path)
+						}
+					}
+				}
+				continue
+			}
+			walkPayloads(item, callback)
+		}
+	case []interface{}:
+		for _, item := range value {
+			walkPayloads(item, callback)
+		}
+	}
+}
+
+// isFileReference returns true if the value is a single line file reference
+// instead of an inline snippet
+func isFileReference(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && !strings.Contains(value, "\n")
+}
+
+// hasAnyTag returns true if the tags contain any of the wanted tags
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, want := range wanted {
+			if strings.EqualFold(tag, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
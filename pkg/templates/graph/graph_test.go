@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/disk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateGraph(t *testing.T) {
+	templatesDir := t.TempDir()
+	config.DefaultConfig.SetTemplatesDir(templatesDir)
+
+	files := map[string]string{
+		"helpers/users.txt": "admin\n",
+		"helpers/flow.js":   "const ssh = require('vulmap/ssh');\n",
+		"http/login.yaml": `id: login
+info:
+  tags: auth
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}/login?user={{user}}"
+    payloads:
+      user: helpers/users.txt
+`,
+		"http/panel.yaml": `id: panel
+info:
+  tags: panel
+flow: helpers/flow.js
+`,
+		"javascript/smb.yaml": `id: smb
+info:
+  tags: network
+javascript:
+  - code: |
+      const smb = require("vulmap/smb");
+`,
+		"workflows/login-workflow.yaml": `id: login-workflow
+workflows:
+  - template: http/panel.yaml
+    subtemplates:
+      - template: http/login.yaml
+`,
+		"workflows/auth-workflow.yaml": `id: auth-workflow
+workflows:
+  - tags: auth
+`,
+		"http/missing.yaml": `id: missing
+http:
+  - payloads:
+      user: helpers/unknown.txt
+`,
+		"helpers/not-a-template.yaml": "key: value\n",
+	}
+	var templatePaths []string
+	for name, content := range files {
+		path := filepath.Join(templatesDir, filepath.FromSlash(name))
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte(content), 0644))
+		templatePaths = append(templatePaths, path)
+	}
+
+	g, err := Build(disk.NewCatalog(templatesDir), templatePaths)
+	require.Nil(t, err, "could not build template graph")
+	require.NotContains(t, g.Nodes, "helpers/not-a-template.yaml")
+	require.Equal(t, ModuleNode, g.Nodes["vulmap/ssh"].Type)
+	require.True(t, g.Nodes["helpers/unknown.txt"].Missing)
+
+	require.Equal(t, []string{"http/login.yaml", "workflows/auth-workflow.yaml", "workflows/login-workflow.yaml"}, g.Dependents(g.Resolve("helpers/users.txt")...))
+	require.Equal(t, []string{"http/panel.yaml", "workflows/login-workflow.yaml"}, g.Dependents(g.Resolve("vulmap/ssh")...))
+	require.Equal(t, []string{"javascript/smb.yaml"}, g.Dependents(g.Resolve("vulmap/smb")...))
+	require.Equal(t, []string{"http/login.yaml"}, g.Resolve("login"))
+	require.Equal(t, []string{"http/panel.yaml"}, g.Resolve(filepath.Join(templatesDir, "http", "panel.yaml")))
+	require.Empty(t, g.Resolve("unknown"))
+
+	var dot bytes.Buffer
+	require.Nil(t, g.WriteDOT(&dot))
+	require.Contains(t, dot.String(), `"workflows/auth-workflow.yaml" -> "http/login.yaml" [label="tag"];`)
+	require.Contains(t, dot.String(), `"http/panel.yaml" -> "helpers/flow.js" [label="flow"];`)
+
+	var output struct {
+		Nodes []Node `json:"nodes"`
+		Edges []Edge `json:"edges"`
+	}
+	var buf bytes.Buffer
+	require.Nil(t, g.WriteJSON(&buf))
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &output))
+	require.Len(t, output.Nodes, len(g.Nodes))
+	require.Contains(t, output.Edges, Edge{From: "workflows/login-workflow.yaml", To: "http/login.yaml", Kind: SubtemplateEdge})
+}
@@ -0,0 +1,230 @@
+package signer
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"aead.dev/minisign"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+
+	"github.com/khulnasoft-lab/gologger"
+)
+
+// Signature algorithms supported for signing and verifying templates
+const (
+	ECDSA    = "ecdsa"
+	SSH      = "ssh"
+	Minisign = "minisign"
+	Sigstore = "sigstore"
+)
+
+const (
+	// AlgorithmEnvName is the environment variable selecting the signature algorithm
+	AlgorithmEnvName = "VULMAP_SIGNATURE_ALGORITHM"
+	// PublicKeyEnvName is the environment variable with a custom public key
+	// used to verify template signatures
+	PublicKeyEnvName = "VULMAP_SIGNATURE_PUBLIC_KEY"
+)
+
+// SupportedAlgorithms contains the supported signature algorithms
+var SupportedAlgorithms = []string{ECDSA, SSH, Minisign, Sigstore}
+
+// Backend signs and verifies template data with a signature algorithm
+type Backend interface {
+	// Algorithm returns the signature algorithm of the backend
+	Algorithm() string
+	// Identifier returns the identity of the signer
+	Identifier() string
+	// Fingerprint returns the sha256 fingerprint of the public key material
+	Fingerprint() string
+	// Fragment returns the md5 hash of the public key identifying the
+	// signer of a template when re-signing it
+	Fragment() string
+	// Sign signs the data and returns the signature
+	Sign(data []byte) ([]byte, error)
+	// Verify verifies the signature of the data
+	Verify(data, signature []byte) (bool, error)
+}
+
+// NewBackend creates the backend of the signature algorithm with the
+// public key and the optional private key. If no public key is given
+// it is derived from the private key.
+func NewBackend(algorithm string, publicKey, privateKey []byte) (Backend, error) {
+	if algorithm == "" {
+		algorithm = ECDSA
+		if len(publicKey) > 0 {
+			algorithm = DetectAlgorithm(publicKey)
+		}
+	}
+	switch algorithm {
+	case ECDSA:
+		handler := &KeyHandler{UserCert: publicKey, PrivateKey: privateKey}
+		if err := handler.ParseUserCert(); err != nil {
+			return nil, err
+		}
+		if len(privateKey) > 0 {
+			if err := handler.ParsePrivateKey(); err != nil {
+				return nil, err
+			}
+		}
+		return newECDSABackend(handler), nil
+	case SSH:
+		return newSSHBackend(publicKey, privateKey)
+	case Minisign:
+		return newMinisignBackend(publicKey, privateKey)
+	case Sigstore:
+		return newSigstoreBackend(publicKey, privateKey)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, algorithm)
+	}
+}
+
+// DetectAlgorithm returns the signature algorithm of the public key
+// or an empty string if the key format is unknown
+func DetectAlgorithm(publicKey []byte) string {
+	if block, _ := pem.Decode(publicKey); block != nil {
+		switch block.Type {
+		case CertType:
+			return ECDSA
+		case "PUBLIC KEY", "CERTIFICATE":
+			return Sigstore
+		}
+		return ""
+	}
+	if _, _, _, _, err := ssh.ParseAuthorizedKey(publicKey); err == nil {
+		return SSH
+	}
+	var minisignKey minisign.PublicKey
+	if err := minisignKey.UnmarshalText(bytes.TrimSpace(publicKey)); err == nil {
+		return Minisign
+	}
+	return ""
+}
+
+// ecdsaBackend signs templates with the vulmap user certificate
+type ecdsaBackend struct {
+	handler *KeyHandler
+}
+
+func newECDSABackend(handler *KeyHandler) *ecdsaBackend {
+	return &ecdsaBackend{handler: handler}
+}
+
+func (e *ecdsaBackend) Algorithm() string { return ECDSA }
+
+func (e *ecdsaBackend) Identifier() string {
+	return e.handler.cert.Subject.CommonName
+}
+
+func (e *ecdsaBackend) Fingerprint() string {
+	return certFingerprint(e.handler)
+}
+
+func (e *ecdsaBackend) Fragment() string {
+	if e.handler.ecdsaPubKey == nil {
+		return ""
+	}
+	hashed := md5.Sum(e.handler.ecdsaPubKey.X.Bytes())
+	return hex.EncodeToString(hashed[:])
+}
+
+func (e *ecdsaBackend) Sign(data []byte) ([]byte, error) {
+	if e.handler.ecdsaKey == nil {
+		return nil, ErrNoPrivateKey
+	}
+	return signGob(e.handler.ecdsaKey, data)
+}
+
+func (e *ecdsaBackend) Verify(data, signature []byte) (bool, error) {
+	return verifyGob(e.handler.ecdsaPubKey, data, signature)
+}
+
+// signGob signs the data with the ecdsa key and returns the gob
+// encoded signature
+func signGob(privateKey *ecdsa.PrivateKey, data []byte) ([]byte, error) {
+	dataHash := sha256.Sum256(data)
+	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, privateKey, dataHash[:])
+	if err != nil {
+		return nil, err
+	}
+	var signatureData bytes.Buffer
+	if err := gob.NewEncoder(&signatureData).Encode(ecdsaSignature); err != nil {
+		return nil, err
+	}
+	return signatureData.Bytes(), nil
+}
+
+// verifyGob verifies a gob encoded signature created by signGob
+func verifyGob(publicKey *ecdsa.PublicKey, data, signatureData []byte) (bool, error) {
+	dataHash := sha256.Sum256(data)
+
+	var signature []byte
+	if err := gob.NewDecoder(bytes.NewReader(signatureData)).Decode(&signature); err != nil {
+		return false, err
+	}
+	return ecdsa.VerifyASN1(publicKey, dataHash[:], signature), nil
+}
+
+// keyFingerprint returns the sha256 fingerprint of public key material
+func keyFingerprint(publicKey []byte) string {
+	hashed := sha256.Sum256(publicKey)
+	return hex.EncodeToString(hashed[:])
+}
+
+// keyFragment returns the md5 fragment of public key material
+func keyFragment(publicKey []byte) string {
+	hashed := md5.Sum(publicKey)
+	return hex.EncodeToString(hashed[:])
+}
+
+// signDigest signs the data with a crypto.Signer hashing it with sha256
+// except for ed25519 keys which sign the data as is
+func signDigest(signer crypto.Signer, data []byte) ([]byte, error) {
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		return signer.Sign(nil, data, crypto.Hash(0))
+	}
+	hashed := sha256.Sum256(data)
+	return signer.Sign(rand.Reader, hashed[:], crypto.SHA256)
+}
+
+// verifyDigest verifies a signature created by signDigest
+func verifyDigest(publicKey crypto.PublicKey, data, signature []byte) bool {
+	hashed := sha256.Sum256(data)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, hashed[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature) == nil
+	default:
+		return false
+	}
+}
+
+// readKeyPassphrase prompts for the passphrase of an encrypted private key
+var readKeyPassphrase = func() ([]byte, error) {
+	gologger.Info().Msgf("Private Key is encrypted with passphrase")
+	fmt.Printf("[*] Enter passphrase (exit to abort): ")
+	bin, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println()
+	if string(bin) == "exit" {
+		return nil, fmt.Errorf("private key requires passphrase, but none was provided")
+	}
+	return bin, nil
+}
@@ -0,0 +1,239 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"aead.dev/minisign"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+var testTemplateData = []byte("id: signed-template\ninfo:\n  name: signed template\n  author: vulmap\n")
+
+type testSignableTemplate struct{}
+
+func (testSignableTemplate) GetFileImports() []string { return nil }
+func (testSignableTemplate) HasCodeProtocol() bool    { return false }
+
+// requireSignVerify signs the template data with the signer and verifies
+// it with the verifier
+func requireSignVerify(t *testing.T, signer, verifier *TemplateSigner) {
+	signature, err := signer.Sign(testTemplateData, testSignableTemplate{})
+	require.Nil(t, err, "could not sign template")
+	signed := append(append([]byte{}, testTemplateData...), "\n"+signature...)
+
+	verified, err := verifier.Verify(signed, testSignableTemplate{})
+	require.Nil(t, err, "could not verify template")
+	require.True(t, verified, "signature not verified")
+
+	tampered := append([]byte("# tampered\n"), signed...)
+	verified, _ = verifier.Verify(tampered, testSignableTemplate{})
+	require.False(t, verified, "tampered template verified")
+}
+
+func TestDetectAlgorithm(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	require.Nil(t, err)
+	sshKey, err := ssh.NewPublicKey(&ecdsaKey.PublicKey)
+	require.Nil(t, err)
+	minisignKey, _, err := minisign.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	minisignText, err := minisignKey.MarshalText()
+	require.Nil(t, err)
+
+	require.Equal(t, ECDSA, DetectAlgorithm(newTestCert(t, "alice")))
+	require.Equal(t, Sigstore, DetectAlgorithm(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	require.Equal(t, SSH, DetectAlgorithm(ssh.MarshalAuthorizedKey(sshKey)))
+	require.Equal(t, Minisign, DetectAlgorithm(minisignText))
+	require.Equal(t, "", DetectAlgorithm([]byte("not a key")))
+}
+
+func TestSSHBackend(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.Nil(t, err)
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	require.Nil(t, err)
+	authorizedKey := append(bytes.TrimSpace(ssh.MarshalAuthorizedKey(sshPublicKey)), " alice@example.com\n"...)
+
+	signer, err := NewTemplateSignerWithAlgorithm(SSH, nil, pem.EncodeToMemory(block))
+	require.Nil(t, err, "could not create ssh signer")
+	verifier, err := NewTemplateSigVerifierWithAlgorithm("", authorizedKey)
+	require.Nil(t, err, "could not create ssh verifier")
+	require.Equal(t, SSH, verifier.Algorithm())
+	require.Equal(t, "alice@example.com", verifier.Identifier())
+	require.Equal(t, signer.Fingerprint(), verifier.Fingerprint())
+	requireSignVerify(t, signer, verifier)
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	otherBlock, err := ssh.MarshalPrivateKey(otherKey, "")
+	require.Nil(t, err)
+	other, err := NewTemplateSignerWithAlgorithm(SSH, nil, pem.EncodeToMemory(otherBlock))
+	require.Nil(t, err)
+	signature, err := other.Sign(testTemplateData, testSignableTemplate{})
+	require.Nil(t, err)
+	verified, _ := verifier.Verify(append(append([]byte{}, testTemplateData...), "\n"+signature...), testSignableTemplate{})
+	require.False(t, verified, "signature of other key verified")
+}
+
+func TestMinisignBackend(t *testing.T) {
+	publicKey, privateKey, err := minisign.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	publicKeyText, err := publicKey.MarshalText()
+	require.Nil(t, err)
+
+	encryptedKey, err := minisign.EncryptKey("vulmap", privateKey)
+	require.Nil(t, err)
+
+	// minisign private keys are always encrypted, the passphrase prompt is replaced
+	defer func(prompt func() ([]byte, error)) { readKeyPassphrase = prompt }(readKeyPassphrase)
+	readKeyPassphrase = func() ([]byte, error) { return []byte("vulmap"), nil }
+	signer, err := NewTemplateSignerWithAlgorithm(Minisign, publicKeyText, encryptedKey)
+	require.Nil(t, err, "could not create minisign signer")
+	verifier, err := NewTemplateSigVerifierWithAlgorithm(Minisign, publicKeyText)
+	require.Nil(t, err, "could not create minisign verifier")
+	require.Equal(t, signer.Fingerprint(), verifier.Fingerprint())
+	requireSignVerify(t, signer, verifier)
+
+	readKeyPassphrase = func() ([]byte, error) { return []byte("wrong"), nil }
+	_, err = NewTemplateSignerWithAlgorithm(Minisign, nil, encryptedKey)
+	require.ErrorContains(t, err, "could not decrypt minisign private key", "could decrypt private key with wrong passphrase")
+}
+
+func TestSigstoreBackend(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	privateDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.Nil(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.Nil(t, err)
+
+	signer, err := NewTemplateSignerWithAlgorithm(Sigstore, nil, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateDER}))
+	require.Nil(t, err, "could not create sigstore signer")
+	verifier, err := NewTemplateSigVerifierWithAlgorithm("", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	require.Nil(t, err, "could not create sigstore verifier")
+	require.Equal(t, Sigstore, verifier.Algorithm())
+	requireSignVerify(t, signer, verifier)
+}
+
+func TestSigstoreKeylessBundle(t *testing.T) {
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sigstore"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.Nil(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.Nil(t, err)
+
+	// short-lived signing certificate issued to the identity
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	issuer, err := asn1.Marshal("https://issuer.example.com")
+	require.Nil(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       now.Add(-time.Minute),
+		NotAfter:        now.Add(9 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses:  []string{"alice@example.com"},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: issuer}},
+	}, ca, &leafKey.PublicKey, caKey)
+	require.Nil(t, err)
+
+	// signed data of templates is trimmed of surrounding newlines
+	data := RemoveSignatureFromData(testTemplateData)
+	signature, err := signDigest(leafKey, data)
+	require.Nil(t, err)
+	hashed := sha256.Sum256(data)
+
+	// rekor log entry of the signature with its signed entry timestamp
+	rekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	rekorDER, err := x509.MarshalPKIXPublicKey(&rekorKey.PublicKey)
+	require.Nil(t, err)
+	rekorKeyID := sha256.Sum256(rekorDER)
+	body := hashedRekord{Kind: "hashedrekord"}
+	body.Spec.Data.Hash.Algorithm = "sha256"
+	body.Spec.Data.Hash.Value = hex.EncodeToString(hashed[:])
+	body.Spec.Signature.Content = signature
+	canonicalizedBody, err := json.Marshal(body)
+	require.Nil(t, err)
+	entry := sigstoreTlogEntry{LogIndex: 42, IntegratedTime: now.Unix(), CanonicalizedBody: canonicalizedBody}
+	entry.LogID.KeyID = rekorKeyID[:]
+	payload, err := json.Marshal(rekorPayload{
+		Body:           base64.StdEncoding.EncodeToString(canonicalizedBody),
+		IntegratedTime: entry.IntegratedTime,
+		LogID:          hex.EncodeToString(rekorKeyID[:]),
+		LogIndex:       entry.LogIndex,
+	})
+	require.Nil(t, err)
+	set, err := signDigest(rekorKey, payload)
+	require.Nil(t, err)
+	entry.InclusionPromise = &struct {
+		SignedEntryTimestamp []byte `json:"signedEntryTimestamp"`
+	}{SignedEntryTimestamp: set}
+
+	bundle := &sigstoreBundle{MediaType: sigstoreBundleMediaType, MessageSignature: &sigstoreMessageSignature{Signature: signature}}
+	bundle.MessageSignature.MessageDigest.Algorithm = "SHA2_256"
+	bundle.MessageSignature.MessageDigest.Digest = hashed[:]
+	bundle.VerificationMaterial.Certificate = &sigstoreCertificate{RawBytes: leafDER}
+	bundle.VerificationMaterial.TlogEntries = []sigstoreTlogEntry{entry}
+	bundleData, err := json.Marshal(bundle)
+	require.Nil(t, err)
+
+	roots := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	t.Setenv(SigstoreRekorKeyEnvName, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rekorDER})))
+	t.Setenv(SigstoreIssuerEnvName, "https://issuer.example.com")
+
+	_, err = NewTemplateSigVerifierWithAlgorithm(Sigstore, roots)
+	require.NotNil(t, err, "created keyless verifier without identity")
+
+	t.Setenv(SigstoreIdentityEnvName, "alice@example.com")
+	verifier, err := NewTemplateSigVerifierWithAlgorithm(Sigstore, roots)
+	require.Nil(t, err, "could not create keyless verifier")
+	require.Equal(t, "alice@example.com", verifier.Identifier())
+
+	signed := append(append([]byte{}, testTemplateData...), "\n"+SignaturePattern+hex.EncodeToString(bundleData)...)
+	verified, err := verifier.Verify(signed, testSignableTemplate{})
+	require.Nil(t, err, "could not verify keyless bundle")
+	require.True(t, verified, "keyless bundle not verified")
+
+	verified, _ = verifier.Verify(append([]byte("# tampered\n"), signed...), testSignableTemplate{})
+	require.False(t, verified, "tampered template verified")
+
+	t.Setenv(SigstoreIdentityEnvName, "bob@example.com")
+	verifier, err = NewTemplateSigVerifierWithAlgorithm(Sigstore, roots)
+	require.Nil(t, err)
+	verified, err = verifier.Verify(signed, testSignableTemplate{})
+	require.NotNil(t, err, "verified bundle of other identity")
+	require.False(t, verified)
+}
@@ -1,6 +1,8 @@
 package signer
 
 import (
+	"os"
+
 	"github.com/khulnasoft-lab/gologger"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
 	"github.com/khulnasoft-lab/vulmap/pkg/keys"
@@ -18,17 +20,50 @@ func init() {
 		gologger.Error().Msgf("Could not parse pd vulmap certificate: %s\n", err)
 		return
 	}
-	DefaultTemplateVerifiers = append(DefaultTemplateVerifiers, &TemplateSigner{handler: h})
+	DefaultTemplateVerifiers = append(DefaultTemplateVerifiers, &TemplateSigner{backend: newECDSABackend(h)})
 
 	// try to load default user cert
 	usr := &KeyHandler{}
 	if err := usr.ReadCert(CertEnvVarName, config.DefaultConfig.GetKeysDir()); err == nil {
 		if err := usr.ParseUserCert(); err != nil {
 			gologger.Error().Msgf("malformed user cert found: %s\n", err)
-			return
+		} else {
+			DefaultTemplateVerifiers = append(DefaultTemplateVerifiers, &TemplateSigner{backend: newECDSABackend(usr)})
+		}
+	}
+
+	// try to load custom public key of the signature algorithm
+	if publicKey := usr.getEnvContent(PublicKeyEnvName); publicKey != nil {
+		if verifier, err := NewTemplateSigVerifierWithAlgorithm(os.Getenv(AlgorithmEnvName), publicKey); err != nil {
+			gologger.Error().Msgf("malformed custom public key found: %s\n", err)
+		} else {
+			DefaultTemplateVerifiers = append(DefaultTemplateVerifiers, verifier)
+		}
+	}
+
+	DefaultTemplateVerifiers = withTrustStore(DefaultTemplateVerifiers, DefaultTrustStoreDir())
+}
+
+// withTrustStore adds the signers of the trust store to the verifiers
+// and removes revoked signers
+func withTrustStore(verifiers []*TemplateSigner, dir string) []*TemplateSigner {
+	trustStore, err := LoadTrustStore(dir)
+	if err != nil {
+		gologger.Error().Msgf("Could not load trust store: %s\n", err)
+		return verifiers
+	}
+	trusted, err := trustStore.Verifiers()
+	if err != nil {
+		gologger.Error().Msgf("Could not load trusted signers: %s\n", err)
+	}
+
+	result := make([]*TemplateSigner, 0, len(verifiers)+len(trusted))
+	for _, verifier := range append(verifiers, trusted...) {
+		if !trustStore.IsRevoked(verifier.Fingerprint()) {
+			result = append(result, verifier)
 		}
-		DefaultTemplateVerifiers = append(DefaultTemplateVerifiers, &TemplateSigner{handler: usr})
 	}
+	return result
 }
 
 // AddSignerToDefault adds a signer to the default list of signers
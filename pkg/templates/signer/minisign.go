@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"aead.dev/minisign"
+)
+
+// minisignBackend signs templates with minisign keys
+type minisignBackend struct {
+	publicKey  minisign.PublicKey
+	privateKey *minisign.PrivateKey
+}
+
+func newMinisignBackend(publicKey, privateKey []byte) (*minisignBackend, error) {
+	backend := &minisignBackend{}
+	if len(privateKey) > 0 {
+		// minisign private keys are always encrypted
+		passphrase, err := readKeyPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		key, err := minisign.DecryptKey(string(passphrase), bytes.TrimSpace(privateKey))
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt minisign private key: %w", err)
+		}
+		backend.privateKey = &key
+		backend.publicKey = key.Public().(minisign.PublicKey)
+	}
+	if len(publicKey) > 0 {
+		var parsed minisign.PublicKey
+		if err := parsed.UnmarshalText(bytes.TrimSpace(publicKey)); err != nil {
+			return nil, err
+		}
+		if backend.privateKey != nil && !parsed.Equal(backend.publicKey) {
+			return nil, errors.New("minisign public key does not match the private key")
+		}
+		backend.publicKey = parsed
+	}
+	if backend.privateKey == nil && len(publicKey) == 0 {
+		return nil, ErrNoCertificate
+	}
+	return backend, nil
+}
+
+func (m *minisignBackend) Algorithm() string { return Minisign }
+
+func (m *minisignBackend) Identifier() string {
+	return "minisign:" + strings.ToUpper(strconv.FormatUint(m.publicKey.ID(), 16))
+}
+
+func (m *minisignBackend) Fingerprint() string {
+	return keyFingerprint([]byte(m.publicKey.String()))
+}
+
+func (m *minisignBackend) Fragment() string {
+	return keyFragment([]byte(m.publicKey.String()))
+}
+
+func (m *minisignBackend) Sign(data []byte) ([]byte, error) {
+	if m.privateKey == nil {
+		return nil, ErrNoPrivateKey
+	}
+	return minisign.Sign(*m.privateKey, data), nil
+}
+
+func (m *minisignBackend) Verify(data, signature []byte) (bool, error) {
+	return minisign.Verify(m.publicKey, data, signature), nil
+}
@@ -0,0 +1,424 @@
+package signer
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
+)
+
+const (
+	// SigstoreIdentityEnvName is the environment variable with the certificate
+	// identity (email or uri) required for keyless sigstore bundles
+	SigstoreIdentityEnvName = "VULMAP_SIGSTORE_IDENTITY"
+	// SigstoreIssuerEnvName is the environment variable with the oidc issuer
+	// required for keyless sigstore bundles
+	SigstoreIssuerEnvName = "VULMAP_SIGSTORE_ISSUER"
+	// SigstoreRekorKeyEnvName is the environment variable with the rekor public
+	// key verifying the transparency log entries of sigstore bundles
+	SigstoreRekorKeyEnvName = "VULMAP_SIGSTORE_REKOR_KEY"
+
+	sigstoreBundleMediaType = "application/vnd.dev.sigstore.bundle.v0.3+json"
+)
+
+var (
+	// oids of the fulcio certificate extensions with the oidc issuer
+	oidFulcioIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// sigstoreBundle is a sigstore bundle with a message signature
+type sigstoreBundle struct {
+	MediaType            string `json:"mediaType"`
+	VerificationMaterial struct {
+		PublicKey            *sigstorePublicKey   `json:"publicKey,omitempty"`
+		Certificate          *sigstoreCertificate `json:"certificate,omitempty"`
+		X509CertificateChain *struct {
+			Certificates []sigstoreCertificate `json:"certificates"`
+		} `json:"x509CertificateChain,omitempty"`
+		TlogEntries []sigstoreTlogEntry `json:"tlogEntries,omitempty"`
+	} `json:"verificationMaterial"`
+	MessageSignature *sigstoreMessageSignature `json:"messageSignature,omitempty"`
+}
+
+type sigstorePublicKey struct {
+	Hint string `json:"hint,omitempty"`
+}
+
+type sigstoreCertificate struct {
+	RawBytes []byte `json:"rawBytes"`
+}
+
+type sigstoreMessageSignature struct {
+	MessageDigest struct {
+		Algorithm string `json:"algorithm"`
+		Digest    []byte `json:"digest"`
+	} `json:"messageDigest"`
+	Signature []byte `json:"signature"`
+}
+
+// sigstoreTlogEntry is a rekor transparency log entry of a bundle
+type sigstoreTlogEntry struct {
+	LogIndex int64 `json:"logIndex,string"`
+	LogID    struct {
+		KeyID []byte `json:"keyId"`
+	} `json:"logId"`
+	IntegratedTime   int64 `json:"integratedTime,string"`
+	InclusionPromise *struct {
+		SignedEntryTimestamp []byte `json:"signedEntryTimestamp"`
+	} `json:"inclusionPromise,omitempty"`
+	CanonicalizedBody []byte `json:"canonicalizedBody"`
+}
+
+// rekorPayload is the canonical payload signed by the signed entry timestamp
+type rekorPayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// hashedRekord is the canonicalized body of a hashedrekord log entry
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content []byte `json:"content"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// sigstoreBackend signs templates with sigstore bundles and verifies them
+// offline, either with a public key or keyless with fulcio certificates
+// chaining to trusted roots and logged in rekor.
+type sigstoreBackend struct {
+	// publicKey verifies bundles signed with a key
+	publicKey crypto.PublicKey
+	signer    crypto.Signer
+	// roots verify the certificates of keyless bundles
+	roots    *x509.CertPool
+	rootsRaw []byte
+	identity string
+	issuer   string
+	rekorKey crypto.PublicKey
+}
+
+func newSigstoreBackend(publicKey, privateKey []byte) (*sigstoreBackend, error) {
+	backend := &sigstoreBackend{
+		identity: os.Getenv(SigstoreIdentityEnvName),
+		issuer:   os.Getenv(SigstoreIssuerEnvName),
+	}
+	if rekorKey := (&KeyHandler{}).getEnvContent(SigstoreRekorKeyEnvName); rekorKey != nil {
+		key, err := parsePEMPublicKey(rekorKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse rekor public key: %w", err)
+		}
+		backend.rekorKey = key
+	}
+	if len(privateKey) > 0 {
+		signer, err := parsePEMPrivateKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse sigstore private key: %w", err)
+		}
+		backend.signer, backend.publicKey = signer, signer.Public()
+	}
+
+	for rest := publicKey; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if backend.publicKey != nil && !publicKeyEqual(backend.publicKey, key) {
+				return nil, errors.New("sigstore public key does not match the private key")
+			}
+			backend.publicKey = key
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if backend.roots == nil {
+				backend.roots = x509.NewCertPool()
+			}
+			backend.roots.AddCert(cert)
+			backend.rootsRaw = append(backend.rootsRaw, cert.Raw...)
+		default:
+			return nil, fmt.Errorf("unsupported sigstore key material %q", block.Type)
+		}
+	}
+
+	switch {
+	case backend.publicKey != nil && backend.roots != nil:
+		return nil, errors.New("sigstore key material must contain either a public key or root certificates")
+	case backend.roots != nil && backend.identity == "":
+		return nil, fmt.Errorf("keyless sigstore verification requires a certificate identity (%s)", SigstoreIdentityEnvName)
+	case backend.roots != nil && backend.rekorKey == nil:
+		return nil, fmt.Errorf("keyless sigstore verification requires a rekor public key (%s)", SigstoreRekorKeyEnvName)
+	case backend.publicKey == nil && backend.roots == nil:
+		return nil, ErrNoCertificate
+	}
+	return backend, nil
+}
+
+func (s *sigstoreBackend) Algorithm() string { return Sigstore }
+
+func (s *sigstoreBackend) Identifier() string {
+	if s.roots != nil {
+		return s.identity
+	}
+	return "sigstore:" + s.Fingerprint()[:16]
+}
+
+func (s *sigstoreBackend) Fingerprint() string {
+	if s.roots != nil {
+		return keyFingerprint(append(append([]byte{}, s.rootsRaw...), s.identity...))
+	}
+	der, _ := x509.MarshalPKIXPublicKey(s.publicKey)
+	return keyFingerprint(der)
+}
+
+func (s *sigstoreBackend) Fragment() string {
+	if s.roots != nil {
+		return ""
+	}
+	der, _ := x509.MarshalPKIXPublicKey(s.publicKey)
+	return keyFragment(der)
+}
+
+// Sign signs the data with the private key and returns a sigstore bundle.
+// Keyless signing requires an oidc identity and is not supported offline.
+func (s *sigstoreBackend) Sign(data []byte) ([]byte, error) {
+	if s.signer == nil {
+		return nil, ErrNoPrivateKey
+	}
+	signature, err := signDigest(s.signer, data)
+	if err != nil {
+		return nil, err
+	}
+	hashed := sha256.Sum256(data)
+
+	bundle := &sigstoreBundle{
+		MediaType:        sigstoreBundleMediaType,
+		MessageSignature: &sigstoreMessageSignature{Signature: signature},
+	}
+	bundle.VerificationMaterial.PublicKey = &sigstorePublicKey{Hint: s.Fingerprint()}
+	bundle.MessageSignature.MessageDigest.Algorithm = "SHA2_256"
+	bundle.MessageSignature.MessageDigest.Digest = hashed[:]
+	return json.Marshal(bundle)
+}
+
+// Verify verifies the sigstore bundle of the data offline
+func (s *sigstoreBackend) Verify(data, signature []byte) (bool, error) {
+	var bundle sigstoreBundle
+	if err := json.Unmarshal(signature, &bundle); err != nil {
+		return false, fmt.Errorf("invalid sigstore bundle: %w", err)
+	}
+	if bundle.MessageSignature == nil {
+		return false, errors.New("sigstore bundle has no message signature")
+	}
+	hashed := sha256.Sum256(data)
+	if digest := bundle.MessageSignature.MessageDigest; len(digest.Digest) > 0 {
+		if digest.Algorithm != "SHA2_256" {
+			return false, fmt.Errorf("unsupported sigstore digest algorithm %s", digest.Algorithm)
+		}
+		if !bytes.Equal(digest.Digest, hashed[:]) {
+			return false, nil
+		}
+	}
+
+	publicKey := s.publicKey
+	if s.roots != nil {
+		// keyless certificates are short-lived and verified at the time
+		// the signature was logged in rekor
+		integratedTime, err := s.verifyTlogEntries(&bundle, hashed[:])
+		if err != nil {
+			return false, err
+		}
+		leaf, err := s.verifyCertificate(&bundle, integratedTime)
+		if err != nil {
+			return false, err
+		}
+		publicKey = leaf.PublicKey
+	} else if bundle.VerificationMaterial.Certificate != nil || bundle.VerificationMaterial.X509CertificateChain != nil {
+		return false, errors.New("sigstore bundle is not signed with a public key")
+	}
+	return verifyDigest(publicKey, data, bundle.MessageSignature.Signature), nil
+}
+
+// verifyCertificate verifies the signing certificate of a keyless bundle
+// chains to the trusted roots at the time it was logged and was issued
+// to the expected identity
+func (s *sigstoreBackend) verifyCertificate(bundle *sigstoreBundle, integratedTime time.Time) (*x509.Certificate, error) {
+	var rawCerts [][]byte
+	if material := bundle.VerificationMaterial; material.Certificate != nil {
+		rawCerts = append(rawCerts, material.Certificate.RawBytes)
+	} else if material.X509CertificateChain != nil {
+		for _, cert := range material.X509CertificateChain.Certificates {
+			rawCerts = append(rawCerts, cert.RawBytes)
+		}
+	}
+	if len(rawCerts) == 0 {
+		return nil, errors.New("sigstore bundle has no signing certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	leaf := certs[0]
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         s.roots,
+		Intermediates: intermediates,
+		CurrentTime:   integratedTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return nil, fmt.Errorf("could not verify sigstore certificate: %w", err)
+	}
+
+	identities := leaf.EmailAddresses
+	for _, uri := range leaf.URIs {
+		identities = append(identities, uri.String())
+	}
+	if !sliceutil.Contains(identities, s.identity) {
+		return nil, fmt.Errorf("sigstore certificate was not issued to %s", s.identity)
+	}
+	if s.issuer != "" && certificateIssuer(leaf) != s.issuer {
+		return nil, fmt.Errorf("sigstore certificate was not issued by %s", s.issuer)
+	}
+	return leaf, nil
+}
+
+// verifyTlogEntries verifies the signed entry timestamp of a rekor log
+// entry of the signature and returns the time it was logged at
+func (s *sigstoreBackend) verifyTlogEntries(bundle *sigstoreBundle, hashed []byte) (time.Time, error) {
+	if s.rekorKey == nil {
+		return time.Time{}, fmt.Errorf("no rekor public key to verify sigstore bundle (%s)", SigstoreRekorKeyEnvName)
+	}
+	der, err := x509.MarshalPKIXPublicKey(s.rekorKey)
+	if err != nil {
+		return time.Time{}, err
+	}
+	rekorKeyID := sha256.Sum256(der)
+
+	for _, entry := range bundle.VerificationMaterial.TlogEntries {
+		if entry.InclusionPromise == nil || !bytes.Equal(entry.LogID.KeyID, rekorKeyID[:]) {
+			continue
+		}
+		payload, err := json.Marshal(rekorPayload{
+			Body:           base64.StdEncoding.EncodeToString(entry.CanonicalizedBody),
+			IntegratedTime: entry.IntegratedTime,
+			LogID:          hex.EncodeToString(entry.LogID.KeyID),
+			LogIndex:       entry.LogIndex,
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !verifyDigest(s.rekorKey, payload, entry.InclusionPromise.SignedEntryTimestamp) {
+			return time.Time{}, errors.New("invalid signed entry timestamp of sigstore bundle")
+		}
+
+		var body hashedRekord
+		if err := json.Unmarshal(entry.CanonicalizedBody, &body); err != nil {
+			return time.Time{}, err
+		}
+		if body.Kind != "hashedrekord" || body.Spec.Data.Hash.Algorithm != "sha256" {
+			return time.Time{}, fmt.Errorf("unsupported rekor entry %s", body.Kind)
+		}
+		if body.Spec.Data.Hash.Value != hex.EncodeToString(hashed) || !bytes.Equal(body.Spec.Signature.Content, bundle.MessageSignature.Signature) {
+			return time.Time{}, errors.New("rekor entry does not match the sigstore bundle")
+		}
+		return time.Unix(entry.IntegratedTime, 0), nil
+	}
+	return time.Time{}, errors.New("sigstore bundle has no rekor entry of a trusted log")
+}
+
+// certificateIssuer returns the oidc issuer of a fulcio certificate
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidFulcioIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidFulcioIssuer):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// parsePEMPublicKey parses a pem encoded pkix public key
+func parsePEMPublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block containing the public key")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// parsePEMPrivateKey parses a pem encoded pkcs8 or ec private key
+func parsePEMPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block containing the private key")
+	}
+	if x509.IsEncryptedPEMBlock(block) { // nolint: all
+		passphrase, err := readKeyPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		if block.Bytes, err = x509.DecryptPEMBlock(block, passphrase); err != nil { // nolint: all
+			return nil, err
+		}
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return signer, nil
+}
+
+// publicKeyEqual returns true if both public keys are equal
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
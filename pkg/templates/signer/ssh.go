@@ -0,0 +1,165 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// SSHSignatureNamespace is the namespace of ssh template signatures
+	// as used by `ssh-keygen -Y sign -n vulmap-template`
+	SSHSignatureNamespace = "vulmap-template"
+
+	sshSigMagic     = "SSHSIG"
+	sshSigVersion   = 1
+	sshSigHash      = "sha512"
+	sshSigPEMHeader = "SSH SIGNATURE"
+)
+
+// sshBackend signs templates with ssh keys using the sshsig format
+// of openssh so signatures can be verified by ssh-keygen as well
+type sshBackend struct {
+	publicKey ssh.PublicKey
+	signer    ssh.Signer
+	comment   string
+}
+
+func newSSHBackend(publicKey, privateKey []byte) (*sshBackend, error) {
+	backend := &sshBackend{}
+	if len(privateKey) > 0 {
+		signer, err := ssh.ParsePrivateKey(privateKey)
+		var missingErr *ssh.PassphraseMissingError
+		if errors.As(err, &missingErr) {
+			var passphrase []byte
+			if passphrase, err = readKeyPassphrase(); err != nil {
+				return nil, err
+			}
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(privateKey, passphrase)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not parse ssh private key: %w", err)
+		}
+		backend.signer = signer
+		backend.publicKey = signer.PublicKey()
+	}
+	if len(publicKey) > 0 {
+		parsed, comment, _, _, err := ssh.ParseAuthorizedKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse ssh public key: %w", err)
+		}
+		if backend.publicKey != nil && !bytes.Equal(backend.publicKey.Marshal(), parsed.Marshal()) {
+			return nil, errors.New("ssh public key does not match the private key")
+		}
+		backend.publicKey, backend.comment = parsed, comment
+	}
+	if backend.publicKey == nil {
+		return nil, ErrNoCertificate
+	}
+	return backend, nil
+}
+
+func (s *sshBackend) Algorithm() string { return SSH }
+
+func (s *sshBackend) Identifier() string {
+	if s.comment != "" {
+		return s.comment
+	}
+	return ssh.FingerprintSHA256(s.publicKey)
+}
+
+func (s *sshBackend) Fingerprint() string {
+	return keyFingerprint(s.publicKey.Marshal())
+}
+
+func (s *sshBackend) Fragment() string {
+	return keyFragment(s.publicKey.Marshal())
+}
+
+// sshSignedData is the data signed by a sshsig signature
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+// sshSignature is the sshsig signature blob
+type sshSignature struct {
+	Version       uint32
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+func (s *sshBackend) signedData(data []byte) []byte {
+	hashed := sha512.Sum512(data)
+	return append([]byte(sshSigMagic), ssh.Marshal(sshSignedData{
+		Namespace:     SSHSignatureNamespace,
+		HashAlgorithm: sshSigHash,
+		Hash:          string(hashed[:]),
+	})...)
+}
+
+func (s *sshBackend) Sign(data []byte) ([]byte, error) {
+	if s.signer == nil {
+		return nil, ErrNoPrivateKey
+	}
+	var signature *ssh.Signature
+	var err error
+	// ssh-rsa signatures use sha1, openssh requires rsa-sha2-512 for sshsig
+	if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.publicKey.Type() == ssh.KeyAlgoRSA {
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, s.signedData(data), ssh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = s.signer.Sign(rand.Reader, s.signedData(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+	blob := append([]byte(sshSigMagic), ssh.Marshal(sshSignature{
+		Version:       sshSigVersion,
+		PublicKey:     string(s.publicKey.Marshal()),
+		Namespace:     SSHSignatureNamespace,
+		HashAlgorithm: sshSigHash,
+		Signature:     string(ssh.Marshal(signature)),
+	})...)
+	return pem.EncodeToMemory(&pem.Block{Type: sshSigPEMHeader, Bytes: blob}), nil
+}
+
+func (s *sshBackend) Verify(data, signatureData []byte) (bool, error) {
+	block, _ := pem.Decode(signatureData)
+	if block == nil || block.Type != sshSigPEMHeader {
+		return false, errors.New("invalid ssh signature")
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(sshSigMagic)) {
+		return false, errors.New("invalid ssh signature magic")
+	}
+	var sig sshSignature
+	if err := ssh.Unmarshal(block.Bytes[len(sshSigMagic):], &sig); err != nil {
+		return false, err
+	}
+	if sig.Version != sshSigVersion {
+		return false, fmt.Errorf("unsupported ssh signature version %d", sig.Version)
+	}
+	if sig.Namespace != SSHSignatureNamespace || sig.HashAlgorithm != sshSigHash {
+		return false, fmt.Errorf("unsupported ssh signature namespace %q with hash %q", sig.Namespace, sig.HashAlgorithm)
+	}
+	if !bytes.Equal([]byte(sig.PublicKey), s.publicKey.Marshal()) {
+		return false, nil
+	}
+	var signature ssh.Signature
+	if err := ssh.Unmarshal([]byte(sig.Signature), &signature); err != nil {
+		return false, err
+	}
+	if signature.Format == ssh.KeyAlgoRSA {
+		return false, errors.New("ssh-rsa signatures using sha1 are not supported")
+	}
+	return s.publicKey.Verify(s.signedData(data), &signature) == nil, nil
+}
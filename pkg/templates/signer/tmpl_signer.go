@@ -2,15 +2,11 @@ package signer
 
 import (
 	"bytes"
-	"crypto/ecdsa"
-	"crypto/md5"
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/gob"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
@@ -18,6 +14,7 @@ import (
 	"github.com/khulnasoft-lab/gologger"
 	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
 	errorutil "github.com/khulnasoft-lab/utils/errors"
+	sliceutil "github.com/khulnasoft-lab/utils/slice"
 )
 
 var (
@@ -45,13 +42,71 @@ type SignableTemplate interface {
 
 type TemplateSigner struct {
 	sync.Once
-	handler  *KeyHandler
+	backend  Backend
 	fragment string
+	// name is the name of the signer in the trust store
+	name string
+	// scopes restrict the templates the signer is trusted for
+	scopes []string
 }
 
 // Identifier returns the identifier for the template signer
 func (t *TemplateSigner) Identifier() string {
-	return t.handler.cert.Subject.CommonName
+	if t.name != "" {
+		return t.name
+	}
+	return t.backend.Identifier()
+}
+
+// Algorithm returns the signature algorithm of the template signer
+func (t *TemplateSigner) Algorithm() string {
+	return t.backend.Algorithm()
+}
+
+// Fingerprint returns the sha256 fingerprint of the signer public key
+func (t *TemplateSigner) Fingerprint() string {
+	return t.backend.Fingerprint()
+}
+
+// Scopes returns the scopes restricting the templates the signer is trusted for
+func (t *TemplateSigner) Scopes() []string {
+	return t.scopes
+}
+
+// InScope returns true if the signer is trusted to sign a template
+// with the protocol types located at the template path
+func (t *TemplateSigner) InScope(protocolTypes []string, templatePath string) bool {
+	var allowedProtocols, allowedPaths []string
+	for _, scope := range t.scopes {
+		if strings.HasPrefix(scope, PathScopePrefix) {
+			allowedPaths = append(allowedPaths, strings.TrimPrefix(scope, PathScopePrefix))
+		} else {
+			allowedProtocols = append(allowedProtocols, scope)
+		}
+	}
+	if len(allowedProtocols) > 0 {
+		for _, protocolType := range protocolTypes {
+			if !sliceutil.Contains(allowedProtocols, protocolType) {
+				return false
+			}
+		}
+	}
+	if len(allowedPaths) == 0 {
+		return true
+	}
+	absPath, err := filepath.Abs(templatePath)
+	if err != nil {
+		return false
+	}
+	for _, allowedPath := range allowedPaths {
+		if !filepath.IsAbs(allowedPath) {
+			allowedPath = filepath.Join(config.DefaultConfig.TemplatesDirectory, allowedPath)
+		}
+		if relPath, err := filepath.Rel(filepath.Clean(allowedPath), absPath); err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
 }
 
 // fragment is optional part of signature that is used to identify the user
@@ -59,10 +114,7 @@ func (t *TemplateSigner) Identifier() string {
 func (t *TemplateSigner) GetUserFragment() string {
 	// wrap with sync.Once to reduce unnecessary md5 hashing
 	t.Do(func() {
-		if t.handler.ecdsaPubKey != nil {
-			hashed := md5.Sum(t.handler.ecdsaPubKey.X.Bytes())
-			t.fragment = fmt.Sprintf("%x", hashed)
-		}
+		t.fragment = t.backend.Fragment()
 	})
 	return t.fragment
 }
@@ -109,16 +161,11 @@ func (t *TemplateSigner) Sign(data []byte, tmpl SignableTemplate) (string, error
 // Note: this should not be used for signing templates as file references
 // in templates are not processed use template.SignTemplate() instead
 func (t *TemplateSigner) sign(data []byte) (string, error) {
-	dataHash := sha256.Sum256(data)
-	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, t.handler.ecdsaKey, dataHash[:])
+	signatureData, err := t.backend.Sign(data)
 	if err != nil {
 		return "", err
 	}
-	var signatureData bytes.Buffer
-	if err := gob.NewEncoder(&signatureData).Encode(ecdsaSignature); err != nil {
-		return "", err
-	}
-	return fmt.Sprintf(SignatureFmt, signatureData.Bytes(), t.GetUserFragment()), nil
+	return fmt.Sprintf(SignatureFmt, signatureData, t.GetUserFragment()), nil
 }
 
 // Verify verifies the given template with the template signer
@@ -128,10 +175,7 @@ func (t *TemplateSigner) Verify(data []byte, tmpl SignableTemplate) (bool, error
 		return false, errors.New("digest not found")
 	}
 
-	digestData = bytes.TrimSpace(bytes.TrimPrefix(digestData, []byte(SignaturePattern)))
-	// remove fragment from digest as it is used for re-signing purposes only
-	digestString := strings.TrimSuffix(string(digestData), ":"+t.GetUserFragment())
-	digest, err := hex.DecodeString(digestString)
+	digest, err := t.decodeDigest(digestData)
 	if err != nil {
 		return false, err
 	}
@@ -150,17 +194,34 @@ func (t *TemplateSigner) Verify(data []byte, tmpl SignableTemplate) (bool, error
 	return t.verify(buff.Bytes(), digest)
 }
 
+// SignData signs arbitrary data such as template bundles with the template
+// signer and returns the signature in the template digest format
+func (t *TemplateSigner) SignData(data []byte) (string, error) {
+	return t.sign(data)
+}
+
+// VerifyData verifies the signature of arbitrary data created by SignData
+func (t *TemplateSigner) VerifyData(data []byte, signature string) (bool, error) {
+	digest, err := t.decodeDigest([]byte(signature))
+	if err != nil {
+		return false, err
+	}
+	return t.verify(data, digest)
+}
+
+// decodeDigest decodes the signature of a `# digest: <signature>:<fragment>` line
+func (t *TemplateSigner) decodeDigest(digestData []byte) ([]byte, error) {
+	digestData = bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(digestData), []byte(strings.TrimSpace(SignaturePattern))))
+	// remove fragment from digest as it is used for re-signing purposes only
+	digestString := strings.TrimSuffix(string(digestData), ":"+t.GetUserFragment())
+	return hex.DecodeString(digestString)
+}
+
 // Verify verifies the given data with the template signer
 // Note: this should not be used for verifying templates as file references
 // in templates are not processed
 func (t *TemplateSigner) verify(data, signatureData []byte) (bool, error) {
-	dataHash := sha256.Sum256(data)
-
-	var signature []byte
-	if err := gob.NewDecoder(bytes.NewReader(signatureData)).Decode(&signature); err != nil {
-		return false, err
-	}
-	return ecdsa.VerifyASN1(t.handler.ecdsaPubKey, dataHash[:], signature), nil
+	return t.backend.Verify(data, signatureData)
 }
 
 // NewTemplateSigner creates a new signer for signing templates
@@ -198,10 +259,29 @@ func NewTemplateSigner(cert, privateKey []byte) (*TemplateSigner, error) {
 		return nil, err
 	}
 	return &TemplateSigner{
-		handler: handler,
+		backend: newECDSABackend(handler),
 	}, nil
 }
 
+// NewTemplateSignerWithAlgorithm creates a new signer for signing templates
+// with the signature algorithm. Algorithms other than ecdsa read the private
+// key from the environment if none is given and derive the public key from it.
+func NewTemplateSignerWithAlgorithm(algorithm string, publicKey, privateKey []byte) (*TemplateSigner, error) {
+	if algorithm == "" || algorithm == ECDSA {
+		return NewTemplateSigner(publicKey, privateKey)
+	}
+	if privateKey == nil {
+		if privateKey = (&KeyHandler{}).getEnvContent(PrivateKeyEnvName); privateKey == nil {
+			return nil, fmt.Errorf("%s private key is required for signing with %s", PrivateKeyEnvName, algorithm)
+		}
+	}
+	backend, err := NewBackend(algorithm, publicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateSigner{backend: backend}, nil
+}
+
 // NewTemplateSignerFromFiles creates a new signer for signing templates
 func NewTemplateSignerFromFiles(cert, privKey string) (*TemplateSigner, error) {
 	certData, err := os.ReadFile(cert)
@@ -229,6 +309,17 @@ func NewTemplateSigVerifier(cert []byte) (*TemplateSigner, error) {
 		return nil, err
 	}
 	return &TemplateSigner{
-		handler: handler,
+		backend: newECDSABackend(handler),
 	}, nil
 }
+
+// NewTemplateSigVerifierWithAlgorithm creates a new signer for verifying templates
+// with the public key of the signature algorithm. The algorithm is inferred from
+// the public key if none is given.
+func NewTemplateSigVerifierWithAlgorithm(algorithm string, publicKey []byte) (*TemplateSigner, error) {
+	backend, err := NewBackend(algorithm, publicKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateSigner{backend: backend}, nil
+}
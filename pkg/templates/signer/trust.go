@@ -0,0 +1,247 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	fileutil "github.com/khulnasoft-lab/utils/file"
+	"github.com/khulnasoft-lab/vulmap/pkg/catalog/config"
+)
+
+const (
+	// TrustStoreEnvName is the environment variable overriding the trust store directory
+	TrustStoreEnvName = "VULMAP_TRUST_STORE"
+	// TrustStoreFilename is the filename of the trust store index
+	TrustStoreFilename = "trust.yaml"
+	// PathScopePrefix is the prefix of scopes restricting a signer to a directory
+	PathScopePrefix = "path:"
+)
+
+// TrustedSigner is a signer trusted to sign templates
+type TrustedSigner struct {
+	// Name is the name of the signer
+	Name string `yaml:"name"`
+	// Algorithm is the signature algorithm of the signer (default ecdsa)
+	Algorithm string `yaml:"algorithm,omitempty"`
+	// Certificate is the certificate or public key filename in the trust store directory
+	Certificate string `yaml:"certificate"`
+	// Fingerprint is the sha256 fingerprint of the certificate or public key
+	Fingerprint string `yaml:"fingerprint"`
+	// Scopes restrict the templates the signer is trusted for.
+	//
+	// A scope is either a protocol type (e.g. code) the signer may sign
+	// or a path:<dir> directory the signed templates must be located in.
+	// A signer without scopes is trusted for all templates.
+	Scopes []string `yaml:"scopes,omitempty"`
+	// AddedAt is the time the signer was added to the trust store
+	AddedAt time.Time `yaml:"added-at"`
+}
+
+// RevokedSigner is a signer whose signatures are no longer trusted
+type RevokedSigner struct {
+	// Name is the name of the signer
+	Name string `yaml:"name,omitempty"`
+	// Fingerprint is the sha256 fingerprint of the certificate or public key
+	Fingerprint string `yaml:"fingerprint"`
+	// RevokedAt is the time the signer was revoked
+	RevokedAt time.Time `yaml:"revoked-at"`
+}
+
+// TrustStore is a directory of trusted signer certificates
+// with a revocation list
+type TrustStore struct {
+	Signers []*TrustedSigner `yaml:"signers,omitempty"`
+	Revoked []*RevokedSigner `yaml:"revoked,omitempty"`
+
+	dir string
+}
+
+// DefaultTrustStoreDir returns the trust store directory from the
+// environment variable or the vulmap keys directory
+func DefaultTrustStoreDir() string {
+	if dir := os.Getenv(TrustStoreEnvName); dir != "" {
+		return dir
+	}
+	return filepath.Join(config.DefaultConfig.GetKeysDir(), "trust")
+}
+
+// LoadTrustStore loads the trust store from the directory. A missing
+// trust store is returned empty.
+func LoadTrustStore(dir string) (*TrustStore, error) {
+	store := &TrustStore{dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, TrustStoreFilename))
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("could not parse trust store: %w", err)
+	}
+	return store, nil
+}
+
+// Dir returns the directory of the trust store
+func (s *TrustStore) Dir() string {
+	return s.dir
+}
+
+// Save writes the trust store index to its directory
+func (s *TrustStore) Save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(s.dir, TrustStoreFilename)
+	_ = fileutil.FixMissingDirs(filename)
+	return os.WriteFile(filename, data, 0600)
+}
+
+// Add adds the signer of the certificate or public key of the signature
+// algorithm to the trust store. The algorithm is inferred from the key if
+// none is given and the name defaults to the identifier of the signer.
+func (s *TrustStore) Add(name, algorithm string, cert []byte, scopes []string) (*TrustedSigner, error) {
+	verifier, err := NewTemplateSigVerifierWithAlgorithm(algorithm, cert)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = verifier.Identifier()
+	}
+	fingerprint := verifier.Fingerprint()
+	if s.IsRevoked(fingerprint) {
+		return nil, fmt.Errorf("certificate %s was revoked", fingerprint)
+	}
+	for _, signer := range s.Signers {
+		if signer.Name == name {
+			return nil, fmt.Errorf("signer %s already exists", name)
+		}
+		if signer.Fingerprint == fingerprint {
+			return nil, fmt.Errorf("certificate is already trusted as %s", signer.Name)
+		}
+	}
+	for _, scope := range scopes {
+		if strings.TrimPrefix(scope, PathScopePrefix) == "" {
+			return nil, fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+
+	signer := &TrustedSigner{
+		Name:        name,
+		Certificate: fingerprint[:16] + ".crt",
+		Fingerprint: fingerprint,
+		Scopes:      scopes,
+		AddedAt:     time.Now(),
+	}
+	if verifier.Algorithm() != ECDSA {
+		signer.Algorithm = verifier.Algorithm()
+		signer.Certificate = fingerprint[:16] + ".pub"
+	}
+	filename := filepath.Join(s.dir, signer.Certificate)
+	_ = fileutil.FixMissingDirs(filename)
+	if err := os.WriteFile(filename, cert, 0600); err != nil {
+		return nil, err
+	}
+	s.Signers = append(s.Signers, signer)
+	return signer, nil
+}
+
+// Revoke revokes the signer with the name or certificate fingerprint
+// and removes it from the trusted signers.
+func (s *TrustStore) Revoke(nameOrFingerprint string) (*RevokedSigner, error) {
+	revoked := &RevokedSigner{Fingerprint: nameOrFingerprint, RevokedAt: time.Now()}
+	for i, signer := range s.Signers {
+		if signer.Name != nameOrFingerprint && signer.Fingerprint != nameOrFingerprint {
+			continue
+		}
+		revoked.Name, revoked.Fingerprint = signer.Name, signer.Fingerprint
+		_ = os.Remove(filepath.Join(s.dir, signer.Certificate))
+		s.Signers = append(s.Signers[:i], s.Signers[i+1:]...)
+		break
+	}
+	if revoked.Name == "" {
+		if _, err := hex.DecodeString(nameOrFingerprint); err != nil || len(nameOrFingerprint) != sha256.Size*2 {
+			return nil, fmt.Errorf("no signer found for %s", nameOrFingerprint)
+		}
+	}
+	if s.IsRevoked(revoked.Fingerprint) {
+		return nil, fmt.Errorf("certificate %s is already revoked", revoked.Fingerprint)
+	}
+	s.Revoked = append(s.Revoked, revoked)
+	return revoked, nil
+}
+
+// IsRevoked returns true if the certificate fingerprint was revoked
+func (s *TrustStore) IsRevoked(fingerprint string) bool {
+	for _, revoked := range s.Revoked {
+		if strings.EqualFold(revoked.Fingerprint, fingerprint) {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifiers returns the template verifiers of the trusted signers.
+// Signers whose certificate can't be loaded are skipped and reported
+// in the returned error.
+func (s *TrustStore) Verifiers() ([]*TemplateSigner, error) {
+	verifiers := make([]*TemplateSigner, 0, len(s.Signers))
+	var errs []error
+	for _, signer := range s.Signers {
+		if s.IsRevoked(signer.Fingerprint) {
+			continue
+		}
+		verifier, err := s.readCertificate(signer)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		verifier.name, verifier.scopes = signer.Name, signer.Scopes
+		verifiers = append(verifiers, verifier)
+	}
+	return verifiers, errors.Join(errs...)
+}
+
+// readCertificate reads and validates the certificate of a trusted signer
+func (s *TrustStore) readCertificate(signer *TrustedSigner) (*TemplateSigner, error) {
+	cert, err := os.ReadFile(filepath.Join(s.dir, signer.Certificate))
+	if err != nil {
+		return nil, fmt.Errorf("could not read certificate of %s: %w", signer.Name, err)
+	}
+	algorithm := signer.Algorithm
+	if algorithm == "" {
+		algorithm = ECDSA
+	}
+	verifier, err := NewTemplateSigVerifierWithAlgorithm(algorithm, cert)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse certificate of %s: %w", signer.Name, err)
+	}
+	if verifier.Fingerprint() != signer.Fingerprint {
+		return nil, fmt.Errorf("certificate of %s does not match its fingerprint", signer.Name)
+	}
+	return verifier, nil
+}
+
+// CertificateFingerprint returns the sha256 fingerprint of a certificate or
+// public key of the signature algorithm
+func CertificateFingerprint(algorithm string, cert []byte) (string, error) {
+	verifier, err := NewTemplateSigVerifierWithAlgorithm(algorithm, cert)
+	if err != nil {
+		return "", err
+	}
+	return verifier.Fingerprint(), nil
+}
+
+func certFingerprint(handler *KeyHandler) string {
+	hashed := sha256.Sum256(handler.cert.Raw)
+	return hex.EncodeToString(hashed[:])
+}
@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCert(t *testing.T, identifier string) []byte {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	cert, err := (&KeyHandler{}).generateCertWithKey(identifier, privateKey)
+	require.Nil(t, err)
+	return cert
+}
+
+func TestTrustStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := LoadTrustStore(dir)
+	require.Nil(t, err, "could not load empty trust store")
+	require.Empty(t, store.Signers)
+
+	alice, err := store.Add("", "", newTestCert(t, "alice"), []string{"code", "path:/templates/internal"})
+	require.Nil(t, err, "could not add signer")
+	require.Equal(t, "alice", alice.Name)
+	bob, err := store.Add("bob", "", newTestCert(t, "robert"), nil)
+	require.Nil(t, err, "could not add signer")
+
+	_, err = store.Add("bob", "", newTestCert(t, "bob"), nil)
+	require.NotNil(t, err, "added duplicate signer")
+	require.Nil(t, store.Save())
+
+	store, err = LoadTrustStore(dir)
+	require.Nil(t, err, "could not load trust store")
+	verifiers, err := store.Verifiers()
+	require.Nil(t, err, "could not load verifiers")
+	require.Len(t, verifiers, 2)
+	require.Equal(t, "alice", verifiers[0].Identifier())
+	require.Equal(t, alice.Fingerprint, verifiers[0].Fingerprint())
+
+	revoked, err := store.Revoke("bob")
+	require.Nil(t, err, "could not revoke signer")
+	require.Equal(t, bob.Fingerprint, revoked.Fingerprint)
+	require.True(t, store.IsRevoked(bob.Fingerprint))
+	require.NoFileExists(t, filepath.Join(dir, bob.Certificate))
+
+	_, err = store.Revoke("unknown")
+	require.NotNil(t, err, "revoked unknown signer")
+
+	verifiers, err = store.Verifiers()
+	require.Nil(t, err)
+	require.Len(t, verifiers, 1)
+
+	_, err = store.Add("bob", "", newTestCert(t, "robert"), nil)
+	require.Nil(t, err, "could not add new certificate for revoked name")
+}
+
+func TestTemplateSignerInScope(t *testing.T) {
+	handler := &KeyHandler{UserCert: newTestCert(t, "alice")}
+	require.Nil(t, handler.ParseUserCert())
+
+	unscoped := &TemplateSigner{backend: newECDSABackend(handler)}
+	require.True(t, unscoped.InScope([]string{"code"}, "/templates/code.yaml"))
+
+	scoped := &TemplateSigner{backend: newECDSABackend(handler), scopes: []string{"code", "http", "path:/templates/internal"}}
+	require.True(t, scoped.InScope([]string{"code", "http"}, "/templates/internal/code.yaml"))
+	require.False(t, scoped.InScope([]string{"code", "javascript"}, "/templates/internal/code.yaml"), "protocol out of scope")
+	require.False(t, scoped.InScope([]string{"code"}, "/templates/public/code.yaml"), "path out of scope")
+	require.False(t, scoped.InScope([]string{"code"}, "/templates/internal-other/code.yaml"), "path prefix out of scope")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/common/variables"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/dns"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/file"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols/grpc"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/headless"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/http"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols/javascript"
@@ -117,6 +118,9 @@ type Template struct {
 	// description: |
 	//   Javascript contains the javascript request to make in the template.
 	RequestsJavascript []*javascript.Request `yaml:"javascript,omitempty" json:"javascript,omitempty" jsonschema:"title=javascript requests to make,description=Javascript requests to make for the template"`
+	// description: |
+	//   GRPC contains the gRPC request to make in the template.
+	RequestsGRPC []*grpc.Request `yaml:"grpc,omitempty" json:"grpc,omitempty" jsonschema:"title=grpc requests to make,description=gRPC requests to make for the template"`
 
 	// description: |
 	//   Workflows is a yaml based workflow declaration code.
@@ -144,6 +148,13 @@ type Template struct {
 	//   Constants contains any scalar constant for the current template
 	Constants map[string]interface{} `yaml:"constants,omitempty" json:"constants,omitempty" jsonschema:"title=constant for the template,description=constants contains any constant for the template"`
 
+	// description: |
+	//   Tests contains test cases evaluating the template matchers and extractors
+	//   offline against recorded fixture responses.
+	//
+	//   Tests are run using the `-test-templates` flag and are ignored during scans.
+	Tests []*TestCase `yaml:"tests,omitempty" json:"tests,omitempty" jsonschema:"title=template test cases,description=Test cases evaluating the template offline against recorded fixtures"`
+
 	// TotalRequests is the total number of requests for the template.
 	TotalRequests int `yaml:"-" json:"-"`
 	// Executer is the actual template executor for running template requests
@@ -153,6 +164,8 @@ type Template struct {
 
 	// Verified defines if the template signature is digitally verified
 	Verified bool `yaml:"-" json:"-"`
+	// SignedBy is the identifier of the signer that verified the template signature
+	SignedBy string `yaml:"-" json:"-"`
 
 	// RequestsQueue contains all template requests in order (both protocol & request order)
 	RequestsQueue []protocols.Request `yaml:"-" json:"-"`
@@ -186,6 +199,8 @@ func (template *Template) Type() types.ProtocolType {
 		return types.CodeProtocol
 	case len(template.RequestsJavascript) > 0:
 		return types.JavascriptProtocol
+	case len(template.RequestsGRPC) > 0:
+		return types.GRPCProtocol
 	default:
 		return types.InvalidProtocol
 	}
@@ -196,6 +211,16 @@ func (template *Template) HasCodeProtocol() bool {
 	return len(template.RequestsCode) > 0
 }
 
+// HasFuzzingRules returns true if a http request of the template has fuzzing rules
+func (template *Template) HasFuzzingRules() bool {
+	for _, request := range template.RequestsHTTP {
+		if len(request.Fuzzing) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // validateAllRequestIDs check if that protocol already has given id if not
 // then is is manually set to proto_index
 func (template *Template) validateAllRequestIDs() {
@@ -274,6 +299,13 @@ func (template *Template) validateAllRequestIDs() {
 			}
 		}
 	}
+	if len(template.RequestsGRPC) > 1 {
+		for i, req := range template.RequestsGRPC {
+			if req.ID == "" {
+				req.ID = req.Type().String() + "_" + strconv.Itoa(i+1)
+			}
+		}
+	}
 }
 
 // MarshalYAML forces recursive struct validation during marshal operation
@@ -432,6 +464,8 @@ func (template *Template) addRequestsToQueue(keys ...string) {
 			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsCode)...)
 		case types.JavascriptProtocol.String():
 			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsJavascript)...)
+		case types.GRPCProtocol.String():
+			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsGRPC)...)
 			// for deprecated protocols
 		case "requests":
 			template.RequestsQueue = append(template.RequestsQueue, template.convertRequestToProtocolsRequest(template.RequestsHTTP)...)
@@ -448,7 +482,8 @@ func (template *Template) hasMultipleRequests() bool {
 		len(template.RequestsHTTP) + len(template.RequestsHeadless) +
 		len(template.RequestsNetwork) + len(template.RequestsSSL) +
 		len(template.RequestsWebsocket) + len(template.RequestsWHOIS) +
-		len(template.RequestsCode) + len(template.RequestsJavascript)
+		len(template.RequestsCode) + len(template.RequestsJavascript) +
+		len(template.RequestsGRPC)
 	return counter > 1
 }
 
@@ -19,6 +19,7 @@ var (
 	GENERATORSAttackTypeHolderDoc encoder.Doc
 	HTTPMethodTypeHolderDoc       encoder.Doc
 	FUZZRuleDoc                   encoder.Doc
+	TIMINGConfigDoc               encoder.Doc
 	SignatureTypeHolderDoc        encoder.Doc
 	DNSRequestDoc                 encoder.Doc
 	DNSRequestTypeHolderDoc       encoder.Doc
@@ -30,21 +31,24 @@ var (
 	ENGINEActionDoc               encoder.Doc
 	ActionTypeHolderDoc           encoder.Doc
 	USERAGENTUserAgentHolderDoc   encoder.Doc
+	ENGINEDOMXSSDoc               encoder.Doc
 	SSLRequestDoc                 encoder.Doc
 	WEBSOCKETRequestDoc           encoder.Doc
 	WEBSOCKETInputDoc             encoder.Doc
 	WHOISRequestDoc               encoder.Doc
 	CODERequestDoc                encoder.Doc
 	JAVASCRIPTRequestDoc          encoder.Doc
+	GRPCRequestDoc                encoder.Doc
 	HTTPSignatureTypeHolderDoc    encoder.Doc
 	VARIABLESVariableDoc          encoder.Doc
+	TestCaseDoc                   encoder.Doc
 )
 
 func init() {
 	TemplateDoc.Type = "Template"
 	TemplateDoc.Comments[encoder.LineComment] = " Template is a YAML input file which defines all the requests and"
 	TemplateDoc.Description = "Template is a YAML input file which defines all the requests and\n other metadata for a template."
-	TemplateDoc.Fields = make([]encoder.Doc, 20)
+	TemplateDoc.Fields = make([]encoder.Doc, 22)
 	TemplateDoc.Fields[0].Name = "id"
 	TemplateDoc.Fields[0].Type = "string"
 	TemplateDoc.Fields[0].Note = ""
@@ -132,34 +136,44 @@ func init() {
 	TemplateDoc.Fields[14].Note = ""
 	TemplateDoc.Fields[14].Description = "Javascript contains the javascript request to make in the template."
 	TemplateDoc.Fields[14].Comments[encoder.LineComment] = "Javascript contains the javascript request to make in the template."
-	TemplateDoc.Fields[15].Name = "self-contained"
-	TemplateDoc.Fields[15].Type = "bool"
+	TemplateDoc.Fields[15].Name = "grpc"
+	TemplateDoc.Fields[15].Type = "[]grpc.Request"
 	TemplateDoc.Fields[15].Note = ""
-	TemplateDoc.Fields[15].Description = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[16].Name = "stop-at-first-match"
+	TemplateDoc.Fields[15].Description = "GRPC contains the gRPC request to make in the template."
+	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "GRPC contains the gRPC request to make in the template."
+	TemplateDoc.Fields[16].Name = "self-contained"
 	TemplateDoc.Fields[16].Type = "bool"
 	TemplateDoc.Fields[16].Note = ""
-	TemplateDoc.Fields[16].Description = "Stop execution once first match is found"
-	TemplateDoc.Fields[16].Comments[encoder.LineComment] = "Stop execution once first match is found"
-	TemplateDoc.Fields[17].Name = "signature"
-	TemplateDoc.Fields[17].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[16].Description = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[16].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[17].Name = "stop-at-first-match"
+	TemplateDoc.Fields[17].Type = "bool"
 	TemplateDoc.Fields[17].Note = ""
-	TemplateDoc.Fields[17].Description = "Signature is the request signature method"
-	TemplateDoc.Fields[17].Comments[encoder.LineComment] = "Signature is the request signature method"
-	TemplateDoc.Fields[17].Values = []string{
+	TemplateDoc.Fields[17].Description = "Stop execution once first match is found"
+	TemplateDoc.Fields[17].Comments[encoder.LineComment] = "Stop execution once first match is found"
+	TemplateDoc.Fields[18].Name = "signature"
+	TemplateDoc.Fields[18].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[18].Note = ""
+	TemplateDoc.Fields[18].Description = "Signature is the request signature method"
+	TemplateDoc.Fields[18].Comments[encoder.LineComment] = "Signature is the request signature method"
+	TemplateDoc.Fields[18].Values = []string{
 		"AWS",
 	}
-	TemplateDoc.Fields[18].Name = "variables"
-	TemplateDoc.Fields[18].Type = "variables.Variable"
-	TemplateDoc.Fields[18].Note = ""
-	TemplateDoc.Fields[18].Description = "Variables contains any variables for the current request."
-	TemplateDoc.Fields[18].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
-	TemplateDoc.Fields[19].Name = "constants"
-	TemplateDoc.Fields[19].Type = "map[string]interface{}"
+	TemplateDoc.Fields[19].Name = "variables"
+	TemplateDoc.Fields[19].Type = "variables.Variable"
 	TemplateDoc.Fields[19].Note = ""
-	TemplateDoc.Fields[19].Description = "Constants contains any scalar constant for the current template"
-	TemplateDoc.Fields[19].Comments[encoder.LineComment] = "Constants contains any scalar constant for the current template"
+	TemplateDoc.Fields[19].Description = "Variables contains any variables for the current request."
+	TemplateDoc.Fields[19].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
+	TemplateDoc.Fields[20].Name = "constants"
+	TemplateDoc.Fields[20].Type = "map[string]interface{}"
+	TemplateDoc.Fields[20].Note = ""
+	TemplateDoc.Fields[20].Description = "Constants contains any scalar constant for the current template"
+	TemplateDoc.Fields[20].Comments[encoder.LineComment] = "Constants contains any scalar constant for the current template"
+	TemplateDoc.Fields[21].Name = "tests"
+	TemplateDoc.Fields[21].Type = "[]TestCase"
+	TemplateDoc.Fields[21].Note = ""
+	TemplateDoc.Fields[21].Description = "Tests contains test cases evaluating the template matchers and extractors\noffline against recorded fixture responses.\n\nTests are run using the `-test-templates` flag and are ignored during scans."
+	TemplateDoc.Fields[21].Comments[encoder.LineComment] = "Tests contains test cases evaluating the template matchers and extractors"
 
 	MODELInfoDoc.Type = "model.Info"
 	MODELInfoDoc.Comments[encoder.LineComment] = " Info contains metadata information about a template"
@@ -443,6 +457,122 @@ func init() {
 			Key:   "duration",
 			Value: "HTTP request time duration",
 		},
+		{
+			Key:   "race_timings",
+			Value: "Times in milliseconds until the responses of synchronised race requests were received",
+		},
+		{
+			Key:   "race_index",
+			Value: "Index of the request in synchronised race requests",
+		},
+		{
+			Key:   "desync_index",
+			Value: "Index of the request in the desync probe",
+		},
+		{
+			Key:   "desync_connection",
+			Value: "Index of the connection the request of the desync probe was sent on",
+		},
+		{
+			Key:   "desync_connections",
+			Value: "Indexes of the connections the requests of the desync probe were sent on",
+		},
+		{
+			Key:   "desync_timeout",
+			Value: "Whether the request of the desync probe received no response in time",
+		},
+		{
+			Key:   "desync_timings",
+			Value: "Times in milliseconds until the responses of the desync probe were received",
+		},
+		{
+			Key:   "desync_status_codes",
+			Value: "Status codes of the responses of the desync probe in order, 0 for missing responses",
+		},
+		{
+			Key:   "desync_baseline",
+			Value: "Time in milliseconds until the response to the last request sent alone was received",
+		},
+		{
+			Key:   "desync_baseline_code",
+			Value: "Status code of the response to the last request sent alone",
+		},
+		{
+			Key:   "desync_pending",
+			Value: "Data received on the connection after the last response of the desync probe",
+		},
+		{
+			Key:   "baseline_status_code",
+			Value: "Status code of the baseline response of diff matchers",
+		},
+		{
+			Key:   "baseline_body",
+			Value: "Body of the baseline response of diff matchers",
+		},
+		{
+			Key:   "baseline_header",
+			Value: "Headers of the baseline response of diff matchers",
+		},
+		{
+			Key:   "baseline_response",
+			Value: "Baseline response of diff matchers",
+		},
+		{
+			Key:   "connection_index",
+			Value: "Index of the connection the request of a same-connection sequence was sent on",
+		},
+		{
+			Key:   "connection_reused",
+			Value: "Whether the connection was used by a previous request of the same-connection sequence",
+		},
+		{
+			Key:   "<resp_N>",
+			Value: "HTTP response N of the same-connection sequence",
+		},
+		{
+			Key:   "<resp_N_status_code>",
+			Value: "Status code of response N of the same-connection sequence",
+		},
+		{
+			Key:   "<resp_N_timing>",
+			Value: "Time in milliseconds until response N of the same-connection sequence was received",
+		},
+		{
+			Key:   "<resp_N_reused>",
+			Value: "Whether request N of the same-connection sequence reused the connection",
+		},
+		{
+			Key:   "timing_confidence",
+			Value: "Confidence between 0 and 1 that the payload delays the response in timing analysis",
+		},
+		{
+			Key:   "timing_confirmed",
+			Value: "Whether the delay was confirmed by timing analysis",
+		},
+		{
+			Key:   "timing_slope",
+			Value: "Increase of the latency in seconds for each second of delay in timing analysis",
+		},
+		{
+			Key:   "timing_r_squared",
+			Value: "Coefficient of determination of the latency model of timing analysis",
+		},
+		{
+			Key:   "timing_baseline",
+			Value: "Mean latency in seconds of the control requests of timing analysis",
+		},
+		{
+			Key:   "timing_deviation",
+			Value: "Standard deviation of the latency in seconds of the control requests of timing analysis",
+		},
+		{
+			Key:   "timing_delays",
+			Value: "Delays in seconds of the requests of timing analysis in order",
+		},
+		{
+			Key:   "timing_latencies",
+			Value: "Latencies in seconds of the requests of timing analysis in order",
+		},
 		{
 			Key:   "all",
 			Value: "HTTP response body + headers",
@@ -456,7 +586,7 @@ func init() {
 			Value: "HTTP response headers in name:value format",
 		},
 	}
-	HTTPRequestDoc.Fields = make([]encoder.Doc, 31)
+	HTTPRequestDoc.Fields = make([]encoder.Doc, 38)
 	HTTPRequestDoc.Fields[0].Name = "path"
 	HTTPRequestDoc.Fields[0].Type = "[]string"
 	HTTPRequestDoc.Fields[0].Note = ""
@@ -562,84 +692,127 @@ func init() {
 	HTTPRequestDoc.Fields[15].Note = ""
 	HTTPRequestDoc.Fields[15].Description = "Fuzzing describes schema to fuzz http requests"
 	HTTPRequestDoc.Fields[15].Comments[encoder.LineComment] = " Fuzzing describes schema to fuzz http requests"
-	HTTPRequestDoc.Fields[16].Name = "signature"
-	HTTPRequestDoc.Fields[16].Type = "SignatureTypeHolder"
+	HTTPRequestDoc.Fields[16].Name = "timing"
+	HTTPRequestDoc.Fields[16].Type = "timing.Config"
 	HTTPRequestDoc.Fields[16].Note = ""
-	HTTPRequestDoc.Fields[16].Description = "Signature is the request signature method"
-	HTTPRequestDoc.Fields[16].Comments[encoder.LineComment] = "Signature is the request signature method"
-	HTTPRequestDoc.Fields[16].Values = []string{
-		"AWS",
-	}
-	HTTPRequestDoc.Fields[17].Name = "cookie-reuse"
+	HTTPRequestDoc.Fields[16].Description = "Timing confirms time-based blind payloads by statistical analysis of response latencies.\n\nEach request is sent repeatedly with its delay variable set to 0 as control and to each\ndelay, the delay is confirmed if the latency increases by the delay. The event of the last\nrequest contains timing_confidence and timing_confirmed along with the latency model.\nFuzzing payloads using the delay variable are analysed for each generated request."
+	HTTPRequestDoc.Fields[16].Comments[encoder.LineComment] = "Timing confirms time-based blind payloads by statistical analysis of response latencies."
+	HTTPRequestDoc.Fields[17].Name = "graphql"
 	HTTPRequestDoc.Fields[17].Type = "bool"
 	HTTPRequestDoc.Fields[17].Note = ""
-	HTTPRequestDoc.Fields[17].Description = "CookieReuse is an optional setting that enables cookie reuse for\nall requests defined in raw section."
-	HTTPRequestDoc.Fields[17].Comments[encoder.LineComment] = "CookieReuse is an optional setting that enables cookie reuse for"
-	HTTPRequestDoc.Fields[18].Name = "read-all"
-	HTTPRequestDoc.Fields[18].Type = "bool"
+	HTTPRequestDoc.Fields[17].Description = "GraphQL treats the request as a GraphQL endpoint.\n\nThe schema is retrieved using introspection and a query or mutation is sent\nfor every field with placeholder arguments, which can be fuzzed using the graphql\nfuzzing part. Field suggestions, batching and depth limits are probed and exposed\nas graphql_field_suggestions, graphql_batching and graphql_depth_limit variables."
+	HTTPRequestDoc.Fields[17].Comments[encoder.LineComment] = "GraphQL treats the request as a GraphQL endpoint."
+	HTTPRequestDoc.Fields[18].Name = "signature"
+	HTTPRequestDoc.Fields[18].Type = "SignatureTypeHolder"
 	HTTPRequestDoc.Fields[18].Note = ""
-	HTTPRequestDoc.Fields[18].Description = "Enables force reading of the entire raw unsafe request body ignoring\nany specified content length headers."
-	HTTPRequestDoc.Fields[18].Comments[encoder.LineComment] = "Enables force reading of the entire raw unsafe request body ignoring"
-	HTTPRequestDoc.Fields[19].Name = "redirects"
+	HTTPRequestDoc.Fields[18].Description = "Signature is the request signature method"
+	HTTPRequestDoc.Fields[18].Comments[encoder.LineComment] = "Signature is the request signature method"
+	HTTPRequestDoc.Fields[18].Values = []string{
+		"AWS",
+	}
+	HTTPRequestDoc.Fields[19].Name = "cookie-reuse"
 	HTTPRequestDoc.Fields[19].Type = "bool"
 	HTTPRequestDoc.Fields[19].Note = ""
-	HTTPRequestDoc.Fields[19].Description = "Redirects specifies whether redirects should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
-	HTTPRequestDoc.Fields[19].Comments[encoder.LineComment] = "Redirects specifies whether redirects should be followed by the HTTP Client."
-	HTTPRequestDoc.Fields[20].Name = "host-redirects"
+	HTTPRequestDoc.Fields[19].Description = "CookieReuse is an optional setting that enables cookie reuse for\nall requests defined in raw section."
+	HTTPRequestDoc.Fields[19].Comments[encoder.LineComment] = "CookieReuse is an optional setting that enables cookie reuse for"
+	HTTPRequestDoc.Fields[20].Name = "read-all"
 	HTTPRequestDoc.Fields[20].Type = "bool"
 	HTTPRequestDoc.Fields[20].Note = ""
-	HTTPRequestDoc.Fields[20].Description = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
-	HTTPRequestDoc.Fields[20].Comments[encoder.LineComment] = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client."
-	HTTPRequestDoc.Fields[21].Name = "pipeline"
+	HTTPRequestDoc.Fields[20].Description = "Enables force reading of the entire raw unsafe request body ignoring\nany specified content length headers."
+	HTTPRequestDoc.Fields[20].Comments[encoder.LineComment] = "Enables force reading of the entire raw unsafe request body ignoring"
+	HTTPRequestDoc.Fields[21].Name = "redirects"
 	HTTPRequestDoc.Fields[21].Type = "bool"
 	HTTPRequestDoc.Fields[21].Note = ""
-	HTTPRequestDoc.Fields[21].Description = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining\n\nAll requests must be idempotent (GET/POST). This can be used for race conditions/billions requests."
-	HTTPRequestDoc.Fields[21].Comments[encoder.LineComment] = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining"
-	HTTPRequestDoc.Fields[22].Name = "unsafe"
+	HTTPRequestDoc.Fields[21].Description = "Redirects specifies whether redirects should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
+	HTTPRequestDoc.Fields[21].Comments[encoder.LineComment] = "Redirects specifies whether redirects should be followed by the HTTP Client."
+	HTTPRequestDoc.Fields[22].Name = "host-redirects"
 	HTTPRequestDoc.Fields[22].Type = "bool"
 	HTTPRequestDoc.Fields[22].Note = ""
-	HTTPRequestDoc.Fields[22].Description = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests.\n\nThis uses the [rawhttp](https://github.com/khulnasoft-lab/rawhttp) engine to achieve complete\ncontrol over the request, with no normalization performed by the client."
-	HTTPRequestDoc.Fields[22].Comments[encoder.LineComment] = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests."
-	HTTPRequestDoc.Fields[23].Name = "race"
+	HTTPRequestDoc.Fields[22].Description = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client.\n\nThis can be used in conjunction with `max-redirects` to control the HTTP request redirects."
+	HTTPRequestDoc.Fields[22].Comments[encoder.LineComment] = "Redirects specifies whether only redirects to the same host should be followed by the HTTP Client."
+	HTTPRequestDoc.Fields[23].Name = "pipeline"
 	HTTPRequestDoc.Fields[23].Type = "bool"
 	HTTPRequestDoc.Fields[23].Note = ""
-	HTTPRequestDoc.Fields[23].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
-	HTTPRequestDoc.Fields[23].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
-	HTTPRequestDoc.Fields[24].Name = "req-condition"
+	HTTPRequestDoc.Fields[23].Description = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining\n\nAll requests must be idempotent (GET/POST). This can be used for race conditions/billions requests."
+	HTTPRequestDoc.Fields[23].Comments[encoder.LineComment] = "Pipeline defines if the attack should be performed with HTTP 1.1 Pipelining"
+	HTTPRequestDoc.Fields[24].Name = "unsafe"
 	HTTPRequestDoc.Fields[24].Type = "bool"
 	HTTPRequestDoc.Fields[24].Note = ""
-	HTTPRequestDoc.Fields[24].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
-	HTTPRequestDoc.Fields[24].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
-	HTTPRequestDoc.Fields[25].Name = "stop-at-first-match"
+	HTTPRequestDoc.Fields[24].Description = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests.\n\nThis uses the [rawhttp](https://github.com/khulnasoft-lab/rawhttp) engine to achieve complete\ncontrol over the request, with no normalization performed by the client."
+	HTTPRequestDoc.Fields[24].Comments[encoder.LineComment] = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests."
+	HTTPRequestDoc.Fields[25].Name = "race"
 	HTTPRequestDoc.Fields[25].Type = "bool"
 	HTTPRequestDoc.Fields[25].Note = ""
-	HTTPRequestDoc.Fields[25].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[25].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[26].Name = "skip-variables-check"
-	HTTPRequestDoc.Fields[26].Type = "bool"
+	HTTPRequestDoc.Fields[25].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
+	HTTPRequestDoc.Fields[25].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
+	HTTPRequestDoc.Fields[26].Name = "race-mode"
+	HTTPRequestDoc.Fields[26].Type = "string"
 	HTTPRequestDoc.Fields[26].Note = ""
-	HTTPRequestDoc.Fields[26].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[26].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[27].Name = "iterate-all"
+	HTTPRequestDoc.Fields[26].Description = "RaceMode is the synchronisation mode of race condition attacks.\n\nlast-byte sends every request on its own HTTP/1.1 connection withholding the last byte\nuntil all requests were sent, single-packet completes all requests multiplexed on a HTTP/2\nconnection in a single packet falling back to last-byte if the server doesn't support HTTP/2.\nThe time until each response was received is available as race_timings."
+	HTTPRequestDoc.Fields[26].Comments[encoder.LineComment] = "RaceMode is the synchronisation mode of race condition attacks."
+	HTTPRequestDoc.Fields[26].Values = []string{
+		"last-byte",
+		"single-packet",
+	}
+	HTTPRequestDoc.Fields[27].Name = "desync"
 	HTTPRequestDoc.Fields[27].Type = "bool"
 	HTTPRequestDoc.Fields[27].Note = ""
-	HTTPRequestDoc.Fields[27].Description = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[28].Name = "digest-username"
-	HTTPRequestDoc.Fields[28].Type = "string"
+	HTTPRequestDoc.Fields[27].Description = "Desync enables the request smuggling and desync detection mode.\n\nThe raw requests are sent unmodified in order on a single connection as a probe, a new\nconnection is only opened if the server closes the connection or doesn't respond in time.\nThe last request is also sent alone on a fresh connection as baseline before the probe.\nResponse timings, status codes and connections of the probe are available to matchers."
+	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "Desync enables the request smuggling and desync detection mode."
+	HTTPRequestDoc.Fields[28].Name = "desync-timeout"
+	HTTPRequestDoc.Fields[28].Type = "int"
 	HTTPRequestDoc.Fields[28].Note = ""
-	HTTPRequestDoc.Fields[28].Description = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[29].Name = "digest-password"
+	HTTPRequestDoc.Fields[28].Description = "DesyncTimeout is the time in seconds to wait for each response of a desync probe.\n\nRequests which don't receive a response in time have a status code of 0 and desync_timeout set.\nDefaults to the configured timeout."
+	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "DesyncTimeout is the time in seconds to wait for each response of a desync probe."
+	HTTPRequestDoc.Fields[29].Name = "desync-protocol"
 	HTTPRequestDoc.Fields[29].Type = "string"
 	HTTPRequestDoc.Fields[29].Note = ""
-	HTTPRequestDoc.Fields[29].Description = "DigestAuthPassword specifies the password for digest authentication"
-	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
-	HTTPRequestDoc.Fields[30].Name = "disable-path-automerge"
+	HTTPRequestDoc.Fields[29].Description = "DesyncProtocol is the protocol of the connections of desync probes.\n\nWith h2 the raw requests are sent as HTTP/2 streams on a single connection to detect\ndesyncs of front-ends downgrading requests to HTTP/1.1 (H2.CL and H2.TE). The headers\nare sent as they are, including content-length and transfer-encoding. Requires https."
+	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "DesyncProtocol is the protocol of the connections of desync probes."
+	HTTPRequestDoc.Fields[29].Values = []string{
+		"http/1.1",
+		"h2",
+	}
+	HTTPRequestDoc.Fields[30].Name = "same-connection"
 	HTTPRequestDoc.Fields[30].Type = "bool"
 	HTTPRequestDoc.Fields[30].Note = ""
-	HTTPRequestDoc.Fields[30].Description = "DisablePathAutomerge disables merging target url path with raw request path"
-	HTTPRequestDoc.Fields[30].Comments[encoder.LineComment] = "DisablePathAutomerge disables merging target url path with raw request path"
+	HTTPRequestDoc.Fields[30].Description = "SameConnection sends the requests in order on a single keep-alive connection.\n\nEach request is sent after the response to the previous one was received, a new connection\nis only opened if the server closes the connection. The responses of the sequence are\navailable as resp_1..N along with their resp_N_status_code, resp_N_timing and resp_N_reused."
+	HTTPRequestDoc.Fields[30].Comments[encoder.LineComment] = "SameConnection sends the requests in order on a single keep-alive connection."
+	HTTPRequestDoc.Fields[31].Name = "req-condition"
+	HTTPRequestDoc.Fields[31].Type = "bool"
+	HTTPRequestDoc.Fields[31].Note = ""
+	HTTPRequestDoc.Fields[31].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
+	HTTPRequestDoc.Fields[31].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
+	HTTPRequestDoc.Fields[32].Name = "stop-at-first-match"
+	HTTPRequestDoc.Fields[32].Type = "bool"
+	HTTPRequestDoc.Fields[32].Note = ""
+	HTTPRequestDoc.Fields[32].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[32].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[33].Name = "skip-variables-check"
+	HTTPRequestDoc.Fields[33].Type = "bool"
+	HTTPRequestDoc.Fields[33].Note = ""
+	HTTPRequestDoc.Fields[33].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[33].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[34].Name = "iterate-all"
+	HTTPRequestDoc.Fields[34].Type = "bool"
+	HTTPRequestDoc.Fields[34].Note = ""
+	HTTPRequestDoc.Fields[34].Description = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[34].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[35].Name = "digest-username"
+	HTTPRequestDoc.Fields[35].Type = "string"
+	HTTPRequestDoc.Fields[35].Note = ""
+	HTTPRequestDoc.Fields[35].Description = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[35].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[36].Name = "digest-password"
+	HTTPRequestDoc.Fields[36].Type = "string"
+	HTTPRequestDoc.Fields[36].Note = ""
+	HTTPRequestDoc.Fields[36].Description = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[36].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[37].Name = "disable-path-automerge"
+	HTTPRequestDoc.Fields[37].Type = "bool"
+	HTTPRequestDoc.Fields[37].Note = ""
+	HTTPRequestDoc.Fields[37].Description = "DisablePathAutomerge disables merging target url path with raw request path"
+	HTTPRequestDoc.Fields[37].Comments[encoder.LineComment] = "DisablePathAutomerge disables merging target url path with raw request path"
 
 	GENERATORSAttackTypeHolderDoc.Type = "generators.AttackTypeHolder"
 	GENERATORSAttackTypeHolderDoc.Comments[encoder.LineComment] = " AttackTypeHolder is used to hold internal type of the protocol"
@@ -669,6 +842,10 @@ func init() {
 			TypeName:  "javascript.Request",
 			FieldName: "attack",
 		},
+		{
+			TypeName:  "grpc.Request",
+			FieldName: "attack",
+		},
 	}
 	GENERATORSAttackTypeHolderDoc.Fields = make([]encoder.Doc, 1)
 	GENERATORSAttackTypeHolderDoc.Fields[0].Name = ""
@@ -739,10 +916,12 @@ func init() {
 	FUZZRuleDoc.Fields[1].Name = "part"
 	FUZZRuleDoc.Fields[1].Type = "string"
 	FUZZRuleDoc.Fields[1].Note = ""
-	FUZZRuleDoc.Fields[1].Description = "Part is the part of request to fuzz.\n\nquery fuzzes the query part of url. More parts will be added later."
+	FUZZRuleDoc.Fields[1].Description = "Part is the part of request to fuzz.\n\nquery fuzzes the query part of url. headers fuzzes the request headers.\ngraphql fuzzes the variables of a GraphQL request body."
 	FUZZRuleDoc.Fields[1].Comments[encoder.LineComment] = "Part is the part of request to fuzz."
 	FUZZRuleDoc.Fields[1].Values = []string{
 		"query",
+		"headers",
+		"graphql",
 	}
 	FUZZRuleDoc.Fields[2].Name = "mode"
 	FUZZRuleDoc.Fields[2].Type = "string"
@@ -782,6 +961,53 @@ func init() {
 
 	FUZZRuleDoc.Fields[6].AddExample("Examples of fuzz", []string{"{{ssrf}}", "{{interactsh-url}}", "example-value"})
 
+	TIMINGConfigDoc.Type = "timing.Config"
+	TIMINGConfigDoc.Comments[encoder.LineComment] = " Config is the configuration of the timing analysis of blind payloads."
+	TIMINGConfigDoc.Description = "Config is the configuration of the timing analysis of blind payloads."
+	TIMINGConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "http.Request",
+			FieldName: "timing",
+		},
+		{
+			TypeName:  "network.Request",
+			FieldName: "timing",
+		},
+		{
+			TypeName:  "javascript.Request",
+			FieldName: "timing",
+		},
+	}
+	TIMINGConfigDoc.Fields = make([]encoder.Doc, 4)
+	TIMINGConfigDoc.Fields[0].Name = "variable"
+	TIMINGConfigDoc.Fields[0].Type = "string"
+	TIMINGConfigDoc.Fields[0].Note = ""
+	TIMINGConfigDoc.Fields[0].Description = "Variable is the name of the variable holding the delay in seconds used by the payloads.\n\nThe variable is 0 for control requests. Defaults to delay."
+	TIMINGConfigDoc.Fields[0].Comments[encoder.LineComment] = "Variable is the name of the variable holding the delay in seconds used by the payloads."
+
+	TIMINGConfigDoc.Fields[0].AddExample("", "delay")
+	TIMINGConfigDoc.Fields[1].Name = "delays"
+	TIMINGConfigDoc.Fields[1].Type = "[]int"
+	TIMINGConfigDoc.Fields[1].Note = ""
+	TIMINGConfigDoc.Fields[1].Description = "Delays are the delays in seconds injected by the analysis.\n\nThe delays must be lower than the timeout of the requests. Defaults to 1, 2 and 3 seconds."
+	TIMINGConfigDoc.Fields[1].Comments[encoder.LineComment] = "Delays are the delays in seconds injected by the analysis."
+
+	TIMINGConfigDoc.Fields[1].AddExample("", []int{2, 4, 6})
+	TIMINGConfigDoc.Fields[2].Name = "samples"
+	TIMINGConfigDoc.Fields[2].Type = "int"
+	TIMINGConfigDoc.Fields[2].Note = ""
+	TIMINGConfigDoc.Fields[2].Description = "Samples is the number of requests sent for each delay and the control. Defaults to 2."
+	TIMINGConfigDoc.Fields[2].Comments[encoder.LineComment] = "Samples is the number of requests sent for each delay and the control. Defaults to 2."
+
+	TIMINGConfigDoc.Fields[2].AddExample("", 3)
+	TIMINGConfigDoc.Fields[3].Name = "min-confidence"
+	TIMINGConfigDoc.Fields[3].Type = "float64"
+	TIMINGConfigDoc.Fields[3].Note = ""
+	TIMINGConfigDoc.Fields[3].Description = "MinConfidence is the confidence between 0 and 1 required to confirm the delay. Defaults to 0.9."
+	TIMINGConfigDoc.Fields[3].Comments[encoder.LineComment] = "MinConfidence is the confidence between 0 and 1 required to confirm the delay. Defaults to 0.9."
+
+	TIMINGConfigDoc.Fields[3].AddExample("", 0.95)
+
 	SignatureTypeHolderDoc.Type = "SignatureTypeHolder"
 	SignatureTypeHolderDoc.Comments[encoder.LineComment] = " SignatureTypeHolder is used to hold internal type of the signature"
 	SignatureTypeHolderDoc.Description = "SignatureTypeHolder is used to hold internal type of the signature"
@@ -1099,8 +1325,44 @@ func init() {
 			Key:   "raw",
 			Value: "Full Network protocol data",
 		},
+		{
+			Key:   "duration",
+			Value: "Time in seconds between the first write and the last read of the connection",
+		},
+		{
+			Key:   "timing_confidence",
+			Value: "Confidence between 0 and 1 that the payload delays the response in timing analysis",
+		},
+		{
+			Key:   "timing_confirmed",
+			Value: "Whether the delay was confirmed by timing analysis",
+		},
+		{
+			Key:   "timing_slope",
+			Value: "Increase of the latency in seconds for each second of delay in timing analysis",
+		},
+		{
+			Key:   "timing_r_squared",
+			Value: "Coefficient of determination of the latency model of timing analysis",
+		},
+		{
+			Key:   "timing_baseline",
+			Value: "Mean latency in seconds of the control requests of timing analysis",
+		},
+		{
+			Key:   "timing_deviation",
+			Value: "Standard deviation of the latency in seconds of the control requests of timing analysis",
+		},
+		{
+			Key:   "timing_delays",
+			Value: "Delays in seconds of the requests of timing analysis in order",
+		},
+		{
+			Key:   "timing_latencies",
+			Value: "Latencies in seconds of the requests of timing analysis in order",
+		},
 	}
-	NETWORKRequestDoc.Fields = make([]encoder.Doc, 9)
+	NETWORKRequestDoc.Fields = make([]encoder.Doc, 10)
 	NETWORKRequestDoc.Fields[0].Name = "id"
 	NETWORKRequestDoc.Fields[0].Type = "string"
 	NETWORKRequestDoc.Fields[0].Note = ""
@@ -1152,6 +1414,11 @@ func init() {
 	NETWORKRequestDoc.Fields[8].Comments[encoder.LineComment] = "ReadAll determines if the data stream should be read till the end regardless of the size"
 
 	NETWORKRequestDoc.Fields[8].AddExample("", false)
+	NETWORKRequestDoc.Fields[9].Name = "timing"
+	NETWORKRequestDoc.Fields[9].Type = "timing.Config"
+	NETWORKRequestDoc.Fields[9].Note = ""
+	NETWORKRequestDoc.Fields[9].Description = "Timing confirms time-based blind payloads by statistical analysis of response latencies.\n\nThe inputs are sent repeatedly with the delay variable set to 0 as control and to each\ndelay, the delay is confirmed if the latency increases by the delay. Delays must be lower\nthan the read timeout of 5 seconds. The event of the last request contains timing_confidence\nand timing_confirmed along with the latency model."
+	NETWORKRequestDoc.Fields[9].Comments[encoder.LineComment] = "Timing confirms time-based blind payloads by statistical analysis of response latencies."
 
 	NETWORKInputDoc.Type = "network.Input"
 	NETWORKInputDoc.Comments[encoder.LineComment] = ""
@@ -1258,8 +1525,24 @@ func init() {
 			Key:   "resp,body,data",
 			Value: "Headless response received from client (default)",
 		},
+		{
+			Key:   "dom_xss_sink",
+			Value: "Sinks reached by tainted values, one per line (dom-xss)",
+		},
+		{
+			Key:   "dom_xss_payload",
+			Value: "Tainted values which reached the sinks, one per line (dom-xss)",
+		},
+		{
+			Key:   "dom_xss_stack",
+			Value: "Stack traces of the tainted sink calls (dom-xss)",
+		},
+		{
+			Key:   "dom_xss",
+			Value: "JSON list of the reports of tainted values reaching sinks (dom-xss)",
+		},
 	}
-	HEADLESSRequestDoc.Fields = make([]encoder.Doc, 9)
+	HEADLESSRequestDoc.Fields = make([]encoder.Doc, 10)
 	HEADLESSRequestDoc.Fields[0].Name = "id"
 	HEADLESSRequestDoc.Fields[0].Type = "string"
 	HEADLESSRequestDoc.Fields[0].Note = ""
@@ -1300,11 +1583,16 @@ func init() {
 	HEADLESSRequestDoc.Fields[7].Note = ""
 	HEADLESSRequestDoc.Fields[7].Description = "Fuzzing describes schema to fuzz headless requests"
 	HEADLESSRequestDoc.Fields[7].Comments[encoder.LineComment] = " Fuzzing describes schema to fuzz headless requests"
-	HEADLESSRequestDoc.Fields[8].Name = "cookie-reuse"
-	HEADLESSRequestDoc.Fields[8].Type = "bool"
+	HEADLESSRequestDoc.Fields[8].Name = "dom-xss"
+	HEADLESSRequestDoc.Fields[8].Type = "engine.DOMXSS"
 	HEADLESSRequestDoc.Fields[8].Note = ""
-	HEADLESSRequestDoc.Fields[8].Description = "CookieReuse is an optional setting that enables cookie reuse"
-	HEADLESSRequestDoc.Fields[8].Comments[encoder.LineComment] = "CookieReuse is an optional setting that enables cookie reuse"
+	HEADLESSRequestDoc.Fields[8].Description = "DOMXSS enables the DOM XSS detection by instrumenting the sinks of the pages\n(innerHTML, eval, document.write, location, postMessage handlers, etc.).\n\nThe values containing a marker reaching the sinks are reported in the dom_xss_sink,\ndom_xss_payload and dom_xss_stack parts. Combined with the fuzzing rules and payloads\nincluding the {{dom_xss_marker}} variable, this confirms DOM XSS automatically."
+	HEADLESSRequestDoc.Fields[8].Comments[encoder.LineComment] = "DOMXSS enables the DOM XSS detection by instrumenting the sinks of the pages"
+	HEADLESSRequestDoc.Fields[9].Name = "cookie-reuse"
+	HEADLESSRequestDoc.Fields[9].Type = "bool"
+	HEADLESSRequestDoc.Fields[9].Note = ""
+	HEADLESSRequestDoc.Fields[9].Description = "CookieReuse is an optional setting that enables cookie reuse"
+	HEADLESSRequestDoc.Fields[9].Comments[encoder.LineComment] = "CookieReuse is an optional setting that enables cookie reuse"
 
 	ENGINEActionDoc.Type = "engine.Action"
 	ENGINEActionDoc.Comments[encoder.LineComment] = " Action is an action taken by the browser to reach a navigation"
@@ -1375,6 +1663,15 @@ func init() {
 		"debug",
 		"sleep",
 		"waitvisible",
+		"hover",
+		"dragdrop",
+		"frame",
+		"dialog",
+		"getstorage",
+		"setstorage",
+		"waitidle",
+		"waitresponse",
+		"dumpdom",
 	}
 
 	USERAGENTUserAgentHolderDoc.Type = "userAgent.UserAgentHolder"
@@ -1399,6 +1696,50 @@ func init() {
 		"custom",
 	}
 
+	ENGINEDOMXSSDoc.Type = "engine.DOMXSS"
+	ENGINEDOMXSSDoc.Comments[encoder.LineComment] = " DOMXSS contains the configuration of the DOM XSS detection of a headless request."
+	ENGINEDOMXSSDoc.Description = "DOMXSS contains the configuration of the DOM XSS detection of a headless request.\n\n The sinks are instrumented by a hook script injected before the page load, which\n reports the values containing a marker reaching the sinks."
+	ENGINEDOMXSSDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "headless.Request",
+			FieldName: "dom-xss",
+		},
+	}
+	ENGINEDOMXSSDoc.Fields = make([]encoder.Doc, 3)
+	ENGINEDOMXSSDoc.Fields[0].Name = "sinks"
+	ENGINEDOMXSSDoc.Fields[0].Type = "[]string"
+	ENGINEDOMXSSDoc.Fields[0].Note = ""
+	ENGINEDOMXSSDoc.Fields[0].Description = "Sinks is the list of instrumented sinks, all the sinks are instrumented by default."
+	ENGINEDOMXSSDoc.Fields[0].Comments[encoder.LineComment] = "Sinks is the list of instrumented sinks, all the sinks are instrumented by default."
+	ENGINEDOMXSSDoc.Fields[0].Values = []string{
+		"innerHTML",
+		"outerHTML",
+		"insertAdjacentHTML",
+		"document.write",
+		"eval",
+		"Function",
+		"setTimeout",
+		"setInterval",
+		"setAttribute",
+		"location",
+		"postMessage",
+		"alert",
+	}
+	ENGINEDOMXSSDoc.Fields[1].Name = "markers"
+	ENGINEDOMXSSDoc.Fields[1].Type = "[]string"
+	ENGINEDOMXSSDoc.Fields[1].Note = ""
+	ENGINEDOMXSSDoc.Fields[1].Description = "Markers are the strings identifying the tainted values reaching the sinks.\n\nBy default, the random value of the dom_xss_marker variable is used as marker\nand has to be included in the payloads."
+	ENGINEDOMXSSDoc.Fields[1].Comments[encoder.LineComment] = "Markers are the strings identifying the tainted values reaching the sinks."
+
+	ENGINEDOMXSSDoc.Fields[1].AddExample("", []string{"{{dom_xss_marker}}"})
+	ENGINEDOMXSSDoc.Fields[2].Name = "script"
+	ENGINEDOMXSSDoc.Fields[2].Type = "string"
+	ENGINEDOMXSSDoc.Fields[2].Note = ""
+	ENGINEDOMXSSDoc.Fields[2].Description = "Script is a custom hook script executed before the page load, which can\nreport the tainted values of other sinks with the report(sink, value) function."
+	ENGINEDOMXSSDoc.Fields[2].Comments[encoder.LineComment] = "Script is a custom hook script executed before the page load, which can"
+
+	ENGINEDOMXSSDoc.Fields[2].AddExample("", "const html = $.fn.html; $.fn.html = function(value) { report('jquery.html', value); return html.apply(this, arguments); }")
+
 	SSLRequestDoc.Type = "ssl.Request"
 	SSLRequestDoc.Comments[encoder.LineComment] = " Request is a request for the SSL protocol"
 	SSLRequestDoc.Description = "Request is a request for the SSL protocol"
@@ -1672,8 +2013,40 @@ func init() {
 			Key:   "matched",
 			Value: "Matched is the input which was matched upon",
 		},
+		{
+			Key:   "timing_confidence",
+			Value: "Confidence between 0 and 1 that the payload delays the response in timing analysis",
+		},
+		{
+			Key:   "timing_confirmed",
+			Value: "Whether the delay was confirmed by timing analysis",
+		},
+		{
+			Key:   "timing_slope",
+			Value: "Increase of the latency in seconds for each second of delay in timing analysis",
+		},
+		{
+			Key:   "timing_r_squared",
+			Value: "Coefficient of determination of the latency model of timing analysis",
+		},
+		{
+			Key:   "timing_baseline",
+			Value: "Mean latency in seconds of the control requests of timing analysis",
+		},
+		{
+			Key:   "timing_deviation",
+			Value: "Standard deviation of the latency in seconds of the control requests of timing analysis",
+		},
+		{
+			Key:   "timing_delays",
+			Value: "Delays in seconds of the requests of timing analysis in order",
+		},
+		{
+			Key:   "timing_latencies",
+			Value: "Latencies in seconds of the requests of timing analysis in order",
+		},
 	}
-	JAVASCRIPTRequestDoc.Fields = make([]encoder.Doc, 9)
+	JAVASCRIPTRequestDoc.Fields = make([]encoder.Doc, 10)
 	JAVASCRIPTRequestDoc.Fields[0].Name = "id"
 	JAVASCRIPTRequestDoc.Fields[0].Type = "string"
 	JAVASCRIPTRequestDoc.Fields[0].Note = ""
@@ -1721,6 +2094,73 @@ func init() {
 	JAVASCRIPTRequestDoc.Fields[8].Note = ""
 	JAVASCRIPTRequestDoc.Fields[8].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
 	JAVASCRIPTRequestDoc.Fields[8].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
+	JAVASCRIPTRequestDoc.Fields[9].Name = "timing"
+	JAVASCRIPTRequestDoc.Fields[9].Type = "timing.Config"
+	JAVASCRIPTRequestDoc.Fields[9].Note = ""
+	JAVASCRIPTRequestDoc.Fields[9].Description = "Timing confirms time-based blind payloads by statistical analysis of response latencies.\n\nThe code is executed repeatedly with the delay variable set to 0 as control and to each\ndelay, the delay is confirmed if the execution time increases by the delay. The event of\nthe last execution contains timing_confidence and timing_confirmed along with the latency model."
+	JAVASCRIPTRequestDoc.Fields[9].Comments[encoder.LineComment] = "Timing confirms time-based blind payloads by statistical analysis of response latencies."
+
+	GRPCRequestDoc.Type = "grpc.Request"
+	GRPCRequestDoc.Comments[encoder.LineComment] = " Request is a request for the gRPC protocol"
+	GRPCRequestDoc.Description = "Request is a request for the gRPC protocol"
+	GRPCRequestDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Template",
+			FieldName: "grpc",
+		},
+	}
+	GRPCRequestDoc.Fields = make([]encoder.Doc, 9)
+	GRPCRequestDoc.Fields[0].Name = "id"
+	GRPCRequestDoc.Fields[0].Type = "string"
+	GRPCRequestDoc.Fields[0].Note = ""
+	GRPCRequestDoc.Fields[0].Description = "ID is the optional id of the request"
+	GRPCRequestDoc.Fields[0].Comments[encoder.LineComment] = " ID is the optional id of the request"
+	GRPCRequestDoc.Fields[1].Name = "address"
+	GRPCRequestDoc.Fields[1].Type = "string"
+	GRPCRequestDoc.Fields[1].Note = ""
+	GRPCRequestDoc.Fields[1].Description = "Address contains address for the request"
+	GRPCRequestDoc.Fields[1].Comments[encoder.LineComment] = "Address contains address for the request"
+
+	GRPCRequestDoc.Fields[1].AddExample("", "{{Host}}:{{Port}}")
+	GRPCRequestDoc.Fields[2].Name = "tls"
+	GRPCRequestDoc.Fields[2].Type = "bool"
+	GRPCRequestDoc.Fields[2].Note = ""
+	GRPCRequestDoc.Fields[2].Description = "TLS enables tls for the connection to the gRPC server."
+	GRPCRequestDoc.Fields[2].Comments[encoder.LineComment] = "TLS enables tls for the connection to the gRPC server."
+	GRPCRequestDoc.Fields[3].Name = "reflection"
+	GRPCRequestDoc.Fields[3].Type = "bool"
+	GRPCRequestDoc.Fields[3].Note = ""
+	GRPCRequestDoc.Fields[3].Description = "Reflection lists the services exposed by the server using\nserver reflection and makes them available as `services`."
+	GRPCRequestDoc.Fields[3].Comments[encoder.LineComment] = "Reflection lists the services exposed by the server using"
+	GRPCRequestDoc.Fields[4].Name = "method"
+	GRPCRequestDoc.Fields[4].Type = "string"
+	GRPCRequestDoc.Fields[4].Note = ""
+	GRPCRequestDoc.Fields[4].Description = "Method is the fully qualified name of the unary method to invoke.\n\nMethod descriptors are resolved using server reflection."
+	GRPCRequestDoc.Fields[4].Comments[encoder.LineComment] = "Method is the fully qualified name of the unary method to invoke."
+
+	GRPCRequestDoc.Fields[4].AddExample("", "grpc.health.v1.Health/Check")
+	GRPCRequestDoc.Fields[5].Name = "message"
+	GRPCRequestDoc.Fields[5].Type = "string"
+	GRPCRequestDoc.Fields[5].Note = ""
+	GRPCRequestDoc.Fields[5].Description = "Message is the JSON encoded request message for the method.\n\nIt supports DSL Helper Functions as well as normal expressions."
+	GRPCRequestDoc.Fields[5].Comments[encoder.LineComment] = "Message is the JSON encoded request message for the method."
+
+	GRPCRequestDoc.Fields[5].AddExample("", "{\"service\": \"\"}")
+	GRPCRequestDoc.Fields[6].Name = "metadata"
+	GRPCRequestDoc.Fields[6].Type = "map[string]string"
+	GRPCRequestDoc.Fields[6].Note = ""
+	GRPCRequestDoc.Fields[6].Description = "Metadata contains metadata (headers) sent with the request."
+	GRPCRequestDoc.Fields[6].Comments[encoder.LineComment] = "Metadata contains metadata (headers) sent with the request."
+	GRPCRequestDoc.Fields[7].Name = "attack"
+	GRPCRequestDoc.Fields[7].Type = "generators.AttackTypeHolder"
+	GRPCRequestDoc.Fields[7].Note = ""
+	GRPCRequestDoc.Fields[7].Description = "Attack is the type of payload combinations to perform.\n\nSniper is each payload once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
+	GRPCRequestDoc.Fields[7].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
+	GRPCRequestDoc.Fields[8].Name = "payloads"
+	GRPCRequestDoc.Fields[8].Type = "map[string]interface{}"
+	GRPCRequestDoc.Fields[8].Note = ""
+	GRPCRequestDoc.Fields[8].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
+	GRPCRequestDoc.Fields[8].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
 
 	HTTPSignatureTypeHolderDoc.Type = "http.SignatureTypeHolder"
 	HTTPSignatureTypeHolderDoc.Comments[encoder.LineComment] = " SignatureTypeHolder is used to hold internal type of the signature"
@@ -1743,6 +2183,51 @@ func init() {
 		},
 	}
 	VARIABLESVariableDoc.Fields = make([]encoder.Doc, 0)
+
+	TestCaseDoc.Type = "TestCase"
+	TestCaseDoc.Comments[encoder.LineComment] = " TestCase is a test case of a template evaluating the template"
+	TestCaseDoc.Description = "TestCase is a test case of a template evaluating the template\n matchers and extractors offline against a recorded fixture response."
+	TestCaseDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Template",
+			FieldName: "tests",
+		},
+	}
+	TestCaseDoc.Fields = make([]encoder.Doc, 6)
+	TestCaseDoc.Fields[0].Name = "name"
+	TestCaseDoc.Fields[0].Type = "string"
+	TestCaseDoc.Fields[0].Note = ""
+	TestCaseDoc.Fields[0].Description = "Name is the name of the test case."
+	TestCaseDoc.Fields[0].Comments[encoder.LineComment] = "Name is the name of the test case."
+
+	TestCaseDoc.Fields[0].AddExample("", "vulnerable-version")
+	TestCaseDoc.Fields[1].Name = "http"
+	TestCaseDoc.Fields[1].Type = "string"
+	TestCaseDoc.Fields[1].Note = ""
+	TestCaseDoc.Fields[1].Description = "HTTP is the raw http response fixture, optionally preceded by the raw request.\n\nThe fixture is evaluated against all http requests of the template."
+	TestCaseDoc.Fields[1].Comments[encoder.LineComment] = "HTTP is the raw http response fixture, optionally preceded by the raw request."
+	TestCaseDoc.Fields[2].Name = "dns"
+	TestCaseDoc.Fields[2].Type = "string"
+	TestCaseDoc.Fields[2].Note = ""
+	TestCaseDoc.Fields[2].Description = "DNS contains the dns answer records fixture in zone file format, one record per line.\n\nThe fixture is evaluated against all dns requests of the template."
+	TestCaseDoc.Fields[2].Comments[encoder.LineComment] = "DNS contains the dns answer records fixture in zone file format, one record per line."
+
+	TestCaseDoc.Fields[2].AddExample("", "example.com. 300 IN CNAME example.azurewebsites.net.")
+	TestCaseDoc.Fields[3].Name = "network"
+	TestCaseDoc.Fields[3].Type = "string"
+	TestCaseDoc.Fields[3].Note = ""
+	TestCaseDoc.Fields[3].Description = "Network contains the raw bytes read from the network connection.\n\nThe fixture is evaluated against all network requests of the template."
+	TestCaseDoc.Fields[3].Comments[encoder.LineComment] = "Network contains the raw bytes read from the network connection."
+	TestCaseDoc.Fields[4].Name = "matched"
+	TestCaseDoc.Fields[4].Type = "bool"
+	TestCaseDoc.Fields[4].Note = ""
+	TestCaseDoc.Fields[4].Description = "Matched is the expected outcome of the template for the fixture."
+	TestCaseDoc.Fields[4].Comments[encoder.LineComment] = "Matched is the expected outcome of the template for the fixture."
+	TestCaseDoc.Fields[5].Name = "extracted"
+	TestCaseDoc.Fields[5].Type = "map[string][]string"
+	TestCaseDoc.Fields[5].Note = ""
+	TestCaseDoc.Fields[5].Description = "Extracted contains the expected values of named extractors.\n\nValues are compared regardless of their order, extractors not listed are not compared."
+	TestCaseDoc.Fields[5].Comments[encoder.LineComment] = "Extracted contains the expected values of named extractors."
 }
 
 // GetTemplateDoc returns documentation for the file templates_doc.go.
@@ -1761,6 +2246,7 @@ func GetTemplateDoc() *encoder.FileDoc {
 			&GENERATORSAttackTypeHolderDoc,
 			&HTTPMethodTypeHolderDoc,
 			&FUZZRuleDoc,
+			&TIMINGConfigDoc,
 			&SignatureTypeHolderDoc,
 			&DNSRequestDoc,
 			&DNSRequestTypeHolderDoc,
@@ -1772,14 +2258,17 @@ func GetTemplateDoc() *encoder.FileDoc {
 			&ENGINEActionDoc,
 			&ActionTypeHolderDoc,
 			&USERAGENTUserAgentHolderDoc,
+			&ENGINEDOMXSSDoc,
 			&SSLRequestDoc,
 			&WEBSOCKETRequestDoc,
 			&WEBSOCKETInputDoc,
 			&WHOISRequestDoc,
 			&CODERequestDoc,
 			&JAVASCRIPTRequestDoc,
+			&GRPCRequestDoc,
 			&HTTPSignatureTypeHolderDoc,
 			&VARIABLESVariableDoc,
+			&TestCaseDoc,
 		},
 	}
 }
@@ -0,0 +1,168 @@
+package templates
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
+)
+
+// TestCase is a test case of a template evaluating the template
+// matchers and extractors offline against a recorded fixture response.
+type TestCase struct {
+	// description: |
+	//   Name is the name of the test case.
+	// examples:
+	//   - value: "\"vulnerable-version\""
+	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"title=name of the test case,description=Name of the test case"`
+	// description: |
+	//   HTTP is the raw http response fixture, optionally preceded by the raw request.
+	//
+	//   The fixture is evaluated against all http requests of the template.
+	HTTP string `yaml:"http,omitempty" json:"http,omitempty" jsonschema:"title=raw http response fixture,description=Raw HTTP response fixture evaluated against the http requests"`
+	// description: |
+	//   DNS contains the dns answer records fixture in zone file format, one record per line.
+	//
+	//   The fixture is evaluated against all dns requests of the template.
+	// examples:
+	//   - value: "\"example.com. 300 IN CNAME example.azurewebsites.net.\""
+	DNS string `yaml:"dns,omitempty" json:"dns,omitempty" jsonschema:"title=dns answer records fixture,description=DNS answer records fixture in zone file format evaluated against the dns requests"`
+	// description: |
+	//   Network contains the raw bytes read from the network connection.
+	//
+	//   The fixture is evaluated against all network requests of the template.
+	Network string `yaml:"network,omitempty" json:"network,omitempty" jsonschema:"title=network response fixture,description=Raw network response bytes fixture evaluated against the network requests"`
+	// description: |
+	//   Matched is the expected outcome of the template for the fixture.
+	Matched bool `yaml:"matched" json:"matched" jsonschema:"title=expected match outcome,description=Expected match outcome of the template for the fixture"`
+	// description: |
+	//   Extracted contains the expected values of named extractors.
+	//
+	//   Values are compared regardless of their order, extractors not listed are not compared.
+	Extracted map[string][]string `yaml:"extracted,omitempty" json:"extracted,omitempty" jsonschema:"title=expected extracted values,description=Expected values of named extractors"`
+}
+
+// TestResult is the result of running a template test case
+type TestResult struct {
+	// Name is the name of the test case
+	Name string
+	// Failures contains the differences between the expected and actual outcome
+	Failures []string
+}
+
+// Passed returns true if the test case outcome matched the expectations
+func (result *TestResult) Passed() bool {
+	return len(result.Failures) == 0
+}
+
+// RunTests runs the test cases of a compiled template and returns their results.
+func (template *Template) RunTests() []*TestResult {
+	results := make([]*TestResult, 0, len(template.Tests))
+	for i, test := range template.Tests {
+		name := test.Name
+		if name == "" {
+			name = fmt.Sprintf("test-%d", i+1)
+		}
+		result := &TestResult{Name: name}
+		if err := template.runTest(test, result); err != nil {
+			result.Failures = append(result.Failures, err.Error())
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// runTest evaluates the template requests against the fixtures of the test case
+func (template *Template) runTest(test *TestCase, result *TestResult) error {
+	fixtures := []struct {
+		fixture  string
+		requests []protocols.Request
+	}{
+		{test.HTTP, template.convertRequestToProtocolsRequest(template.RequestsHTTP)},
+		{test.DNS, template.convertRequestToProtocolsRequest(template.RequestsDNS)},
+		{test.Network, template.convertRequestToProtocolsRequest(template.RequestsNetwork)},
+	}
+
+	var evaluated, matched bool
+	extracted := make(map[string][]string)
+	for _, item := range fixtures {
+		if item.fixture == "" {
+			continue
+		}
+		for _, request := range item.requests {
+			fixtureRequest, ok := request.(protocols.FixtureRequest)
+			if !ok {
+				continue
+			}
+			event, err := fixtureRequest.FixtureToDSLMap(item.fixture)
+			if err != nil {
+				return errors.Wrapf(err, "could not read %s fixture", request.Type())
+			}
+			evaluated = true
+			for _, operator := range request.GetCompiledOperators() {
+				if operator == nil {
+					continue
+				}
+				operatorResult, ok := operator.Execute(event, request.Match, request.Extract, false)
+				if !ok || operatorResult == nil {
+					continue
+				}
+				if isTestMatch(operator, operatorResult) {
+					matched = true
+				}
+				for name, values := range operatorResult.Extracts {
+					extracted[name] = append(extracted[name], values...)
+				}
+				for name, values := range operatorResult.DynamicValues {
+					extracted[name] = append(extracted[name], values...)
+				}
+			}
+		}
+	}
+	if !evaluated {
+		return fmt.Errorf("no fixture for the %s requests of the template", template.Type())
+	}
+
+	if matched != test.Matched {
+		result.Failures = append(result.Failures, fmt.Sprintf("matched: expected %v, got %v", test.Matched, matched))
+	}
+	names := make([]string, 0, len(test.Extracted))
+	for name := range test.Extracted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		expected, got := uniqueSorted(test.Extracted[name]), uniqueSorted(extracted[name])
+		if strings.Join(expected, "\x00") != strings.Join(got, "\x00") {
+			result.Failures = append(result.Failures, fmt.Sprintf("extracted %q: expected %q, got %q", name, expected, got))
+		}
+	}
+	return nil
+}
+
+// isTestMatch returns true if the operators result would create an output event
+func isTestMatch(operator *operators.Operators, result *operators.Result) bool {
+	if len(operator.Matchers) > 0 {
+		return result.Matched
+	}
+	return result.Extracted || len(result.Extracts) > 0
+}
+
+// uniqueSorted returns the sorted unique values of a slice
+func uniqueSorted(values []string) []string {
+	unique := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := unique[value]; ok {
+			continue
+		}
+		unique[value] = struct{}{}
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
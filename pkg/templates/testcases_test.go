@@ -0,0 +1,58 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/khulnasoft-lab/vulmap/pkg/templates"
+)
+
+func TestTemplateRunTests(t *testing.T) {
+	setup()
+
+	t.Run("http", func(t *testing.T) {
+		got, err := templates.Parse("tests/testcases-http.yaml", nil, executerOpts)
+		require.Nil(t, err, "could not parse template")
+
+		results := got.RunTests()
+		require.Len(t, results, 3)
+		require.True(t, results[0].Passed(), "vulnerable-version failed: %v", results[0].Failures)
+		require.True(t, results[1].Passed(), "not-found failed: %v", results[1].Failures)
+
+		require.Equal(t, "wrong-expectation", results[2].Name)
+		require.Equal(t, []string{
+			"matched: expected false, got true",
+			`extracted "version": expected ["1.2.3"], got ["2.0"]`,
+		}, results[2].Failures)
+	})
+
+	t.Run("dns", func(t *testing.T) {
+		got, err := templates.Parse("tests/testcases-dns.yaml", nil, executerOpts)
+		require.Nil(t, err, "could not parse template")
+
+		for _, result := range got.RunTests() {
+			require.True(t, result.Passed(), "%s failed: %v", result.Name, result.Failures)
+		}
+	})
+
+	t.Run("network", func(t *testing.T) {
+		got, err := templates.Parse("tests/testcases-network.yaml", nil, executerOpts)
+		require.Nil(t, err, "could not parse template")
+
+		for _, result := range got.RunTests() {
+			require.True(t, result.Passed(), "%s failed: %v", result.Name, result.Failures)
+		}
+	})
+
+	t.Run("missing-fixture", func(t *testing.T) {
+		template := &templates.Template{
+			ID:    "missing-fixture",
+			Tests: []*templates.TestCase{{Network: "data", Matched: true}},
+		}
+		results := template.RunTests()
+		require.Len(t, results, 1)
+		require.Equal(t, "test-1", results[0].Name)
+		require.False(t, results[0].Passed())
+	})
+}
@@ -26,9 +26,12 @@ func parseWorkflow(preprocessor Preprocessor, workflow *workflows.WorkflowTempla
 	if workflow.Template == "" && workflow.Tags.IsEmpty() {
 		return errors.New("invalid workflow with no templates or tags")
 	}
-	if len(workflow.Subtemplates) > 0 || len(workflow.Matchers) > 0 {
+	if len(workflow.Subtemplates) > 0 || len(workflow.Matchers) > 0 || len(workflow.OnFailure) > 0 {
 		shouldNotValidate = true
 	}
+	if err := workflow.Compile(); err != nil {
+		return errors.Wrap(err, "could not compile workflow step")
+	}
 	if err := parseWorkflowTemplate(workflow, preprocessor, options, loader, shouldNotValidate); err != nil {
 		return err
 	}
@@ -38,6 +41,12 @@ func parseWorkflow(preprocessor Preprocessor, workflow *workflows.WorkflowTempla
 			continue
 		}
 	}
+	for _, subtemplates := range workflow.OnFailure {
+		if err := parseWorkflow(preprocessor, subtemplates, options, loader); err != nil {
+			gologger.Warning().Msgf("Could not parse workflow: %v\n", err)
+			continue
+		}
+	}
 	for _, matcher := range workflow.Matchers {
 		if len(matcher.Name.ToSlice()) > 0 {
 			if err := matcher.Compile(); err != nil {
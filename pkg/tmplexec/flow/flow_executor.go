@@ -79,6 +79,8 @@ func NewFlowExecutor(requests []protocols.Request, input *contextargs.Context, o
 			allprotos[templateTypes.CodeProtocol.String()] = append(allprotos[templateTypes.CodeProtocol.String()], req)
 		case templateTypes.JavascriptProtocol:
 			allprotos[templateTypes.JavascriptProtocol.String()] = append(allprotos[templateTypes.JavascriptProtocol.String()], req)
+		case templateTypes.GRPCProtocol:
+			allprotos[templateTypes.GRPCProtocol.String()] = append(allprotos[templateTypes.GRPCProtocol.String()], req)
 		default:
 			gologger.Error().Msgf("invalid request type %s", req.Type().String())
 		}
@@ -70,6 +70,10 @@ type Options struct {
 	InternalResolversList []string // normalized from resolvers flag as well as file provided.
 	// ProjectPath allows vulmap to use a user defined project folder
 	ProjectPath string
+	// RecordPath is the directory to record all protocol exchanges of the scan to
+	RecordPath string
+	// ReplayPath is the directory to replay recorded protocol exchanges from
+	ReplayPath string
 	// InteractshURL is the URL for the interactsh server.
 	InteractshURL string
 	// Interactsh Authorization header value for self-hosted servers
@@ -196,6 +200,12 @@ type Options struct {
 	// using same matchers/extractors from http protocol without the need
 	// to send a new request, reading responses from a file.
 	OfflineHTTP bool
+	// PassiveProxy is the listen address of the intercepting proxy matching
+	// the observed responses with the passive templates.
+	PassiveProxy string
+	// PassiveProxyFuzz executes the fuzzing templates on the requests observed
+	// by the passive proxy.
+	PassiveProxyFuzz bool
 	// Force HTTP2 requests
 	ForceAttemptHTTP2 bool
 	// StatsJSON writes stats output in JSON format
@@ -216,6 +226,21 @@ type Options struct {
 	SystemResolvers bool
 	// ShowActions displays a list of all headless actions
 	ShowActions bool
+	// Crawl crawls the inputs with the headless browser to discover the
+	// requests used as inputs of the fuzzing templates
+	Crawl bool
+	// CrawlDepth is the maximum depth of the links followed by the crawler
+	CrawlDepth int
+	// CrawlScope contains the regexes of the in scope URLs of the crawler
+	CrawlScope goflags.StringSlice
+	// CrawlOutOfScope contains the regexes of the out of scope URLs of the crawler
+	CrawlOutOfScope goflags.StringSlice
+	// SessionExport is the file the session state (cookies, storage and
+	// authentication headers) of the headless pages is exported to
+	SessionExport string
+	// SessionImport is the file the session state used by the http requests
+	// and headless pages is imported from
+	SessionImport string
 	// Deprecated: Enabled by default through clistats . Metrics enables display of metrics via an http endpoint
 	Metrics bool
 	// Debug mode allows debugging request/responses for the engine
@@ -234,6 +259,8 @@ type Options struct {
 	Silent bool
 	// Validate validates the templates passed to vulmap.
 	Validate bool
+	// TestTemplates runs the embedded test cases of the templates passed to vulmap.
+	TestTemplates bool
 	// NoStrictSyntax disables strict syntax check on vulmap templates (allows custom key-value pairs).
 	NoStrictSyntax bool
 	// Verbose flag indicates whether to show verbose output or not
@@ -330,6 +357,10 @@ type Options struct {
 	DisableStdin bool
 	// IncludeConditions is the list of conditions templates should match
 	IncludeConditions goflags.StringSlice
+	// TemplateQuery is the list of template queries templates should match
+	TemplateQuery goflags.StringSlice
+	// TemplateQueryExplain explains why templates were or weren't selected by the template queries
+	TemplateQueryExplain bool
 	// Enable uncover engine
 	Uncover bool
 	// Uncover search query
@@ -384,6 +415,22 @@ type Options struct {
 	AzureServiceURL string
 	// AzureTemplateDisableDownload disables downloading templates from Azure Blob Storage
 	AzureTemplateDisableDownload bool
+	// GitTemplateRepo is the list of clone urls of custom templates git repositories. Example: https://gitea.example.com/org/templates.git#v1.0.0
+	GitTemplateRepo []string
+	// GitTemplateUsername is the username used with the token to clone/pull from private git repositories over http(s)
+	GitTemplateUsername string
+	// GitTemplateToken is the token used to clone/pull from private git repositories over http(s)
+	GitTemplateToken string
+	// GitTemplateSSHKey is the private key file used to clone/pull from private git repositories over ssh
+	GitTemplateSSHKey string
+	// GitTemplateDisableDownload disables downloading templates from custom git repositories
+	GitTemplateDisableDownload bool
+	// HTTPTemplateURL is the list of urls of custom templates tarballs. Example: https://artifacts.example.com/templates.tar.gz#sha256=<checksum>
+	HTTPTemplateURL []string
+	// HTTPTemplateToken is the bearer token used to download custom templates tarballs
+	HTTPTemplateToken string
+	// HTTPTemplateDisableDownload disables downloading templates from custom tarball urls
+	HTTPTemplateDisableDownload bool
 	// Scan Strategy (auto,hosts-spray,templates-spray)
 	ScanStrategy string
 	// Fuzzing Type overrides template level fuzzing-type configuration
@@ -392,12 +439,34 @@ type Options struct {
 	FuzzingMode string
 	// TlsImpersonate enables TLS impersonation
 	TlsImpersonate bool
-	// CodeTemplateSignaturePublicKey is the custom public key used to verify the template signature (algorithm is automatically inferred from the length)
+	// CodeTemplateSignaturePublicKey is the custom public key used to verify the template signature (algorithm is automatically inferred from the key format)
 	CodeTemplateSignaturePublicKey string
-	// CodeTemplateSignatureAlgorithm specifies the sign algorithm (rsa, ecdsa)
+	// CodeTemplateSignatureAlgorithm specifies the sign algorithm (ecdsa, ssh, minisign, sigstore)
 	CodeTemplateSignatureAlgorithm string
 	// SignTemplates enables signing of templates
 	SignTemplates bool
+	// TrustAction is the trust store action to perform (list, add, revoke)
+	TrustAction string
+	// TrustCert is the certificate of the signer to add to or revoke from the trust store
+	TrustCert string
+	// TrustName is the name of the signer to add to or revoke from the trust store
+	TrustName string
+	// TrustScopes are the scopes of the signer to add to the trust store
+	TrustScopes goflags.StringSlice
+	// BundleExport is the file to export the signed offline templates bundle to
+	BundleExport string
+	// BundleImport is the signed offline templates bundle file to import
+	BundleImport string
+	// Lockfile is the templates lockfile pinning the installed templates
+	Lockfile string
+	// LockfileSync installs the templates of the lockfile instead of
+	// refusing to run when the installed templates don't match
+	LockfileSync bool
+	// TemplateGraph is the format of the template dependency graph to output (dot, json)
+	TemplateGraph string
+	// TemplateDependents contains the templates, helper files or javascript
+	// modules to list the depending templates of
+	TemplateDependents goflags.StringSlice
 }
 
 // ShouldLoadResume resume file
@@ -3,8 +3,11 @@ package workflows
 import (
 	"fmt"
 
+	"github.com/Knetic/govaluate"
+
 	"github.com/khulnasoft-lab/vulmap/pkg/model/types/stringslice"
 	"github.com/khulnasoft-lab/vulmap/pkg/operators"
+	"github.com/khulnasoft-lab/vulmap/pkg/operators/common/dsl"
 	"github.com/khulnasoft-lab/vulmap/pkg/protocols"
 	templateTypes "github.com/khulnasoft-lab/vulmap/pkg/templates/types"
 )
@@ -37,8 +40,105 @@ type WorkflowTemplate struct {
 	// description: |
 	//    Subtemplates are run if the `template` field Template matches.
 	Subtemplates []*WorkflowTemplate `yaml:"subtemplates,omitempty" json:"subtemplates,omitempty" jsonschema:"title=subtemplate based result matchers,description=Subtemplates are ran if the template field Template matches"`
+	// description: |
+	//    Condition is an optional DSL expression evaluated over the values
+	//    exported by the parent steps. The step is only run if it evaluates to true.
+	// examples:
+	//   - value: "\"compare_versions(version, '< 5.8.0')\""
+	Condition string `yaml:"condition,omitempty" json:"condition,omitempty" jsonschema:"title=condition to run the step,description=DSL expression evaluated over parent results to run the step"`
+	// description: |
+	//    Export is a list of extracted values of the step passed to its subtemplates,
+	//    matcher subtemplates and on-failure steps.
+	//
+	//    Only exported values are passed to child steps when specified. Values are
+	//    collected for steps having subtemplates or matchers.
+	// examples:
+	//   - value: >
+	//       []string{"version", "token"}
+	Export stringslice.StringSlice `yaml:"export,omitempty" json:"export,omitempty" jsonschema:"title=extracted values to export,description=Extracted values of the step passed to child steps"`
+	// description: |
+	//    Import adds values passed to the step templates, evaluated over the values exported by the parent steps.
+	//
+	//    Keys are the names and values are DSL expressions evaluated over the exported values. Imported
+	//    values don't override the variables declared by the templates with the same name.
+	// examples:
+	//   - value: >
+	//       map[string]string{"api_token": "token", "api_path": "'/api/v' + version"}
+	Import map[string]string `yaml:"import,omitempty" json:"import,omitempty" jsonschema:"title=values to import,description=Values passed to the step templates evaluated over exported values"`
+	// description: |
+	//    OnFailure steps are run if the step does not match or fails with an error.
+	OnFailure []*WorkflowTemplate `yaml:"on-failure,omitempty" json:"on-failure,omitempty" jsonschema:"title=steps to run on failure,description=Steps to run if the step does not match or fails"`
 	// Executers perform the actual execution for the workflow template
 	Executers []*ProtocolExecuterPair `yaml:"-" json:"-"`
+
+	condition *govaluate.EvaluableExpression
+	imports   map[string]*govaluate.EvaluableExpression
+}
+
+// Compile compiles the condition and imports of the workflow step
+func (template *WorkflowTemplate) Compile() error {
+	if template.Condition != "" {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(template.Condition, dsl.HelperFunctions)
+		if err != nil {
+			return fmt.Errorf("could not compile condition %s: %w", template.Condition, err)
+		}
+		template.condition = compiled
+	}
+	if len(template.Import) > 0 {
+		template.imports = make(map[string]*govaluate.EvaluableExpression, len(template.Import))
+		for name, expression := range template.Import {
+			compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, dsl.HelperFunctions)
+			if err != nil {
+				return fmt.Errorf("could not compile import %s: %w", name, err)
+			}
+			template.imports[name] = compiled
+		}
+	}
+	return nil
+}
+
+// MatchCondition returns true if the step has no condition or
+// the condition evaluates to true for the values.
+func (template *WorkflowTemplate) MatchCondition(values map[string]interface{}) (bool, error) {
+	if template.condition == nil {
+		return true, nil
+	}
+	result, err := template.condition.Evaluate(values)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := result.(bool)
+	return ok && matched, nil
+}
+
+// ImportValues returns the imported variables of the step evaluated over the values.
+func (template *WorkflowTemplate) ImportValues(values map[string]interface{}) (map[string]interface{}, error) {
+	imported := make(map[string]interface{}, len(template.imports))
+	for name, expression := range template.imports {
+		result, err := expression.Evaluate(values)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate import %s: %w", name, err)
+		}
+		imported[name] = result
+	}
+	return imported, nil
+}
+
+// ExportValues returns the exported values of the step from the result.
+//
+// Values are looked up in the extracted values and the internal
+// (dynamic) extracted values of the result.
+func (template *WorkflowTemplate) ExportValues(result *operators.Result) map[string][]string {
+	names := template.Export.ToSlice()
+	exported := make(map[string][]string, len(names))
+	for _, name := range names {
+		if values, ok := result.Extracts[name]; ok && len(values) > 0 {
+			exported[name] = values
+		} else if values, ok := result.DynamicValues[name]; ok && len(values) > 0 {
+			exported[name] = values
+		}
+	}
+	return exported
 }
 
 // ProtocolExecuterPair is a pair of protocol executer and its options
@@ -41,3 +41,21 @@ func TestWorkflowMatchAndCompile(t *testing.T) {
 		require.False(t, matched, "could not match value")
 	})
 }
+
+func TestWorkflowTemplateCompile(t *testing.T) {
+	for _, condition := range []string{"compare_versions(version, ", "unknown_helper(version)"} {
+		require.Error(t, (&WorkflowTemplate{Condition: condition}).Compile(), "could compile invalid condition %s", condition)
+	}
+
+	template := &WorkflowTemplate{Condition: "compare_versions(version, '< 5.8.0')", Import: map[string]string{"api_path": "'/api/v' + version"}}
+	require.Nil(t, template.Compile(), "could not compile workflow step")
+	matched, err := template.MatchCondition(map[string]interface{}{"version": "5.7.2"})
+	require.Nil(t, err, "could not evaluate condition")
+	require.True(t, matched, "could not match condition")
+	_, err = template.MatchCondition(map[string]interface{}{})
+	require.Error(t, err, "could evaluate condition without value")
+
+	imported, err := template.ImportValues(map[string]interface{}{"version": "5.7.2"})
+	require.Nil(t, err, "could not evaluate imports")
+	require.Equal(t, map[string]interface{}{"api_path": "/api/v5.7.2"}, imported, "could not get imported values")
+}